@@ -0,0 +1,25 @@
+// Package cache define el contrato de caché compartido por los servicios de SICORA
+// y sus implementaciones: LRU en proceso y Redis (cualquier servidor compatible con el protocolo)
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss indica que la llave no está en la caché o expiró
+var ErrMiss = errors.New("cache: llave no encontrada")
+
+// Cache almacena valores binarios con expiración
+// Las implementaciones deben ser seguras para uso concurrente
+type Cache interface {
+	// Get retorna el valor de la llave o ErrMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set guarda el valor; ttl <= 0 significa sin expiración
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete elimina las llaves; las inexistentes se ignoran
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// testBackend verifica el contrato de Cache sobre una implementación
+func testBackend(t *testing.T, c Cache) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, "ausente"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get(ausente): se esperaba ErrMiss, se obtuvo %v", err)
+	}
+
+	if err := c.Set(ctx, "a", []byte("uno"), time.Minute); err != nil {
+		t.Fatalf("Set(a): %v", err)
+	}
+	if err := c.Set(ctx, "b", []byte("dos"), 0); err != nil {
+		t.Fatalf("Set(b): %v", err)
+	}
+
+	value, err := c.Get(ctx, "a")
+	if err != nil || string(value) != "uno" {
+		t.Errorf("Get(a) = %q, %v", value, err)
+	}
+
+	if err := c.Set(ctx, "a", []byte("otro"), time.Minute); err != nil {
+		t.Fatalf("Set(a) reemplazo: %v", err)
+	}
+	if value, _ := c.Get(ctx, "a"); string(value) != "otro" {
+		t.Errorf("Get(a) tras reemplazo = %q", value)
+	}
+
+	if err := c.Delete(ctx, "a", "b", "ausente"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for _, key := range []string{"a", "b"} {
+		if _, err := c.Get(ctx, key); !errors.Is(err, ErrMiss) {
+			t.Errorf("Get(%s) tras Delete: se esperaba ErrMiss, se obtuvo %v", key, err)
+		}
+	}
+
+	if err := c.Delete(ctx); err != nil {
+		t.Errorf("Delete sin llaves: %v", err)
+	}
+} // fin testBackend
+
+func TestLRU(t *testing.T) {
+	testBackend(t, NewLRU(10))
+}
+
+func TestLRUEviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+	c.Get(ctx, "a") // "b" queda como la menos usada
+	c.Set(ctx, "c", []byte("3"), 0)
+
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, ErrMiss) {
+		t.Errorf("la entrada menos usada debía descartarse, se obtuvo %v", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := c.Get(ctx, key); err != nil {
+			t.Errorf("Get(%s): %v", key, err)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d", c.Len())
+	}
+
+	// El valor retornado es una copia
+	value, _ := c.Get(ctx, "a")
+	value[0] = 'x'
+	if again, _ := c.Get(ctx, "a"); string(again) != "1" {
+		t.Errorf("la caché no debe compartir el slice retornado, se obtuvo %q", again)
+	}
+}
+
+func TestLRUExpiration(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	c := NewLRU(10)
+	c.now = func() time.Time { return clock }
+
+	c.Set(ctx, "corta", []byte("1"), time.Minute)
+	c.Set(ctx, "permanente", []byte("2"), 0)
+
+	clock = clock.Add(time.Minute)
+	if _, err := c.Get(ctx, "corta"); !errors.Is(err, ErrMiss) {
+		t.Errorf("la entrada debía expirar, se obtuvo %v", err)
+	}
+	if _, err := c.Get(ctx, "permanente"); err != nil {
+		t.Errorf("Get(permanente): %v", err)
+	}
+	if c.Len() != 1 {
+		t.Errorf("la entrada expirada debía descartarse, Len = %d", c.Len())
+	}
+}
+
+func newTestRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewRedis(client, "test:"), server
+}
+
+func TestRedis(t *testing.T) {
+	c, server := newTestRedis(t)
+	testBackend(t, c)
+
+	// Las llaves se guardan con el prefijo y expiran en el servidor
+	ctx := context.Background()
+	c.Set(ctx, "a", []byte("uno"), time.Minute)
+	if !server.Exists("test:a") {
+		t.Errorf("se esperaba la llave con prefijo, llaves: %v", server.Keys())
+	}
+
+	server.FastForward(time.Minute)
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, ErrMiss) {
+		t.Errorf("la llave debía expirar, se obtuvo %v", err)
+	}
+}
+
+func TestRedisFromConfig(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	c, err := NewRedisFromConfig(context.Background(), RedisConfig{Addr: server.Addr()}, "test:")
+	if err != nil {
+		t.Fatalf("NewRedisFromConfig: %v", err)
+	}
+	defer c.Close()
+	testBackend(t, c)
+
+	addr := server.Addr()
+	server.Close()
+	if _, err := NewRedisFromConfig(context.Background(), RedisConfig{Addr: addr}, "test:"); err == nil {
+		t.Error("se esperaba error con el servidor detenido")
+	}
+}
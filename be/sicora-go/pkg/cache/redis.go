@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Verificar que implementa la interfaz
+var _ Cache = (*Redis)(nil)
+
+// Redis implementa Cache sobre un servidor que hable el protocolo de Redis (Redis, Valkey, KeyDB...)
+// Todas las llaves se guardan con el prefijo dado para compartir el servidor entre servicios
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// RedisConfig contiene los parámetros de conexión al servidor
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+}
+
+// NewRedis crea la caché sobre un cliente existente
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// NewRedisFromConfig crea el cliente y verifica la conexión con PING
+func NewRedisFromConfig(ctx context.Context, cfg RedisConfig, prefix string) (*Redis, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return NewRedis(client, prefix), nil
+}
+
+// Get retorna el valor o ErrMiss si la llave no existe
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+
+	return value, err
+}
+
+// Set guarda el valor con expiración; ttl <= 0 significa sin expiración
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, max(ttl, 0)).Err()
+}
+
+// Delete elimina las llaves con un solo DEL
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+
+	return c.client.Del(ctx, prefixed...).Err()
+}
+
+// Close cierra la conexión con el servidor
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,191 @@
+package errors
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale es el idioma de los mensajes cuando el cliente no pide otro o su catálogo no tiene el código
+const DefaultLocale = "es"
+
+// Catalog son las plantillas de los mensajes de un idioma por código
+// Las plantillas usan {nombre} para los parámetros del error y {field} para el nombre del campo,
+// que se busca en el mismo catálogo con el código FieldCode(campo); sin él se usa el campo tal cual
+type Catalog map[Code]string
+
+// FieldCode retorna el código con el que se registra en un Catalog el nombre legible de un campo
+func FieldCode(field string) Code {
+	return Code("field." + field)
+}
+
+// kindCode retorna el código del título de los problem+json de un tipo de error
+func kindCode(kind Kind) Code {
+	return Code("kind." + string(kind))
+}
+
+var catalogs = struct {
+	sync.RWMutex
+	byLocale map[string]Catalog
+}{byLocale: map[string]Catalog{}}
+
+// Register agrega las plantillas al catálogo del idioma ("es", "en"); un código ya registrado se reemplaza
+// Cada servicio registra desde init los mensajes de sus códigos y los nombres de sus campos
+func Register(locale string, catalog Catalog) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	locale = strings.ToLower(locale)
+	if catalogs.byLocale[locale] == nil {
+		catalogs.byLocale[locale] = make(Catalog, len(catalog))
+	}
+	for code, template := range catalog {
+		catalogs.byLocale[locale][code] = template
+	}
+}
+
+// Locales retorna los idiomas con catálogo registrado, ordenados
+func Locales() []string {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	locales := make([]string, 0, len(catalogs.byLocale))
+	for locale := range catalogs.byLocale {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// Has indica si el catálogo del idioma tiene la plantilla del código, sin recurrir a DefaultLocale
+// Permite verificar en las pruebas de cada servicio que sus códigos tengan mensaje en todos los idiomas
+func Has(locale string, code Code) bool {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	_, ok := catalogs.byLocale[strings.ToLower(locale)][code]
+	return ok
+}
+
+// Codes retorna los códigos con plantilla en el catálogo del idioma, ordenados, sin recurrir a DefaultLocale
+// Con él las pruebas de cada servicio comparan los catálogos de sus idiomas sin leer el código fuente
+func Codes(locale string) []Code {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	catalog := catalogs.byLocale[strings.ToLower(locale)]
+	codes := make([]Code, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// lookup busca la plantilla del código en el idioma y luego en DefaultLocale
+func lookup(locale string, code Code) (string, bool) {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	if template, ok := catalogs.byLocale[strings.ToLower(locale)][code]; ok {
+		return template, true
+	}
+	template, ok := catalogs.byLocale[DefaultLocale][code]
+	return template, ok
+}
+
+// render reemplaza {field} y los parámetros en la plantilla
+func render(template, locale, field string, params map[string]any) string {
+	if field != "" && strings.Contains(template, "{field}") {
+		label, ok := lookup(locale, FieldCode(field))
+		if !ok {
+			label = field
+		}
+		template = strings.ReplaceAll(template, "{field}", label)
+	}
+
+	for name, value := range params {
+		template = strings.ReplaceAll(template, "{"+name+"}", formatParam(value))
+	}
+	return template
+}
+
+// formatParam convierte un parámetro en texto; las listas se separan con comas
+func formatParam(value any) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, ", ")
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// MatchLocale elige el idioma registrado que mejor corresponde a la cabecera Accept-Language
+// ("en-US,en;q=0.9,es;q=0.8"); compara solo el idioma principal y retorna DefaultLocale si ninguno coincide
+func MatchLocale(acceptLanguage string) string {
+	registered := Locales()
+
+	best, bestWeight := DefaultLocale, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if weight > bestWeight && slices.Contains(registered, language) {
+			best, bestWeight = language, weight
+		}
+	}
+
+	return best
+} // fin MatchLocale
+
+func init() {
+	Register("es", Catalog{
+		CodeRequired:         "El campo {field} es obligatorio",
+		CodeTooShort:         "El campo {field} debe tener al menos {min} caracteres",
+		CodeTooLong:          "El campo {field} no debe exceder los {max} caracteres",
+		CodeInvalid:          "El valor de {field} no es válido",
+		CodeInvalidFormat:    "El campo {field} no tiene un formato válido",
+		CodeInvalidOption:    "El valor de {field} no es válido: debe ser uno de {options}",
+		CodeOutOfRange:       "El campo {field} debe estar entre {min} y {max}",
+		CodeValidationFailed: "Los datos tienen {count} errores de validación",
+		CodeInternal:         "Ocurrió un error interno, intente de nuevo más tarde",
+
+		kindCode(KindValidation):     "Datos no válidos",
+		kindCode(KindInvalidRequest): "Petición no válida",
+		kindCode(KindNotFound):       "Recurso no encontrado",
+		kindCode(KindConflict):       "Conflicto con el estado actual",
+		kindCode(KindForbidden):      "Operación no permitida",
+		kindCode(KindInternal):       "Error interno",
+	})
+
+	Register("en", Catalog{
+		CodeRequired:         "{field} is required",
+		CodeTooShort:         "{field} must be at least {min} characters long",
+		CodeTooLong:          "{field} must not exceed {max} characters",
+		CodeInvalid:          "{field} is not valid",
+		CodeInvalidFormat:    "{field} has an invalid format",
+		CodeInvalidOption:    "{field} is not valid: must be one of {options}",
+		CodeOutOfRange:       "{field} must be between {min} and {max}",
+		CodeValidationFailed: "The data has {count} validation errors",
+		CodeInternal:         "An internal error occurred, please try again later",
+
+		kindCode(KindValidation):     "Invalid data",
+		kindCode(KindInvalidRequest): "Invalid request",
+		kindCode(KindNotFound):       "Resource not found",
+		kindCode(KindConflict):       "Conflict with the current state",
+		kindCode(KindForbidden):      "Operation not allowed",
+		kindCode(KindInternal):       "Internal error",
+	})
+} // fin init
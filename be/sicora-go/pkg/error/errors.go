@@ -0,0 +1,194 @@
+// Package errors define los errores estructurados compartidos por los servicios de SICORA:
+// cada error tiene un tipo, un código estable, el campo que lo provocó y los parámetros del mensaje
+// (longitud mínima, opciones válidas...), de modo que el cliente pueda resaltar los campos inválidos
+// y traducir el mensaje. Los mensajes salen de catálogos por idioma y los errores se convierten en
+// respuestas HTTP problem+json (RFC 9457)
+package errors
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Code identifica el error de forma estable; los clientes lo usan en lugar del mensaje
+// Los códigos genéricos no tienen prefijo, los propios de un servicio sí ("user.duplicate")
+type Code string
+
+// Códigos genéricos de validación; sus mensajes están en los catálogos es y en de este paquete
+const (
+	CodeRequired      Code = "required"       // Campo vacío u obligatorio sin valor
+	CodeTooShort      Code = "too_short"      // Parámetro min: longitud mínima en caracteres
+	CodeTooLong       Code = "too_long"       // Parámetro max: longitud máxima en caracteres
+	CodeInvalid       Code = "invalid"        // Valor no válido sin más detalle
+	CodeInvalidFormat Code = "invalid_format" // Valor que no cumple el formato esperado
+	CodeInvalidOption Code = "invalid_option" // Parámetro options: lista de valores permitidos
+	CodeOutOfRange    Code = "out_of_range"   // Parámetros min y max: rango permitido, inclusivo
+
+	CodeValidationFailed Code = "validation_failed" // Una List; parámetro count: número de errores
+	CodeInternal         Code = "internal"          // Error no clasificado; su causa no se expone al cliente
+)
+
+// Kind es la categoría del error y determina el estado HTTP de la respuesta
+type Kind string
+
+const (
+	KindValidation     Kind = "validation"      // 422: los datos no cumplen las reglas de dominio
+	KindInvalidRequest Kind = "invalid_request" // 400: la petición está mal formada (filtros, cursores)
+	KindNotFound       Kind = "not_found"       // 404
+	KindConflict       Kind = "conflict"        // 409: duplicados, versiones, estados incompatibles
+	KindForbidden      Kind = "forbidden"       // 403
+	KindInternal       Kind = "internal"        // 500
+)
+
+// Status retorna el estado HTTP del tipo de error
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusUnprocessableEntity
+	case KindInvalidRequest:
+		return http.StatusBadRequest
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error es un error estructurado; su texto es el mensaje del catálogo en DefaultLocale
+// Los errores se construyen con New o Validation y no se modifican después: With retorna una copia,
+// así un error declarado como variable de paquete se puede usar como base sin alterarlo
+type Error struct {
+	Kind   Kind           `json:"kind"`
+	Code   Code           `json:"code"`
+	Field  string         `json:"field,omitempty"`  // Nombre del campo en JSON ("first_name"); vacío si no es de un campo
+	Params map[string]any `json:"params,omitempty"` // Valores para el mensaje: {min} en la plantilla es Params["min"]
+	cause  error          // Error original cuando se obtuvo con From
+}
+
+// New crea un error del tipo y código dados
+func New(kind Kind, code Code) *Error {
+	return &Error{Kind: kind, Code: code}
+}
+
+// Validation crea un error de validación del campo dado
+func Validation(code Code, field string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Field: field}
+}
+
+// With retorna una copia del error con el parámetro agregado
+func (e *Error) With(name string, value any) *Error {
+	copied := *e
+	copied.Params = maps.Clone(e.Params)
+	if copied.Params == nil {
+		copied.Params = make(map[string]any, 1)
+	}
+	copied.Params[name] = value
+	return &copied
+}
+
+// OnField retorna una copia del error asociada al campo dado
+func (e *Error) OnField(field string) *Error {
+	copied := *e
+	copied.Field = field
+	return &copied
+}
+
+func (e *Error) Error() string {
+	return e.Message(DefaultLocale)
+}
+
+// Message retorna el mensaje del error en el idioma dado, o en DefaultLocale si el catálogo del idioma no tiene el código
+// Sin plantilla en ningún catálogo se usa el texto del error original y, en último caso, el código
+func (e *Error) Message(locale string) string {
+	if template, ok := lookup(locale, e.Code); ok {
+		return render(template, locale, e.Field, e.Params)
+	}
+	if e.cause != nil {
+		return e.cause.Error()
+	}
+	return string(e.Code)
+}
+
+// Is permite comparar por código con errors.Is: un error de referencia sin campo coincide con cualquier campo
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Code == e.Code && (t.Field == "" || t.Field == e.Field)
+}
+
+// Unwrap retorna el error original cuando se obtuvo con From
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Structured lo implementan los errores propios de un servicio que llevan datos adicionales
+// (el campo duplicado, la versión en conflicto) y se reportan como *Error
+type Structured interface {
+	Structured() *Error
+}
+
+// definition asocia un error sentinela con su tipo y código
+type definition struct {
+	sentinel error
+	kind     Kind
+	code     Code
+}
+
+var definitions struct {
+	sync.RWMutex
+	list []definition
+}
+
+// Define asocia un error sentinela (creado con errors.New) con su tipo y código para que From lo reconozca
+// con errors.Is, también envuelto o a través de los errores que lo implementan con un método Is
+// Se llama desde init en el paquete que declara el sentinela
+func Define(sentinel error, kind Kind, code Code) {
+	definitions.Lock()
+	defer definitions.Unlock()
+	definitions.list = append(definitions.list, definition{sentinel: sentinel, kind: kind, code: code})
+}
+
+// From obtiene el *Error que representa err: el propio *Error si lo contiene, el de un Structured,
+// el de un sentinela registrado con Define y, si no se reconoce, un error interno que conserva la causa
+// Un sentinela envuelto como "%w: detalle" conserva el detalle en el parámetro detail
+// Retorna nil si err es nil. Una *List se representa con su primer error; NewProblem la trata completa
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var structured *Error
+	if errors.As(err, &structured) {
+		return structured
+	}
+
+	var custom Structured
+	if errors.As(err, &custom) {
+		result := *custom.Structured()
+		result.cause = err
+		return &result
+	}
+
+	definitions.RLock()
+	defer definitions.RUnlock()
+	for _, def := range definitions.list {
+		if errors.Is(err, def.sentinel) {
+			result := &Error{Kind: def.kind, Code: def.code, cause: err}
+			if detail, ok := strings.CutPrefix(err.Error(), def.sentinel.Error()+": "); ok {
+				result.Params = map[string]any{"detail": detail}
+			}
+			return result
+		}
+	}
+
+	return &Error{Kind: KindInternal, Code: CodeInternal, cause: err}
+} // fin From
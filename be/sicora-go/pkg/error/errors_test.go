@@ -0,0 +1,264 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var errTestMissing = errors.New("registro de prueba no encontrado")
+
+func init() {
+	Define(errTestMissing, KindNotFound, "test.missing")
+	Register("es", Catalog{FieldCode("first_name"): "nombre", "test.missing": "El registro no existe"})
+	Register("en", Catalog{FieldCode("first_name"): "first name"})
+}
+
+// testStructured es un error propio de un servicio que se reporta como *Error
+type testStructured struct{ field string }
+
+func (e *testStructured) Error() string { return "duplicado: " + e.field }
+
+func (e *testStructured) Structured() *Error {
+	return New(KindConflict, "test.duplicate").OnField(e.field)
+}
+
+func TestMessages(t *testing.T) {
+	err := Validation(CodeTooShort, "first_name").With("min", 2)
+
+	if got, want := err.Error(), "El campo nombre debe tener al menos 2 caracteres"; got != want {
+		t.Errorf("Error() = %q, se esperaba %q", got, want)
+	}
+	if got, want := err.Message("en"), "first name must be at least 2 characters long"; got != want {
+		t.Errorf("Message(en) = %q, se esperaba %q", got, want)
+	}
+	if got, want := err.Message("fr"), err.Error(); got != want {
+		t.Errorf("Message(fr) = %q, se esperaba el mensaje en %s %q", got, DefaultLocale, want)
+	}
+
+	options := Validation(CodeInvalidOption, "role").With("options", []string{"a", "b"})
+	if got, want := options.Message("en"), "role is not valid: must be one of a, b"; got != want {
+		t.Errorf("Message con lista = %q, se esperaba %q", got, want)
+	}
+
+	if got := New(KindValidation, "test.sin_plantilla").Error(); got != "test.sin_plantilla" {
+		t.Errorf("Error() sin plantilla = %q, se esperaba el código", got)
+	}
+} // fin TestMessages
+
+func TestWithCopies(t *testing.T) {
+	base := Validation(CodeTooLong, "first_name")
+	long := base.With("max", 100)
+
+	if base.Params != nil {
+		t.Errorf("With modificó el error base: %v", base.Params)
+	}
+	if long.Params["max"] != 100 {
+		t.Errorf("With: params = %v", long.Params)
+	}
+	if other := long.OnField("last_name"); long.Field != "first_name" || other.Field != "last_name" {
+		t.Errorf("OnField: original %q, copia %q", long.Field, other.Field)
+	}
+}
+
+func TestIs(t *testing.T) {
+	err := fmt.Errorf("crear usuario: %w", Validation(CodeRequired, "email"))
+
+	if !errors.Is(err, Validation(CodeRequired, "")) {
+		t.Error("errors.Is por código sin campo: se esperaba true")
+	}
+	if !errors.Is(err, Validation(CodeRequired, "email")) {
+		t.Error("errors.Is por código y campo: se esperaba true")
+	}
+	if errors.Is(err, Validation(CodeRequired, "first_name")) {
+		t.Error("errors.Is con otro campo: se esperaba false")
+	}
+	if errors.Is(err, Validation(CodeTooShort, "")) {
+		t.Error("errors.Is con otro código: se esperaba false")
+	}
+}
+
+func TestFrom(t *testing.T) {
+	if From(nil) != nil {
+		t.Error("From(nil): se esperaba nil")
+	}
+
+	missing := From(fmt.Errorf("buscar: %w", errTestMissing))
+	if missing.Kind != KindNotFound || missing.Code != "test.missing" || missing.Error() != "El registro no existe" {
+		t.Errorf("From de sentinela = %+v (%q)", missing, missing.Error())
+	}
+	if !errors.Is(missing, errTestMissing) {
+		t.Error("From de sentinela: se esperaba conservar la causa")
+	}
+	if got := missing.Message("en"); got != "El registro no existe" {
+		t.Errorf("Message(en) sin plantilla en inglés = %q, se esperaba el mensaje en %s", got, DefaultLocale)
+	}
+
+	duplicate := From(&testStructured{field: "email"})
+	if duplicate.Kind != KindConflict || duplicate.Code != "test.duplicate" || duplicate.Field != "email" {
+		t.Errorf("From de Structured = %+v", duplicate)
+	}
+
+	detailed := From(fmt.Errorf("%w: segmento %q", errTestMissing, "x"))
+	if detailed.Code != "test.missing" || detailed.Params["detail"] != `segmento "x"` {
+		t.Errorf("From de sentinela envuelto con detalle = %+v", detailed)
+	}
+
+	cause := errors.New("conexión rechazada")
+	internal := From(cause)
+	if internal.Kind != KindInternal || internal.Code != CodeInternal || !errors.Is(internal, cause) {
+		t.Errorf("From de error desconocido = %+v", internal)
+	}
+} // fin TestFrom
+
+func TestList(t *testing.T) {
+	var list List
+	if list.Err() != nil {
+		t.Fatal("Err de una lista vacía: se esperaba nil")
+	}
+
+	var inner List
+	inner.Add(Validation(CodeTooShort, "first_name").With("min", 2))
+	inner.Add(nil)
+
+	list.Add(inner.Err())
+	list.Add(Validation(CodeRequired, "email"))
+	list.Add(errTestMissing)
+
+	if len(list.Errors) != 3 {
+		t.Fatalf("len(Errors) = %d, se esperaban 3: %v", len(list.Errors), list.Errors)
+	}
+	if list.Errors[2].Code != "test.missing" {
+		t.Errorf("Add de sentinela: código %q", list.Errors[2].Code)
+	}
+
+	err := fmt.Errorf("validar: %w", list.Err())
+	var first *Error
+	if !errors.As(err, &first) || first.Field != "first_name" {
+		t.Errorf("errors.As sobre la lista: se esperaba el primer error, se obtuvo %v", first)
+	}
+	if !errors.Is(err, Validation(CodeRequired, "email")) || !errors.Is(err, errTestMissing) {
+		t.Error("errors.Is sobre la lista: se esperaba encontrar todos los errores")
+	}
+
+	want := "El campo nombre debe tener al menos 2 caracteres; El campo email es obligatorio; El registro no existe"
+	if got := list.Error(); got != want {
+		t.Errorf("Error() = %q, se esperaba %q", got, want)
+	}
+} // fin TestList
+
+func TestMatchLocale(t *testing.T) {
+	cases := map[string]string{
+		"":                        DefaultLocale,
+		"en":                      "en",
+		"en-US,en;q=0.9,es;q=0.8": "en",
+		"es-CO,es;q=0.9,en;q=0.8": "es",
+		"fr-FR,fr;q=0.9,en;q=0.5": "en",
+		"de,fr":                   DefaultLocale,
+		"es;q=0.2,EN-gb;q=0.7":    "en",
+		"en;q=0,es;q=0.1":         "es",
+		"en;q=abc":                DefaultLocale,
+	}
+	for header, want := range cases {
+		if got := MatchLocale(header); got != want {
+			t.Errorf("MatchLocale(%q) = %q, se esperaba %q", header, got, want)
+		}
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	single := NewProblem(Validation(CodeTooShort, "first_name").With("min", 2), "en")
+	if single.Status != http.StatusUnprocessableEntity || single.Code != CodeTooShort || single.Type != ProblemTypeBase+"too_short" {
+		t.Errorf("problema de un error = %+v", single)
+	}
+	if single.Title != "Invalid data" || single.Detail != "first name must be at least 2 characters long" {
+		t.Errorf("problema de un error: título %q, detalle %q", single.Title, single.Detail)
+	}
+	if len(single.Errors) != 1 || single.Errors[0].Field != "first_name" || single.Errors[0].Params["min"] != 2 {
+		t.Errorf("problema de un error: errors = %+v", single.Errors)
+	}
+
+	var list List
+	list.Add(Validation(CodeTooShort, "first_name").With("min", 2))
+	list.Add(Validation(CodeRequired, "email"))
+	multi := NewProblem(list.Err(), "es")
+	if multi.Status != http.StatusUnprocessableEntity || multi.Code != CodeValidationFailed || len(multi.Errors) != 2 {
+		t.Errorf("problema de una lista = %+v", multi)
+	}
+	if multi.Detail != "Los datos tienen 2 errores de validación" || multi.Errors[1].Message != "El campo email es obligatorio" {
+		t.Errorf("problema de una lista: detalle %q, errores %+v", multi.Detail, multi.Errors)
+	}
+
+	list.Add(errTestMissing)
+	if mixed := NewProblem(list.Err(), "es"); mixed.Status != http.StatusBadRequest {
+		t.Errorf("problema de una lista con tipos distintos: estado %d, se esperaba 400", mixed.Status)
+	}
+
+	notFound := NewProblem(errTestMissing, "es")
+	if notFound.Status != http.StatusNotFound || notFound.Detail != "El registro no existe" || notFound.Errors != nil {
+		t.Errorf("problema de un sentinela = %+v", notFound)
+	}
+
+	internal := NewProblem(errors.New("dsn=postgres://secreto"), "en")
+	if internal.Status != http.StatusInternalServerError || internal.Detail != "An internal error occurred, please try again later" {
+		t.Errorf("problema interno = %+v", internal)
+	}
+} // fin TestNewProblem
+
+func TestWriteProblem(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/users", nil)
+	request.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	recorder := httptest.NewRecorder()
+
+	WriteProblem(recorder, request, Validation(CodeRequired, "email"))
+
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("estado = %d, se esperaba 422", recorder.Code)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != ProblemContentType {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := recorder.Header().Get("Content-Language"); got != "en" {
+		t.Errorf("Content-Language = %q", got)
+	}
+
+	var problem Problem
+	if err := json.NewDecoder(recorder.Body).Decode(&problem); err != nil {
+		t.Fatalf("decodificar problem+json: %v", err)
+	}
+	if problem.Instance != "/api/v1/users" || problem.Code != CodeRequired || problem.Detail != "email is required" {
+		t.Errorf("problem+json = %+v", problem)
+	}
+} // fin TestWriteProblem
+
+// TestCatalogsCoverCodes falla si un código o tipo declarado en errors.go no tiene plantilla en es y en,
+// o si un catálogo tiene un código que el otro no, salvo los que registran estas pruebas
+func TestCatalogsCoverCodes(t *testing.T) {
+	codes := []Code{
+		CodeRequired, CodeTooShort, CodeTooLong, CodeInvalid, CodeInvalidFormat, CodeInvalidOption, CodeOutOfRange,
+		CodeValidationFailed, CodeInternal,
+	}
+	for _, kind := range []Kind{KindValidation, KindInvalidRequest, KindNotFound, KindConflict, KindForbidden, KindInternal} {
+		codes = append(codes, kindCode(kind))
+	}
+
+	for _, locale := range []string{"es", "en"} {
+		for _, code := range codes {
+			if !Has(locale, code) {
+				t.Errorf("el catálogo %s no tiene plantilla para %q", locale, code)
+			}
+		}
+	}
+
+	for _, locales := range [][2]string{{"es", "en"}, {"en", "es"}} {
+		for _, code := range Codes(locales[0]) {
+			// "test.missing" solo está en es para probar el respaldo de DefaultLocale
+			if code != "test.missing" && !Has(locales[1], code) {
+				t.Errorf("el catálogo %s tiene plantilla para %q y el %s no", locales[0], code, locales[1])
+			}
+		}
+	}
+} // fin TestCatalogsCoverCodes
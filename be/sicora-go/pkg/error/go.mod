@@ -0,0 +1,3 @@
+module sicora-be-go/pkg/errors
+
+go 1.25
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"errors"
+	"strings"
+)
+
+// List agrupa los errores de una validación para reportar todos los campos inválidos a la vez
+// errors.As y errors.Is recorren sus errores, así errors.As(err, &target) con target *Error obtiene el primero
+type List struct {
+	Errors []*Error
+}
+
+// Add agrega err a la lista: ignora nil, aplana otra *List y convierte los demás errores con From
+func (l *List) Add(err error) {
+	if err == nil {
+		return
+	}
+	var other *List
+	if errors.As(err, &other) {
+		l.Errors = append(l.Errors, other.Errors...)
+		return
+	}
+	l.Errors = append(l.Errors, From(err))
+}
+
+// Err retorna la lista como error, o nil si está vacía
+func (l *List) Err() error {
+	if len(l.Errors) == 0 {
+		return nil
+	}
+	return l
+}
+
+// Error une los mensajes en DefaultLocale
+func (l *List) Error() string {
+	messages := make([]string, len(l.Errors))
+	for i, err := range l.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap expone los errores de la lista a errors.Is y errors.As
+func (l *List) Unwrap() []error {
+	errs := make([]error, len(l.Errors))
+	for i, err := range l.Errors {
+		errs[i] = err
+	}
+	return errs
+}
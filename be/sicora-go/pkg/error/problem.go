@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ProblemContentType es el tipo de contenido de las respuestas de error (RFC 9457)
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase es el prefijo del campo type; el código del error lo completa ("urn:sicora:problem:too_short")
+var ProblemTypeBase = "urn:sicora:problem:"
+
+// Problem es el cuerpo problem+json de una respuesta de error
+// Code y Errors son extensiones: el código del problema y cada error con su campo, para resaltarlo en el formulario
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     Code         `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError es un error de Problem.Errors con el mensaje ya traducido
+type FieldError struct {
+	Code    Code           `json:"code"`
+	Field   string         `json:"field,omitempty"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// NewProblem convierte err en un Problem con los mensajes en el idioma dado
+// Una *List produce un problema con todos sus errores; los demás errores se obtienen con From
+// y los internos no exponen su causa
+func NewProblem(err error, locale string) *Problem {
+	var list *List
+	if errors.As(err, &list) && len(list.Errors) > 1 {
+		kind := list.Errors[0].Kind
+		for _, e := range list.Errors[1:] {
+			if e.Kind != kind {
+				kind = KindInvalidRequest
+				break
+			}
+		}
+
+		problem := newProblem(kind, CodeValidationFailed, locale)
+		problem.Detail = New(kind, CodeValidationFailed).With("count", len(list.Errors)).Message(locale)
+		for _, e := range list.Errors {
+			problem.Errors = append(problem.Errors, fieldError(e, locale))
+		}
+		return problem
+	}
+
+	e := From(err)
+	problem := newProblem(e.Kind, e.Code, locale)
+	if e.Kind == KindInternal {
+		problem.Detail, _ = lookup(locale, CodeInternal)
+		return problem
+	}
+
+	// El error se detalla en errors si es de un campo o lleva parámetros, como el detail de un sentinela envuelto
+	problem.Detail = e.Message(locale)
+	if e.Field != "" || len(e.Params) > 0 {
+		problem.Errors = []FieldError{fieldError(e, locale)}
+	}
+	return problem
+} // fin NewProblem
+
+// newProblem crea el Problem con tipo, título y estado según el tipo de error
+func newProblem(kind Kind, code Code, locale string) *Problem {
+	title, ok := lookup(locale, kindCode(kind))
+	if !ok {
+		title = http.StatusText(kind.Status())
+	}
+	return &Problem{
+		Type:   ProblemTypeBase + string(code),
+		Title:  title,
+		Status: kind.Status(),
+		Code:   code,
+	}
+}
+
+// fieldError convierte un *Error en un FieldError traducido
+func fieldError(e *Error, locale string) FieldError {
+	return FieldError{Code: e.Code, Field: e.Field, Message: e.Message(locale), Params: e.Params}
+}
+
+// WriteProblem responde err como problem+json en el idioma de la cabecera Accept-Language de la petición
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	locale := MatchLocale(r.Header.Get("Accept-Language"))
+	problem := NewProblem(err, locale)
+	problem.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("Content-Language", locale)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,198 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DashboardDimension es una de las dimensiones del cruce de usuarios del dashboard directivo
+type DashboardDimension string
+
+const (
+	DimensionRole    DashboardDimension = "role"
+	DimensionProgram DashboardDimension = "program"
+	DimensionSede    DashboardDimension = "sede"
+	DimensionStatus  DashboardDimension = "status"
+)
+
+// Valores de la dimensión de estado: si el usuario está habilitado (IsActive)
+const (
+	DashboardStatusActive   = "active"
+	DashboardStatusInactive = "inactive"
+)
+
+// DashboardKeyNone es el valor de la dimensión para los usuarios sin sede o sin programa
+const DashboardKeyNone = ""
+
+// DashboardSnapshot es la foto de los usuarios no eliminados cruzados por rol, programa, sede y estado
+// Las consultas del dashboard se responden desde la foto sin volver a recorrer los usuarios
+type DashboardSnapshot struct {
+	ID      uuid.UUID       `gorm:"column:id_dashboard_snapshot;type:uuid;primaryKey;default:gen_random_uuid()" json:"id_dashboard_snapshot"`
+	TakenAt time.Time       `gorm:"column:taken_at_dashboard_snapshot;type:timestamptz;not null" json:"taken_at_dashboard_snapshot"` // "Datos a" del dashboard
+	Cells   []DashboardCell `gorm:"foreignKey:SnapshotID;references:ID" json:"cells"`                                                // Ordenadas por rol, programa, sede y estado
+}
+
+// TableName especifica el nombre de la tabla
+func (DashboardSnapshot) TableName() string {
+	return "userservice.dashboard_snapshots"
+}
+
+// DashboardCell es el conteo de una combinación de rol, programa, sede y estado
+type DashboardCell struct {
+	SnapshotID uuid.UUID `gorm:"column:snapshot_id_dashboard_cell;type:uuid;primaryKey" json:"-"`
+	Role       string    `gorm:"column:role_dashboard_cell;type:varchar(20);primaryKey" json:"role"`
+	Program    string    `gorm:"column:program_dashboard_cell;type:varchar(50);primaryKey" json:"program"` // DashboardKeyNone sin programa
+	Sede       string    `gorm:"column:sede_dashboard_cell;type:varchar(36);primaryKey" json:"sede"`       // DashboardKeyNone sin sede
+	Status     string    `gorm:"column:status_dashboard_cell;type:varchar(10);primaryKey" json:"status"`
+	Count      int       `gorm:"column:count_dashboard_cell;not null" json:"count"`
+}
+
+// TableName especifica el nombre de la tabla
+func (DashboardCell) TableName() string {
+	return "userservice.dashboard_snapshot_cells"
+}
+
+// Key retorna el valor de la celda en la dimensión
+func (c DashboardCell) Key(dimension DashboardDimension) string {
+	switch dimension {
+	case DimensionRole:
+		return c.Role
+	case DimensionProgram:
+		return c.Program
+	case DimensionSede:
+		return c.Sede
+	default:
+		return c.Status
+	}
+}
+
+// compareCells ordena las celdas por rol, programa, sede y estado
+func compareCells(a, b DashboardCell) int {
+	for _, dimension := range []DashboardDimension{DimensionRole, DimensionProgram, DimensionSede, DimensionStatus} {
+		if order := strings.Compare(a.Key(dimension), b.Key(dimension)); order != 0 {
+			return order
+		}
+	}
+	return 0
+}
+
+// NewDashboardSnapshot crea una foto vacía tomada en takenAt
+func NewDashboardSnapshot(takenAt time.Time) *DashboardSnapshot {
+	return &DashboardSnapshot{ID: uuid.New(), TakenAt: takenAt}
+}
+
+// Add suma count usuarios a la celda de la combinación dada, creándola si no existe
+func (s *DashboardSnapshot) Add(role, program, sede, status string, count int) {
+	cell := DashboardCell{SnapshotID: s.ID, Role: role, Program: program, Sede: sede, Status: status}
+	position, found := slices.BinarySearchFunc(s.Cells, cell, compareCells)
+	if !found {
+		s.Cells = slices.Insert(s.Cells, position, cell)
+	}
+	s.Cells[position].Count += count
+}
+
+// SortCells ordena las celdas byte a byte por rol, programa, sede y estado, el orden en que las deja Add
+func (s *DashboardSnapshot) SortCells() {
+	slices.SortFunc(s.Cells, compareCells)
+}
+
+// DashboardStatus retorna el valor del usuario en la dimensión de estado
+func DashboardStatus(user *User) string {
+	if user.IsActive {
+		return DashboardStatusActive
+	}
+	return DashboardStatusInactive
+}
+
+// Total retorna la cantidad de usuarios de la foto
+func (s *DashboardSnapshot) Total() int {
+	total := 0
+	for _, cell := range s.Cells {
+		total += cell.Count
+	}
+	return total
+}
+
+// Filter retorna una foto con solo las celdas cuyo valor en la dimensión es key
+func (s *DashboardSnapshot) Filter(dimension DashboardDimension, key string) *DashboardSnapshot {
+	filtered := &DashboardSnapshot{ID: s.ID, TakenAt: s.TakenAt}
+	for _, cell := range s.Cells {
+		if cell.Key(dimension) == key {
+			filtered.Cells = append(filtered.Cells, cell)
+		}
+	}
+	return filtered
+}
+
+// CountBy retorna el total de usuarios por valor de la dimensión
+func (s *DashboardSnapshot) CountBy(dimension DashboardDimension) map[string]int {
+	counts := make(map[string]int)
+	for _, cell := range s.Cells {
+		counts[cell.Key(dimension)] += cell.Count
+	}
+	return counts
+}
+
+// CrossTab es la tabla cruzada de dos dimensiones con sus totales por fila y columna
+type CrossTab struct {
+	Rows         DashboardDimension `json:"rows"`
+	Columns      DashboardDimension `json:"columns"`
+	RowKeys      []string           `json:"row_keys"`    // Ordenadas
+	ColumnKeys   []string           `json:"column_keys"` // Ordenadas
+	Counts       [][]int            `json:"counts"`      // Counts[fila][columna]
+	RowTotals    []int              `json:"row_totals"`
+	ColumnTotals []int              `json:"column_totals"`
+	Total        int                `json:"total"`
+	TakenAt      time.Time          `json:"taken_at"`
+}
+
+// CrossTab cruza la foto por dos dimensiones
+func (s *DashboardSnapshot) CrossTab(rows, columns DashboardDimension) *CrossTab {
+	table := &CrossTab{
+		Rows:       rows,
+		Columns:    columns,
+		RowKeys:    sortedKeys(s.CountBy(rows)),
+		ColumnKeys: sortedKeys(s.CountBy(columns)),
+		TakenAt:    s.TakenAt,
+	}
+
+	table.Counts = make([][]int, len(table.RowKeys))
+	for i := range table.Counts {
+		table.Counts[i] = make([]int, len(table.ColumnKeys))
+	}
+	table.RowTotals = make([]int, len(table.RowKeys))
+	table.ColumnTotals = make([]int, len(table.ColumnKeys))
+
+	for _, cell := range s.Cells {
+		row, _ := slices.BinarySearch(table.RowKeys, cell.Key(rows))
+		column, _ := slices.BinarySearch(table.ColumnKeys, cell.Key(columns))
+		table.Counts[row][column] += cell.Count
+		table.RowTotals[row] += cell.Count
+		table.ColumnTotals[column] += cell.Count
+		table.Total += cell.Count
+	}
+
+	return table
+} // fin CrossTab
+
+// Cell retorna el conteo de una fila y columna, cero si alguna no existe
+func (t *CrossTab) Cell(rowKey, columnKey string) int {
+	row, rowFound := slices.BinarySearch(t.RowKeys, rowKey)
+	column, columnFound := slices.BinarySearch(t.ColumnKeys, columnKey)
+	if !rowFound || !columnFound {
+		return 0
+	}
+	return t.Counts[row][column]
+}
+
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,168 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// DocumentType es el tipo de documento de identidad del usuario según el catálogo colombiano
+type DocumentType string
+
+const (
+	DocumentTypeCC        DocumentType = "CC"  // Cédula de ciudadanía
+	DocumentTypeTI        DocumentType = "TI"  // Tarjeta de identidad, solo menores de edad
+	DocumentTypeCE        DocumentType = "CE"  // Cédula de extranjería
+	DocumentTypePPT       DocumentType = "PPT" // Permiso por protección temporal
+	DocumentTypePEP       DocumentType = "PEP" // Permiso especial de permanencia
+	DocumentTypePasaporte DocumentType = "PA"  // Pasaporte
+	DocumentTypeNIT       DocumentType = "NIT" // Número de identificación tributaria
+)
+
+// AdultAge es la mayoría de edad en Colombia
+const AdultAge = 18
+
+// documentRule son las reglas del número y la edad de un tipo de documento
+type documentRule struct {
+	name    string
+	pattern *regexp.Regexp
+	format  *DomainError // Error cuando el número no cumple pattern; ValidateDocument agrega el tipo
+	minors  bool         // Lo pueden tener los menores de edad
+	adults  bool         // Lo pueden tener los mayores de edad
+}
+
+// documentTypes es el catálogo en el orden en que se presenta
+var documentTypes = []DocumentType{
+	DocumentTypeCC, DocumentTypeTI, DocumentTypeCE, DocumentTypePPT, DocumentTypePEP, DocumentTypePasaporte, DocumentTypeNIT,
+}
+
+var documentRules = map[DocumentType]documentRule{
+	DocumentTypeCC:        {"Cédula de ciudadanía", regexp.MustCompile(`^[0-9]{6,10}$`), documentFormat(CodeDocumentDigits, 6, 10), false, true},
+	DocumentTypeTI:        {"Tarjeta de identidad", regexp.MustCompile(`^[0-9]{10,11}$`), documentFormat(CodeDocumentDigits, 10, 11), true, false},
+	DocumentTypeCE:        {"Cédula de extranjería", regexp.MustCompile(`^[0-9]{6,10}$`), documentFormat(CodeDocumentDigits, 6, 10), true, true},
+	DocumentTypePPT:       {"Permiso por protección temporal", regexp.MustCompile(`^[0-9]{6,10}$`), documentFormat(CodeDocumentDigits, 6, 10), true, true},
+	DocumentTypePEP:       {"Permiso especial de permanencia", regexp.MustCompile(`^[0-9]{15}$`), apperrors.Validation(CodeDocumentExactDigits, "document_number").With("length", 15), true, true},
+	DocumentTypePasaporte: {"Pasaporte", regexp.MustCompile(`^[A-Z0-9]{5,20}$`), documentFormat(CodeDocumentAlphanumeric, 5, 20), true, true},
+	DocumentTypeNIT:       {"NIT", regexp.MustCompile(`^[0-9]{6,15}-[0-9]$`), apperrors.Validation(CodeDocumentNITFormat, "document_number").With("example", "899999034-1"), true, true},
+}
+
+// documentFormat crea el error de un número de documento con longitud entre min y max
+func documentFormat(code apperrors.Code, minLen, maxLen int) *DomainError {
+	return apperrors.Validation(code, "document_number").With("min", minLen).With("max", maxLen)
+}
+
+// DocumentTypes retorna el catálogo de tipos de documento
+func DocumentTypes() []DocumentType {
+	return append([]DocumentType(nil), documentTypes...)
+}
+
+// ParseDocumentType convierte el tipo recibido en un DocumentType del catálogo
+// Acepta el código en cualquier combinación de mayúsculas y "pasaporte" como PA
+func ParseDocumentType(raw string) (DocumentType, error) {
+	code := strings.ToUpper(strings.TrimSpace(raw))
+	if code == "PASAPORTE" {
+		code = string(DocumentTypePasaporte)
+	}
+
+	docType := DocumentType(code)
+	if err := validateDocumentType(docType); err != nil {
+		return "", err
+	}
+	return docType, nil
+}
+
+// IsValid indica si el tipo pertenece al catálogo
+func (t DocumentType) IsValid() bool {
+	_, ok := documentRules[t]
+	return ok
+}
+
+// Name retorna el nombre del tipo de documento, vacío si no pertenece al catálogo
+func (t DocumentType) Name() string {
+	return documentRules[t].name
+}
+
+// NormalizeDocumentNumber quita espacios y puntos de miles ("1.012.345.678") y pasa las letras a mayúsculas
+func NormalizeDocumentNumber(raw string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", ".", "").Replace(strings.TrimSpace(raw)))
+}
+
+// ValidateDocument valida el número de documento según las reglas de su tipo
+// El número debe estar normalizado con NormalizeDocumentNumber
+func ValidateDocument(docType DocumentType, number string) error {
+	if err := validateDocumentType(docType); err != nil {
+		return err
+	}
+
+	rule := documentRules[docType]
+	if !rule.pattern.MatchString(number) {
+		return rule.format.With("type", docType)
+	}
+
+	if docType == DocumentTypeNIT && !validNITCheckDigit(number) {
+		return apperrors.Validation(CodeDocumentNITCheckDigit, "document_number")
+	}
+
+	return nil
+} // fin ValidateDocument
+
+// validateDocumentType valida que el tipo pertenezca al catálogo
+func validateDocumentType(docType DocumentType) error {
+	if !docType.IsValid() {
+		codes := make([]string, len(documentTypes))
+		for i, t := range documentTypes {
+			codes[i] = string(t)
+		}
+		return apperrors.Validation(apperrors.CodeInvalidOption, "document_type").With("options", codes)
+	}
+	return nil
+}
+
+// validateDocumentAge verifica que la edad corresponda al tipo de documento en la fecha dada
+// Sin fecha de nacimiento no hay nada que verificar
+func validateDocumentAge(docType DocumentType, birthDate *time.Time, now time.Time) error {
+	if birthDate == nil {
+		return nil
+	}
+
+	rule := documentRules[docType]
+	adult := AgeAt(*birthDate, now) >= AdultAge
+	if adult && !rule.adults {
+		return apperrors.Validation(CodeDocumentMinorsOnly, "document_type").With("type", docType)
+	}
+	if !adult && !rule.minors {
+		return apperrors.Validation(CodeDocumentAdultsOnly, "document_type").With("type", docType)
+	}
+
+	return nil
+} // fin validateDocumentAge
+
+// AgeAt retorna los años cumplidos en la fecha dada
+func AgeAt(birthDate, now time.Time) int {
+	age := now.Year() - birthDate.Year()
+	if now.Month() < birthDate.Month() || (now.Month() == birthDate.Month() && now.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+// nitWeights son los pesos de la DIAN para el dígito de verificación, del último dígito al primero
+var nitWeights = []int{3, 7, 13, 17, 19, 23, 29, 37, 41, 43, 47, 53, 59, 67, 71}
+
+// validNITCheckDigit verifica el dígito de verificación de un NIT con formato "número-dígito"
+func validNITCheckDigit(nit string) bool {
+	number, check, _ := strings.Cut(nit, "-")
+
+	sum := 0
+	for i := range len(number) {
+		sum += int(number[len(number)-1-i]-'0') * nitWeights[i]
+	}
+
+	digit := sum % 11
+	if digit > 1 {
+		digit = 11 - digit
+	}
+	return check == string(rune('0'+digit))
+}
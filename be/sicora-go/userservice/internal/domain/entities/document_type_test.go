@@ -0,0 +1,180 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// errorCode retorna el código del error de dominio, vacío si err es nil o no es un DomainError
+func errorCode(err error) apperrors.Code {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return ""
+}
+
+func TestValidateDocument(t *testing.T) {
+	cases := []struct {
+		docType DocumentType
+		number  string
+		want    apperrors.Code // Vacío si el número es válido
+	}{
+		{DocumentTypeCC, "123456", ""},
+		{DocumentTypeCC, "1012345678", ""},
+		{DocumentTypeCC, "12345", CodeDocumentDigits},
+		{DocumentTypeCC, "10123456789", CodeDocumentDigits},
+		{DocumentTypeCC, "10123A5678", CodeDocumentDigits},
+
+		{DocumentTypeTI, "1012345678", ""},
+		{DocumentTypeTI, "10123456789", ""},
+		{DocumentTypeTI, "123456789", CodeDocumentDigits},
+		{DocumentTypeTI, "101234567890", CodeDocumentDigits},
+
+		{DocumentTypeCE, "123456", ""},
+		{DocumentTypeCE, "1234567890", ""},
+		{DocumentTypeCE, "12345", CodeDocumentDigits},
+		{DocumentTypeCE, "E123456", CodeDocumentDigits},
+
+		{DocumentTypePPT, "1234567", ""},
+		{DocumentTypePPT, "12345678901", CodeDocumentDigits},
+
+		{DocumentTypePEP, "123456789012345", ""},
+		{DocumentTypePEP, "12345678901234", CodeDocumentExactDigits},
+		{DocumentTypePEP, "1234567890123456", CodeDocumentExactDigits},
+
+		{DocumentTypePasaporte, "AB123", ""},
+		{DocumentTypePasaporte, strings.Repeat("A1", 10), ""},
+		{DocumentTypePasaporte, "AB12", CodeDocumentAlphanumeric},
+		{DocumentTypePasaporte, strings.Repeat("A1", 10) + "B", CodeDocumentAlphanumeric},
+		{DocumentTypePasaporte, "ab12345", CodeDocumentAlphanumeric}, // Sin normalizar
+		{DocumentTypePasaporte, "AB-12345", CodeDocumentAlphanumeric},
+
+		{DocumentTypeNIT, "899999034-1", ""},
+		{DocumentTypeNIT, "800197268-4", ""},
+		{DocumentTypeNIT, "123456-3", ""},
+		{DocumentTypeNIT, "860007336-1", ""},
+		{DocumentTypeNIT, "900123456-8", ""},
+		{DocumentTypeNIT, "899999034-2", CodeDocumentNITCheckDigit},
+		{DocumentTypeNIT, "800197268-0", CodeDocumentNITCheckDigit},
+		{DocumentTypeNIT, "8999990341", CodeDocumentNITFormat},
+		{DocumentTypeNIT, "899999034-12", CodeDocumentNITFormat},
+		{DocumentTypeNIT, "12345-1", CodeDocumentNITFormat},
+
+		{DocumentType("RC"), "1012345678", apperrors.CodeInvalidOption},
+		{DocumentType(""), "1012345678", apperrors.CodeInvalidOption},
+	}
+
+	for _, tc := range cases {
+		err := ValidateDocument(tc.docType, tc.number)
+		if got := errorCode(err); got != tc.want || (tc.want == "") != (err == nil) {
+			t.Errorf("ValidateDocument(%s, %q) = %v; se esperaba el código %q", tc.docType, tc.number, err, tc.want)
+		}
+	}
+} // fin TestValidateDocument
+
+func TestValidNITCheckDigit(t *testing.T) {
+	cases := []struct {
+		nit  string
+		want bool
+	}{
+		{"899999034-1", true},
+		{"800197268-4", true},
+		{"860007336-1", true}, // Resto 1: el dígito es 1
+		{"123456-3", true},
+		{"899999034-0", false},
+		{"800197268-7", false},
+		{"123456-8", false},
+	}
+
+	for _, tc := range cases {
+		if got := validNITCheckDigit(tc.nit); got != tc.want {
+			t.Errorf("validNITCheckDigit(%q) = %v, se esperaba %v", tc.nit, got, tc.want)
+		}
+	}
+}
+
+func TestParseDocumentType(t *testing.T) {
+	for raw, want := range map[string]DocumentType{
+		"cc": DocumentTypeCC, " TI ": DocumentTypeTI, "Pasaporte": DocumentTypePasaporte, "pa": DocumentTypePasaporte, "nit": DocumentTypeNIT,
+	} {
+		if got, err := ParseDocumentType(raw); err != nil || got != want {
+			t.Errorf("ParseDocumentType(%q) = %q, %v; se esperaba %q", raw, got, err, want)
+		}
+	}
+	if _, err := ParseDocumentType("RC"); errorCode(err) != apperrors.CodeInvalidOption {
+		t.Errorf("ParseDocumentType(RC): se esperaba %q, se obtuvo %v", apperrors.CodeInvalidOption, err)
+	}
+}
+
+func TestChangeDocument(t *testing.T) {
+	now := time.Now()
+	adult := now.AddDate(-AdultAge, 0, -1)
+	minor := now.AddDate(-AdultAge, 0, 1)
+
+	cases := []struct {
+		name       string
+		docType    DocumentType
+		number     string
+		birthDate  *time.Time
+		newType    DocumentType
+		newNumber  string
+		want       apperrors.Code
+		wantNumber string
+	}{
+		{"TI a CC con el mismo número", DocumentTypeTI, "1012345678", &adult, DocumentTypeCC, "1.012.345.678", "", "1012345678"},
+		{"TI a CC antes de los 18", DocumentTypeTI, "1012345678", &minor, DocumentTypeCC, "1012345678", CodeDocumentAdultsOnly, ""},
+		{"TI a CC sin fecha de nacimiento", DocumentTypeTI, "1012345678", nil, DocumentTypeCC, "1012345678", CodeDocumentBirthDateRequired, ""},
+		{"TI a CE", DocumentTypeTI, "1012345678", &adult, DocumentTypeCE, "1012345678", CodeDocumentTIToCCOnly, ""},
+		{"CC a TI de un adulto", DocumentTypeCC, "1012345678", &adult, DocumentTypeTI, "1012345678", CodeDocumentMinorsOnly, ""},
+		{"CC a pasaporte", DocumentTypeCC, "1012345678", &adult, DocumentTypePasaporte, " ab 12345 ", "", "AB12345"},
+		{"CC sin cambios", DocumentTypeCC, "1012345678", &adult, DocumentTypeCC, "1.012.345.678", CodeDocumentUnchanged, ""},
+		{"número inválido", DocumentTypeCC, "1012345678", &adult, DocumentTypePEP, "12345", CodeDocumentExactDigits, ""},
+	}
+
+	for _, tc := range cases {
+		user := &User{DocumentType: tc.docType, DocumentNumber: tc.number, BirthDate: tc.birthDate}
+		err := user.ChangeDocument(tc.newType, tc.newNumber)
+		if got := errorCode(err); got != tc.want || (tc.want == "") != (err == nil) {
+			t.Errorf("%s: ChangeDocument = %v; se esperaba el código %q", tc.name, err, tc.want)
+			continue
+		}
+
+		wantType, wantNumber := tc.newType, tc.wantNumber
+		if err != nil {
+			wantType, wantNumber = tc.docType, tc.number
+		}
+		if user.DocumentType != wantType || user.DocumentNumber != wantNumber {
+			t.Errorf("%s: documento = %s %q, se esperaba %s %q", tc.name, user.DocumentType, user.DocumentNumber, wantType, wantNumber)
+		}
+	}
+} // fin TestChangeDocument
+
+func TestNeedsCedula(t *testing.T) {
+	now := time.Now()
+	adult := now.AddDate(-AdultAge, 0, 0)
+	minor := now.AddDate(-AdultAge, 0, 1)
+
+	cases := []struct {
+		name      string
+		docType   DocumentType
+		birthDate *time.Time
+		want      bool
+	}{
+		{"TI que cumplió 18 hoy", DocumentTypeTI, &adult, true},
+		{"TI de un menor", DocumentTypeTI, &minor, false},
+		{"TI sin fecha de nacimiento", DocumentTypeTI, nil, false},
+		{"CC de un adulto", DocumentTypeCC, &adult, false},
+	}
+
+	for _, tc := range cases {
+		user := &User{DocumentType: tc.docType, BirthDate: tc.birthDate}
+		if got := user.NeedsCedula(); got != tc.want {
+			t.Errorf("%s: NeedsCedula = %v, se esperaba %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"strings"
+)
+
+// Email es un email en forma canónica: sin espacios alrededor y en minúsculas
+// User y todas las búsquedas por email de los repositorios usan esta forma, así
+// Juan@SENA.edu.co y juan@sena.edu.co son el mismo usuario
+type Email string
+
+// NewEmail normaliza y valida el email, retorna *DomainError si no tiene un formato válido
+func NewEmail(raw string) (Email, error) {
+	if err := validateEmail(raw); err != nil {
+		return "", err
+	}
+	return CanonicalEmail(raw), nil
+}
+
+// CanonicalEmail retorna la forma canónica del email sin validarlo; sirve para buscar por email
+func CanonicalEmail(raw string) Email {
+	return Email(strings.ToLower(strings.TrimSpace(raw)))
+}
+
+// Canonical retorna la forma canónica de un Email construido por conversión directa (Email("Juan@..."))
+func (e Email) Canonical() Email {
+	return CanonicalEmail(string(e))
+}
+
+// String retorna el email como texto
+func (e Email) String() string {
+	return string(e)
+}
+
+// Value guarda y compara el email siempre en forma canónica
+func (e Email) Value() (driver.Value, error) {
+	return string(e.Canonical()), nil
+}
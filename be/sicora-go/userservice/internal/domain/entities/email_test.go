@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+func TestCanonicalEmail(t *testing.T) {
+	cases := map[string]Email{
+		"juan@sena.edu.co":          "juan@sena.edu.co",
+		"Juan@SENA.edu.co":          "juan@sena.edu.co",
+		"  ANA.GOMEZ@misena.edu.co": "ana.gomez@misena.edu.co",
+		"\tluis+test@Sena.Edu.Co\n": "luis+test@sena.edu.co",
+		"":                          "",
+		"No Es Un Email ":           "no es un email", // Sin validar
+	}
+
+	for raw, want := range cases {
+		if got := CanonicalEmail(raw); got != want {
+			t.Errorf("CanonicalEmail(%q) = %q, se esperaba %q", raw, got, want)
+		}
+		if got := Email(raw).Canonical(); got != want {
+			t.Errorf("Email(%q).Canonical() = %q, se esperaba %q", raw, got, want)
+		}
+	}
+}
+
+func TestNewEmail(t *testing.T) {
+	if email, err := NewEmail("  Juan.Rojas@SENA.edu.co "); err != nil || email != "juan.rojas@sena.edu.co" {
+		t.Errorf("NewEmail = %q, %v", email, err)
+	}
+
+	invalid := map[string]apperrors.Code{
+		"":                                   apperrors.CodeRequired,
+		"   ":                                apperrors.CodeRequired,
+		"juan":                               apperrors.CodeInvalidFormat,
+		"juan@sena":                          apperrors.CodeInvalidFormat,
+		"@sena.edu.co":                       apperrors.CodeInvalidFormat,
+		"juan@@sena.edu.co":                  apperrors.CodeInvalidFormat,
+		"juan rojas@sena.edu.co":             apperrors.CodeInvalidFormat,
+		"josé@sena.edu.co":                   apperrors.CodeInvalidFormat,
+		"juan@sena.c":                        apperrors.CodeInvalidFormat,
+		strings.Repeat("a", 93) + "@sena.co": apperrors.CodeTooLong,
+	}
+	for raw, want := range invalid {
+		if email, err := NewEmail(raw); errorCode(err) != want || email != "" {
+			t.Errorf("NewEmail(%q) = %q, %v; se esperaba el código %q", raw, email, err, want)
+		}
+	}
+} // fin TestNewEmail
+
+// TestEmailMatchesLowerIndex verifica que la forma canónica coincida con lower(email), que usa el índice único:
+// dos emails que solo difieren en mayúsculas se guardan igual y chocan en el índice
+func TestEmailMatchesLowerIndex(t *testing.T) {
+	variants := []string{"Ana.Gomez@SENA.edu.co", "ana.gomez@sena.edu.co", " ANA.GOMEZ@sena.EDU.CO"}
+
+	var stored []string
+	for _, raw := range variants {
+		email, err := NewEmail(raw)
+		if err != nil {
+			t.Fatalf("NewEmail(%q): %v", raw, err)
+		}
+
+		value, err := Email(raw).Value()
+		if err != nil {
+			t.Fatalf("Value(%q): %v", raw, err)
+		}
+		if value != string(email) || value != strings.ToLower(value.(string)) {
+			t.Errorf("Value(%q) = %q, se esperaba %q en minúsculas", raw, value, email)
+		}
+		stored = append(stored, value.(string))
+	}
+
+	for _, value := range stored[1:] {
+		if value != stored[0] {
+			t.Errorf("las variantes se guardan distinto: %q", stored)
+		}
+	}
+} // fin TestEmailMatchesLowerIndex
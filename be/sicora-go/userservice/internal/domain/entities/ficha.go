@@ -0,0 +1,159 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// Jornada es el horario en el que se imparte la formación de una ficha
+type Jornada string
+
+const (
+	JornadaDiurna      Jornada = "diurna"
+	JornadaNocturna    Jornada = "nocturna"
+	JornadaMixta       Jornada = "mixta"
+	JornadaMadrugada   Jornada = "madrugada"
+	JornadaFinDeSemana Jornada = "fin_de_semana"
+)
+
+// FichaStatus es la etapa del ciclo de vida de una ficha
+type FichaStatus string
+
+// Ciclo de vida: planned -> in_progress -> finished; planned e in_progress pueden pasar a cancelled
+const (
+	FichaStatusPlanned    FichaStatus = "planned"
+	FichaStatusInProgress FichaStatus = "in_progress"
+	FichaStatusFinished   FichaStatus = "finished"
+	FichaStatusCancelled  FichaStatus = "cancelled"
+)
+
+// MaxFichaCapacity es el máximo de aprendices que admite una ficha
+const MaxFichaCapacity = 100
+
+// Ficha representa un grupo de aprendices que cursa un programa de formación
+// Es la fuente única de las fichas: User.FichaID guarda su número
+type Ficha struct {
+	ID               uuid.UUID   `gorm:"column:id_ficha;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Number           string      `gorm:"column:number_ficha;type:varchar(20);not null;uniqueIndex:uq_fichas_number" json:"number"` // Número de 7 dígitos que usan los demás servicios
+	ProgramID        uuid.UUID   `gorm:"column:program_id_ficha;type:uuid;not null;index" json:"program_id"`
+	SedeID           *uuid.UUID  `gorm:"column:sede_id_ficha;type:uuid;index" json:"sede_id,omitempty"`
+	Jornada          Jornada     `gorm:"column:jornada_ficha;type:varchar(20);not null" json:"jornada"`
+	StartDate        time.Time   `gorm:"column:start_date_ficha;type:date;not null" json:"start_date"` // Fecha sin hora, en UTC
+	EndDate          time.Time   `gorm:"column:end_date_ficha;type:date;not null" json:"end_date"`     // Fecha sin hora, en UTC
+	LeadInstructorID *uuid.UUID  `gorm:"column:lead_instructor_id_ficha;type:uuid;index" json:"lead_instructor_id,omitempty"`
+	Status           FichaStatus `gorm:"column:status_ficha;type:varchar(20);not null" json:"status"`
+	Capacity         int         `gorm:"column:capacity_ficha;not null" json:"capacity"` // Máximo de aprendices
+	CreatedAt        time.Time   `gorm:"column:created_at_ficha;type:timestamptz;not null;default:now()" json:"created_at"`
+	UpdatedAt        time.Time   `gorm:"column:updated_at_ficha;type:timestamptz;not null;default:now()" json:"updated_at"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Ficha) TableName() string {
+	return "userservice.fichas"
+}
+
+// NewFicha crea una ficha programada con validaciones de dominio
+func NewFicha(number string, programID uuid.UUID, jornada Jornada, startDate, endDate time.Time, capacity int) (*Ficha, error) {
+	now := time.Now()
+	ficha := &Ficha{
+		ID:        uuid.New(),
+		Number:    number,
+		ProgramID: programID,
+		Jornada:   jornada,
+		StartDate: CivilDate(startDate),
+		EndDate:   CivilDate(endDate),
+		Status:    FichaStatusPlanned,
+		Capacity:  capacity,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := ficha.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ficha, nil
+} // fin NewFicha
+
+// Validate verifica los datos de la ficha según reglas de dominio
+func (f *Ficha) Validate() error {
+	if err := ValidateFichaID(f.Number); err != nil {
+		return err
+	}
+
+	if f.ProgramID == uuid.Nil {
+		return apperrors.Validation(apperrors.CodeRequired, "program_id")
+	}
+
+	switch f.Jornada {
+	case JornadaDiurna, JornadaNocturna, JornadaMixta, JornadaMadrugada, JornadaFinDeSemana:
+	default:
+		return apperrors.Validation(apperrors.CodeInvalidOption, "jornada").
+			With("options", []string{string(JornadaDiurna), string(JornadaNocturna), string(JornadaMixta), string(JornadaMadrugada), string(JornadaFinDeSemana)})
+	}
+
+	if f.StartDate.IsZero() {
+		return apperrors.Validation(apperrors.CodeRequired, "start_date")
+	}
+	if f.EndDate.IsZero() {
+		return apperrors.Validation(apperrors.CodeRequired, "end_date")
+	}
+	if !f.EndDate.After(f.StartDate) {
+		return apperrors.Validation(CodeEndNotAfterStart, "end_date")
+	}
+
+	switch f.Status {
+	case FichaStatusPlanned, FichaStatusInProgress, FichaStatusFinished, FichaStatusCancelled:
+	default:
+		return apperrors.Validation(apperrors.CodeInvalidOption, "status").
+			With("options", []string{string(FichaStatusPlanned), string(FichaStatusInProgress), string(FichaStatusFinished), string(FichaStatusCancelled)})
+	}
+
+	if f.Capacity < 1 || f.Capacity > MaxFichaCapacity {
+		return apperrors.Validation(apperrors.CodeOutOfRange, "capacity").With("min", 1).With("max", MaxFichaCapacity)
+	}
+
+	return nil
+} // fin Validate
+
+// Start inicia la formación de una ficha programada
+func (f *Ficha) Start() error {
+	return f.transition(FichaStatusInProgress, FichaStatusPlanned)
+}
+
+// Finish cierra una ficha en formación
+func (f *Ficha) Finish() error {
+	return f.transition(FichaStatusFinished, FichaStatusInProgress)
+}
+
+// Cancel cancela una ficha que aún no ha terminado
+func (f *Ficha) Cancel() error {
+	return f.transition(FichaStatusCancelled, FichaStatusPlanned, FichaStatusInProgress)
+}
+
+// transition cambia el estado si el actual es uno de los permitidos
+func (f *Ficha) transition(to FichaStatus, from ...FichaStatus) error {
+	for _, allowed := range from {
+		if f.Status == allowed {
+			f.Status = to
+			f.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return apperrors.New(apperrors.KindConflict, CodeFichaTransition).With("from", f.Status).With("to", to)
+}
+
+// AcceptsAprendices indica si la ficha admite nuevos aprendices: solo programada o en formación
+func (f *Ficha) AcceptsAprendices() bool {
+	return f.Status == FichaStatusPlanned || f.Status == FichaStatusInProgress
+}
+
+// CivilDate retorna la fecha sin hora, en UTC, tal como se guarda en las columnas de tipo date
+func CivilDate(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// FichaMovement es una entrada inmutable del historial de fichas de un aprendiz
+// Un ingreso (FromFichaID nil) lo registra la asignación inicial; un traslado mueve al aprendiz entre fichas
+// El aprendiz pertenece en una fecha a la ficha destino de su último movimiento efectivo hasta esa fecha
+type FichaMovement struct {
+	ID            uuid.UUID  `gorm:"column:id_ficha_movement;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID        uuid.UUID  `gorm:"column:user_id_ficha_movement;type:uuid;not null;index" json:"user_id"`
+	FromFichaID   *string    `gorm:"column:from_ficha_id_ficha_movement;type:varchar(20)" json:"from_ficha_id,omitempty"` // nil en el ingreso
+	ToFichaID     string     `gorm:"column:to_ficha_id_ficha_movement;type:varchar(20);not null;index" json:"to_ficha_id"`
+	Reason        string     `gorm:"column:reason_ficha_movement;type:varchar(500);not null" json:"reason"`
+	ApprovedBy    *uuid.UUID `gorm:"column:approved_by_ficha_movement;type:uuid" json:"approved_by,omitempty"`      // Coordinador o administrador; nil en el ingreso
+	EffectiveDate time.Time  `gorm:"column:effective_date_ficha_movement;type:date;not null" json:"effective_date"` // Fecha sin hora, en UTC
+	CreatedAt     time.Time  `gorm:"column:created_at_ficha_movement;type:timestamptz;not null;default:now()" json:"created_at"`
+}
+
+// TableName especifica el nombre de la tabla
+func (FichaMovement) TableName() string {
+	return "userservice.ficha_movements"
+}
+
+// EnrollmentReason es el motivo de los ingresos que registra la asignación inicial de ficha
+const EnrollmentReason = "Asignación inicial de ficha"
+
+// NewFichaEnrollment crea el ingreso del aprendiz a su primera ficha
+func NewFichaEnrollment(userID uuid.UUID, fichaID string, effectiveDate time.Time) *FichaMovement {
+	return &FichaMovement{
+		ID:            uuid.New(),
+		UserID:        userID,
+		ToFichaID:     fichaID,
+		Reason:        EnrollmentReason,
+		EffectiveDate: CivilDate(effectiveDate),
+		CreatedAt:     time.Now(),
+	}
+}
+
+// NewFichaTransfer crea el traslado del aprendiz a otra ficha con validaciones de dominio
+// La ficha de origen la completa el repositorio con la ficha actual del aprendiz
+func NewFichaTransfer(userID uuid.UUID, toFichaID, reason string, approvedBy uuid.UUID, effectiveDate time.Time) (*FichaMovement, error) {
+	transfer := &FichaMovement{
+		ID:            uuid.New(),
+		UserID:        userID,
+		ToFichaID:     toFichaID,
+		Reason:        strings.TrimSpace(reason),
+		ApprovedBy:    &approvedBy,
+		EffectiveDate: CivilDate(effectiveDate),
+		CreatedAt:     time.Now(),
+	}
+
+	if err := transfer.ValidateTransfer(); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+} // fin NewFichaTransfer
+
+// IsTransfer indica si el movimiento es un traslado entre fichas y no un ingreso
+func (m *FichaMovement) IsTransfer() bool {
+	return m.FromFichaID != nil
+}
+
+// ValidateTransfer verifica los datos del traslado según reglas de dominio
+func (m *FichaMovement) ValidateTransfer() error {
+	if err := ValidateFichaID(m.ToFichaID); err != nil {
+		return err
+	}
+
+	if m.FromFichaID != nil && *m.FromFichaID == m.ToFichaID {
+		return apperrors.Validation(CodeFichaSameDestination, "to_ficha_id")
+	}
+
+	reason := strings.TrimSpace(m.Reason)
+	if utf8.RuneCountInString(reason) < 3 {
+		return apperrors.Validation(apperrors.CodeTooShort, "reason").With("min", 3)
+	}
+	if utf8.RuneCountInString(reason) > 500 {
+		return apperrors.Validation(apperrors.CodeTooLong, "reason").With("max", 500)
+	}
+
+	if m.ApprovedBy == nil || *m.ApprovedBy == uuid.Nil {
+		return apperrors.Validation(apperrors.CodeRequired, "approved_by")
+	}
+
+	if m.EffectiveDate.IsZero() {
+		return apperrors.Validation(apperrors.CodeRequired, "effective_date")
+	}
+
+	return nil
+} // fin ValidateTransfer
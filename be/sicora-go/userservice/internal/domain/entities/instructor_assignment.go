@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// AssignmentRole es el papel del instructor en la ficha
+type AssignmentRole string
+
+const (
+	AssignmentRoleTecnico     AssignmentRole = "tecnico"     // Orienta competencias técnicas del programa
+	AssignmentRoleTransversal AssignmentRole = "transversal" // Orienta competencias transversales (inglés, ética, emprendimiento...)
+	AssignmentRoleSeguimiento AssignmentRole = "seguimiento" // Acompaña la etapa productiva
+)
+
+// InstructorAssignment asigna un instructor a una ficha para una competencia durante un rango de fechas
+// Un instructor puede tener varias asignaciones en la misma ficha, una por competencia
+type InstructorAssignment struct {
+	ID           uuid.UUID      `gorm:"column:id_assignment;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	InstructorID uuid.UUID      `gorm:"column:instructor_id_assignment;type:uuid;not null;index" json:"instructor_id"`
+	FichaID      string         `gorm:"column:ficha_id_assignment;type:varchar(20);not null;index" json:"ficha_id"` // Número de la ficha, como User.FichaID
+	Role         AssignmentRole `gorm:"column:role_assignment;type:varchar(20);not null" json:"role"`
+	Competency   string         `gorm:"column:competency_assignment;type:varchar(100);not null" json:"competency,omitempty"` // Vacía si cubre toda la ficha
+	StartDate    time.Time      `gorm:"column:start_date_assignment;type:date;not null" json:"start_date"`
+	EndDate      *time.Time     `gorm:"column:end_date_assignment;type:date" json:"end_date,omitempty"` // Inclusiva; nil mientras siga vigente
+	CreatedAt    time.Time      `gorm:"column:created_at_assignment;type:timestamptz;not null;default:now()" json:"created_at"`
+}
+
+// TableName especifica el nombre de la tabla
+func (InstructorAssignment) TableName() string {
+	return "userservice.instructor_assignments"
+}
+
+// NewInstructorAssignment crea una asignación vigente desde startDate con validaciones de dominio
+func NewInstructorAssignment(instructorID uuid.UUID, fichaID string, role AssignmentRole, competency string, startDate time.Time) (*InstructorAssignment, error) {
+	assignment := &InstructorAssignment{
+		ID:           uuid.New(),
+		InstructorID: instructorID,
+		FichaID:      fichaID,
+		Role:         role,
+		Competency:   strings.TrimSpace(competency),
+		StartDate:    CivilDate(startDate),
+		CreatedAt:    time.Now(),
+	}
+
+	if err := assignment.Validate(); err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+// Validate verifica los datos de la asignación según reglas de dominio
+func (a *InstructorAssignment) Validate() error {
+	if a.InstructorID == uuid.Nil {
+		return apperrors.Validation(apperrors.CodeRequired, "instructor_id")
+	}
+
+	if err := ValidateFichaID(a.FichaID); err != nil {
+		return err
+	}
+
+	switch a.Role {
+	case AssignmentRoleTecnico, AssignmentRoleTransversal, AssignmentRoleSeguimiento:
+	default:
+		return apperrors.Validation(apperrors.CodeInvalidOption, "role").
+			With("options", []string{string(AssignmentRoleTecnico), string(AssignmentRoleTransversal), string(AssignmentRoleSeguimiento)})
+	}
+
+	if len(a.Competency) > 100 {
+		return apperrors.Validation(apperrors.CodeTooLong, "competency").With("max", 100)
+	}
+
+	if a.StartDate.IsZero() {
+		return apperrors.Validation(apperrors.CodeRequired, "start_date")
+	}
+	if a.EndDate != nil && a.EndDate.Before(a.StartDate) {
+		return apperrors.Validation(CodeEndBeforeStart, "end_date")
+	}
+
+	return nil
+} // fin Validate
+
+// EndOn cierra la asignación; endDate es el último día en que el instructor está asignado
+func (a *InstructorAssignment) EndOn(endDate time.Time) error {
+	end := CivilDate(endDate)
+	if end.Before(a.StartDate) {
+		return apperrors.Validation(CodeEndBeforeStart, "end_date")
+	}
+	a.EndDate = &end
+	return nil
+}
+
+// ActiveOn indica si la asignación está vigente en la fecha dada
+func (a *InstructorAssignment) ActiveOn(date time.Time) bool {
+	day := CivilDate(date)
+	return !day.Before(a.StartDate) && (a.EndDate == nil || !day.After(*a.EndDate))
+}
+
+// Overlaps indica si ambas asignaciones son del mismo instructor, ficha y competencia con fechas que se cruzan
+func (a *InstructorAssignment) Overlaps(other *InstructorAssignment) bool {
+	if a.InstructorID != other.InstructorID || a.FichaID != other.FichaID || a.Competency != other.Competency {
+		return false
+	}
+	startsBeforeOtherEnds := other.EndDate == nil || !a.StartDate.After(*other.EndDate)
+	otherStartsBeforeEnd := a.EndDate == nil || !other.StartDate.After(*a.EndDate)
+	return startsBeforeOtherEnds && otherStartsBeforeEnd
+}
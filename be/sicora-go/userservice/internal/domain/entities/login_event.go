@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginEvent representa un inicio de sesión exitoso en el historial del usuario
+// User.LastLogin conserva solo el más reciente; el historial permite medir la actividad en el tiempo
+type LoginEvent struct {
+	ID         uuid.UUID `gorm:"column:id_login_event;type:uuid;primaryKey;default:gen_random_uuid()" json:"id_login_event"`
+	UserID     uuid.UUID `gorm:"column:user_id_login_event;type:uuid;not null;index" json:"user_id_login_event"`
+	OccurredAt time.Time `gorm:"column:occurred_at_login_event;type:timestamptz;not null" json:"occurred_at_login_event"`
+	IPAddress  *string   `gorm:"column:ip_address_login_event;type:varchar(45)" json:"ip_address_login_event,omitempty"` // IPv4 o IPv6
+	UserAgent  *string   `gorm:"column:user_agent_login_event;type:text" json:"user_agent_login_event,omitempty"`
+}
+
+// TableName especifica el nombre de la tabla
+func (LoginEvent) TableName() string {
+	return "userservice.login_events"
+}
+
+// NewLoginEvent crea el evento de un inicio de sesión ocurrido ahora
+func NewLoginEvent(userID uuid.UUID, ipAddress, userAgent string) *LoginEvent {
+	event := &LoginEvent{UserID: userID, OccurredAt: time.Now()}
+	if ipAddress != "" {
+		event.IPAddress = &ipAddress
+	}
+	if userAgent != "" {
+		event.UserAgent = &userAgent
+	}
+	return event
+}
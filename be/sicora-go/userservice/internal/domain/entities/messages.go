@@ -0,0 +1,176 @@
+package entities
+
+import (
+	"slices"
+
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// Códigos de los errores de dominio propios del servicio; los genéricos (required, too_short...) están en apperrors
+// Los parámetros de cada código se indican entre llaves, como en las plantillas
+const (
+	CodeNameCharacters apperrors.Code = "user.name_characters" // Caracteres distintos de letras, espacios, apóstrofos y guiones
+	CodeNameSeparators apperrors.Code = "user.name_separators" // Separadores seguidos, al inicio o al final
+
+	CodePasswordLowercase apperrors.Code = "user.password_lowercase"
+	CodePasswordUppercase apperrors.Code = "user.password_uppercase"
+	CodePasswordDigit     apperrors.Code = "user.password_digit"
+	CodePasswordSpecial   apperrors.Code = "user.password_special" // {characters}
+
+	CodeDocumentDigits            apperrors.Code = "document.digits"       // {type} {min} {max}
+	CodeDocumentExactDigits       apperrors.Code = "document.exact_digits" // {type} {length}
+	CodeDocumentAlphanumeric      apperrors.Code = "document.alphanumeric" // {type} {min} {max}
+	CodeDocumentNITFormat         apperrors.Code = "document.nit_format"   // {example}
+	CodeDocumentNITCheckDigit     apperrors.Code = "document.nit_check_digit"
+	CodeDocumentMinorsOnly        apperrors.Code = "document.minors_only" // {type}
+	CodeDocumentAdultsOnly        apperrors.Code = "document.adults_only" // {type}
+	CodeDocumentUnchanged         apperrors.Code = "document.unchanged"
+	CodeDocumentTIToCCOnly        apperrors.Code = "document.ti_to_cc_only"
+	CodeDocumentBirthDateRequired apperrors.Code = "document.birth_date_required" // Cambio de TI a CC sin fecha de nacimiento
+
+	CodeFichaNumberFormat    apperrors.Code = "ficha.number_format" // {digits}
+	CodeFichaTransition      apperrors.Code = "ficha.transition"    // {from} {to}
+	CodeFichaSameDestination apperrors.Code = "ficha.same_destination"
+	CodeFichaAprendizOnly    apperrors.Code = "ficha.aprendiz_only"
+
+	CodeCodeFormat       apperrors.Code = "org.code_format"          // {max}: código de regional, centro o programa
+	CodeEndBeforeStart   apperrors.Code = "date.end_before_start"    // La fecha de fin puede ser igual a la de inicio
+	CodeEndNotAfterStart apperrors.Code = "date.end_not_after_start" // La fecha de fin debe ser posterior a la de inicio
+)
+
+// codes son los códigos declarados arriba; Codes los expone para verificar sus plantillas en cada idioma
+var codes = []apperrors.Code{
+	CodeNameCharacters, CodeNameSeparators,
+	CodePasswordLowercase, CodePasswordUppercase, CodePasswordDigit, CodePasswordSpecial,
+	CodeDocumentDigits, CodeDocumentExactDigits, CodeDocumentAlphanumeric, CodeDocumentNITFormat, CodeDocumentNITCheckDigit,
+	CodeDocumentMinorsOnly, CodeDocumentAdultsOnly, CodeDocumentUnchanged, CodeDocumentTIToCCOnly, CodeDocumentBirthDateRequired,
+	CodeFichaNumberFormat, CodeFichaTransition, CodeFichaSameDestination, CodeFichaAprendizOnly,
+	CodeCodeFormat, CodeEndBeforeStart, CodeEndNotAfterStart,
+}
+
+// Codes retorna los códigos de error que declara el paquete
+func Codes() []apperrors.Code {
+	return slices.Clone(codes)
+}
+
+func init() {
+	apperrors.Register("es", apperrors.Catalog{
+		CodeNameCharacters:    "El campo {field} solo puede contener letras, espacios, apóstrofos y guiones",
+		CodeNameSeparators:    "El campo {field} no puede tener espacios, apóstrofos o guiones seguidos, al inicio o al final",
+		CodePasswordLowercase: "La contraseña debe contener al menos una letra minúscula",
+		CodePasswordUppercase: "La contraseña debe contener al menos una letra mayúscula",
+		CodePasswordDigit:     "La contraseña debe contener al menos un número",
+		CodePasswordSpecial:   "La contraseña debe contener al menos un carácter especial ({characters})",
+
+		CodeDocumentDigits:            "El número de documento {type} debe tener entre {min} y {max} dígitos",
+		CodeDocumentExactDigits:       "El número de documento {type} debe tener {length} dígitos",
+		CodeDocumentAlphanumeric:      "El número de documento {type} debe tener entre {min} y {max} letras o números",
+		CodeDocumentNITFormat:         "El NIT debe tener número y dígito de verificación separados por guion ({example})",
+		CodeDocumentNITCheckDigit:     "El dígito de verificación del NIT no es válido",
+		CodeDocumentMinorsOnly:        "El documento {type} es solo para menores de edad",
+		CodeDocumentAdultsOnly:        "El documento {type} es solo para mayores de edad",
+		CodeDocumentUnchanged:         "El documento nuevo es igual al actual",
+		CodeDocumentTIToCCOnly:        "La tarjeta de identidad solo se puede cambiar por cédula de ciudadanía",
+		CodeDocumentBirthDateRequired: "Se requiere la fecha de nacimiento para cambiar la tarjeta de identidad por cédula de ciudadanía",
+
+		CodeFichaNumberFormat:    "El número de ficha debe tener {digits} dígitos",
+		CodeFichaTransition:      "La ficha no puede pasar de {from} a {to}",
+		CodeFichaSameDestination: "La ficha destino del traslado debe ser distinta de la ficha de origen",
+		CodeFichaAprendizOnly:    "Solo los aprendices pueden pertenecer a una ficha",
+
+		CodeCodeFormat:       "El campo {field} debe tener hasta {max} letras, números o guiones",
+		CodeEndBeforeStart:   "La fecha de fin no puede ser anterior a la de inicio",
+		CodeEndNotAfterStart: "La fecha de fin debe ser posterior a la de inicio",
+
+		apperrors.FieldCode("first_name"):      "nombre",
+		apperrors.FieldCode("last_name"):       "apellido",
+		apperrors.FieldCode("email"):           "email",
+		apperrors.FieldCode("password"):        "contraseña",
+		apperrors.FieldCode("role"):            "rol",
+		apperrors.FieldCode("ficha_id"):        "número de ficha",
+		apperrors.FieldCode("document_type"):   "tipo de documento",
+		apperrors.FieldCode("document_number"): "número de documento",
+		apperrors.FieldCode("birth_date"):      "fecha de nacimiento",
+		apperrors.FieldCode("actor_id"):        "solicitante",
+		apperrors.FieldCode("reason"):          "motivo",
+		apperrors.FieldCode("code"):            "código",
+		apperrors.FieldCode("name"):            "nombre",
+		apperrors.FieldCode("address"):         "dirección",
+		apperrors.FieldCode("regional_id"):     "regional",
+		apperrors.FieldCode("centro_id"):       "centro de formación",
+		apperrors.FieldCode("level"):           "nivel",
+		apperrors.FieldCode("duration_months"): "duración en meses",
+		apperrors.FieldCode("program_id"):      "programa de formación",
+		apperrors.FieldCode("jornada"):         "jornada",
+		apperrors.FieldCode("start_date"):      "fecha de inicio",
+		apperrors.FieldCode("end_date"):        "fecha de fin",
+		apperrors.FieldCode("status"):          "estado",
+		apperrors.FieldCode("capacity"):        "capacidad",
+		apperrors.FieldCode("to_ficha_id"):     "ficha destino",
+		apperrors.FieldCode("approved_by"):     "aprobador",
+		apperrors.FieldCode("effective_date"):  "fecha efectiva",
+		apperrors.FieldCode("instructor_id"):   "instructor",
+		apperrors.FieldCode("competency"):      "competencia",
+		apperrors.FieldCode("user_id"):         "usuario",
+	})
+
+	apperrors.Register("en", apperrors.Catalog{
+		CodeNameCharacters:    "{field} can only contain letters, spaces, apostrophes and hyphens",
+		CodeNameSeparators:    "{field} cannot have consecutive, leading or trailing spaces, apostrophes or hyphens",
+		CodePasswordLowercase: "The password must contain at least one lowercase letter",
+		CodePasswordUppercase: "The password must contain at least one uppercase letter",
+		CodePasswordDigit:     "The password must contain at least one digit",
+		CodePasswordSpecial:   "The password must contain at least one special character ({characters})",
+
+		CodeDocumentDigits:            "The {type} document number must have between {min} and {max} digits",
+		CodeDocumentExactDigits:       "The {type} document number must have {length} digits",
+		CodeDocumentAlphanumeric:      "The {type} document number must have between {min} and {max} letters or digits",
+		CodeDocumentNITFormat:         "The NIT must have the number and the check digit separated by a hyphen ({example})",
+		CodeDocumentNITCheckDigit:     "The NIT check digit is not valid",
+		CodeDocumentMinorsOnly:        "The {type} document is only for minors",
+		CodeDocumentAdultsOnly:        "The {type} document is only for adults",
+		CodeDocumentUnchanged:         "The new document is the same as the current one",
+		CodeDocumentTIToCCOnly:        "An identity card (TI) can only be changed to a citizenship card (CC)",
+		CodeDocumentBirthDateRequired: "The birth date is required to change an identity card (TI) to a citizenship card (CC)",
+
+		CodeFichaNumberFormat:    "The ficha number must have {digits} digits",
+		CodeFichaTransition:      "The ficha cannot change from {from} to {to}",
+		CodeFichaSameDestination: "The destination ficha must be different from the origin ficha",
+		CodeFichaAprendizOnly:    "Only aprendices can belong to a ficha",
+
+		CodeCodeFormat:       "{field} must have up to {max} letters, digits or hyphens",
+		CodeEndBeforeStart:   "The end date cannot be before the start date",
+		CodeEndNotAfterStart: "The end date must be after the start date",
+
+		apperrors.FieldCode("first_name"):      "First name",
+		apperrors.FieldCode("last_name"):       "Last name",
+		apperrors.FieldCode("email"):           "Email",
+		apperrors.FieldCode("password"):        "Password",
+		apperrors.FieldCode("role"):            "Role",
+		apperrors.FieldCode("ficha_id"):        "Ficha number",
+		apperrors.FieldCode("document_type"):   "Document type",
+		apperrors.FieldCode("document_number"): "Document number",
+		apperrors.FieldCode("birth_date"):      "Birth date",
+		apperrors.FieldCode("actor_id"):        "Requester",
+		apperrors.FieldCode("reason"):          "Reason",
+		apperrors.FieldCode("code"):            "Code",
+		apperrors.FieldCode("name"):            "Name",
+		apperrors.FieldCode("address"):         "Address",
+		apperrors.FieldCode("regional_id"):     "Regional",
+		apperrors.FieldCode("centro_id"):       "Training center",
+		apperrors.FieldCode("level"):           "Level",
+		apperrors.FieldCode("duration_months"): "Duration in months",
+		apperrors.FieldCode("program_id"):      "Training program",
+		apperrors.FieldCode("jornada"):         "Schedule",
+		apperrors.FieldCode("start_date"):      "Start date",
+		apperrors.FieldCode("end_date"):        "End date",
+		apperrors.FieldCode("status"):          "Status",
+		apperrors.FieldCode("capacity"):        "Capacity",
+		apperrors.FieldCode("to_ficha_id"):     "Destination ficha",
+		apperrors.FieldCode("approved_by"):     "Approver",
+		apperrors.FieldCode("effective_date"):  "Effective date",
+		apperrors.FieldCode("instructor_id"):   "Instructor",
+		apperrors.FieldCode("competency"):      "Competency",
+		apperrors.FieldCode("user_id"):         "User",
+	})
+} // fin init
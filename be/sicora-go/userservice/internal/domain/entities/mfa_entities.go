@@ -12,8 +12,8 @@ type UserMFAMethod struct {
 	UserID          uuid.UUID  `gorm:"column:user_id_user_mfa_method;type:uuid;not null;index" json:"user_id_user_mfa_method"`
 	MethodType      string     `gorm:"column:method_type_user_mfa_method;type:varchar(20);not null" json:"method_type_user_mfa_method"` // 'totp', 'email_otp', 'sms', 'webauthn'
 	IsPrimary       bool       `gorm:"column:is_primary_user_mfa_method;default:false" json:"is_primary_user_mfa_method"`
-	IsEnabled       bool       `gorm:"column:is_enabled_user_mfa_method;default:true" json:"is_enabled_user_mfa_method"`
-	SecretEncrypted *string    `gorm:"column:secret_encrypted_user_mfa_method;type:text" json:"-"` // Solo para TOTP, nunca exponer en JSON
+	IsEnabled       bool       `gorm:"column:is_enabled_user_mfa_method;not null" json:"is_enabled_user_mfa_method"` // Sin default en el tag: GORM cambiaría un false explícito por true
+	SecretEncrypted *string    `gorm:"column:secret_encrypted_user_mfa_method;type:text" json:"-"`                   // Solo para TOTP, nunca exponer en JSON
 	PhoneNumber     *string    `gorm:"column:phone_number_user_mfa_method;type:varchar(20)" json:"phone_number_user_mfa_method,omitempty"`
 	EmailAddress    *string    `gorm:"column:email_address_user_mfa_method;type:varchar(255)" json:"email_address_user_mfa_method,omitempty"`
 	WebAuthnData    *string    `gorm:"column:webauthn_data_user_mfa_method;type:jsonb" json:"-"` // Credenciales WebAuthn, no exponer
@@ -76,18 +76,18 @@ func (s *MFASession) HasExceededAttempts() bool {
 
 // MFAEnforcementPolicy define políticas de MFA por rol
 type MFAEnforcementPolicy struct {
-	ID                 uuid.UUID `gorm:"column:id_mfa_enforcement_policy;type:uuid;primaryKey;default:gen_random_uuid()" json:"id_mfa_enforcement_policy"`
-	RoleName           string    `gorm:"column:role_name_mfa_enforcement_policy;type:varchar(50);not null;uniqueIndex" json:"role_name_mfa_enforcement_policy"`
-	PrimaryMethods     []string  `gorm:"column:primary_methods_mfa_enforcement_policy;type:text[];not null" json:"primary_methods_mfa_enforcement_policy"`          // ['totp', 'webauthn']
-	AlternativeMethods []string  `gorm:"column:alternative_methods_mfa_enforcement_policy;type:text[]" json:"alternative_methods_mfa_enforcement_policy"`           // ['email_otp', 'sms']
-	EnforcementLevel   string    `gorm:"column:enforcement_level_mfa_enforcement_policy;type:varchar(20);not null" json:"enforcement_level_mfa_enforcement_policy"` // 'mandatory', 'recommended', 'optional'
-	GracePeriodDays    int       `gorm:"column:grace_period_days_mfa_enforcement_policy;default:0" json:"grace_period_days_mfa_enforcement_policy"`
-	RequireBackupCodes bool      `gorm:"column:require_backup_codes_mfa_enforcement_policy;default:true" json:"require_backup_codes_mfa_enforcement_policy"`
-	CreatedAt          time.Time `gorm:"column:created_at_mfa_enforcement_policy;type:timestamptz;not null;default:now()" json:"created_at_mfa_enforcement_policy"`
-	UpdatedAt          time.Time `gorm:"column:updated_at_mfa_enforcement_policy;type:timestamptz;not null;default:now()" json:"updated_at_mfa_enforcement_policy"`
+	ID                 uuid.UUID  `gorm:"column:id_mfa_enforcement_policy;type:uuid;primaryKey;default:gen_random_uuid()" json:"id_mfa_enforcement_policy"`
+	RoleName           string     `gorm:"column:role_name_mfa_enforcement_policy;type:varchar(50);not null;uniqueIndex" json:"role_name_mfa_enforcement_policy"`
+	PrimaryMethods     StringList `gorm:"column:primary_methods_mfa_enforcement_policy;type:text[];not null" json:"primary_methods_mfa_enforcement_policy"`          // ['totp', 'webauthn']
+	AlternativeMethods StringList `gorm:"column:alternative_methods_mfa_enforcement_policy;type:text[]" json:"alternative_methods_mfa_enforcement_policy"`           // ['email_otp', 'sms']
+	EnforcementLevel   string     `gorm:"column:enforcement_level_mfa_enforcement_policy;type:varchar(20);not null" json:"enforcement_level_mfa_enforcement_policy"` // 'mandatory', 'recommended', 'optional'
+	GracePeriodDays    int        `gorm:"column:grace_period_days_mfa_enforcement_policy;default:0" json:"grace_period_days_mfa_enforcement_policy"`
+	RequireBackupCodes bool       `gorm:"column:require_backup_codes_mfa_enforcement_policy;not null" json:"require_backup_codes_mfa_enforcement_policy"` // Sin default en el tag, igual que IsEnabled
+	CreatedAt          time.Time  `gorm:"column:created_at_mfa_enforcement_policy;type:timestamptz;not null;default:now()" json:"created_at_mfa_enforcement_policy"`
+	UpdatedAt          time.Time  `gorm:"column:updated_at_mfa_enforcement_policy;type:timestamptz;not null;default:now()" json:"updated_at_mfa_enforcement_policy"`
 }
 
 // TableName especifica el nombre de la tabla
 func (MFAEnforcementPolicy) TableName() string {
 	return "userservice.mfa_enforcement_policies"
-}
\ No newline at end of file
+}
@@ -0,0 +1,162 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// orgCodePattern es el formato de los códigos de regionales y centros en el catálogo SENA
+var orgCodePattern = regexp.MustCompile(`^[0-9A-Za-z\-]{1,20}$`)
+
+// Regional agrupa los centros de formación de un departamento o distrito
+// Jerarquía: Regional -> Centro -> Sede; User.SedeID y Ficha.SedeID apuntan al último nivel
+type Regional struct {
+	ID        uuid.UUID `gorm:"column:id_regional;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Code      string    `gorm:"column:code_regional;type:varchar(20);not null;uniqueIndex:uq_regionales_code" json:"code"` // Código de la regional en el catálogo SENA
+	Name      string    `gorm:"column:name_regional;type:varchar(150);not null" json:"name"`
+	IsActive  bool      `gorm:"column:is_active_regional;not null" json:"is_active"`
+	CreatedAt time.Time `gorm:"column:created_at_regional;type:timestamptz;not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at_regional;type:timestamptz;not null;default:now()" json:"updated_at"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Regional) TableName() string {
+	return "userservice.regionales"
+}
+
+// Centro es un centro de formación de una regional
+type Centro struct {
+	ID         uuid.UUID `gorm:"column:id_centro;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	RegionalID uuid.UUID `gorm:"column:regional_id_centro;type:uuid;not null;index" json:"regional_id"`
+	Code       string    `gorm:"column:code_centro;type:varchar(20);not null;uniqueIndex:uq_centros_code" json:"code"` // Código del centro en el catálogo SENA
+	Name       string    `gorm:"column:name_centro;type:varchar(150);not null" json:"name"`
+	IsActive   bool      `gorm:"column:is_active_centro;not null" json:"is_active"`
+	CreatedAt  time.Time `gorm:"column:created_at_centro;type:timestamptz;not null;default:now()" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at_centro;type:timestamptz;not null;default:now()" json:"updated_at"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Centro) TableName() string {
+	return "userservice.centros"
+}
+
+// Sede es una sede física de un centro de formación; su nombre es único dentro del centro
+type Sede struct {
+	ID        uuid.UUID `gorm:"column:id_sede;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CentroID  uuid.UUID `gorm:"column:centro_id_sede;type:uuid;not null;uniqueIndex:uq_sedes_centro_name,priority:1" json:"centro_id"`
+	Name      string    `gorm:"column:name_sede;type:varchar(150);not null;uniqueIndex:uq_sedes_centro_name,priority:2" json:"name"`
+	Address   string    `gorm:"column:address_sede;type:varchar(255);not null" json:"address,omitempty"`
+	IsActive  bool      `gorm:"column:is_active_sede;not null" json:"is_active"`
+	CreatedAt time.Time `gorm:"column:created_at_sede;type:timestamptz;not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at_sede;type:timestamptz;not null;default:now()" json:"updated_at"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Sede) TableName() string {
+	return "userservice.sedes"
+}
+
+// NewRegional crea una regional activa con validaciones de dominio
+func NewRegional(code, name string) (*Regional, error) {
+	now := time.Now()
+	regional := &Regional{
+		ID:        uuid.New(),
+		Code:      strings.TrimSpace(code),
+		Name:      strings.TrimSpace(name),
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := regional.Validate(); err != nil {
+		return nil, err
+	}
+
+	return regional, nil
+}
+
+// Validate verifica los datos de la regional según reglas de dominio
+func (r *Regional) Validate() error {
+	if !orgCodePattern.MatchString(r.Code) {
+		return apperrors.Validation(CodeCodeFormat, "code").With("max", 20)
+	}
+	return validateOrgName(r.Name)
+}
+
+// NewCentro crea un centro de formación activo con validaciones de dominio
+func NewCentro(regionalID uuid.UUID, code, name string) (*Centro, error) {
+	now := time.Now()
+	centro := &Centro{
+		ID:         uuid.New(),
+		RegionalID: regionalID,
+		Code:       strings.TrimSpace(code),
+		Name:       strings.TrimSpace(name),
+		IsActive:   true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := centro.Validate(); err != nil {
+		return nil, err
+	}
+
+	return centro, nil
+}
+
+// Validate verifica los datos del centro según reglas de dominio
+func (c *Centro) Validate() error {
+	if c.RegionalID == uuid.Nil {
+		return apperrors.Validation(apperrors.CodeRequired, "regional_id")
+	}
+	if !orgCodePattern.MatchString(c.Code) {
+		return apperrors.Validation(CodeCodeFormat, "code").With("max", 20)
+	}
+	return validateOrgName(c.Name)
+}
+
+// NewSede crea una sede activa con validaciones de dominio
+func NewSede(centroID uuid.UUID, name, address string) (*Sede, error) {
+	now := time.Now()
+	sede := &Sede{
+		ID:        uuid.New(),
+		CentroID:  centroID,
+		Name:      strings.TrimSpace(name),
+		Address:   strings.TrimSpace(address),
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := sede.Validate(); err != nil {
+		return nil, err
+	}
+
+	return sede, nil
+}
+
+// Validate verifica los datos de la sede según reglas de dominio
+func (s *Sede) Validate() error {
+	if s.CentroID == uuid.Nil {
+		return apperrors.Validation(apperrors.CodeRequired, "centro_id")
+	}
+	if len(s.Address) > 255 {
+		return apperrors.Validation(apperrors.CodeTooLong, "address").With("max", 255)
+	}
+	return validateOrgName(s.Name)
+}
+
+// validateOrgName verifica el nombre de un nivel de la jerarquía
+func validateOrgName(name string) error {
+	name = strings.TrimSpace(name)
+	if len(name) < 3 {
+		return apperrors.Validation(apperrors.CodeTooShort, "name").With("min", 3)
+	}
+	if len(name) > 150 {
+		return apperrors.Validation(apperrors.CodeTooLong, "name").With("max", 150)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// ProgramLevel representa el nivel de formación de un programa
+type ProgramLevel string
+
+const (
+	ProgramLevelAuxiliar        ProgramLevel = "auxiliar"
+	ProgramLevelOperario        ProgramLevel = "operario"
+	ProgramLevelTecnico         ProgramLevel = "tecnico"
+	ProgramLevelTecnologo       ProgramLevel = "tecnologo"
+	ProgramLevelEspecializacion ProgramLevel = "especializacion_tecnologica"
+)
+
+// Program representa un programa de formación del SENA
+// Los aprendices pertenecen a un programa a través de su ficha
+type Program struct {
+	ID             uuid.UUID    `gorm:"column:id_program;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Code           string       `gorm:"column:code_program;type:varchar(20);not null;uniqueIndex:uq_programs_code" json:"code"` // Código del programa en el catálogo SENA
+	Name           string       `gorm:"column:name_program;type:varchar(200);not null" json:"name"`
+	Level          ProgramLevel `gorm:"column:level_program;type:varchar(30);not null" json:"level"`
+	DurationMonths int          `gorm:"column:duration_months_program;not null" json:"duration_months"` // Etapa lectiva más etapa productiva
+	IsActive       bool         `gorm:"column:is_active_program;not null" json:"is_active"`             // Sin default en el tag, igual que User.IsActive
+	CreatedAt      time.Time    `gorm:"column:created_at_program;type:timestamptz;not null;default:now()" json:"created_at"`
+	UpdatedAt      time.Time    `gorm:"column:updated_at_program;type:timestamptz;not null;default:now()" json:"updated_at"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Program) TableName() string {
+	return "userservice.programs"
+}
+
+// NewProgram crea un programa activo con validaciones de dominio
+func NewProgram(code, name string, level ProgramLevel, durationMonths int) (*Program, error) {
+	now := time.Now()
+	program := &Program{
+		ID:             uuid.New(),
+		Code:           strings.TrimSpace(code),
+		Name:           strings.TrimSpace(name),
+		Level:          level,
+		DurationMonths: durationMonths,
+		IsActive:       true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := program.Validate(); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// Validate verifica los datos del programa según reglas de dominio
+func (p *Program) Validate() error {
+	if matched, _ := regexp.MatchString(`^[0-9A-Za-z\-]{1,20}$`, p.Code); !matched {
+		return apperrors.Validation(CodeCodeFormat, "code").With("max", 20)
+	}
+
+	name := strings.TrimSpace(p.Name)
+	if len(name) < 3 {
+		return apperrors.Validation(apperrors.CodeTooShort, "name").With("min", 3)
+	}
+	if len(name) > 200 {
+		return apperrors.Validation(apperrors.CodeTooLong, "name").With("max", 200)
+	}
+
+	switch p.Level {
+	case ProgramLevelAuxiliar, ProgramLevelOperario, ProgramLevelTecnico, ProgramLevelTecnologo, ProgramLevelEspecializacion:
+	default:
+		return apperrors.Validation(apperrors.CodeInvalidOption, "level").
+			With("options", []string{string(ProgramLevelAuxiliar), string(ProgramLevelOperario), string(ProgramLevelTecnico), string(ProgramLevelTecnologo), string(ProgramLevelEspecializacion)})
+	}
+
+	if p.DurationMonths < 1 || p.DurationMonths > 60 {
+		return apperrors.Validation(apperrors.CodeOutOfRange, "duration_months").With("min", 1).With("max", 60)
+	}
+
+	return nil
+} // fin Validate
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// StringList es una lista de textos persistida como arreglo text[] de PostgreSQL
+// Implementa sql.Scanner y driver.Valuer usando el literal de arreglo ({"a","b"})
+type StringList []string
+
+// Value serializa la lista como literal de arreglo de PostgreSQL
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	quoted := make([]string, len(l))
+	for i, item := range l {
+		escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(item)
+		quoted[i] = `"` + escaped + `"`
+	}
+
+	return "{" + strings.Join(quoted, ",") + "}", nil
+} // fin Value
+
+// Scan interpreta un literal de arreglo de PostgreSQL
+func (l *StringList) Scan(src any) error {
+	var literal string
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		literal = v
+	case []byte:
+		literal = string(v)
+	default:
+		return fmt.Errorf("StringList: tipo no soportado %T", src)
+	}
+
+	literal = strings.TrimSpace(literal)
+	if len(literal) < 2 || literal[0] != '{' || literal[len(literal)-1] != '}' {
+		return fmt.Errorf("StringList: literal de arreglo inválido %q", literal)
+	}
+
+	items := StringList{}
+	body := literal[1 : len(literal)-1]
+	var current strings.Builder
+	inQuotes, escaped, pending := false, false, false
+
+	for _, r := range body {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+			pending = true
+		case r == ',' && !inQuotes:
+			items = append(items, current.String())
+			current.Reset()
+			pending = false
+		default:
+			current.WriteRune(r)
+			pending = true
+		}
+	}
+
+	if pending || current.Len() > 0 {
+		items = append(items, current.String())
+	}
+
+	*l = items
+	return nil
+} // fin Scan
@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// TenantBypass registra que un directivo nacional consultó o modificó usuarios sin el alcance de sede
+// Cada bypass queda en la bitácora de auditoría antes de poder usarse
+type TenantBypass struct {
+	ID        uuid.UUID `gorm:"column:id_tenant_bypass;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ActorID   uuid.UUID `gorm:"column:actor_id_tenant_bypass;type:uuid;not null;index" json:"actor_id"`
+	Reason    string    `gorm:"column:reason_tenant_bypass;type:varchar(500);not null" json:"reason"`
+	GrantedAt time.Time `gorm:"column:granted_at_tenant_bypass;type:timestamptz;not null;default:now()" json:"granted_at"`
+}
+
+// TableName especifica el nombre de la tabla
+func (TenantBypass) TableName() string {
+	return "userservice.tenant_bypasses"
+}
+
+// NewTenantBypass crea el registro del bypass del actor con validaciones de dominio
+// El rol del actor lo verifican repositories.GrantTenantBypass y RecordTenantBypass sobre el usuario guardado
+func NewTenantBypass(actorID uuid.UUID, reason string) (*TenantBypass, error) {
+	bypass := &TenantBypass{
+		ID:        uuid.New(),
+		ActorID:   actorID,
+		Reason:    strings.TrimSpace(reason),
+		GrantedAt: time.Now(),
+	}
+
+	if err := bypass.Validate(); err != nil {
+		return nil, err
+	}
+
+	return bypass, nil
+}
+
+// Validate verifica los datos del bypass según reglas de dominio
+func (b *TenantBypass) Validate() error {
+	if b.ActorID == uuid.Nil {
+		return apperrors.Validation(apperrors.CodeRequired, "actor_id")
+	}
+
+	reason := strings.TrimSpace(b.Reason)
+	if utf8.RuneCountInString(reason) < 10 {
+		return apperrors.Validation(apperrors.CodeTooShort, "reason").With("min", 10)
+	}
+	if utf8.RuneCountInString(reason) > 500 {
+		return apperrors.Validation(apperrors.CodeTooLong, "reason").With("max", 500)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
 )
 
 // UsaerRole representa los roles disponibles en el sistema SICORA
@@ -17,59 +18,81 @@ const (
 	RoleDirectivo   UserRole = "directivo"
 )
 
+// Estados posibles del usuario
+const (
+	UserStatusActive  = "active"
+	UserStatusDeleted = "deleted"
+)
+
 // User representa la entidad de usuario en el dominio SICORA
 // Contiene las reglas de negocio fundamentales para usuarios
 type User struct {
-	ID              uuid.UUID  `json:"id"`
-	FirstName       string     `json:"first_name"`
-	LastName        string     `json:"last_name"`
-	Email           string     `json:"email"`
-	DocumentNumber  string     `json:"document_number"`
-	DocumentType    string     `json:"document_type"`
-	Phone           *string    `json:"phone"`
-	Role            UserRole   `json:"role"`
-	Status          string     `json:"status"`
-	Password        string     `json:"_"` // Nunca serialize un password
-	IsActive        bool       `json:"is_active"`
-	FichaID         *string    `json:"ficha_id,omitempty"` // Solo para aprendices
-	SedeID          *uuid.UUID `json:"sede_id,omitempty"`
-	EmailVerified   bool       `json:"email_verified"`
-	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	ID              uuid.UUID    `gorm:"column:id_user;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	FirstName       string       `gorm:"column:first_name_user;type:varchar(100);not null" json:"first_name"`
+	LastName        string       `gorm:"column:last_name_user;type:varchar(100);not null" json:"last_name"`
+	Email           Email        `gorm:"column:email_user;type:varchar(100);not null;uniqueIndex:uq_users_email_lower_active,expression:LOWER(email_user),where:deleted_at_user IS NULL" json:"email"` // Siempre en forma canónica
+	DocumentNumber  string       `gorm:"column:document_number_user;type:varchar(20);not null;uniqueIndex:uq_users_document_active,priority:2,where:deleted_at_user IS NULL" json:"document_number"`   // Único por tipo de documento
+	DocumentType    DocumentType `gorm:"column:document_type_user;type:varchar(20);not null;uniqueIndex:uq_users_document_active,priority:1" json:"document_type"`
+	BirthDate       *time.Time   `gorm:"column:birth_date_user;type:date" json:"birth_date,omitempty"` // Fecha sin hora, en UTC
+	Phone           *string      `gorm:"column:phone_user;type:varchar(20)" json:"phone"`
+	Role            UserRole     `gorm:"column:role_user;type:varchar(20);not null;index" json:"role"`
+	Status          string       `gorm:"column:status_user;type:varchar(20);not null;default:'active'" json:"status"`
+	Password        string       `gorm:"column:password_user;type:varchar(255)" json:"_"`                       // Nunca serialize un password
+	IsActive        bool         `gorm:"column:is_active_user;not null" json:"is_active"`                       // Sin default en el tag: GORM cambiaría un false explícito por true
+	FichaID         *string      `gorm:"column:ficha_id_user;type:varchar(20);index" json:"ficha_id,omitempty"` // Solo para aprendices
+	SedeID          *uuid.UUID   `gorm:"column:sede_id_user;type:uuid;index" json:"sede_id,omitempty"`
+	EmailVerified   bool         `gorm:"column:email_verified_user;default:false" json:"email_verified"`
+	EmailVerifiedAt *time.Time   `gorm:"column:email_verified_at_user;type:timestamptz" json:"email_verified_at,omitempty"`
 
 	// Legal Consent Fields (Ley 1582/2012 - Habeas data Colombia)
-	AcceptedPrivacyPolicyAt *time.Time `json:"accepted_privacy_policy_at,omitempty"`
-	AcceptedTermsAt         *time.Time `json:"accepted_terms_at,omitempty"`
-	AcceptedDataTreatmentAt *time.Time `json:"accepted_data_treatment_at,omitempty"`
-	PrivacyPolicyVersion    *string    `json:"privacy_policy_version,omitempty"`
-	TermsVersion            *string    `json:"terms_version,omitempty"`
-	DataTreatmentVersion    *string    `json:"data_treatment_version,omitempty"`
-	AcceptanceIPAddress     *string    `json:"acceptance_ip_address,omitempty"`
-
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	LastLogin *time.Time `json:"last_login,omitempty"`
+	AcceptedPrivacyPolicyAt *time.Time `gorm:"column:accepted_privacy_policy_at_user;type:timestamptz" json:"accepted_privacy_policy_at,omitempty"`
+	AcceptedTermsAt         *time.Time `gorm:"column:accepted_terms_at_user;type:timestamptz" json:"accepted_terms_at,omitempty"`
+	AcceptedDataTreatmentAt *time.Time `gorm:"column:accepted_data_treatment_at_user;type:timestamptz" json:"accepted_data_treatment_at,omitempty"`
+	PrivacyPolicyVersion    *string    `gorm:"column:privacy_policy_version_user;type:varchar(20)" json:"privacy_policy_version,omitempty"`
+	TermsVersion            *string    `gorm:"column:terms_version_user;type:varchar(20)" json:"terms_version,omitempty"`
+	DataTreatmentVersion    *string    `gorm:"column:data_treatment_version_user;type:varchar(20)" json:"data_treatment_version,omitempty"`
+	AcceptanceIPAddress     *string    `gorm:"column:acceptance_ip_address_user;type:varchar(45)" json:"acceptance_ip_address,omitempty"`
+
+	CreatedAt time.Time  `gorm:"column:created_at_user;type:timestamptz;not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"column:updated_at_user;type:timestamptz;not null;default:now()" json:"updated_at"`
+	LastLogin *time.Time `gorm:"column:last_login_user;type:timestamptz" json:"last_login,omitempty"`
+
+	// Version se incrementa en cada modificación para el control de concurrencia optimista
+	Version int `gorm:"column:version_user;not null;default:1" json:"version"`
+
+	// Soft delete: el registro se conserva hasta que se purga por retención
+	DeletedAt *time.Time `gorm:"column:deleted_at_user;type:timestamptz;index" json:"deleted_at,omitempty"`
+	DeletedBy *uuid.UUID `gorm:"column:deleted_by_user;type:uuid" json:"deleted_by,omitempty"`
 } // fin User
 
+// TableName especifica el nombre de la tabla
+func (User) TableName() string {
+	return "userservice.users"
+}
+
 // NewUser crea una nueva instancia de User con validaciones de dominio
-func NewUser(firstName, lastName, email, documentNumber, documentType string, role UserRole) (*User, error) {
-	if err := validateUserData(firstName, lastName, email, documentNumber, role); err != nil {
+// Los nombres se normalizan con NormalizeName y el número de documento con NormalizeDocumentNumber
+func NewUser(firstName, lastName, email, documentNumber string, documentType DocumentType, role UserRole) (*User, error) {
+	documentNumber = NormalizeDocumentNumber(documentNumber)
+	if err := validateUserData(firstName, lastName, email, documentNumber, documentType, role); err != nil {
 		return nil, err
 	}
 
 	now := time.Now()
 	user := &User{
 		ID:             uuid.New(),
-		FirstName:      firstName,
-		LastName:       lastName,
-		Email:          email,
+		FirstName:      NormalizeName(firstName),
+		LastName:       NormalizeName(lastName),
+		Email:          CanonicalEmail(email),
 		DocumentNumber: documentNumber,
 		DocumentType:   documentType,
 		Role:           role,
-		Status:         "active",
+		Status:         UserStatusActive,
 		IsActive:       true,
 		EmailVerified:  false,
 		CreatedAt:      now,
 		UpdatedAt:      now,
+		Version:        1,
 	}
 
 	return user, nil
@@ -96,6 +119,16 @@ func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
 }
 
+// CanApproveTransfers verifica si el usuario puede aprobar traslados de ficha
+func (u *User) CanApproveTransfers() bool {
+	return u.Role == RoleCoordinador || u.Role == RoleAdmin
+}
+
+// IsNationalDirectivo verifica si el usuario es un directivo de nivel nacional, sin sede asignada
+func (u *User) IsNationalDirectivo() bool {
+	return u.Role == RoleDirectivo && u.SedeID == nil
+}
+
 // MarkAsLoggedIn actualiza el tiemstamp del último login
 func (u *User) MarkAsLoggedIn() {
 	now := time.Now()
@@ -115,6 +148,87 @@ func (u *User) Activate() {
 	u.UpdatedAt = time.Now()
 }
 
+// MarkAsDeleted marca al usuario como eliminado (soft delete) y lo desactiva
+// deletedBy es nil cuando la eliminación la realiza un proceso del sistema
+func (u *User) MarkAsDeleted(deletedBy *uuid.UUID) {
+	now := time.Now()
+	u.DeletedAt = &now
+	u.DeletedBy = deletedBy
+	u.Status = UserStatusDeleted
+	u.IsActive = false
+	u.UpdatedAt = now
+}
+
+// Restore revierte el soft delete y reactiva al usuario
+func (u *User) Restore() {
+	u.DeletedAt = nil
+	u.DeletedBy = nil
+	u.Status = UserStatusActive
+	u.IsActive = true
+	u.UpdatedAt = time.Now()
+}
+
+// IsDeleted verifica si el usuario fue eliminado (soft delete)
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// SetBirthDate registra la fecha de nacimiento verificando que corresponda al tipo de documento:
+// la tarjeta de identidad es solo para menores de edad y la cédula de ciudadanía solo para mayores
+func (u *User) SetBirthDate(birthDate time.Time) error {
+	now := time.Now()
+	birthDate = CivilDate(birthDate)
+	if birthDate.IsZero() || birthDate.After(now) {
+		return apperrors.Validation(apperrors.CodeInvalid, "birth_date")
+	}
+
+	if err := validateDocumentAge(u.DocumentType, &birthDate, now); err != nil {
+		return err
+	}
+
+	u.BirthDate = &birthDate
+	u.UpdatedAt = now
+	return nil
+} // fin SetBirthDate
+
+// NeedsCedula verifica si el usuario tiene tarjeta de identidad y ya cumplió la mayoría de edad
+func (u *User) NeedsCedula() bool {
+	return u.DocumentType == DocumentTypeTI && u.BirthDate != nil && AgeAt(*u.BirthDate, time.Now()) >= AdultAge
+}
+
+// ChangeDocument cambia el documento del usuario validando el número según el nuevo tipo y la edad
+// La tarjeta de identidad solo se cambia por cédula de ciudadanía, con la fecha de nacimiento registrada
+// y cumplidos 18 años; el número suele conservarse (NUIP), el documento es único por tipo y número
+func (u *User) ChangeDocument(docType DocumentType, number string) error {
+	number = NormalizeDocumentNumber(number)
+	if err := ValidateDocument(docType, number); err != nil {
+		return err
+	}
+
+	if docType == u.DocumentType && number == u.DocumentNumber {
+		return apperrors.Validation(CodeDocumentUnchanged, "document_number")
+	}
+
+	if u.DocumentType == DocumentTypeTI && docType != DocumentTypeTI {
+		if docType != DocumentTypeCC {
+			return apperrors.Validation(CodeDocumentTIToCCOnly, "document_type")
+		}
+		if u.BirthDate == nil {
+			return apperrors.Validation(CodeDocumentBirthDateRequired, "birth_date")
+		}
+	}
+
+	now := time.Now()
+	if err := validateDocumentAge(docType, u.BirthDate, now); err != nil {
+		return err
+	}
+
+	u.DocumentType = docType
+	u.DocumentNumber = number
+	u.UpdatedAt = now
+	return nil
+} // fin ChangeDocument
+
 // AcceptLegalPolicies registra la aceptación de las políticas legales
 // Cumple con Ley 1581/2012 (Habeas Data Colombia)
 func (u *User) AcceptLegalPolicies(privacyVersion, termsVersion, dataVersion, ipAddress string) {
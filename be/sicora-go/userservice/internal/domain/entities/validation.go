@@ -3,150 +3,127 @@ package entities
 import (
 	"regexp"
 	"strings"
-)
-
-// DomainError representa un error del dominio
-type DomainError struct {
-	Message string
-}
+	"unicode"
+	"unicode/utf8"
 
-func (e *DomainError) Error() string {
-	return e.Message
-}
+	"golang.org/x/text/unicode/norm"
+	apperrors "sicora-be-go/pkg/errors"
+)
 
-// NewDomainError crea un nuevo error de dominio
-func NewDomainError(message string) *DomainError {
-	return &DomainError{Message: message}
-}
+// DomainError representa un error del dominio con su código, campo y parámetros
+// Los mensajes están en los catálogos de messages.go; Error() retorna el mensaje en español
+type DomainError = apperrors.Error
 
 // validateUserdata valida los datos básicos del usuario según reglas de dominio
-func validateUserData(firstName, lastName, email, documentNumber string, role UserRole) error {
-	if err := validateFirstName(firstName); err != nil {
-		return err
-	}
-
-	if err := validateLastName(lastName); err != nil {
-		return err
-	}
-
-	if err := validateEmail(email); err != nil {
-		return err
-	}
-
-	if err := validateDocumentNumber(documentNumber); err != nil {
-		return err
-	}
-
-	if err := validateRole(role); err != nil {
-		return err
-	}
-
-	return nil
+// Verifica todos los campos y retorna un *apperrors.List con un error por cada campo inválido
+func validateUserData(firstName, lastName, email, documentNumber string, documentType DocumentType, role UserRole) error {
+	var errs apperrors.List
+	errs.Add(validateFirstName(firstName))
+	errs.Add(validateLastName(lastName))
+	errs.Add(validateEmail(email))
+	errs.Add(ValidateDocument(documentType, documentNumber))
+	errs.Add(validateRole(role))
+	return errs.Err()
 } // fin validateUserData
 
 // validateFirstName valida el nombre del usuario
 func validateFirstName(firstName string) error {
-	firstName = strings.TrimSpace(firstName)
-	if len(firstName) < 2 {
-		return NewDomainError("El nombre debe tener al menos 2 caracteres")
-	}
+	return validateName(firstName, "first_name")
+}
 
-	if len(firstName) > 100 {
-		return NewDomainError("El nombre no debe exceder los 100 caracteres")
-	}
+// validateLastName valida el apellido del usuario
+func validateLastName(lastName string) error {
+	return validateName(lastName, "last_name")
+}
 
-	// Solo letras, espacios y acentos
+// NormalizeName lleva el nombre a NFC, reemplaza el apóstrofo tipográfico (’) por ' y deja un solo espacio entre palabras
+// Así "Maria\u0301  O’Neill " y "María O'Neill" se guardan igual
+func NormalizeName(name string) string {
+	name = norm.NFC.String(strings.ReplaceAll(name, "’", "'"))
+	return strings.Join(strings.Fields(name), " ")
+}
 
-	matched, _ := regexp.MatchString(`^[a-zA-ZáéíóúÁÉÍÓÚñÑ\s]+$`, firstName)
-	if !matched {
-		return NewDomainError("El nombre solo puede contener letras, espacios y acentos")
+// validateName valida un nombre o apellido normalizado con NormalizeName
+// Admite letras de cualquier alfabeto con sus marcas (tildes, diéresis, virgulilla) y palabras separadas por
+// un espacio, un apóstrofo o un guion: "Güiza", "O'Neill", "María-José". La longitud se mide en caracteres
+func validateName(name, field string) error {
+	name = NormalizeName(name)
+	length := utf8.RuneCountInString(name)
+	if length < 2 {
+		return apperrors.Validation(apperrors.CodeTooShort, field).With("min", 2)
 	}
 
-	return nil
-} // fin validateFirstName
-
-// validateLastName valida el apellido del usuario
-func validateLastName(lastName string) error {
-	lastName = strings.TrimSpace(lastName)
-	if len(lastName) < 2 {
-		return NewDomainError("El apellido debe tener al menos 2 caracteres")
+	if length > 100 {
+		return apperrors.Validation(apperrors.CodeTooLong, field).With("max", 100)
 	}
 
-	if len(lastName) > 100 {
-		return NewDomainError("El apellido no debe exceder los 100 caracteres")
+	previous := ' ' // Un separador al inicio cuenta como repetido
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r):
+		case unicode.IsMark(r):
+			if !unicode.IsLetter(previous) && !unicode.IsMark(previous) {
+				return apperrors.Validation(apperrors.CodeInvalidFormat, field)
+			}
+		case r == ' ' || r == '\'' || r == '-':
+			if !unicode.IsLetter(previous) && !unicode.IsMark(previous) {
+				return apperrors.Validation(CodeNameSeparators, field)
+			}
+		default:
+			return apperrors.Validation(CodeNameCharacters, field)
+		}
+		previous = r
 	}
 
-	// Solo letras, espacios y acentos
-	matched, _ := regexp.MatchString(`^[a-zA-ZáéíóúÁÉÍÓÚñÑ\s]+$`, lastName)
-	if !matched {
-		return NewDomainError("El apellido solo puede contener letras, espacios y acentos")
+	if !unicode.IsLetter(previous) && !unicode.IsMark(previous) {
+		return apperrors.Validation(CodeNameSeparators, field)
 	}
 
 	return nil
-} // fin validateLastName
+} // fin validateName
 
 // validateEmail valida el formato del email
 func validateEmail(email string) error {
 	email = strings.TrimSpace(strings.ToLower(email))
 	if len(email) == 0 {
-		return NewDomainError("El email no puede estar vacío")
+		return apperrors.Validation(apperrors.CodeRequired, "email")
 	}
 
 	if len(email) > 100 {
-		return NewDomainError("El email no debe exceder los 100 caracteres")
+		return apperrors.Validation(apperrors.CodeTooLong, "email").With("max", 100)
 	}
 
 	// Validación del formato de email (acepta cualquier dominio válido)
 	emailRegex := `^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}$`
 	matched, _ := regexp.MatchString(emailRegex, email)
 	if !matched {
-		return NewDomainError("El email no tiene un formato válido")
+		return apperrors.Validation(apperrors.CodeInvalidFormat, "email")
 	}
 
 	return nil
 } // fin validateEmail
 
-// validateDocumentNumber valida el número de documento del usuario
-func validateDocumentNumber(documentNumber string) error {
-	documentNumber = strings.TrimSpace(documentNumber)
-	if len(documentNumber) < 7 {
-		return NewDomainError("El número de documento debe tener al menos 7 caracteres")
-	}
-
-	if len(documentNumber) > 20 {
-		return NewDomainError("El número de documento no debe exceder los 20 caracteres")
-	}
-
-	// Permitir números, letras y guiones (para documentos internacionales)
-	matched, _ := regexp.MatchString(`^[a-zA-Z0-9\-]+$`, documentNumber)
-	if !matched {
-		return NewDomainError("El número de documento solo puede contener números, letras y guiones")
-	}
-
-	return nil
-} // fin validateDocumentNumber
-
-
 // validateRole valida que el rol sea válido
 func validateRole(role UserRole) error {
 	switch role {
 	case RoleAprendiz, RoleInstructor, RoleAdmin, RoleCoordinador, RoleDirectivo:
 		return nil
 	default:
-		return NewDomainError("El rol no es válido: debe ser aprendiz, instructor, admin, coordinador o directivo")	
+		return apperrors.Validation(apperrors.CodeInvalidOption, "role").
+			With("options", []string{string(RoleAprendiz), string(RoleInstructor), string(RoleAdmin), string(RoleCoordinador), string(RoleDirectivo)})
 	}
 } // fin validateRole
 
 // ValidateFichaID valida el formato de ID de ficha
 func ValidateFichaID(fichaID string) error {
 	if len(fichaID) == 0 {
-		return NewDomainError("El ID de ficha no puede estar vacío")
+		return apperrors.Validation(apperrors.CodeRequired, "ficha_id")
 	}
 
 	// Las fichas tienen formato numérico de 7 dígitos
 	matched, _ := regexp.MatchString(`^[0-9]{7}$`, fichaID)
 	if !matched {
-		return NewDomainError("El ID de ficha debe tener un formato numérico de 7 dígitos")
+		return apperrors.Validation(CodeFichaNumberFormat, "ficha_id").With("digits", 7)
 	}
 
 	return nil
@@ -155,31 +132,31 @@ func ValidateFichaID(fichaID string) error {
 // ValidatePassword valida la contraseña del usuario de acuerdo con las políticas
 func ValidatePassword(password string) error {
 	if len(password) < 10 {
-		return NewDomainError("La contraseña debe tener al menos 10 caracteres")
+		return apperrors.Validation(apperrors.CodeTooShort, "password").With("min", 10)
 	}
 
 	if len(password) > 128 {
-		return NewDomainError("La contraseña no debe exceder los 128 caracteres")
+		return apperrors.Validation(apperrors.CodeTooLong, "password").With("max", 128)
 	}
 
 	// Al menos una minúscula
 	if matched, _ := regexp.MatchString(`[a-z]`, password); !matched {
-		return NewDomainError("La contraseña debe contener al menos una letra minúscula")
+		return apperrors.Validation(CodePasswordLowercase, "password")
 	}
 
 	// Al menos una mayúscula
 	if matched, _ := regexp.MatchString(`[A-Z]`, password); !matched {
-		return NewDomainError("La contraseña debe contener al menos una letra mayúscula")
+		return apperrors.Validation(CodePasswordUppercase, "password")
 	}
 
 	// Al menos un número
 	if matched, _ := regexp.MatchString(`[0-9]`, password); !matched {
-		return NewDomainError("La contraseña debe contener al menos un número")
+		return apperrors.Validation(CodePasswordDigit, "password")
 	}
 
 	// Al menos un carácter especial
 	if matched, _ := regexp.MatchString(`[!@#$%^&*]`, password); !matched {
-		return NewDomainError("La contraseña debe contener al menos un carácter especial (!@#$%^&*)")
+		return apperrors.Validation(CodePasswordSpecial, "password").With("characters", "!@#$%^&*")
 	}
 
 	return nil
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// TestNewUserUnicodeNames verifica que los nombres reales se acepten y se normalicen en NFC
+func TestNewUserUnicodeNames(t *testing.T) {
+	valid := []struct {
+		first, last string
+		wantFirst   string
+		wantLast    string
+	}{
+		{"Andrés", "Güiza", "Andrés", "Güiza"},
+		{"Seán", "O'Neill", "Seán", "O'Neill"},
+		{"  María-José ", "De  la   Cruz", "María-José", "De la Cruz"},
+		{"Jose\u0301", "O’Connor", "José", "O'Connor"}, // NFD y apóstrofo tipográfico
+		{"Ñusta", "Jachachi", "Ñusta", "Jachachi"},
+		{"Jamanaʼa", "Ipuana", "Jamanaʼa", "Ipuana"}, // Apóstrofo como letra (U+02BC)
+	}
+
+	for i, tc := range valid {
+		user, err := NewUser(tc.first, tc.last, fmt.Sprintf("usuario%03d@sena.edu.co", i+1), fmt.Sprintf("10%08d", i+1), DocumentTypeCC, RoleAprendiz)
+		if err != nil {
+			t.Errorf("NewUser(%q, %q): %v", tc.first, tc.last, err)
+			continue
+		}
+		if user.FirstName != tc.wantFirst || user.LastName != tc.wantLast {
+			t.Errorf("NewUser(%q, %q) = %q, %q; se esperaba %q, %q", tc.first, tc.last, user.FirstName, user.LastName, tc.wantFirst, tc.wantLast)
+		}
+	}
+
+	// La longitud se cuenta en caracteres, no en bytes
+	long := strings.Repeat("ñ", 100)
+	if _, err := NewUser(long, "Gómez", "largo@sena.edu.co", "1099999999", DocumentTypeCC, RoleAprendiz); err != nil {
+		t.Errorf("NewUser con 100 caracteres de dos bytes: %v", err)
+	}
+
+	invalid := []string{"Ψ", "Ana3", "-Ana", "Ana-", "Ana--María", "O''Neill", "Ana - María", "Ana_María", "Ana.", "\u0301Ana", long + "a"}
+	for _, name := range invalid {
+		if _, err := NewUser(name, "Gómez", "invalido@sena.edu.co", "1099999999", DocumentTypeCC, RoleAprendiz); err == nil {
+			t.Errorf("NewUser(%q) debe fallar", name)
+		}
+	}
+} // fin TestNewUserUnicodeNames
+
+// TestNewUserStructuredErrors verifica que la validación reporte todos los campos inválidos con su código y mensaje
+func TestNewUserStructuredErrors(t *testing.T) {
+	_, err := NewUser("A", "Gómez", "sin-arroba", "12", DocumentTypeCC, "rector")
+	var list *apperrors.List
+	if !errors.As(err, &list) {
+		t.Fatalf("NewUser con varios campos inválidos: se esperaba *apperrors.List, se obtuvo %v", err)
+	}
+
+	fields := make([]string, len(list.Errors))
+	for i, e := range list.Errors {
+		fields[i] = e.Field
+	}
+	if want := []string{"first_name", "email", "document_number", "role"}; !slices.Equal(fields, want) {
+		t.Errorf("campos inválidos = %v, se esperaban %v", fields, want)
+	}
+	if first := list.Errors[0]; first.Code != apperrors.CodeTooShort || first.Params["min"] != 2 {
+		t.Errorf("error del nombre = %+v", first)
+	}
+	if got, want := list.Errors[0].Message("en"), "First name must be at least 2 characters long"; got != want {
+		t.Errorf("mensaje en inglés = %q, se esperaba %q", got, want)
+	}
+
+	var domainErr *DomainError
+	if !errors.As(err, &domainErr) || domainErr.Field != "first_name" {
+		t.Errorf("errors.As(*DomainError): se esperaba el primer error, se obtuvo %v", domainErr)
+	}
+} // fin TestNewUserStructuredErrors
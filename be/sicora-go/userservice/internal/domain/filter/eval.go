@@ -0,0 +1,72 @@
+package filter
+
+import "strings"
+
+// Evaluate evalúa la expresión sobre un registro; get retorna el valor de cada campo
+// Sigue la misma semántica que la traducción a SQL: una comparación con un campo nulo es falsa
+func Evaluate(expr Expr, get func(field string) Value) bool {
+	switch e := expr.(type) {
+	case nil:
+		return true
+	case *And:
+		for _, term := range e.Terms {
+			if !Evaluate(term, get) {
+				return false
+			}
+		}
+		return true
+	case *Or:
+		for _, term := range e.Terms {
+			if Evaluate(term, get) {
+				return true
+			}
+		}
+		return false
+	case *Not:
+		return !Evaluate(e.Expr, get)
+	case *Comparison:
+		return compare(e, get(e.Field))
+	default:
+		return false
+	}
+} // fin Evaluate
+
+// compare aplica una comparación al valor del campo
+func compare(c *Comparison, value Value) bool {
+	operand := c.Values[0]
+	if operand.IsNull() {
+		return value.IsNull() == (c.Op == OpEq)
+	}
+
+	if value.IsNull() {
+		return false
+	}
+
+	switch c.Op {
+	case OpEq:
+		return value.Compare(operand) == 0
+	case OpNe:
+		return value.Compare(operand) != 0
+	case OpLt:
+		return value.Compare(operand) < 0
+	case OpLe:
+		return value.Compare(operand) <= 0
+	case OpGt:
+		return value.Compare(operand) > 0
+	case OpGe:
+		return value.Compare(operand) >= 0
+	case OpIn:
+		for _, candidate := range c.Values {
+			if !candidate.IsNull() && value.Compare(candidate) == 0 {
+				return true
+			}
+		}
+		return false
+	case OpContains:
+		return strings.Contains(strings.ToLower(value.Str), strings.ToLower(operand.Str))
+	case OpStartsWith:
+		return strings.HasPrefix(strings.ToLower(value.Str), strings.ToLower(operand.Str))
+	default:
+		return false
+	}
+} // fin compare
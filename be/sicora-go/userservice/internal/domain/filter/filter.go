@@ -0,0 +1,324 @@
+// Package filter implementa un lenguaje pequeño y seguro de filtros y ordenamiento para listados
+//
+//	role eq 'aprendiz' and email_verified eq false and last_login lt 2026-09-01
+//	(ficha_id in ('2558104', '2558105') or sede_id eq null) and not status eq 'deleted'
+//	created_at ge now-30d and email contains 'misena'
+//
+// La expresión se interpreta a un AST tipado y se valida contra un Schema con los campos permitidos;
+// cada repositorio traduce el AST a su motor (SQL parametrizado, evaluación en memoria)
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidFilter indica que la expresión de filtro u ordenamiento no es válida
+// Los errores concretos son *Error con la posición del problema
+var ErrInvalidFilter = errors.New("filtro inválido")
+
+// Error describe un problema en la expresión en una posición (en bytes) dada
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s (posición %d)", ErrInvalidFilter.Error(), e.Msg, e.Pos)
+}
+
+// Is permite usar errors.Is(err, ErrInvalidFilter)
+func (e *Error) Is(target error) bool {
+	return target == ErrInvalidFilter
+}
+
+func errorf(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Kind es el tipo de un campo o de un valor
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindString
+	KindBool
+	KindTime
+	KindUUID
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "texto"
+	case KindBool:
+		return "booleano"
+	case KindTime:
+		return "fecha"
+	case KindUUID:
+		return "uuid"
+	default:
+		return "null"
+	}
+}
+
+// Field describe un campo permitido en filtros y ordenamiento
+type Field struct {
+	Kind     Kind
+	Nullable bool // Admite comparaciones con null
+	Sortable bool // Se permite en el ordenamiento
+}
+
+// Schema es la lista blanca de campos por nombre público
+type Schema map[string]Field
+
+// Op es un operador de comparación
+type Op string
+
+const (
+	OpEq         Op = "eq"
+	OpNe         Op = "ne"
+	OpLt         Op = "lt"
+	OpLe         Op = "le"
+	OpGt         Op = "gt"
+	OpGe         Op = "ge"
+	OpIn         Op = "in"
+	OpContains   Op = "contains"   // Texto, sin distinguir mayúsculas
+	OpStartsWith Op = "startswith" // Texto, sin distinguir mayúsculas
+)
+
+// operators son los operadores admitidos por tipo de campo
+var operators = map[Kind][]Op{
+	KindString: {OpEq, OpNe, OpIn, OpContains, OpStartsWith},
+	KindBool:   {OpEq, OpNe},
+	KindTime:   {OpEq, OpNe, OpLt, OpLe, OpGt, OpGe},
+	KindUUID:   {OpEq, OpNe, OpIn},
+}
+
+// Value es un literal tipado
+type Value struct {
+	Kind Kind
+	Str  string
+	Bool bool
+	Time time.Time
+	UUID uuid.UUID
+}
+
+// Null es el valor nulo
+var Null = Value{Kind: KindNull}
+
+// String construye un valor de texto
+func String(s string) Value { return Value{Kind: KindString, Str: s} }
+
+// Bool construye un valor booleano
+func Bool(b bool) Value { return Value{Kind: KindBool, Bool: b} }
+
+// Time construye un valor de fecha
+func Time(t time.Time) Value { return Value{Kind: KindTime, Time: t} }
+
+// UUID construye un valor uuid
+func UUID(id uuid.UUID) Value { return Value{Kind: KindUUID, UUID: id} }
+
+// IsNull indica si el valor es nulo
+func (v Value) IsNull() bool { return v.Kind == KindNull }
+
+// Any retorna el valor nativo para usarlo como argumento de una consulta
+func (v Value) Any() any {
+	switch v.Kind {
+	case KindString:
+		return v.Str
+	case KindBool:
+		return v.Bool
+	case KindTime:
+		return v.Time
+	case KindUUID:
+		return v.UUID
+	default:
+		return nil
+	}
+}
+
+// Encode serializa el valor como texto; ParseValue hace la operación inversa
+func (v Value) Encode() string {
+	switch v.Kind {
+	case KindString:
+		return v.Str
+	case KindBool:
+		if v.Bool {
+			return "true"
+		}
+		return "false"
+	case KindTime:
+		return v.Time.UTC().Format(time.RFC3339Nano)
+	case KindUUID:
+		return v.UUID.String()
+	default:
+		return ""
+	}
+}
+
+// ParseValue interpreta un valor serializado con Encode
+func ParseValue(kind Kind, s string) (Value, error) {
+	switch kind {
+	case KindString:
+		return String(s), nil
+	case KindBool:
+		switch s {
+		case "true":
+			return Bool(true), nil
+		case "false":
+			return Bool(false), nil
+		}
+	case KindTime:
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return Time(t), nil
+		}
+	case KindUUID:
+		if id, err := uuid.Parse(s); err == nil {
+			return UUID(id), nil
+		}
+	}
+
+	return Null, errorf(0, "valor %q inválido para %s", s, kind)
+} // fin ParseValue
+
+// Compare compara dos valores del mismo tipo; los nulos van después de cualquier valor
+func (v Value) Compare(other Value) int {
+	switch {
+	case v.IsNull() && other.IsNull():
+		return 0
+	case v.IsNull():
+		return 1
+	case other.IsNull():
+		return -1
+	}
+
+	switch v.Kind {
+	case KindString:
+		return strings.Compare(v.Str, other.Str)
+	case KindBool:
+		switch {
+		case v.Bool == other.Bool:
+			return 0
+		case !v.Bool:
+			return -1
+		default:
+			return 1
+		}
+	case KindTime:
+		return v.Time.Compare(other.Time)
+	case KindUUID:
+		return strings.Compare(v.UUID.String(), other.UUID.String())
+	default:
+		return 0
+	}
+} // fin Compare
+
+// Expr es un nodo del AST de filtros: *And, *Or, *Not o *Comparison
+type Expr interface {
+	exprNode()
+}
+
+// And se cumple si se cumplen todos sus términos
+type And struct {
+	Terms []Expr
+}
+
+// Or se cumple si se cumple alguno de sus términos
+type Or struct {
+	Terms []Expr
+}
+
+// Not niega la expresión
+type Not struct {
+	Expr Expr
+}
+
+// Comparison compara un campo contra uno o más valores (varios solo con OpIn)
+// Una comparación sobre un campo nulo es falsa, salvo eq null / ne null
+type Comparison struct {
+	Field  string
+	Op     Op
+	Values []Value
+}
+
+func (*And) exprNode()        {}
+func (*Or) exprNode()         {}
+func (*Not) exprNode()        {}
+func (*Comparison) exprNode() {}
+
+// Validate verifica un AST construido fuera del parser contra el esquema
+func Validate(expr Expr, schema Schema) error {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case *And:
+		return validateTerms(e.Terms, schema)
+	case *Or:
+		return validateTerms(e.Terms, schema)
+	case *Not:
+		if e.Expr == nil {
+			return errorf(0, "not sin expresión")
+		}
+		return Validate(e.Expr, schema)
+	case *Comparison:
+		return validateComparison(e, schema, 0)
+	default:
+		return errorf(0, "nodo de filtro desconocido %T", expr)
+	}
+}
+
+func validateTerms(terms []Expr, schema Schema) error {
+	if len(terms) == 0 {
+		return errorf(0, "grupo de filtros vacío")
+	}
+
+	for _, term := range terms {
+		if term == nil {
+			return errorf(0, "término de filtro vacío")
+		}
+		if err := Validate(term, schema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateComparison verifica campo, operador y tipos de los valores
+func validateComparison(c *Comparison, schema Schema, pos int) error {
+	field, ok := schema[c.Field]
+	if !ok {
+		return errorf(pos, "el campo %q no se puede filtrar", c.Field)
+	}
+
+	if !slices.Contains(operators[field.Kind], c.Op) {
+		return errorf(pos, "el operador %s no aplica al campo %s de tipo %s", c.Op, c.Field, field.Kind)
+	}
+
+	if len(c.Values) == 0 || (c.Op != OpIn && len(c.Values) > 1) {
+		return errorf(pos, "cantidad de valores inválida para %s", c.Op)
+	}
+
+	for _, value := range c.Values {
+		if value.IsNull() {
+			if !field.Nullable {
+				return errorf(pos, "el campo %s no admite null", c.Field)
+			}
+			if c.Op != OpEq && c.Op != OpNe {
+				return errorf(pos, "null solo se compara con eq o ne")
+			}
+			continue
+		}
+		if value.Kind != field.Kind {
+			return errorf(pos, "el campo %s espera un valor de tipo %s", c.Field, field.Kind)
+		}
+	}
+
+	return nil
+} // fin validateComparison
@@ -0,0 +1,194 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var testSchema = Schema{
+	"role":           {Kind: KindString, Sortable: true},
+	"email":          {Kind: KindString, Sortable: true},
+	"email_verified": {Kind: KindBool},
+	"created_at":     {Kind: KindTime, Sortable: true},
+	"last_login":     {Kind: KindTime, Nullable: true, Sortable: true},
+	"ficha_id":       {Kind: KindString, Nullable: true},
+	"sede_id":        {Kind: KindUUID, Nullable: true},
+}
+
+func TestParse(t *testing.T) {
+	fixed := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = time.Now })
+
+	sede := uuid.MustParse("6f1c2a52-8a39-4a4e-9a57-1d2f8f8e9a10")
+
+	cases := []struct {
+		input string
+		want  Expr
+	}{
+		{"", nil},
+		{"role eq 'aprendiz'", &Comparison{Field: "role", Op: OpEq, Values: []Value{String("aprendiz")}}},
+		{
+			"role eq 'aprendiz' and email_verified eq false and last_login lt 2026-09-01",
+			&And{Terms: []Expr{
+				&Comparison{Field: "role", Op: OpEq, Values: []Value{String("aprendiz")}},
+				&Comparison{Field: "email_verified", Op: OpEq, Values: []Value{Bool(false)}},
+				&Comparison{Field: "last_login", Op: OpLt, Values: []Value{Time(time.Date(2026, 9, 1, 0, 0, 0, 0, Location))}},
+			}},
+		},
+		{
+			"ROLE EQ 'a' OR role eq 'b' and not ficha_id eq null",
+			&Or{Terms: []Expr{
+				&Comparison{Field: "role", Op: OpEq, Values: []Value{String("a")}},
+				&And{Terms: []Expr{
+					&Comparison{Field: "role", Op: OpEq, Values: []Value{String("b")}},
+					&Not{Expr: &Comparison{Field: "ficha_id", Op: OpEq, Values: []Value{Null}}},
+				}},
+			}},
+		},
+		{
+			"(ficha_id in ('2558104', '2558105') or sede_id eq '" + sede.String() + "')",
+			&Or{Terms: []Expr{
+				&Comparison{Field: "ficha_id", Op: OpIn, Values: []Value{String("2558104"), String("2558105")}},
+				&Comparison{Field: "sede_id", Op: OpEq, Values: []Value{UUID(sede)}},
+			}},
+		},
+		{"email contains 'o''brien'", &Comparison{Field: "email", Op: OpContains, Values: []Value{String("o'brien")}}},
+		{"created_at ge now-30d", &Comparison{Field: "created_at", Op: OpGe, Values: []Value{Time(fixed.AddDate(0, 0, -30))}}},
+		{"created_at lt 2026-09-01T08:00:00Z", &Comparison{Field: "created_at", Op: OpLt, Values: []Value{Time(time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC))}}},
+	}
+
+	for _, tc := range cases {
+		got, err := Parse(tc.input, testSchema)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tc.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Parse(%q) = %#v, se esperaba %#v", tc.input, got, tc.want)
+		}
+	}
+} // fin TestParse
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		"password eq 'x'",             // campo fuera de la lista blanca
+		"role gt 'a'",                 // operador no admitido para texto
+		"role eq aprendiz",            // texto sin comillas
+		"role eq 'aprendiz",           // comillas sin cerrar
+		"email_verified eq 'false'",   // tipo incorrecto
+		"role eq null",                // campo no nulo
+		"last_login lt null",          // null solo con eq o ne
+		"sede_id eq 'no-es-uuid'",     // uuid inválido
+		"role eq 'a' and",             // expresión incompleta
+		"(role eq 'a'",                // paréntesis sin cerrar
+		"role eq 'a' role eq 'b'",     // falta el conector
+		"role in ()",                  // in sin valores
+		"role eq 'a'; drop table x",   // sobra texto
+		"created_at lt now-30y",       // unidad relativa inválida
+		"email_verified in (true)",    // in no aplica a booleanos
+		"not not not not not not not", // not sin comparación
+	}
+
+	for _, input := range cases {
+		_, err := Parse(input, testSchema)
+		if !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("Parse(%q): se esperaba ErrInvalidFilter, se obtuvo %v", input, err)
+		}
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	input := "role eq 'a'"
+	for range MaxComparisons {
+		input += " or role eq 'a'"
+	}
+	if _, err := Parse(input, testSchema); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("se esperaba error por exceso de comparaciones, se obtuvo %v", err)
+	}
+
+	nested := "role eq 'a'"
+	for range MaxDepth + 1 {
+		nested = "(" + nested + ")"
+	}
+	if _, err := Parse(nested, testSchema); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("se esperaba error por anidamiento, se obtuvo %v", err)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	login := time.Date(2026, 8, 15, 0, 0, 0, 0, time.UTC)
+	record := map[string]Value{
+		"role":           String("aprendiz"),
+		"email":          String("Ana.Gomez@sena.edu.co"),
+		"email_verified": Bool(false),
+		"last_login":     Time(login),
+		"ficha_id":       Null,
+	}
+	get := func(field string) Value { return record[field] }
+
+	cases := []struct {
+		input string
+		want  bool
+	}{
+		{"role eq 'aprendiz' and email_verified eq false and last_login lt 2026-09-01", true},
+		{"role eq 'aprendiz' and last_login gt 2026-09-01", false},
+		{"role in ('instructor', 'aprendiz')", true},
+		{"email contains 'GOMEZ'", true},
+		{"email startswith 'ana.'", true},
+		{"ficha_id eq null", true},
+		{"ficha_id ne null", false},
+		// Una comparación con un campo nulo es falsa, también al negarla
+		{"ficha_id eq '2558104'", false},
+		{"ficha_id ne '2558104'", false},
+		{"not ficha_id eq '2558104'", true},
+		{"role eq 'instructor' or not email_verified eq true", true},
+	}
+
+	for _, tc := range cases {
+		expr, err := Parse(tc.input, testSchema)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.input, err)
+		}
+		if got := Evaluate(expr, get); got != tc.want {
+			t.Errorf("Evaluate(%q) = %v, se esperaba %v", tc.input, got, tc.want)
+		}
+	}
+} // fin TestEvaluate
+
+func TestParseSort(t *testing.T) {
+	cases := []struct {
+		input string
+		want  []SortField
+	}{
+		{"", nil},
+		{"email", []SortField{{Field: "email"}}},
+		{"role asc, created_at DESC", []SortField{{Field: "role"}, {Field: "created_at", Desc: true}}},
+		{"-created_at,email", []SortField{{Field: "created_at", Desc: true}, {Field: "email"}}},
+	}
+
+	for _, tc := range cases {
+		got, err := ParseSort(tc.input, testSchema)
+		if err != nil {
+			t.Errorf("ParseSort(%q): %v", tc.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseSort(%q) = %v, se esperaba %v", tc.input, got, tc.want)
+		}
+	}
+
+	if got := FormatSort([]SortField{{Field: "role"}, {Field: "created_at", Desc: true}}); got != "role,-created_at" {
+		t.Errorf("FormatSort = %q", got)
+	}
+
+	for _, input := range []string{"ficha_id", "email sideways", "email,email", "role,email,created_at,last_login", "email,"} {
+		if _, err := ParseSort(input, testSchema); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("ParseSort(%q): se esperaba ErrInvalidFilter, se obtuvo %v", input, err)
+		}
+	}
+} // fin TestParseSort
@@ -0,0 +1,368 @@
+package filter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Límites para acotar el costo de interpretar y ejecutar una expresión
+const (
+	MaxExpressionLength = 2000
+	MaxComparisons      = 32
+	MaxDepth            = 16
+)
+
+// Location es la zona horaria con la que se interpretan las fechas sin hora (AAAA-MM-DD)
+var Location = loadLocation()
+
+// now permite fijar el reloj de las fechas relativas (now-30d) en las pruebas
+var now = time.Now
+
+// relativeTime reconoce now, now-30d, now+2h, now-15m
+var relativeTime = regexp.MustCompile(`^now(?:([+-])(\d{1,5})([dhm]))?$`)
+
+func loadLocation() *time.Location {
+	if loc, err := time.LoadLocation("America/Bogota"); err == nil {
+		return loc
+	}
+	return time.FixedZone("COT", -5*60*60) // Colombia no tiene horario de verano
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// tokenize separa la expresión en palabras, textos entre comillas simples y puntuación
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		switch c := input[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokenComma, ",", i})
+			i++
+		case c == '\'':
+			// '' dentro del texto representa una comilla simple
+			start := i
+			var text strings.Builder
+			for i++; ; i++ {
+				if i >= len(input) {
+					return nil, errorf(start, "texto sin cerrar")
+				}
+				if input[i] == '\'' {
+					if i+1 < len(input) && input[i+1] == '\'' {
+						text.WriteByte('\'')
+						i++
+						continue
+					}
+					i++
+					break
+				}
+				text.WriteByte(input[i])
+			}
+			tokens = append(tokens, token{tokenString, text.String(), start})
+		default:
+			start := i
+			for i < len(input) && !strings.ContainsRune(" \t\n\r(),'", rune(input[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, input[start:i], start})
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "", len(input)}), nil
+} // fin tokenize
+
+type parser struct {
+	schema      Schema
+	tokens      []token
+	pos         int
+	comparisons int
+}
+
+// Parse interpreta la expresión y la valida contra el esquema
+// Una expresión vacía retorna nil (sin filtro)
+func Parse(input string, schema Schema) (Expr, error) {
+	if len(input) > MaxExpressionLength {
+		return nil, errorf(MaxExpressionLength, "la expresión supera %d caracteres", MaxExpressionLength)
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{schema: schema, tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, errorf(tok.pos, "se esperaba and, or o fin de la expresión y se encontró %q", tok.text)
+	}
+
+	return expr, nil
+} // fin Parse
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword consume la palabra reservada si es la siguiente
+func (p *parser) keyword(word string) bool {
+	tok := p.peek()
+	if tok.kind == tokenWord && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, errorf(tok.pos, "se esperaba %s", what)
+	}
+	return tok, nil
+}
+
+func (p *parser) parseOr(depth int) (Expr, error) {
+	terms, err := p.parseTerms(depth, "or", p.parseAnd)
+	if err != nil || len(terms) == 1 {
+		return first(terms), err
+	}
+	return &Or{Terms: terms}, nil
+}
+
+func (p *parser) parseAnd(depth int) (Expr, error) {
+	terms, err := p.parseTerms(depth, "and", p.parseUnary)
+	if err != nil || len(terms) == 1 {
+		return first(terms), err
+	}
+	return &And{Terms: terms}, nil
+}
+
+// parseTerms lee términos separados por el conector dado
+func (p *parser) parseTerms(depth int, connector string, parseTerm func(int) (Expr, error)) ([]Expr, error) {
+	var terms []Expr
+	for {
+		term, err := parseTerm(depth)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+
+		if !p.keyword(connector) {
+			return terms, nil
+		}
+	}
+}
+
+func (p *parser) parseUnary(depth int) (Expr, error) {
+	if depth > MaxDepth {
+		return nil, errorf(p.peek().pos, "la expresión supera %d niveles de anidamiento", MaxDepth)
+	}
+
+	if p.keyword("not") {
+		expr, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+
+	return p.parseComparison()
+} // fin parseUnary
+
+// parseComparison lee campo operador valor, o campo in (valor, ...)
+func (p *parser) parseComparison() (Expr, error) {
+	fieldTok, err := p.expect(tokenWord, "un campo")
+	if err != nil {
+		return nil, err
+	}
+
+	p.comparisons++
+	if p.comparisons > MaxComparisons {
+		return nil, errorf(fieldTok.pos, "la expresión supera %d comparaciones", MaxComparisons)
+	}
+
+	name := strings.ToLower(fieldTok.text)
+	field, ok := p.schema[name]
+	if !ok {
+		return nil, errorf(fieldTok.pos, "el campo %q no se puede filtrar", fieldTok.text)
+	}
+
+	opTok, err := p.expect(tokenWord, "un operador")
+	if err != nil {
+		return nil, err
+	}
+	comparison := &Comparison{Field: name, Op: Op(strings.ToLower(opTok.text))}
+
+	if comparison.Op == OpIn {
+		if _, err := p.expect(tokenLParen, "'(' después de in"); err != nil {
+			return nil, err
+		}
+		for {
+			value, err := p.parseValue(field.Kind)
+			if err != nil {
+				return nil, err
+			}
+			comparison.Values = append(comparison.Values, value)
+
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+		if _, err := p.expect(tokenRParen, "')' al cerrar in"); err != nil {
+			return nil, err
+		}
+	} else {
+		value, err := p.parseValue(field.Kind)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = []Value{value}
+	}
+
+	if err := validateComparison(comparison, p.schema, fieldTok.pos); err != nil {
+		return nil, err
+	}
+
+	return comparison, nil
+} // fin parseComparison
+
+// parseValue interpreta un literal según el tipo del campo comparado
+func (p *parser) parseValue(kind Kind) (Value, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString:
+		switch kind {
+		case KindString:
+			return String(tok.text), nil
+		case KindUUID:
+			if id, err := uuid.Parse(tok.text); err == nil {
+				return UUID(id), nil
+			}
+			return Null, errorf(tok.pos, "uuid inválido %q", tok.text)
+		case KindTime:
+			if t, ok := parseTime(tok.text); ok {
+				return Time(t), nil
+			}
+			return Null, errorf(tok.pos, "fecha inválida %q", tok.text)
+		default:
+			return Null, errorf(tok.pos, "se esperaba un valor de tipo %s", kind)
+		}
+	case tokenWord:
+		word := strings.ToLower(tok.text)
+		switch {
+		case word == "null":
+			return Null, nil
+		case word == "true" || word == "false":
+			return Bool(word == "true"), nil
+		}
+		if t, ok := parseTime(tok.text); ok {
+			return Time(t), nil
+		}
+		return Null, errorf(tok.pos, "valor inválido %q; los textos van entre comillas simples", tok.text)
+	default:
+		return Null, errorf(tok.pos, "se esperaba un valor")
+	}
+} // fin parseValue
+
+// parseTime acepta AAAA-MM-DD (medianoche en Location), RFC3339 y fechas relativas a now
+func parseTime(text string) (time.Time, bool) {
+	if t, err := time.ParseInLocation(time.DateOnly, text, Location); err == nil {
+		return t, true
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, text); err == nil {
+		return t, true
+	}
+
+	match := relativeTime.FindStringSubmatch(strings.ToLower(text))
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	t := now()
+	if match[1] == "" {
+		return t, true
+	}
+
+	amount, _ := strconv.Atoi(match[2])
+	var offset time.Duration
+	switch match[3] {
+	case "d":
+		return t.AddDate(0, 0, sign(match[1])*amount), true
+	case "h":
+		offset = time.Duration(amount) * time.Hour
+	default:
+		offset = time.Duration(amount) * time.Minute
+	}
+
+	return t.Add(time.Duration(sign(match[1])) * offset), true
+} // fin parseTime
+
+func sign(s string) int {
+	if s == "-" {
+		return -1
+	}
+	return 1
+}
+
+func first(terms []Expr) Expr {
+	if len(terms) == 0 {
+		return nil
+	}
+	return terms[0]
+}
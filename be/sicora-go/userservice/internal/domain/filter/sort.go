@@ -0,0 +1,85 @@
+package filter
+
+import "strings"
+
+// MaxSortFields es la cantidad máxima de columnas de ordenamiento
+const MaxSortFields = 3
+
+// SortField es una columna de ordenamiento
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// ParseSort interpreta una lista separada por comas: "last_name asc, created_at desc" o "last_name,-created_at"
+// Sin dirección se ordena de forma ascendente. Una lista vacía retorna nil
+func ParseSort(input string, schema Schema) ([]SortField, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	var fields []SortField
+	pos := 0
+	for _, part := range strings.Split(input, ",") {
+		words := strings.Fields(strings.ToLower(part))
+		if len(words) == 0 || len(words) > 2 {
+			return nil, errorf(pos, "columna de ordenamiento inválida %q", strings.TrimSpace(part))
+		}
+
+		field := SortField{Field: words[0]}
+		if rest, ok := strings.CutPrefix(field.Field, "-"); ok {
+			field = SortField{Field: rest, Desc: true}
+		}
+
+		if len(words) == 2 {
+			switch words[1] {
+			case "asc":
+			case "desc":
+				field.Desc = !field.Desc
+			default:
+				return nil, errorf(pos, "dirección de ordenamiento inválida %q", words[1])
+			}
+		}
+
+		fields = append(fields, field)
+		pos += len(part) + 1
+	}
+
+	if err := ValidateSort(fields, schema); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+} // fin ParseSort
+
+// ValidateSort verifica que las columnas existan, se puedan ordenar y no se repitan
+func ValidateSort(fields []SortField, schema Schema) error {
+	if len(fields) > MaxSortFields {
+		return errorf(0, "se permiten máximo %d columnas de ordenamiento", MaxSortFields)
+	}
+
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !schema[field.Field].Sortable {
+			return errorf(0, "no se puede ordenar por %q", field.Field)
+		}
+		if seen[field.Field] {
+			return errorf(0, "columna de ordenamiento repetida %q", field.Field)
+		}
+		seen[field.Field] = true
+	}
+
+	return nil
+} // fin ValidateSort
+
+// FormatSort retorna la forma canónica del ordenamiento ("last_name,-created_at")
+func FormatSort(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Field
+		if field.Desc {
+			parts[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
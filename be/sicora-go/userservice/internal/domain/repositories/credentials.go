@@ -0,0 +1,18 @@
+package repositories
+
+import "context"
+
+// credentialsKey marca en el contexto que el llamador necesita la contraseña de los usuarios que lee
+type credentialsKey struct{}
+
+// WithCredentials marca que el llamador necesita User.Password, el hash de la contraseña, p. ej. para autenticar
+// Sin la marca, las lecturas que pasan por una caché retornan los usuarios sin Password; ver UserRepository
+func WithCredentials(ctx context.Context) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, true)
+}
+
+// NeedsCredentials indica si el contexto pasó por WithCredentials
+func NeedsCredentials(ctx context.Context) bool {
+	needs, _ := ctx.Value(credentialsKey{}).(bool)
+	return needs
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories/internal/tenantctx"
+)
+
+// DashboardSnapshotRepository guarda las fotos del cruce de usuarios del dashboard directivo
+// Un proceso periódico las refresca para que el dashboard se cargue con una sola lectura
+// Las fotos son nacionales: todas las operaciones requieren ver todas las sedes y retornan
+// ErrOutsideTenant con alcance de sede y ErrNoTenant sin alcance, como RequireAllSedes
+type DashboardSnapshotRepository interface {
+	// Save guarda la foto con todas sus celdas
+	Save(ctx context.Context, snapshot *entities.DashboardSnapshot) error
+
+	// Latest obtiene la foto más reciente con sus celdas, retorna nil si no hay ninguna
+	Latest(ctx context.Context) (*entities.DashboardSnapshot, error)
+
+	// Prune elimina las fotos tomadas antes de la fecha dada, conservando siempre la más reciente
+	Prune(ctx context.Context, takenBefore time.Time) (int64, error)
+}
+
+// RefreshDashboardSnapshot toma una foto del cruce de usuarios, la guarda y elimina las anteriores a la retención
+// Es la tarea programada del dashboard: la foto es nacional y se toma como proceso interno, sin el alcance que traiga ctx
+func RefreshDashboardSnapshot(ctx context.Context, users UserRepository, snapshots DashboardSnapshotRepository, retention time.Duration) (*entities.DashboardSnapshot, error) {
+	ctx = tenantctx.Without(ctx)
+
+	snapshot, err := users.AggregateDashboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := snapshots.Save(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	if _, err := snapshots.Prune(ctx, snapshot.TakenAt.Add(-retention)); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+} // fin RefreshDashboardSnapshot
@@ -0,0 +1,282 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"userservice/internal/domain/filter"
+
+	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// Errores comunes que las implementaciones de repositorio deben retornar
+// para que la capa de aplicación pueda distinguirlos con errors.Is
+var (
+	// ErrUserNotFound indica que el usuario a modificar no existe
+	ErrUserNotFound = errors.New("usuario no encontrado")
+
+	// ErrDuplicateUser indica que ya existe un usuario con el mismo email o documento
+	// Los errores concretos son *DuplicateUserError cuando se conoce el campo en conflicto
+	ErrDuplicateUser = errors.New("ya existe un usuario con el mismo email o documento")
+
+	// ErrVersionConflict indica que el usuario fue modificado por otra operación
+	// Los errores concretos son *VersionConflictError
+	ErrVersionConflict = errors.New("el usuario fue modificado por otra operación")
+
+	// ErrMFARecordNotFound indica que el método, código, sesión o política MFA no existe
+	ErrMFARecordNotFound = errors.New("registro MFA no encontrado")
+
+	// ErrUnsupportedFilter indica que la implementación no puede aplicar un filtro o consulta
+	ErrUnsupportedFilter = errors.New("filtro no soportado por el repositorio")
+
+	// ErrInvalidFilter indica que la expresión de filtro u ordenamiento no es válida
+	// Los errores concretos son *filter.Error con la posición del problema
+	ErrInvalidFilter = filter.ErrInvalidFilter
+
+	// ErrInvalidCursor indica que el cursor de paginación está corrupto o no corresponde al ordenamiento solicitado
+	ErrInvalidCursor = errors.New("cursor de paginación inválido")
+)
+
+// VersionConflictError indica que la versión del usuario a guardar no coincide con la almacenada
+type VersionConflictError struct {
+	UserID          uuid.UUID
+	ExpectedVersion int
+	CurrentVersion  int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: usuario %s en versión %d, se intentó guardar sobre la versión %d",
+		ErrVersionConflict.Error(), e.UserID, e.CurrentVersion, e.ExpectedVersion)
+}
+
+// Is permite comparar con errors.Is(err, ErrVersionConflict)
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// Structured reporta el conflicto con las versiones para que el cliente recargue el usuario
+func (e *VersionConflictError) Structured() *apperrors.Error {
+	return apperrors.New(apperrors.KindConflict, CodeVersionConflict).
+		With("current_version", e.CurrentVersion).
+		With("expected_version", e.ExpectedVersion)
+}
+
+// DuplicateUserError indica qué campo único ("email", "document_number", "id") ya está en uso
+type DuplicateUserError struct {
+	Field string
+}
+
+func (e *DuplicateUserError) Error() string {
+	if e.Field == "" {
+		return ErrDuplicateUser.Error()
+	}
+	return fmt.Sprintf("%s (%s)", ErrDuplicateUser.Error(), e.Field)
+}
+
+// Is permite comparar con errors.Is(err, ErrDuplicateUser)
+func (e *DuplicateUserError) Is(target error) bool {
+	return target == ErrDuplicateUser
+}
+
+// Structured reporta el duplicado sobre el campo en conflicto
+func (e *DuplicateUserError) Structured() *apperrors.Error {
+	return apperrors.New(apperrors.KindConflict, CodeDuplicateUser).OnField(e.Field)
+}
+
+// Códigos con los que se reportan los errores del paquete; apperrors.From los reconoce con errors.Is
+const (
+	CodeUserNotFound      apperrors.Code = "user.not_found"
+	CodeDuplicateUser     apperrors.Code = "user.duplicate"
+	CodeVersionConflict   apperrors.Code = "user.version_conflict"
+	CodeMFANotFound       apperrors.Code = "mfa.not_found"
+	CodeUnsupportedFilter apperrors.Code = "filter.unsupported"
+	CodeInvalidFilter     apperrors.Code = "filter.invalid"
+	CodeInvalidCursor     apperrors.Code = "cursor.invalid"
+	CodeInvalidActivity   apperrors.Code = "activity.invalid_query"
+	CodeInvalidTrend      apperrors.Code = "trend.invalid_query"
+
+	CodeRegionalNotFound  apperrors.Code = "org.regional_not_found"
+	CodeCentroNotFound    apperrors.Code = "org.centro_not_found"
+	CodeSedeNotFound      apperrors.Code = "org.sede_not_found"
+	CodeDuplicateRegional apperrors.Code = "org.duplicate_regional"
+	CodeDuplicateCentro   apperrors.Code = "org.duplicate_centro"
+	CodeDuplicateSede     apperrors.Code = "org.duplicate_sede"
+	CodeInvalidOrgLevel   apperrors.Code = "org.invalid_level"
+
+	CodeOutsideTenant      apperrors.Code = "tenant.outside"
+	CodeNoTenant           apperrors.Code = "tenant.missing"
+	CodeTenantBypassDenied apperrors.Code = "tenant.bypass_denied"
+
+	CodeProgramNotFound  apperrors.Code = "program.not_found"
+	CodeDuplicateProgram apperrors.Code = "program.duplicate"
+
+	CodeFichaNotFound         apperrors.Code = "ficha.not_found"
+	CodeDuplicateFicha        apperrors.Code = "ficha.duplicate"
+	CodeFichaFull             apperrors.Code = "ficha.full"
+	CodeFichaClosed           apperrors.Code = "ficha.closed"
+	CodeFichaOtherSede        apperrors.Code = "ficha.other_sede"
+	CodeAlreadyInFicha        apperrors.Code = "ficha.already_member"
+	CodeNotInFicha            apperrors.Code = "ficha.not_member"
+	CodeInvalidApprover       apperrors.Code = "ficha.invalid_approver"
+	CodeBackdatedMovement     apperrors.Code = "ficha.backdated_movement"
+	CodeInvalidLeadInstructor apperrors.Code = "ficha.invalid_lead_instructor"
+	CodeAssignmentNotFound    apperrors.Code = "assignment.not_found"
+	CodeOverlappingAssignment apperrors.Code = "assignment.overlapping"
+	CodeInvalidInstructor     apperrors.Code = "assignment.invalid_instructor"
+)
+
+// codes son los códigos declarados arriba; Codes los expone para verificar sus plantillas en cada idioma
+var codes = []apperrors.Code{
+	CodeUserNotFound, CodeDuplicateUser, CodeVersionConflict, CodeMFANotFound,
+	CodeUnsupportedFilter, CodeInvalidFilter, CodeInvalidCursor, CodeInvalidActivity, CodeInvalidTrend,
+	CodeRegionalNotFound, CodeCentroNotFound, CodeSedeNotFound,
+	CodeDuplicateRegional, CodeDuplicateCentro, CodeDuplicateSede, CodeInvalidOrgLevel,
+	CodeOutsideTenant, CodeNoTenant, CodeTenantBypassDenied,
+	CodeProgramNotFound, CodeDuplicateProgram,
+	CodeFichaNotFound, CodeDuplicateFicha, CodeFichaFull, CodeFichaClosed, CodeFichaOtherSede,
+	CodeAlreadyInFicha, CodeNotInFicha, CodeInvalidApprover, CodeBackdatedMovement, CodeInvalidLeadInstructor,
+	CodeAssignmentNotFound, CodeOverlappingAssignment, CodeInvalidInstructor,
+}
+
+// Codes retorna los códigos de error que declara el paquete
+func Codes() []apperrors.Code {
+	return slices.Clone(codes)
+}
+
+// init registra el tipo y el código de cada error del paquete y sus mensajes
+// Los errores de filtros y consultas se envuelven con el detalle del problema ("%w: segmento ..."),
+// que apperrors.From conserva en el parámetro detail; el mensaje es el de la plantilla
+func init() {
+	for _, def := range []struct {
+		err  error
+		kind apperrors.Kind
+		code apperrors.Code
+	}{
+		{ErrUserNotFound, apperrors.KindNotFound, CodeUserNotFound},
+		{ErrDuplicateUser, apperrors.KindConflict, CodeDuplicateUser},
+		{ErrVersionConflict, apperrors.KindConflict, CodeVersionConflict},
+		{ErrMFARecordNotFound, apperrors.KindNotFound, CodeMFANotFound},
+		{ErrUnsupportedFilter, apperrors.KindInvalidRequest, CodeUnsupportedFilter},
+		{ErrInvalidFilter, apperrors.KindInvalidRequest, CodeInvalidFilter},
+		{ErrInvalidCursor, apperrors.KindInvalidRequest, CodeInvalidCursor},
+		{ErrInvalidActivityQuery, apperrors.KindInvalidRequest, CodeInvalidActivity},
+		{ErrInvalidTrendQuery, apperrors.KindInvalidRequest, CodeInvalidTrend},
+
+		{ErrRegionalNotFound, apperrors.KindNotFound, CodeRegionalNotFound},
+		{ErrCentroNotFound, apperrors.KindNotFound, CodeCentroNotFound},
+		{ErrSedeNotFound, apperrors.KindNotFound, CodeSedeNotFound},
+		{ErrDuplicateRegional, apperrors.KindConflict, CodeDuplicateRegional},
+		{ErrDuplicateCentro, apperrors.KindConflict, CodeDuplicateCentro},
+		{ErrDuplicateSede, apperrors.KindConflict, CodeDuplicateSede},
+		{ErrInvalidOrgLevel, apperrors.KindInvalidRequest, CodeInvalidOrgLevel},
+
+		{ErrOutsideTenant, apperrors.KindForbidden, CodeOutsideTenant},
+		{ErrNoTenant, apperrors.KindInternal, CodeNoTenant}, // Falta acotar el contexto: es un error del servicio
+		{ErrTenantBypassDenied, apperrors.KindForbidden, CodeTenantBypassDenied},
+
+		{ErrProgramNotFound, apperrors.KindNotFound, CodeProgramNotFound},
+		{ErrDuplicateProgram, apperrors.KindConflict, CodeDuplicateProgram},
+
+		{ErrFichaNotFound, apperrors.KindNotFound, CodeFichaNotFound},
+		{ErrDuplicateFicha, apperrors.KindConflict, CodeDuplicateFicha},
+		{ErrFichaFull, apperrors.KindConflict, CodeFichaFull},
+		{ErrFichaClosed, apperrors.KindConflict, CodeFichaClosed},
+		{ErrFichaOtherSede, apperrors.KindValidation, CodeFichaOtherSede},
+		{ErrAlreadyInFicha, apperrors.KindConflict, CodeAlreadyInFicha},
+		{ErrNotInFicha, apperrors.KindConflict, CodeNotInFicha},
+		{ErrInvalidApprover, apperrors.KindValidation, CodeInvalidApprover},
+		{ErrBackdatedMovement, apperrors.KindValidation, CodeBackdatedMovement},
+		{ErrInvalidLeadInstructor, apperrors.KindValidation, CodeInvalidLeadInstructor},
+		{ErrAssignmentNotFound, apperrors.KindNotFound, CodeAssignmentNotFound},
+		{ErrOverlappingAssignment, apperrors.KindConflict, CodeOverlappingAssignment},
+		{ErrInvalidInstructor, apperrors.KindValidation, CodeInvalidInstructor},
+	} {
+		apperrors.Define(def.err, def.kind, def.code)
+	}
+
+	apperrors.Register("es", apperrors.Catalog{
+		CodeUserNotFound:    "Usuario no encontrado",
+		CodeDuplicateUser:   "Ya existe un usuario con el mismo email o documento",
+		CodeVersionConflict: "El usuario fue modificado por otra operación, recárguelo e intente de nuevo",
+		CodeMFANotFound:     "Registro MFA no encontrado",
+		CodeInvalidCursor:   "Cursor de paginación inválido",
+
+		CodeUnsupportedFilter: "El filtro o la consulta no están soportados",
+		CodeInvalidFilter:     "La expresión de filtro u ordenamiento no es válida",
+		CodeInvalidActivity:   "La consulta de actividad no es válida",
+		CodeInvalidTrend:      "La consulta de tendencia de registros no es válida",
+
+		CodeRegionalNotFound:  "Regional no encontrada",
+		CodeCentroNotFound:    "Centro de formación no encontrado",
+		CodeSedeNotFound:      "Sede no encontrada",
+		CodeDuplicateRegional: "Ya existe una regional con el mismo código",
+		CodeDuplicateCentro:   "Ya existe un centro de formación con el mismo código",
+		CodeDuplicateSede:     "El centro de formación ya tiene una sede con el mismo nombre",
+		CodeInvalidOrgLevel:   "El nivel de la jerarquía debe ser regional, centro o sede",
+
+		CodeOutsideTenant:      "La sede está fuera del alcance permitido",
+		CodeNoTenant:           "La operación no tiene un alcance de sede",
+		CodeTenantBypassDenied: "Solo un directivo de nivel nacional puede consultar todas las sedes",
+
+		CodeProgramNotFound:  "Programa de formación no encontrado",
+		CodeDuplicateProgram: "Ya existe un programa con el mismo código",
+
+		CodeFichaNotFound:         "Ficha no encontrada",
+		CodeDuplicateFicha:        "Ya existe una ficha con el mismo número",
+		CodeFichaFull:             "La ficha no tiene cupos disponibles",
+		CodeFichaClosed:           "La ficha no admite aprendices",
+		CodeFichaOtherSede:        "La ficha pertenece a otra sede que el aprendiz",
+		CodeAlreadyInFicha:        "El aprendiz ya pertenece a otra ficha",
+		CodeNotInFicha:            "El aprendiz no pertenece a ninguna ficha",
+		CodeInvalidApprover:       "Quien aprueba el traslado no existe o no puede aprobarlo",
+		CodeBackdatedMovement:     "La fecha efectiva es anterior al último movimiento de ficha del aprendiz",
+		CodeInvalidLeadInstructor: "El instructor líder no existe o no es instructor",
+		CodeAssignmentNotFound:    "Asignación de instructor no encontrada",
+		CodeOverlappingAssignment: "El instructor ya está asignado a la competencia de la ficha en esas fechas",
+		CodeInvalidInstructor:     "El usuario no existe o no es instructor",
+	})
+
+	apperrors.Register("en", apperrors.Catalog{
+		CodeUserNotFound:    "User not found",
+		CodeDuplicateUser:   "A user with the same email or document already exists",
+		CodeVersionConflict: "The user was modified by another operation, reload it and try again",
+		CodeMFANotFound:     "MFA record not found",
+		CodeInvalidCursor:   "Invalid pagination cursor",
+
+		CodeUnsupportedFilter: "The filter or query is not supported",
+		CodeInvalidFilter:     "The filter or sort expression is not valid",
+		CodeInvalidActivity:   "The activity query is not valid",
+		CodeInvalidTrend:      "The registration trend query is not valid",
+
+		CodeRegionalNotFound:  "Regional not found",
+		CodeCentroNotFound:    "Training center not found",
+		CodeSedeNotFound:      "Sede not found",
+		CodeDuplicateRegional: "A regional with the same code already exists",
+		CodeDuplicateCentro:   "A training center with the same code already exists",
+		CodeDuplicateSede:     "The training center already has a sede with the same name",
+		CodeInvalidOrgLevel:   "The hierarchy level must be regional, centro or sede",
+
+		CodeOutsideTenant:      "The sede is outside the allowed scope",
+		CodeNoTenant:           "The operation has no sede scope",
+		CodeTenantBypassDenied: "Only a national-level directivo can query all sedes",
+
+		CodeProgramNotFound:  "Training program not found",
+		CodeDuplicateProgram: "A program with the same code already exists",
+
+		CodeFichaNotFound:         "Ficha not found",
+		CodeDuplicateFicha:        "A ficha with the same number already exists",
+		CodeFichaFull:             "The ficha has no available places",
+		CodeFichaClosed:           "The ficha does not accept aprendices",
+		CodeFichaOtherSede:        "The ficha belongs to a different sede than the aprendiz",
+		CodeAlreadyInFicha:        "The aprendiz already belongs to another ficha",
+		CodeNotInFicha:            "The aprendiz does not belong to any ficha",
+		CodeInvalidApprover:       "The approver of the transfer does not exist or cannot approve it",
+		CodeBackdatedMovement:     "The effective date is before the aprendiz's last ficha movement",
+		CodeInvalidLeadInstructor: "The lead instructor does not exist or is not an instructor",
+		CodeAssignmentNotFound:    "Instructor assignment not found",
+		CodeOverlappingAssignment: "The instructor is already assigned to the ficha competency on those dates",
+		CodeInvalidInstructor:     "The user does not exist or is not an instructor",
+	})
+} // fin init
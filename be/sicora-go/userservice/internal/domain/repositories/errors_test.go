@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"testing"
+
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// TestCatalogsCoverCodes falla si un código del paquete no tiene plantilla en es y en,
+// o si un catálogo tiene un código que el otro no
+func TestCatalogsCoverCodes(t *testing.T) {
+	for _, locale := range []string{"es", "en"} {
+		for _, code := range Codes() {
+			if !apperrors.Has(locale, code) {
+				t.Errorf("el catálogo %s no tiene plantilla para %q", locale, code)
+			}
+		}
+	}
+
+	for _, locales := range [][2]string{{"es", "en"}, {"en", "es"}} {
+		for _, code := range apperrors.Codes(locales[0]) {
+			if !apperrors.Has(locales[1], code) {
+				t.Errorf("el catálogo %s tiene plantilla para %q y el %s no", locales[0], code, locales[1])
+			}
+		}
+	}
+}
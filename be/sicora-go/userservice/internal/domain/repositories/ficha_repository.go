@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"userservice/internal/domain/entities"
+
+	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+var (
+	// ErrFichaNotFound indica que la ficha no existe
+	ErrFichaNotFound = errors.New("ficha no encontrada")
+
+	// ErrDuplicateFicha indica que ya existe una ficha con el mismo número
+	ErrDuplicateFicha = errors.New("ya existe una ficha con el mismo número")
+
+	// ErrFichaFull indica que la ficha ya tiene tantos aprendices como su capacidad
+	ErrFichaFull = errors.New("la ficha no tiene cupos disponibles")
+
+	// ErrFichaClosed indica que la ficha terminó o fue cancelada y no admite aprendices
+	ErrFichaClosed = errors.New("la ficha no admite aprendices")
+
+	// ErrFichaOtherSede indica que la ficha es de una sede distinta a la del aprendiz
+	ErrFichaOtherSede = errors.New("la ficha pertenece a otra sede que el aprendiz")
+
+	// ErrAlreadyInFicha indica que el aprendiz ya pertenece a otra ficha; debe trasladarse
+	ErrAlreadyInFicha = errors.New("el aprendiz ya pertenece a otra ficha")
+
+	// ErrNotInFicha indica que el aprendiz no pertenece a ninguna ficha y no puede trasladarse
+	ErrNotInFicha = errors.New("el aprendiz no pertenece a ninguna ficha")
+
+	// ErrInvalidApprover indica que quien aprueba el traslado no existe o no es coordinador ni administrador
+	ErrInvalidApprover = errors.New("quien aprueba el traslado no existe o no puede aprobarlo")
+
+	// ErrBackdatedMovement indica que la fecha efectiva es anterior al último movimiento de ficha del aprendiz
+	ErrBackdatedMovement = errors.New("la fecha efectiva es anterior al último movimiento de ficha del aprendiz")
+
+	// ErrInvalidLeadInstructor indica que el instructor líder no existe o no tiene rol de instructor
+	ErrInvalidLeadInstructor = errors.New("el instructor líder no existe o no es instructor")
+)
+
+// FichaFilters define los filtros disponibles para listar fichas
+type FichaFilters struct {
+	ProgramID        *uuid.UUID            `json:"program_id,omitempty"`
+	SedeID           *uuid.UUID            `json:"sede_id,omitempty"`
+	Status           *entities.FichaStatus `json:"status,omitempty"`
+	LeadInstructorID *uuid.UUID            `json:"lead_instructor_id,omitempty"`
+}
+
+// FichaRepository define las operaciones de persistencia para las fichas de formación
+// Los aprendices ingresan a una ficha con UserRepository.AssignFicha y cambian de ficha con
+// UserRepository.TransferFicha; ambos respetan su capacidad y quedan en el historial de movimientos
+// Todas las operaciones respetan el alcance de sede del contexto como UserRepository, según la sede de la ficha:
+// las fichas fuera del alcance, o sin sede, se comportan como inexistentes. El instructor líder no se acota
+type FichaRepository interface {
+	// Create registra una nueva ficha
+	// Retorna ErrDuplicateFicha si el número ya existe, ErrProgramNotFound si el programa no existe,
+	// ErrSedeNotFound si la sede no está registrada y ErrInvalidLeadInstructor si el instructor líder no es un instructor
+	// Con alcance de sede retorna ErrOutsideTenant si la sede está fuera de él
+	Create(ctx context.Context, ficha *entities.Ficha) error
+
+	// GetByNumber obtiene una ficha por su número, retorna nil si no existe
+	GetByNumber(ctx context.Context, number string) (*entities.Ficha, error)
+
+	// Update actualiza una ficha existente, retorna ErrFichaNotFound si no existe
+	// Aplica las mismas validaciones de programa, sede, instructor líder y alcance que Create
+	Update(ctx context.Context, ficha *entities.Ficha) error
+
+	// List obtiene las fichas que cumplen los filtros ordenadas por número
+	List(ctx context.Context, filters FichaFilters) ([]*entities.Ficha, error)
+}
+
+// CheckFichaAssignment verifica que el usuario pueda sumarse a la ficha, que ya tiene members aprendices
+// La ficha debe ser de la misma sede que el aprendiz
+// Las implementaciones de UserRepository.AssignFicha la usan para que todas reporten los mismos errores
+func CheckFichaAssignment(user *entities.User, ficha *entities.Ficha, members int) error {
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !user.IsAprendiz() {
+		return apperrors.Validation(entities.CodeFichaAprendizOnly, "user_id")
+	}
+	if ficha == nil {
+		return ErrFichaNotFound
+	}
+	if !sameSede(user.SedeID, ficha.SedeID) {
+		return ErrFichaOtherSede
+	}
+	if !ficha.AcceptsAprendices() {
+		return ErrFichaClosed
+	}
+	if members >= ficha.Capacity {
+		return ErrFichaFull
+	}
+	return nil
+}
+
+// CheckFichaTransfer verifica el traslado del usuario a la ficha destino, que ya tiene members aprendices,
+// y completa su ficha de origen. last es el último movimiento del aprendiz, nil si no tiene historial
+// Las implementaciones de UserRepository.TransferFicha la usan para que todas reporten los mismos errores
+func CheckFichaTransfer(transfer *entities.FichaMovement, user, approver *entities.User, last *entities.FichaMovement, ficha *entities.Ficha, members int) error {
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !user.IsAprendiz() {
+		return apperrors.Validation(entities.CodeFichaAprendizOnly, "user_id")
+	}
+	if user.FichaID == nil {
+		return ErrNotInFicha
+	}
+
+	from := *user.FichaID
+	transfer.FromFichaID = &from
+	if err := transfer.ValidateTransfer(); err != nil {
+		return err
+	}
+
+	if approver == nil || !approver.CanApproveTransfers() {
+		return ErrInvalidApprover
+	}
+	if last != nil && transfer.EffectiveDate.Before(last.EffectiveDate) {
+		return ErrBackdatedMovement
+	}
+
+	return CheckFichaAssignment(user, ficha, members)
+} // fin CheckFichaTransfer
+
+// sameSede indica si ambas referencias apuntan a la misma sede o ninguna tiene sede
+func sameSede(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
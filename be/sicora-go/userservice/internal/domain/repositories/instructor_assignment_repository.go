@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"userservice/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrAssignmentNotFound indica que la asignación de instructor no existe
+	ErrAssignmentNotFound = errors.New("asignación de instructor no encontrada")
+
+	// ErrOverlappingAssignment indica que el instructor ya está asignado a la misma competencia de la ficha en esas fechas
+	ErrOverlappingAssignment = errors.New("el instructor ya está asignado a la competencia de la ficha en esas fechas")
+
+	// ErrInvalidInstructor indica que el usuario no existe o no tiene rol de instructor
+	ErrInvalidInstructor = errors.New("el usuario no existe o no es instructor")
+)
+
+// InstructorAssignmentRepository define las operaciones de persistencia de las asignaciones de instructores a fichas
+// Las consultas por instructor y por ficha permiten acotar datos según las asignaciones vigentes
+// Todas las operaciones respetan el alcance de sede del contexto según la sede de la ficha: las asignaciones
+// a fichas fuera del alcance se comportan como inexistentes. El instructor no se acota
+type InstructorAssignmentRepository interface {
+	// Create registra una asignación
+	// Retorna ErrInvalidInstructor si el usuario no es un instructor, ErrFichaNotFound si la ficha no existe o está fuera del alcance
+	// y ErrOverlappingAssignment si se cruza con otra asignación del instructor a la misma competencia de la ficha
+	Create(ctx context.Context, assignment *entities.InstructorAssignment) error
+
+	// GetByID obtiene una asignación por su ID, retorna nil si no existe
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.InstructorAssignment, error)
+
+	// End cierra la asignación en endDate, último día en que el instructor está asignado
+	// Retorna ErrAssignmentNotFound si no existe y *entities.DomainError si endDate es anterior al inicio
+	End(ctx context.Context, id uuid.UUID, endDate time.Time) error
+
+	// ListByInstructor obtiene las asignaciones del instructor ordenadas por ficha e inicio
+	// Con activeOn distinto de cero solo retorna las vigentes en esa fecha; en cero, todo el historial
+	ListByInstructor(ctx context.Context, instructorID uuid.UUID, activeOn time.Time) ([]*entities.InstructorAssignment, error)
+
+	// ListByFicha obtiene las asignaciones de la ficha ordenadas por inicio e instructor
+	// Con activeOn distinto de cero solo retorna las vigentes en esa fecha; en cero, todo el historial
+	ListByFicha(ctx context.Context, fichaID string, activeOn time.Time) ([]*entities.InstructorAssignment, error)
+
+	// IsAssigned indica si el instructor tiene alguna asignación vigente en la ficha en la fecha dada
+	IsAssigned(ctx context.Context, instructorID uuid.UUID, fichaID string, on time.Time) (bool, error)
+}
@@ -0,0 +1,17 @@
+// Package tenantctx marca el contexto de los procesos internos del servicio, que ven todas las sedes
+// Es interno de repositories: solo ese paquete y repositorytest pueden marcar un contexto así;
+// el resto del servicio lo acota con repositories.WithTenant o repositories.GrantTenantBypass
+package tenantctx
+
+import "context"
+
+// Key es la llave del alcance de sede en el contexto; repositories guarda en ella su alcance o Internal
+type Key struct{}
+
+// Internal es el valor de Key en el contexto de un proceso interno
+type Internal struct{}
+
+// Without marca el contexto como de un proceso interno del servicio
+func Without(ctx context.Context) context.Context {
+	return context.WithValue(ctx, Key{}, Internal{})
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// MFAMethodRepository define las operaciones de persistencia para los métodos MFA de un usuario
+type MFAMethodRepository interface {
+	// Create registra un nuevo método MFA
+	Create(ctx context.Context, method *entities.UserMFAMethod) error
+
+	// GetByID obtiene un método MFA por su ID, retorna nil si no existe
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.UserMFAMethod, error)
+
+	// GetByUserID obtiene todos los métodos MFA configurados por un usuario
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.UserMFAMethod, error)
+
+	// GetEnabledByUserID obtiene los métodos MFA habilitados de un usuario, el primario primero
+	GetEnabledByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.UserMFAMethod, error)
+
+	// GetPrimaryByUserID obtiene el método MFA primario habilitado, retorna nil si no tiene
+	GetPrimaryByUserID(ctx context.Context, userID uuid.UUID) (*entities.UserMFAMethod, error)
+
+	// GetByUserAndType obtiene el método de un tipo dado ('totp', 'email_otp', ...), retorna nil si no existe
+	GetByUserAndType(ctx context.Context, userID uuid.UUID, methodType string) (*entities.UserMFAMethod, error)
+
+	// Update actualiza un método MFA existente, retorna ErrMFARecordNotFound si no existe
+	Update(ctx context.Context, method *entities.UserMFAMethod) error
+
+	// SetPrimary marca el método como primario y desmarca los demás del usuario
+	// Retorna ErrMFARecordNotFound si el método no pertenece al usuario
+	SetPrimary(ctx context.Context, userID, methodID uuid.UUID) error
+
+	// MarkAsUsed registra el último uso exitoso del método
+	MarkAsUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+
+	// Delete elimina un método MFA
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// DeleteByUserID elimina todos los métodos MFA de un usuario (reset de MFA)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// BackupCodeRepository define las operaciones de persistencia para los códigos de recuperación
+type BackupCodeRepository interface {
+	// CreateBatch guarda un nuevo juego de códigos de recuperación
+	CreateBatch(ctx context.Context, codes []*entities.MFABackupCode) error
+
+	// GetUnusedByUserID obtiene los códigos no usados y no expirados de un usuario
+	GetUnusedByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.MFABackupCode, error)
+
+	// CountUnusedByUserID cuenta los códigos no usados y no expirados de un usuario
+	CountUnusedByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// MarkAsUsed marca un código como usado, retorna ErrMFARecordNotFound si no existe o ya fue usado
+	MarkAsUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+
+	// DeleteByUserID elimina todos los códigos de un usuario (regeneración)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+
+	// DeleteExpired elimina los códigos expirados antes de la fecha dada
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// MFASessionRepository define las operaciones de persistencia para las sesiones de verificación MFA
+type MFASessionRepository interface {
+	// Create registra una nueva sesión de verificación
+	Create(ctx context.Context, session *entities.MFASession) error
+
+	// GetByID obtiene una sesión por su ID, retorna nil si no existe
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.MFASession, error)
+
+	// GetActiveByUserID obtiene la sesión más reciente no verificada y no expirada, retorna nil si no hay
+	GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*entities.MFASession, error)
+
+	// IncrementAttempts suma un intento fallido a la sesión
+	IncrementAttempts(ctx context.Context, id uuid.UUID) error
+
+	// MarkAsVerified marca la sesión como verificada
+	MarkAsVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error
+
+	// DeleteExpired elimina las sesiones expiradas antes de la fecha dada
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// MFAEnforcementPolicyRepository define las operaciones de persistencia para las políticas MFA por rol
+type MFAEnforcementPolicyRepository interface {
+	// Create registra una nueva política
+	Create(ctx context.Context, policy *entities.MFAEnforcementPolicy) error
+
+	// GetByRoleName obtiene la política de un rol, retorna nil si el rol no tiene política
+	GetByRoleName(ctx context.Context, roleName string) (*entities.MFAEnforcementPolicy, error)
+
+	// List obtiene todas las políticas ordenadas por rol
+	List(ctx context.Context) ([]*entities.MFAEnforcementPolicy, error)
+
+	// Update actualiza una política existente, retorna ErrMFARecordNotFound si no existe
+	Update(ctx context.Context, policy *entities.MFAEnforcementPolicy) error
+
+	// Delete elimina una política
+	Delete(ctx context.Context, id uuid.UUID) error
+}
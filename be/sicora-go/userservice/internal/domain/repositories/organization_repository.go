@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"userservice/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrRegionalNotFound indica que la regional no existe
+	ErrRegionalNotFound = errors.New("regional no encontrada")
+
+	// ErrCentroNotFound indica que el centro de formación no existe
+	ErrCentroNotFound = errors.New("centro de formación no encontrado")
+
+	// ErrSedeNotFound indica que la sede no existe
+	ErrSedeNotFound = errors.New("sede no encontrada")
+
+	// ErrDuplicateRegional indica que ya existe una regional con el mismo código
+	ErrDuplicateRegional = errors.New("ya existe una regional con el mismo código")
+
+	// ErrDuplicateCentro indica que ya existe un centro de formación con el mismo código
+	ErrDuplicateCentro = errors.New("ya existe un centro de formación con el mismo código")
+
+	// ErrDuplicateSede indica que el centro ya tiene una sede con el mismo nombre
+	ErrDuplicateSede = errors.New("el centro de formación ya tiene una sede con el mismo nombre")
+
+	// ErrInvalidOrgLevel indica que el nivel de la jerarquía no es regional, centro ni sede
+	ErrInvalidOrgLevel = errors.New("nivel de la jerarquía inválido")
+)
+
+// OrgLevel es un nivel de la jerarquía Regional -> Centro -> Sede
+type OrgLevel string
+
+const (
+	OrgLevelRegional OrgLevel = "regional"
+	OrgLevelCentro   OrgLevel = "centro"
+	OrgLevelSede     OrgLevel = "sede"
+)
+
+// Valid indica si el nivel es uno de los de la jerarquía
+func (l OrgLevel) Valid() bool {
+	return slices.Contains([]OrgLevel{OrgLevelRegional, OrgLevelCentro, OrgLevelSede}, l)
+}
+
+// OrgFilter acota los usuarios a los de una sede, un centro o una regional según su sede
+// Los campos indicados se combinan; los usuarios sin sede solo cumplen el filtro vacío
+type OrgFilter struct {
+	RegionalID *uuid.UUID `json:"regional_id,omitempty"`
+	CentroID   *uuid.UUID `json:"centro_id,omitempty"`
+	SedeID     *uuid.UUID `json:"sede_id,omitempty"`
+}
+
+// IsZero indica si el filtro no acota ningún nivel
+func (f OrgFilter) IsZero() bool {
+	return f.RegionalID == nil && f.CentroID == nil && f.SedeID == nil
+}
+
+// Matches indica si una sede, dada su ruta en la jerarquía, cumple el filtro; path es nil sin sede
+func (f OrgFilter) Matches(path *SedePath) bool {
+	if f.IsZero() {
+		return true
+	}
+	if path == nil {
+		return false
+	}
+	return (f.SedeID == nil || *f.SedeID == path.SedeID) &&
+		(f.CentroID == nil || *f.CentroID == path.CentroID) &&
+		(f.RegionalID == nil || *f.RegionalID == path.RegionalID)
+}
+
+// SedePath es la ubicación de una sede en la jerarquía
+type SedePath struct {
+	SedeID     uuid.UUID
+	CentroID   uuid.UUID
+	RegionalID uuid.UUID
+}
+
+// Key retorna el ID del nodo del nivel dado como texto, TrendKeyNone si path es nil
+func (p *SedePath) Key(level OrgLevel) string {
+	if p == nil {
+		return TrendKeyNone
+	}
+	switch level {
+	case OrgLevelRegional:
+		return p.RegionalID.String()
+	case OrgLevelCentro:
+		return p.CentroID.String()
+	default:
+		return p.SedeID.String()
+	}
+}
+
+// OrganizationRepository define las operaciones de persistencia de la jerarquía Regional -> Centro -> Sede
+// Los usuarios y las fichas solo pueden apuntar a sedes registradas aquí
+// Todas las operaciones respetan el alcance de sede del contexto: una sede se ve si está en el alcance
+// y un centro o una regional si contiene alguna sede del alcance; los demás se comportan como inexistentes
+// Con alcance solo se crean o modifican sedes del alcance: regionales y centros retornan ErrOutsideTenant
+type OrganizationRepository interface {
+	// CreateRegional registra una regional, retorna ErrDuplicateRegional si el código ya existe
+	CreateRegional(ctx context.Context, regional *entities.Regional) error
+
+	// CreateCentro registra un centro de formación
+	// Retorna ErrRegionalNotFound si la regional no existe y ErrDuplicateCentro si el código ya existe
+	CreateCentro(ctx context.Context, centro *entities.Centro) error
+
+	// CreateSede registra una sede
+	// Retorna ErrCentroNotFound si el centro no existe y ErrDuplicateSede si el centro ya tiene una sede con ese nombre
+	// Con alcance de sede retorna ErrOutsideTenant si la nueva sede queda fuera de él
+	CreateSede(ctx context.Context, sede *entities.Sede) error
+
+	// GetRegional obtiene una regional por su ID, retorna nil si no existe
+	GetRegional(ctx context.Context, id uuid.UUID) (*entities.Regional, error)
+
+	// GetCentro obtiene un centro de formación por su ID, retorna nil si no existe
+	GetCentro(ctx context.Context, id uuid.UUID) (*entities.Centro, error)
+
+	// GetSede obtiene una sede por su ID, retorna nil si no existe
+	GetSede(ctx context.Context, id uuid.UUID) (*entities.Sede, error)
+
+	// UpdateRegional actualiza una regional, retorna ErrRegionalNotFound si no existe
+	// y ErrDuplicateRegional si el nuevo código ya lo usa otra regional
+	UpdateRegional(ctx context.Context, regional *entities.Regional) error
+
+	// UpdateCentro actualiza un centro de formación, que puede cambiar de regional
+	// Retorna ErrCentroNotFound si no existe, ErrRegionalNotFound si la regional no existe
+	// y ErrDuplicateCentro si el nuevo código ya lo usa otro centro
+	UpdateCentro(ctx context.Context, centro *entities.Centro) error
+
+	// UpdateSede actualiza una sede, que puede cambiar de centro
+	// Retorna ErrSedeNotFound si no existe, ErrCentroNotFound si el centro no existe
+	// y ErrDuplicateSede si el centro ya tiene otra sede con ese nombre. Con alcance de sede retorna
+	// ErrOutsideTenant si el nuevo centro la saca de él
+	UpdateSede(ctx context.Context, sede *entities.Sede) error
+
+	// ListRegionales obtiene las regionales ordenadas por código; activeOnly excluye las inactivas
+	ListRegionales(ctx context.Context, activeOnly bool) ([]*entities.Regional, error)
+
+	// ListCentros obtiene los centros ordenados por código, solo los de la regional si regionalID no es nil
+	ListCentros(ctx context.Context, regionalID *uuid.UUID, activeOnly bool) ([]*entities.Centro, error)
+
+	// ListSedes obtiene las sedes ordenadas por nombre, solo las del centro si centroID no es nil
+	ListSedes(ctx context.Context, centroID *uuid.UUID, activeOnly bool) ([]*entities.Sede, error)
+
+	// GetSedePath obtiene la ubicación de la sede en la jerarquía, retorna nil si no existe
+	GetSedePath(ctx context.Context, sedeID uuid.UUID) (*SedePath, error)
+}
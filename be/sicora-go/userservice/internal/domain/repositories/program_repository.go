@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"userservice/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrProgramNotFound indica que el programa de formación no existe
+	ErrProgramNotFound = errors.New("programa de formación no encontrado")
+
+	// ErrDuplicateProgram indica que ya existe un programa con el mismo código
+	ErrDuplicateProgram = errors.New("ya existe un programa con el mismo código")
+)
+
+// ProgramRepository define las operaciones de persistencia para los programas de formación
+// Las fichas de un programa se consultan con FichaRepository.List
+type ProgramRepository interface {
+	// Create registra un nuevo programa, retorna ErrDuplicateProgram si el código ya existe
+	Create(ctx context.Context, program *entities.Program) error
+
+	// GetByID obtiene un programa por su ID, retorna nil si no existe
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Program, error)
+
+	// GetByCode obtiene un programa por su código, retorna nil si no existe
+	GetByCode(ctx context.Context, code string) (*entities.Program, error)
+
+	// Update actualiza un programa existente, retorna ErrProgramNotFound si no existe
+	// y ErrDuplicateProgram si el nuevo código ya lo usa otro programa
+	Update(ctx context.Context, program *entities.Program) error
+
+	// List obtiene los programas ordenados por código; activeOnly excluye los inactivos
+	List(ctx context.Context, activeOnly bool) ([]*entities.Program, error)
+}
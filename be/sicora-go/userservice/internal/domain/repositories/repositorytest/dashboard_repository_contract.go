@@ -0,0 +1,141 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// DashboardSnapshotRepositoryFactory crea un repositorio de fotos vacío y aislado para cada caso de prueba
+type DashboardSnapshotRepositoryFactory func(t *testing.T) repositories.DashboardSnapshotRepository
+
+// RunDashboardSnapshotRepositoryContract ejecuta la suite de contrato sobre la implementación dada
+func RunDashboardSnapshotRepositoryContract(t *testing.T, newRepo DashboardSnapshotRepositoryFactory) {
+	t.Run("SaveAndLatest", func(t *testing.T) { testSnapshotSaveAndLatest(t, newRepo(t)) })
+	t.Run("Prune", func(t *testing.T) { testSnapshotPrune(t, newRepo(t)) })
+	t.Run("Tenant", func(t *testing.T) { testSnapshotTenant(t, newRepo(t)) })
+}
+
+// newTestSnapshot construye una foto con celdas en desorden para verificar que se conserva el orden del dominio
+func newTestSnapshot(takenAt time.Time, sede uuid.UUID) *entities.DashboardSnapshot {
+	snapshot := entities.NewDashboardSnapshot(takenAt.Truncate(time.Microsecond))
+	snapshot.Add(string(entities.RoleInstructor), entities.DashboardKeyNone, sede.String(), entities.DashboardStatusActive, 4)
+	snapshot.Add(string(entities.RoleAprendiz), entities.DashboardKeyNone, entities.DashboardKeyNone, entities.DashboardStatusInactive, 2)
+	snapshot.Add(string(entities.RoleAprendiz), entities.DashboardKeyNone, sede.String(), entities.DashboardStatusActive, 30)
+	return snapshot
+}
+
+func testSnapshotSaveAndLatest(t *testing.T, repo repositories.DashboardSnapshotRepository) {
+	ctx := InternalContext()
+
+	if latest, err := repo.Latest(ctx); err != nil || latest != nil {
+		t.Fatalf("Latest sin fotos = %v, %v", latest, err)
+	}
+
+	sede := uuid.New()
+	older := newTestSnapshot(time.Now().Add(-time.Hour), sede)
+	newer := newTestSnapshot(time.Now(), sede)
+	newer.Add(string(entities.RoleAdmin), entities.DashboardKeyNone, entities.DashboardKeyNone, entities.DashboardStatusActive, 1)
+	for _, snapshot := range []*entities.DashboardSnapshot{newer, older} {
+		if err := repo.Save(ctx, snapshot); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	latest, err := repo.Latest(ctx)
+	if err != nil || latest == nil {
+		t.Fatalf("Latest = %v, %v", latest, err)
+	}
+	if latest.ID != newer.ID || !latest.TakenAt.Equal(newer.TakenAt) {
+		t.Errorf("Latest = %s tomada en %v, se esperaba %s tomada en %v", latest.ID, latest.TakenAt, newer.ID, newer.TakenAt)
+	}
+	if len(latest.Cells) != len(newer.Cells) {
+		t.Fatalf("Latest tiene %d celdas, se esperaban %d", len(latest.Cells), len(newer.Cells))
+	}
+	for i, cell := range latest.Cells {
+		if cell != newer.Cells[i] {
+			t.Errorf("celda %d = %+v, se esperaba %+v", i, cell, newer.Cells[i])
+		}
+	}
+
+	// Las consultas del dashboard se responden desde la foto guardada
+	if latest.Total() != 37 || latest.CountBy(entities.DimensionSede)[sede.String()] != 34 {
+		t.Errorf("Total = %d, por sede = %v", latest.Total(), latest.CountBy(entities.DimensionSede))
+	}
+
+	// Modificar la foto obtenida no altera la guardada
+	latest.Cells[0].Count = 1000
+	if again, _ := repo.Latest(ctx); again == nil || again.Cells[0].Count == 1000 {
+		t.Error("Latest debe retornar una copia de la foto")
+	}
+
+	// Una foto sin usuarios también se guarda
+	empty := entities.NewDashboardSnapshot(time.Now().Add(time.Minute).Truncate(time.Microsecond))
+	if err := repo.Save(ctx, empty); err != nil {
+		t.Fatalf("Save(vacía): %v", err)
+	}
+	if latest, err := repo.Latest(ctx); err != nil || latest == nil || latest.ID != empty.ID || len(latest.Cells) != 0 {
+		t.Errorf("Latest tras foto vacía = %+v, %v", latest, err)
+	}
+} // fin testSnapshotSaveAndLatest
+
+func testSnapshotPrune(t *testing.T, repo repositories.DashboardSnapshotRepository) {
+	ctx := InternalContext()
+	sede := uuid.New()
+	now := time.Now()
+
+	snapshots := []*entities.DashboardSnapshot{
+		newTestSnapshot(now.Add(-72*time.Hour), sede),
+		newTestSnapshot(now.Add(-48*time.Hour), sede),
+		newTestSnapshot(now.Add(-time.Hour), sede),
+	}
+	for _, snapshot := range snapshots {
+		if err := repo.Save(ctx, snapshot); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	if pruned, err := repo.Prune(ctx, now.Add(-24*time.Hour)); err != nil || pruned != 2 {
+		t.Errorf("Prune(24h) = %d, %v; se esperaban 2", pruned, err)
+	}
+
+	// La foto más reciente se conserva aunque sea anterior a la fecha
+	if pruned, err := repo.Prune(ctx, now); err != nil || pruned != 0 {
+		t.Errorf("Prune(ahora) = %d, %v; se esperaba 0", pruned, err)
+	}
+	if latest, err := repo.Latest(ctx); err != nil || latest == nil || latest.ID != snapshots[2].ID || len(latest.Cells) != 3 {
+		t.Errorf("Latest tras Prune = %+v, %v", latest, err)
+	}
+} // fin testSnapshotPrune
+
+// testSnapshotTenant verifica que la foto nacional no llegue a un contexto acotado a una sede ni a uno sin alcance
+func testSnapshotTenant(t *testing.T, repo repositories.DashboardSnapshotRepository) {
+	sede := uuid.New()
+	if err := repo.Save(InternalContext(), newTestSnapshot(time.Now(), sede)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		want error
+	}{
+		"con alcance": {repositories.WithTenant(context.Background(), repositories.OrgFilter{SedeID: &sede}), repositories.ErrOutsideTenant},
+		"sin alcance": {context.Background(), repositories.ErrNoTenant},
+	} {
+		if latest, err := repo.Latest(tc.ctx); !errors.Is(err, tc.want) || latest != nil {
+			t.Errorf("Latest %s = %v, %v; se esperaba %v", name, latest, err, tc.want)
+		}
+		if err := repo.Save(tc.ctx, newTestSnapshot(time.Now(), sede)); !errors.Is(err, tc.want) {
+			t.Errorf("Save %s: se esperaba %v, se obtuvo %v", name, tc.want, err)
+		}
+		if _, err := repo.Prune(tc.ctx, time.Now().Add(time.Hour)); !errors.Is(err, tc.want) {
+			t.Errorf("Prune %s: se esperaba %v, se obtuvo %v", name, tc.want, err)
+		}
+	}
+} // fin testSnapshotTenant
@@ -0,0 +1,426 @@
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RunFichaRepositoryContract ejecuta la suite de contrato sobre la implementación dada
+// Verifica también GetByFicha, AssignFicha y TransferFicha de los usuarios, que dependen de las fichas existentes
+func RunFichaRepositoryContract(t *testing.T, newRepos RepositoriesFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) { testFichaCreateAndGet(t, newRepos(t)) })
+	t.Run("UpdateLifecycle", func(t *testing.T) { testFichaUpdateLifecycle(t, newRepos(t)) })
+	t.Run("List", func(t *testing.T) { testFichaList(t, newRepos(t)) })
+	t.Run("GetByFicha", func(t *testing.T) { testGetByFicha(t, newRepos(t)) })
+	t.Run("AssignFicha", func(t *testing.T) { testAssignFicha(t, newRepos(t)) })
+	t.Run("TransferFicha", func(t *testing.T) { testTransferFicha(t, newRepos(t)) })
+	t.Run("FichaHistory", func(t *testing.T) { testFichaHistory(t, newRepos(t)) })
+}
+
+// newTestFicha construye una ficha programada de un año en jornada diurna
+func newTestFicha(t *testing.T, number string, programID uuid.UUID, capacity int) *entities.Ficha {
+	t.Helper()
+
+	start := time.Date(2026, time.February, 2, 0, 0, 0, 0, time.UTC)
+	ficha, err := entities.NewFicha(number, programID, entities.JornadaDiurna, start, start.AddDate(1, 0, 0), capacity)
+	if err != nil {
+		t.Fatalf("ficha de prueba inválida: %v", err)
+	}
+
+	ficha.CreatedAt = ficha.CreatedAt.Truncate(time.Microsecond)
+	ficha.UpdatedAt = ficha.CreatedAt
+	return ficha
+}
+
+func mustCreateFichas(t *testing.T, repo repositories.FichaRepository, fichas ...*entities.Ficha) {
+	t.Helper()
+	for _, ficha := range fichas {
+		if err := repo.Create(InternalContext(), ficha); err != nil {
+			t.Fatalf("Create(%s): %v", ficha.Number, err)
+		}
+	}
+}
+
+func mustGetFicha(t *testing.T, repo repositories.FichaRepository, number string) *entities.Ficha {
+	t.Helper()
+	ficha, err := repo.GetByNumber(InternalContext(), number)
+	if err != nil {
+		t.Fatalf("GetByNumber: %v", err)
+	}
+	if ficha == nil {
+		t.Fatalf("GetByNumber(%s): ficha no encontrada", number)
+	}
+	return ficha
+}
+
+func testFichaCreateAndGet(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repos.Programs, program)
+	instructor := NewTestUser(t, 1, "Marta", "Díaz", entities.RoleInstructor)
+	aprendiz := NewTestUser(t, 2, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repos.Users, instructor, aprendiz)
+
+	sede := mustCreateSedePath(t, repos.Organization, "11").SedeID
+	ficha := newTestFicha(t, "2558104", program.ID, 30)
+	ficha.SedeID = &sede
+	ficha.LeadInstructorID = &instructor.ID
+	mustCreateFichas(t, repos.Fichas, ficha)
+
+	got := mustGetFicha(t, repos.Fichas, "2558104")
+	if got.ID != ficha.ID || got.ProgramID != program.ID || got.Jornada != entities.JornadaDiurna ||
+		got.Status != entities.FichaStatusPlanned || got.Capacity != 30 {
+		t.Errorf("GetByNumber = %+v, se esperaba %+v", got, ficha)
+	}
+	if !got.StartDate.Equal(ficha.StartDate) || !got.EndDate.Equal(ficha.EndDate) {
+		t.Errorf("GetByNumber: fechas %v - %v, se esperaban %v - %v", got.StartDate, got.EndDate, ficha.StartDate, ficha.EndDate)
+	}
+	if got.SedeID == nil || *got.SedeID != sede || got.LeadInstructorID == nil || *got.LeadInstructorID != instructor.ID {
+		t.Errorf("GetByNumber: sede %v e instructor líder %v", got.SedeID, got.LeadInstructorID)
+	}
+	if got, err := repos.Fichas.GetByNumber(ctx, "9999999"); err != nil || got != nil {
+		t.Errorf("GetByNumber inexistente = %v, %v", got, err)
+	}
+
+	duplicate := newTestFicha(t, "2558104", program.ID, 30)
+	if err := repos.Fichas.Create(ctx, duplicate); !errors.Is(err, repositories.ErrDuplicateFicha) {
+		t.Errorf("Create con número repetido: se esperaba ErrDuplicateFicha, se obtuvo %v", err)
+	}
+
+	orphan := newTestFicha(t, "2558105", uuid.New(), 30)
+	if err := repos.Fichas.Create(ctx, orphan); !errors.Is(err, repositories.ErrProgramNotFound) {
+		t.Errorf("Create sin programa: se esperaba ErrProgramNotFound, se obtuvo %v", err)
+	}
+
+	led := newTestFicha(t, "2558106", program.ID, 30)
+	led.LeadInstructorID = &aprendiz.ID
+	if err := repos.Fichas.Create(ctx, led); !errors.Is(err, repositories.ErrInvalidLeadInstructor) {
+		t.Errorf("Create con aprendiz como líder: se esperaba ErrInvalidLeadInstructor, se obtuvo %v", err)
+	}
+} // fin testFichaCreateAndGet
+
+func testFichaUpdateLifecycle(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repos.Programs, program)
+	mustCreateFichas(t, repos.Fichas, newTestFicha(t, "2558104", program.ID, 30))
+
+	ficha := mustGetFicha(t, repos.Fichas, "2558104")
+	if err := ficha.Finish(); err == nil {
+		t.Errorf("Finish de una ficha programada: se esperaba error")
+	}
+	if err := ficha.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	ficha.Capacity = 25
+	ficha.Jornada = entities.JornadaNocturna
+	ficha.Number = "2558199" // El número no cambia
+	if err := repos.Fichas.Update(ctx, ficha); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got := mustGetFicha(t, repos.Fichas, "2558104")
+	if got.Status != entities.FichaStatusInProgress || got.Capacity != 25 || got.Jornada != entities.JornadaNocturna {
+		t.Errorf("Update: se obtuvo %+v", got)
+	}
+	if err := got.Cancel(); err != nil || got.AcceptsAprendices() {
+		t.Errorf("Cancel de una ficha en formación = %v, admite aprendices: %v", err, got.AcceptsAprendices())
+	}
+
+	missing := newTestFicha(t, "2558105", program.ID, 30)
+	if err := repos.Fichas.Update(ctx, missing); !errors.Is(err, repositories.ErrFichaNotFound) {
+		t.Errorf("Update inexistente: se esperaba ErrFichaNotFound, se obtuvo %v", err)
+	}
+} // fin testFichaUpdateLifecycle
+
+func testFichaList(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+
+	software := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	redes := newTestProgram(t, "233104", "Gestión de Redes de Datos")
+	mustCreatePrograms(t, repos.Programs, software, redes)
+	instructor := NewTestUser(t, 1, "Marta", "Díaz", entities.RoleInstructor)
+	mustCreate(t, repos.Users, instructor)
+
+	sede := mustCreateSedePath(t, repos.Organization, "11").SedeID
+	led := newTestFicha(t, "2558106", software.ID, 30)
+	led.LeadInstructorID = &instructor.ID
+	led.SedeID = &sede
+	started := newTestFicha(t, "2558104", software.ID, 30)
+	started.Status = entities.FichaStatusInProgress
+	mustCreateFichas(t, repos.Fichas, led, started, newTestFicha(t, "2558105", redes.ID, 30))
+
+	inProgress := entities.FichaStatusInProgress
+	cases := []struct {
+		name    string
+		filters repositories.FichaFilters
+		want    []string
+	}{
+		{"todas", repositories.FichaFilters{}, []string{"2558104", "2558105", "2558106"}},
+		{"programa", repositories.FichaFilters{ProgramID: &software.ID}, []string{"2558104", "2558106"}},
+		{"sede", repositories.FichaFilters{SedeID: &sede}, []string{"2558106"}},
+		{"estado", repositories.FichaFilters{Status: &inProgress}, []string{"2558104"}},
+		{"instructor líder", repositories.FichaFilters{LeadInstructorID: &instructor.ID}, []string{"2558106"}},
+	}
+	for _, tc := range cases {
+		fichas, err := repos.Fichas.List(ctx, tc.filters)
+		if err != nil {
+			t.Fatalf("List %s: %v", tc.name, err)
+		}
+		assertFichaNumbers(t, "List "+tc.name, fichas, tc.want)
+	}
+} // fin testFichaList
+
+func testGetByFicha(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	ficha := "2558104"
+	other := "2558105"
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repos.Programs, program)
+	mustCreateFichas(t, repos.Fichas, newTestFicha(t, ficha, program.ID, 30), newTestFicha(t, other, program.ID, 30))
+
+	first := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	first.FichaID = &ficha
+	second := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	second.FichaID = &ficha
+	elsewhere := NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleAprendiz)
+	elsewhere.FichaID = &other
+	instructor := NewTestUser(t, 4, "Marta", "Díaz", entities.RoleInstructor)
+	instructor.FichaID = &ficha
+	mustCreate(t, repos.Users, first, second, elsewhere, instructor)
+
+	users, err := repos.Users.GetByFicha(ctx, ficha, time.Time{})
+	if err != nil {
+		t.Fatalf("GetByFicha: %v", err)
+	}
+	assertEmails(t, "GetByFicha", users, []entities.Email{first.Email, second.Email})
+
+	if _, err := repos.Users.GetByFicha(ctx, "9999999", time.Time{}); !errors.Is(err, repositories.ErrFichaNotFound) {
+		t.Errorf("GetByFicha inexistente: se esperaba ErrFichaNotFound, se obtuvo %v", err)
+	}
+} // fin testGetByFicha
+
+func testAssignFicha(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repos.Programs, program)
+	closed := newTestFicha(t, "2558106", program.ID, 30)
+	closed.Status = entities.FichaStatusFinished
+	mustCreateFichas(t, repos.Fichas,
+		newTestFicha(t, "2558104", program.ID, 2),
+		newTestFicha(t, "2558105", program.ID, 30),
+		closed)
+
+	aprendices := make([]*entities.User, 4)
+	for i := range aprendices {
+		aprendices[i] = NewTestUser(t, i+1, "Ana", "Gómez", entities.RoleAprendiz)
+	}
+	instructor := NewTestUser(t, 10, "Marta", "Díaz", entities.RoleInstructor)
+	coordinador := NewTestUser(t, 11, "Jorge", "Mora", entities.RoleCoordinador)
+	mustCreate(t, repos.Users, append(aprendices, instructor, coordinador)...)
+
+	for _, aprendiz := range aprendices[:2] {
+		if err := repos.Users.AssignFicha(ctx, aprendiz.ID, "2558104"); err != nil {
+			t.Fatalf("AssignFicha: %v", err)
+		}
+	}
+	assigned := mustGet(t, repos.Users, aprendices[0].ID)
+	if assigned.FichaID == nil || *assigned.FichaID != "2558104" || assigned.Version != aprendices[0].Version+1 {
+		t.Errorf("AssignFicha: ficha %v, versión %d", assigned.FichaID, assigned.Version)
+	}
+
+	// Reasignar la ficha actual no ocupa otro cupo
+	if err := repos.Users.AssignFicha(ctx, aprendices[0].ID, "2558104"); err != nil {
+		t.Errorf("AssignFicha a la ficha actual: %v", err)
+	}
+	if err := repos.Users.AssignFicha(ctx, aprendices[2].ID, "2558104"); !errors.Is(err, repositories.ErrFichaFull) {
+		t.Errorf("AssignFicha sin cupos: se esperaba ErrFichaFull, se obtuvo %v", err)
+	}
+	if err := repos.Users.AssignFicha(ctx, aprendices[1].ID, "2558105"); !errors.Is(err, repositories.ErrAlreadyInFicha) {
+		t.Errorf("AssignFicha a otra ficha: se esperaba ErrAlreadyInFicha, se obtuvo %v", err)
+	}
+
+	// Trasladar a un aprendiz libera su cupo
+	transfer, err := entities.NewFichaTransfer(aprendices[1].ID, "2558105", "Cambio de jornada", coordinador.ID, time.Now())
+	if err != nil {
+		t.Fatalf("NewFichaTransfer: %v", err)
+	}
+	if err := repos.Users.TransferFicha(ctx, transfer); err != nil {
+		t.Fatalf("TransferFicha: %v", err)
+	}
+	if err := repos.Users.AssignFicha(ctx, aprendices[2].ID, "2558104"); err != nil {
+		t.Errorf("AssignFicha tras liberar un cupo: %v", err)
+	}
+
+	if err := repos.Users.AssignFicha(ctx, aprendices[3].ID, "2558106"); !errors.Is(err, repositories.ErrFichaClosed) {
+		t.Errorf("AssignFicha a ficha terminada: se esperaba ErrFichaClosed, se obtuvo %v", err)
+	}
+	if err := repos.Users.AssignFicha(ctx, aprendices[3].ID, "9999999"); !errors.Is(err, repositories.ErrFichaNotFound) {
+		t.Errorf("AssignFicha a ficha inexistente: se esperaba ErrFichaNotFound, se obtuvo %v", err)
+	}
+	if err := repos.Users.AssignFicha(ctx, uuid.New(), "2558105"); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("AssignFicha de usuario inexistente: se esperaba ErrUserNotFound, se obtuvo %v", err)
+	}
+	var domainErr *entities.DomainError
+	if err := repos.Users.AssignFicha(ctx, instructor.ID, "2558105"); !errors.As(err, &domainErr) {
+		t.Errorf("AssignFicha de un instructor: se esperaba DomainError, se obtuvo %v", err)
+	}
+} // fin testAssignFicha
+
+func testTransferFicha(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	today := time.Now()
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repos.Programs, program)
+	mustCreateFichas(t, repos.Fichas,
+		newTestFicha(t, "2558104", program.ID, 30),
+		newTestFicha(t, "2558105", program.ID, 1))
+
+	aprendiz := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	other := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	unassigned := NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleAprendiz)
+	instructor := NewTestUser(t, 10, "Marta", "Díaz", entities.RoleInstructor)
+	coordinador := NewTestUser(t, 11, "Jorge", "Mora", entities.RoleCoordinador)
+	mustCreate(t, repos.Users, aprendiz, other, unassigned, instructor, coordinador)
+	for _, user := range []*entities.User{aprendiz, other} {
+		if err := repos.Users.AssignFicha(ctx, user.ID, "2558104"); err != nil {
+			t.Fatalf("AssignFicha: %v", err)
+		}
+	}
+
+	newTransfer := func(userID uuid.UUID, to string, approvedBy uuid.UUID, effective time.Time) *entities.FichaMovement {
+		t.Helper()
+		transfer, err := entities.NewFichaTransfer(userID, to, "Cambio de jornada por trabajo", approvedBy, effective)
+		if err != nil {
+			t.Fatalf("NewFichaTransfer: %v", err)
+		}
+		return transfer
+	}
+
+	var domainErr *entities.DomainError
+	cases := []struct {
+		name     string
+		transfer *entities.FichaMovement
+		check    func(error) bool
+	}{
+		{"sin ficha", newTransfer(unassigned.ID, "2558105", coordinador.ID, today), isErr(repositories.ErrNotInFicha)},
+		{"a la misma ficha", newTransfer(aprendiz.ID, "2558104", coordinador.ID, today), func(err error) bool { return errors.As(err, &domainErr) }},
+		{"aprobado por instructor", newTransfer(aprendiz.ID, "2558105", instructor.ID, today), isErr(repositories.ErrInvalidApprover)},
+		{"aprobador inexistente", newTransfer(aprendiz.ID, "2558105", uuid.New(), today), isErr(repositories.ErrInvalidApprover)},
+		{"antes del ingreso", newTransfer(aprendiz.ID, "2558105", coordinador.ID, today.AddDate(0, 0, -1)), isErr(repositories.ErrBackdatedMovement)},
+		{"a ficha inexistente", newTransfer(aprendiz.ID, "9999999", coordinador.ID, today), isErr(repositories.ErrFichaNotFound)},
+		{"usuario inexistente", newTransfer(uuid.New(), "2558105", coordinador.ID, today), isErr(repositories.ErrUserNotFound)},
+	}
+	for _, tc := range cases {
+		if err := repos.Users.TransferFicha(ctx, tc.transfer); !tc.check(err) {
+			t.Errorf("TransferFicha %s: error inesperado %v", tc.name, err)
+		}
+	}
+
+	if err := repos.Users.TransferFicha(ctx, newTransfer(aprendiz.ID, "2558105", coordinador.ID, today)); err != nil {
+		t.Fatalf("TransferFicha: %v", err)
+	}
+	moved := mustGet(t, repos.Users, aprendiz.ID)
+	if moved.FichaID == nil || *moved.FichaID != "2558105" || moved.Version != aprendiz.Version+2 {
+		t.Errorf("TransferFicha: ficha %v, versión %d", moved.FichaID, moved.Version)
+	}
+
+	if err := repos.Users.TransferFicha(ctx, newTransfer(other.ID, "2558105", coordinador.ID, today)); !errors.Is(err, repositories.ErrFichaFull) {
+		t.Errorf("TransferFicha sin cupos: se esperaba ErrFichaFull, se obtuvo %v", err)
+	}
+} // fin testTransferFicha
+
+func testFichaHistory(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	today := entities.CivilDate(time.Now())
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repos.Programs, program)
+	mustCreateFichas(t, repos.Fichas, newTestFicha(t, "2558104", program.ID, 30), newTestFicha(t, "2558105", program.ID, 30))
+
+	aprendiz := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	stays := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	coordinador := NewTestUser(t, 11, "Jorge", "Mora", entities.RoleCoordinador)
+	mustCreate(t, repos.Users, aprendiz, stays, coordinador)
+	for _, user := range []*entities.User{aprendiz, stays} {
+		if err := repos.Users.AssignFicha(ctx, user.ID, "2558104"); err != nil {
+			t.Fatalf("AssignFicha: %v", err)
+		}
+	}
+
+	transfer, err := entities.NewFichaTransfer(aprendiz.ID, "2558105", "Cambio de jornada por trabajo", coordinador.ID, today.AddDate(0, 0, 10))
+	if err != nil {
+		t.Fatalf("NewFichaTransfer: %v", err)
+	}
+	if err := repos.Users.TransferFicha(ctx, transfer); err != nil {
+		t.Fatalf("TransferFicha: %v", err)
+	}
+
+	history, err := repos.Users.GetFichaHistory(ctx, aprendiz.ID)
+	if err != nil {
+		t.Fatalf("GetFichaHistory: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("GetFichaHistory: %d movimientos, se esperaban 2", len(history))
+	}
+	if enrollment := history[0]; enrollment.IsTransfer() || enrollment.ToFichaID != "2558104" || !enrollment.EffectiveDate.Equal(today) {
+		t.Errorf("ingreso = %+v", enrollment)
+	}
+	last := history[1]
+	if !last.IsTransfer() || *last.FromFichaID != "2558104" || last.ToFichaID != "2558105" ||
+		last.ApprovedBy == nil || *last.ApprovedBy != coordinador.ID || last.Reason != "Cambio de jornada por trabajo" ||
+		!last.EffectiveDate.Equal(today.AddDate(0, 0, 10)) {
+		t.Errorf("traslado = %+v", last)
+	}
+
+	// Quién estaba en cada ficha en cada fecha
+	for _, tc := range []struct {
+		ficha string
+		on    time.Time
+		want  []entities.Email
+	}{
+		{"2558104", today.AddDate(0, 0, -1), nil},
+		{"2558104", today.AddDate(0, 0, 5), []entities.Email{aprendiz.Email, stays.Email}},
+		{"2558105", today.AddDate(0, 0, 5), nil},
+		{"2558104", today.AddDate(0, 0, 15), []entities.Email{stays.Email}},
+		{"2558105", today.AddDate(0, 0, 10), []entities.Email{aprendiz.Email}},
+	} {
+		users, err := repos.Users.GetByFicha(ctx, tc.ficha, tc.on)
+		if err != nil {
+			t.Fatalf("GetByFicha: %v", err)
+		}
+		assertEmails(t, "GetByFicha "+tc.ficha+" "+tc.on.Format(time.DateOnly), users, tc.want)
+	}
+
+	if _, err := repos.Users.GetFichaHistory(ctx, uuid.New()); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("GetFichaHistory de usuario inexistente: se esperaba ErrUserNotFound, se obtuvo %v", err)
+	}
+} // fin testFichaHistory
+
+// isErr retorna un verificador de errors.Is para tablas de casos
+func isErr(target error) func(error) bool {
+	return func(err error) bool { return errors.Is(err, target) }
+}
+
+func assertFichaNumbers(t *testing.T, name string, fichas []*entities.Ficha, want []string) {
+	t.Helper()
+	if len(fichas) != len(want) {
+		t.Fatalf("%s: %d fichas, se esperaban %d", name, len(fichas), len(want))
+	}
+	for i, ficha := range fichas {
+		if ficha.Number != want[i] {
+			t.Errorf("%s[%d] = %s, se esperaba %s", name, i, ficha.Number, want[i])
+		}
+	}
+}
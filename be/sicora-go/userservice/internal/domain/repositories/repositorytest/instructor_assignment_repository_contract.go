@@ -0,0 +1,195 @@
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RunInstructorAssignmentRepositoryContract ejecuta la suite de contrato sobre la implementación dada
+func RunInstructorAssignmentRepositoryContract(t *testing.T, newRepos RepositoriesFactory) {
+	t.Run("Create", func(t *testing.T) { testAssignmentCreate(t, newRepos(t)) })
+	t.Run("End", func(t *testing.T) { testAssignmentEnd(t, newRepos(t)) })
+	t.Run("Queries", func(t *testing.T) { testAssignmentQueries(t, newRepos(t)) })
+}
+
+// assignmentFixture crea un programa, las fichas dadas y un instructor para las pruebas de asignaciones
+func assignmentFixture(t *testing.T, repos Repositories, fichas ...string) *entities.User {
+	t.Helper()
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repos.Programs, program)
+	for _, number := range fichas {
+		mustCreateFichas(t, repos.Fichas, newTestFicha(t, number, program.ID, 30))
+	}
+
+	instructor := NewTestUser(t, 1, "Marta", "Díaz", entities.RoleInstructor)
+	mustCreate(t, repos.Users, instructor)
+	return instructor
+}
+
+func newTestAssignment(t *testing.T, instructorID uuid.UUID, fichaID, competency string, start time.Time) *entities.InstructorAssignment {
+	t.Helper()
+
+	assignment, err := entities.NewInstructorAssignment(instructorID, fichaID, entities.AssignmentRoleTecnico, competency, start)
+	if err != nil {
+		t.Fatalf("asignación de prueba inválida: %v", err)
+	}
+	assignment.CreatedAt = assignment.CreatedAt.Truncate(time.Microsecond)
+	return assignment
+}
+
+func mustCreateAssignments(t *testing.T, repo repositories.InstructorAssignmentRepository, assignments ...*entities.InstructorAssignment) {
+	t.Helper()
+	for _, assignment := range assignments {
+		if err := repo.Create(InternalContext(), assignment); err != nil {
+			t.Fatalf("Create(%s, %s): %v", assignment.FichaID, assignment.Competency, err)
+		}
+	}
+}
+
+// day construye la fecha del día dado de 2026
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func testAssignmentCreate(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	instructor := assignmentFixture(t, repos, "2558104")
+	aprendiz := NewTestUser(t, 2, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repos.Users, aprendiz)
+
+	programming := newTestAssignment(t, instructor.ID, "2558104", "Programación", day(time.February, 2))
+	end := day(time.June, 30)
+	programming.EndDate = &end
+	mustCreateAssignments(t, repos.Assignments, programming)
+
+	got, err := repos.Assignments.GetByID(ctx, programming.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetByID = %v, %v", got, err)
+	}
+	if got.InstructorID != instructor.ID || got.FichaID != "2558104" || got.Role != entities.AssignmentRoleTecnico ||
+		got.Competency != "Programación" || !got.StartDate.Equal(programming.StartDate) || got.EndDate == nil || !got.EndDate.Equal(end) {
+		t.Errorf("GetByID = %+v, se esperaba %+v", got, programming)
+	}
+	if got, err := repos.Assignments.GetByID(ctx, uuid.New()); err != nil || got != nil {
+		t.Errorf("GetByID inexistente = %v, %v", got, err)
+	}
+
+	// El último día de la asignación cuenta: empezar ese día se cruza, empezar al siguiente no
+	overlapping := newTestAssignment(t, instructor.ID, "2558104", "Programación", end)
+	if err := repos.Assignments.Create(ctx, overlapping); !errors.Is(err, repositories.ErrOverlappingAssignment) {
+		t.Errorf("Create cruzada: se esperaba ErrOverlappingAssignment, se obtuvo %v", err)
+	}
+	mustCreateAssignments(t, repos.Assignments,
+		newTestAssignment(t, instructor.ID, "2558104", "Programación", end.AddDate(0, 0, 1)),
+		newTestAssignment(t, instructor.ID, "2558104", "Bases de datos", day(time.March, 1)))
+
+	// Una asignación abierta que empieza antes se cruza con todas las posteriores
+	earlier := newTestAssignment(t, instructor.ID, "2558104", "Programación", day(time.January, 5))
+	if err := repos.Assignments.Create(ctx, earlier); !errors.Is(err, repositories.ErrOverlappingAssignment) {
+		t.Errorf("Create abierta antes de otra: se esperaba ErrOverlappingAssignment, se obtuvo %v", err)
+	}
+
+	if err := repos.Assignments.Create(ctx, newTestAssignment(t, aprendiz.ID, "2558104", "", day(time.March, 1))); !errors.Is(err, repositories.ErrInvalidInstructor) {
+		t.Errorf("Create de un aprendiz: se esperaba ErrInvalidInstructor, se obtuvo %v", err)
+	}
+	if err := repos.Assignments.Create(ctx, newTestAssignment(t, uuid.New(), "2558104", "", day(time.March, 1))); !errors.Is(err, repositories.ErrInvalidInstructor) {
+		t.Errorf("Create de un usuario inexistente: se esperaba ErrInvalidInstructor, se obtuvo %v", err)
+	}
+	if err := repos.Assignments.Create(ctx, newTestAssignment(t, instructor.ID, "9999999", "", day(time.March, 1))); !errors.Is(err, repositories.ErrFichaNotFound) {
+		t.Errorf("Create en ficha inexistente: se esperaba ErrFichaNotFound, se obtuvo %v", err)
+	}
+} // fin testAssignmentCreate
+
+func testAssignmentEnd(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	instructor := assignmentFixture(t, repos, "2558104")
+
+	assignment := newTestAssignment(t, instructor.ID, "2558104", "", day(time.February, 2))
+	mustCreateAssignments(t, repos.Assignments, assignment)
+
+	var domainErr *entities.DomainError
+	if err := repos.Assignments.End(ctx, assignment.ID, day(time.February, 1)); !errors.As(err, &domainErr) {
+		t.Errorf("End antes del inicio: se esperaba DomainError, se obtuvo %v", err)
+	}
+	if err := repos.Assignments.End(ctx, uuid.New(), day(time.March, 1)); !errors.Is(err, repositories.ErrAssignmentNotFound) {
+		t.Errorf("End inexistente: se esperaba ErrAssignmentNotFound, se obtuvo %v", err)
+	}
+
+	// La hora se descarta: la asignación termina el día dado
+	if err := repos.Assignments.End(ctx, assignment.ID, day(time.March, 1).Add(15*time.Hour)); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	got, _ := repos.Assignments.GetByID(ctx, assignment.ID)
+	if got == nil || got.EndDate == nil || !got.EndDate.Equal(day(time.March, 1)) {
+		t.Errorf("End: se obtuvo %+v", got)
+	}
+
+	for on, want := range map[time.Time]bool{day(time.March, 1): true, day(time.March, 2): false} {
+		if assigned, err := repos.Assignments.IsAssigned(ctx, instructor.ID, "2558104", on); err != nil || assigned != want {
+			t.Errorf("IsAssigned el %s = %v, %v; se esperaba %v", on.Format(time.DateOnly), assigned, err, want)
+		}
+	}
+} // fin testAssignmentEnd
+
+func testAssignmentQueries(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	instructor := assignmentFixture(t, repos, "2558104", "2558105")
+	other := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleInstructor)
+	mustCreate(t, repos.Users, other)
+
+	ended := newTestAssignment(t, instructor.ID, "2558104", "Programación", day(time.February, 2))
+	end := day(time.April, 30)
+	ended.EndDate = &end
+	current := newTestAssignment(t, instructor.ID, "2558105", "", day(time.March, 1))
+	english := newTestAssignment(t, other.ID, "2558104", "Inglés", day(time.February, 9))
+	english.Role = entities.AssignmentRoleTransversal
+	mustCreateAssignments(t, repos.Assignments, current, ended, english)
+
+	cases := []struct {
+		name string
+		list func() ([]*entities.InstructorAssignment, error)
+		want []uuid.UUID
+	}{
+		{"fichas del instructor", func() ([]*entities.InstructorAssignment, error) {
+			return repos.Assignments.ListByInstructor(ctx, instructor.ID, time.Time{})
+		}, []uuid.UUID{ended.ID, current.ID}},
+		{"fichas vigentes del instructor", func() ([]*entities.InstructorAssignment, error) {
+			return repos.Assignments.ListByInstructor(ctx, instructor.ID, day(time.May, 4))
+		}, []uuid.UUID{current.ID}},
+		{"instructores de la ficha", func() ([]*entities.InstructorAssignment, error) {
+			return repos.Assignments.ListByFicha(ctx, "2558104", time.Time{})
+		}, []uuid.UUID{ended.ID, english.ID}},
+		{"instructores vigentes de la ficha", func() ([]*entities.InstructorAssignment, error) {
+			return repos.Assignments.ListByFicha(ctx, "2558104", day(time.February, 5))
+		}, []uuid.UUID{ended.ID}},
+		{"ficha sin asignaciones", func() ([]*entities.InstructorAssignment, error) {
+			return repos.Assignments.ListByFicha(ctx, "9999999", time.Time{})
+		}, []uuid.UUID{}},
+	}
+	for _, tc := range cases {
+		assignments, err := tc.list()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(assignments) != len(tc.want) {
+			t.Errorf("%s: %d asignaciones, se esperaban %d", tc.name, len(assignments), len(tc.want))
+			continue
+		}
+		for i, assignment := range assignments {
+			if assignment.ID != tc.want[i] {
+				t.Errorf("%s[%d] = %s (%s), se esperaba %s", tc.name, i, assignment.ID, assignment.FichaID, tc.want[i])
+			}
+		}
+	}
+
+	if assigned, err := repos.Assignments.IsAssigned(ctx, other.ID, "2558105", day(time.May, 4)); err != nil || assigned {
+		t.Errorf("IsAssigned sin asignación = %v, %v", assigned, err)
+	}
+} // fin testAssignmentQueries
@@ -0,0 +1,481 @@
+package repositorytest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RunMFARepositoryContract ejecuta la suite de contrato de los repositorios MFA sobre la implementación dada
+// Los métodos, códigos y sesiones pertenecen a usuarios creados con Users, que al purgarse los eliminan
+func RunMFARepositoryContract(t *testing.T, newRepos RepositoriesFactory) {
+	t.Run("Methods", func(t *testing.T) { testMFAMethods(t, newRepos(t)) })
+	t.Run("MethodsPrimary", func(t *testing.T) { testMFAMethodsPrimary(t, newRepos(t)) })
+	t.Run("BackupCodes", func(t *testing.T) { testMFABackupCodes(t, newRepos(t)) })
+	t.Run("Sessions", func(t *testing.T) { testMFASessions(t, newRepos(t)) })
+	t.Run("Policies", func(t *testing.T) { testMFAPolicies(t, newRepos(t).MFAPolicies) })
+	t.Run("PurgeCascade", func(t *testing.T) { testMFAPurgeCascade(t, newRepos(t)) })
+}
+
+// mustCreateMFAMethods registra los métodos y verifica que se les asignó ID
+func mustCreateMFAMethods(t *testing.T, repo repositories.MFAMethodRepository, methods ...*entities.UserMFAMethod) {
+	t.Helper()
+	for _, method := range methods {
+		if err := repo.Create(InternalContext(), method); err != nil {
+			t.Fatalf("Create(%s): %v", method.MethodType, err)
+		}
+		if method.ID == uuid.Nil {
+			t.Fatalf("Create(%s) no asignó ID", method.MethodType)
+		}
+	}
+}
+
+func testMFAMethods(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	repo := repos.MFAMethods
+
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleInstructor)
+	other := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleInstructor)
+	mustCreate(t, repos.Users, user, other)
+
+	// Un método deshabilitado se guarda deshabilitado
+	email := "ana@sena.edu.co"
+	totp := &entities.UserMFAMethod{UserID: user.ID, MethodType: "totp", IsEnabled: true}
+	emailOTP := &entities.UserMFAMethod{UserID: user.ID, MethodType: "email_otp", EmailAddress: &email}
+	otherTOTP := &entities.UserMFAMethod{UserID: other.ID, MethodType: "totp", IsEnabled: true}
+	mustCreateMFAMethods(t, repo, totp, emailOTP, otherTOTP)
+
+	got, err := repo.GetByID(ctx, emailOTP.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetByID = %v, %v", got, err)
+	}
+	if got.UserID != user.ID || got.MethodType != "email_otp" || got.IsEnabled || got.IsPrimary ||
+		got.EmailAddress == nil || *got.EmailAddress != email {
+		t.Errorf("GetByID = %+v", got)
+	}
+	if got, err := repo.GetByID(ctx, uuid.New()); err != nil || got != nil {
+		t.Errorf("GetByID inexistente = %v, %v", got, err)
+	}
+
+	if got, err := repo.GetByUserAndType(ctx, user.ID, "totp"); err != nil || got == nil || got.ID != totp.ID {
+		t.Errorf("GetByUserAndType(totp) = %v, %v", got, err)
+	}
+	if got, err := repo.GetByUserAndType(ctx, user.ID, "sms"); err != nil || got != nil {
+		t.Errorf("GetByUserAndType(sms) = %v, %v", got, err)
+	}
+
+	methods, err := repo.GetByUserID(ctx, user.ID)
+	if err != nil || len(methods) != 2 {
+		t.Fatalf("GetByUserID = %v, %v", methods, err)
+	}
+	if methods, err := repo.GetByUserID(ctx, uuid.New()); err != nil || len(methods) != 0 {
+		t.Errorf("GetByUserID sin métodos = %v, %v", methods, err)
+	}
+
+	// Update conserva el usuario y la fecha de creación
+	emailOTP.IsEnabled = true
+	emailOTP.UserID = other.ID
+	if err := repo.Update(ctx, emailOTP); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err = repo.GetByID(ctx, emailOTP.ID)
+	if err != nil || got == nil || !got.IsEnabled || got.UserID != user.ID {
+		t.Errorf("GetByID tras Update = %+v, %v", got, err)
+	}
+	missing := &entities.UserMFAMethod{ID: uuid.New(), UserID: user.ID, MethodType: "sms"}
+	if err := repo.Update(ctx, missing); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("Update inexistente = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+
+	usedAt := time.Now().Truncate(time.Microsecond)
+	if err := repo.MarkAsUsed(ctx, totp.ID, usedAt); err != nil {
+		t.Fatalf("MarkAsUsed: %v", err)
+	}
+	if got, err := repo.GetByID(ctx, totp.ID); err != nil || got == nil || got.LastUsedAt == nil || !got.LastUsedAt.Equal(usedAt) {
+		t.Errorf("GetByID tras MarkAsUsed = %+v, %v", got, err)
+	}
+	if err := repo.MarkAsUsed(ctx, uuid.New(), usedAt); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("MarkAsUsed inexistente = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+
+	if err := repo.Delete(ctx, emailOTP.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Delete(ctx, emailOTP.ID); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("Delete repetido = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+
+	// DeleteByUserID solo elimina los métodos del usuario y no falla si no tiene
+	if err := repo.DeleteByUserID(ctx, user.ID); err != nil {
+		t.Fatalf("DeleteByUserID: %v", err)
+	}
+	if err := repo.DeleteByUserID(ctx, user.ID); err != nil {
+		t.Errorf("DeleteByUserID sin métodos: %v", err)
+	}
+	if methods, err := repo.GetByUserID(ctx, user.ID); err != nil || len(methods) != 0 {
+		t.Errorf("GetByUserID tras DeleteByUserID = %v, %v", methods, err)
+	}
+	if got, err := repo.GetByID(ctx, otherTOTP.ID); err != nil || got == nil {
+		t.Errorf("DeleteByUserID eliminó métodos de otro usuario: %v, %v", got, err)
+	}
+} // fin testMFAMethods
+
+func testMFAMethodsPrimary(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	repo := repos.MFAMethods
+
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleInstructor)
+	other := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleInstructor)
+	mustCreate(t, repos.Users, user, other)
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	totp := &entities.UserMFAMethod{UserID: user.ID, MethodType: "totp", IsEnabled: true, CreatedAt: base}
+	sms := &entities.UserMFAMethod{UserID: user.ID, MethodType: "sms", IsEnabled: true, CreatedAt: base.Add(time.Minute)}
+	webauthn := &entities.UserMFAMethod{UserID: user.ID, MethodType: "webauthn", CreatedAt: base.Add(2 * time.Minute)}
+	otherTOTP := &entities.UserMFAMethod{UserID: other.ID, MethodType: "totp", IsEnabled: true}
+	mustCreateMFAMethods(t, repo, totp, sms, webauthn, otherTOTP)
+
+	if got, err := repo.GetPrimaryByUserID(ctx, user.ID); err != nil || got != nil {
+		t.Errorf("GetPrimaryByUserID sin primario = %v, %v", got, err)
+	}
+
+	// El primario va primero y los demás por fecha de creación; solo hay un primario por usuario
+	if err := repo.SetPrimary(ctx, user.ID, totp.ID); err != nil {
+		t.Fatalf("SetPrimary(totp): %v", err)
+	}
+	if err := repo.SetPrimary(ctx, user.ID, sms.ID); err != nil {
+		t.Fatalf("SetPrimary(sms): %v", err)
+	}
+	methods, err := repo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+	if got := mfaMethodTypes(methods); !reflect.DeepEqual(got, []string{"sms", "totp", "webauthn"}) {
+		t.Errorf("GetByUserID = %v, se esperaba [sms totp webauthn]", got)
+	}
+	if methods[1].IsPrimary {
+		t.Error("SetPrimary no desmarcó el primario anterior")
+	}
+
+	enabled, err := repo.GetEnabledByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetEnabledByUserID: %v", err)
+	}
+	if got := mfaMethodTypes(enabled); !reflect.DeepEqual(got, []string{"sms", "totp"}) {
+		t.Errorf("GetEnabledByUserID = %v, se esperaba [sms totp]", got)
+	}
+
+	if got, err := repo.GetPrimaryByUserID(ctx, user.ID); err != nil || got == nil || got.ID != sms.ID {
+		t.Errorf("GetPrimaryByUserID = %v, %v", got, err)
+	}
+
+	// Un primario deshabilitado no cuenta como primario
+	if err := repo.SetPrimary(ctx, user.ID, webauthn.ID); err != nil {
+		t.Fatalf("SetPrimary(webauthn): %v", err)
+	}
+	if got, err := repo.GetPrimaryByUserID(ctx, user.ID); err != nil || got != nil {
+		t.Errorf("GetPrimaryByUserID con primario deshabilitado = %v, %v", got, err)
+	}
+
+	// SetPrimary falla con un método inexistente o de otro usuario y no cambia el primario
+	if err := repo.SetPrimary(ctx, user.ID, uuid.New()); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("SetPrimary(inexistente) = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+	if err := repo.SetPrimary(ctx, user.ID, otherTOTP.ID); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("SetPrimary(método de otro usuario) = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+	if got, err := repo.GetByID(ctx, webauthn.ID); err != nil || got == nil || !got.IsPrimary {
+		t.Errorf("SetPrimary fallido cambió el primario: %+v, %v", got, err)
+	}
+	if got, err := repo.GetByID(ctx, otherTOTP.ID); err != nil || got == nil || got.IsPrimary {
+		t.Errorf("SetPrimary fallido marcó el método de otro usuario: %+v, %v", got, err)
+	}
+} // fin testMFAMethodsPrimary
+
+// mfaMethodTypes retorna los tipos de los métodos en orden
+func mfaMethodTypes(methods []*entities.UserMFAMethod) []string {
+	types := make([]string, 0, len(methods))
+	for _, method := range methods {
+		types = append(types, method.MethodType)
+	}
+	return types
+}
+
+func testMFABackupCodes(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	repo := repos.BackupCodes
+
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleInstructor)
+	other := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleInstructor)
+	mustCreate(t, repos.Users, user, other)
+
+	if err := repo.CreateBatch(ctx, nil); err != nil {
+		t.Errorf("CreateBatch vacío: %v", err)
+	}
+
+	// Solo los códigos vigentes cuentan, en orden de creación
+	now := time.Now().Truncate(time.Microsecond)
+	batch := []*entities.MFABackupCode{
+		{UserID: user.ID, CodeHash: "a", CreatedAt: now.Add(-3 * time.Minute), ExpiresAt: now.AddDate(1, 0, 0)},
+		{UserID: user.ID, CodeHash: "b", CreatedAt: now.Add(-2 * time.Minute), ExpiresAt: now.AddDate(1, 0, 0)},
+		{UserID: user.ID, CodeHash: "c", CreatedAt: now.Add(-time.Minute), ExpiresAt: now.AddDate(1, 0, 0)},
+		{UserID: user.ID, CodeHash: "vencido", ExpiresAt: now.Add(-time.Hour)},
+		{UserID: other.ID, CodeHash: "otro", ExpiresAt: now.AddDate(1, 0, 0)},
+	}
+	if err := repo.CreateBatch(ctx, batch); err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+	for _, code := range batch {
+		if code.ID == uuid.Nil {
+			t.Fatalf("CreateBatch no asignó ID a %s", code.CodeHash)
+		}
+	}
+
+	unused, err := repo.GetUnusedByUserID(ctx, user.ID)
+	if err != nil || len(unused) != 3 {
+		t.Fatalf("GetUnusedByUserID = %v, %v", unused, err)
+	}
+	for i, hash := range []string{"a", "b", "c"} {
+		if unused[i].CodeHash != hash {
+			t.Errorf("GetUnusedByUserID[%d] = %s, se esperaba %s", i, unused[i].CodeHash, hash)
+		}
+	}
+
+	// Un código no se usa dos veces
+	if err := repo.MarkAsUsed(ctx, batch[1].ID, now); err != nil {
+		t.Fatalf("MarkAsUsed: %v", err)
+	}
+	if err := repo.MarkAsUsed(ctx, batch[1].ID, now); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("MarkAsUsed(usado) = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+	if err := repo.MarkAsUsed(ctx, uuid.New(), now); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("MarkAsUsed(inexistente) = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+	if count, err := repo.CountUnusedByUserID(ctx, user.ID); err != nil || count != 2 {
+		t.Errorf("CountUnusedByUserID = %d, %v; se esperaban 2", count, err)
+	}
+
+	// DeleteExpired solo elimina los vencidos antes de la fecha dada
+	if deleted, err := repo.DeleteExpired(ctx, now.Add(-2*time.Hour)); err != nil || deleted != 0 {
+		t.Errorf("DeleteExpired antes del vencimiento = %d, %v", deleted, err)
+	}
+	if deleted, err := repo.DeleteExpired(ctx, now); err != nil || deleted != 1 {
+		t.Errorf("DeleteExpired = %d, %v; se esperaba 1", deleted, err)
+	}
+
+	if err := repo.DeleteByUserID(ctx, user.ID); err != nil {
+		t.Fatalf("DeleteByUserID: %v", err)
+	}
+	if count, err := repo.CountUnusedByUserID(ctx, user.ID); err != nil || count != 0 {
+		t.Errorf("CountUnusedByUserID tras DeleteByUserID = %d, %v", count, err)
+	}
+	if count, err := repo.CountUnusedByUserID(ctx, other.ID); err != nil || count != 1 {
+		t.Errorf("DeleteByUserID eliminó códigos de otro usuario: %d, %v", count, err)
+	}
+} // fin testMFABackupCodes
+
+func testMFASessions(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	repo := repos.MFASessions
+
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleInstructor)
+	mustCreate(t, repos.Users, user)
+
+	if got, err := repo.GetActiveByUserID(ctx, user.ID); err != nil || got != nil {
+		t.Errorf("GetActiveByUserID sin sesiones = %v, %v", got, err)
+	}
+
+	// Sin MaxAttempts se usa el valor por defecto
+	now := time.Now().Truncate(time.Microsecond)
+	older := &entities.MFASession{UserID: user.ID, MethodType: "email_otp", CreatedAt: now.Add(-2 * time.Minute), ExpiresAt: now.Add(5 * time.Minute)}
+	newer := &entities.MFASession{UserID: user.ID, MethodType: "totp", MaxAttempts: 5, CreatedAt: now.Add(-time.Minute), ExpiresAt: now.Add(5 * time.Minute)}
+	expired := &entities.MFASession{UserID: user.ID, MethodType: "sms", CreatedAt: now, ExpiresAt: now.Add(-time.Minute)}
+	for _, session := range []*entities.MFASession{older, newer, expired} {
+		if err := repo.Create(ctx, session); err != nil {
+			t.Fatalf("Create(%s): %v", session.MethodType, err)
+		}
+		if session.ID == uuid.Nil {
+			t.Fatalf("Create(%s) no asignó ID", session.MethodType)
+		}
+	}
+
+	got, err := repo.GetByID(ctx, older.ID)
+	if err != nil || got == nil || got.MaxAttempts != 3 || got.Attempts != 0 || got.IsVerified {
+		t.Errorf("GetByID = %+v, %v; se esperaban 3 intentos máximos", got, err)
+	}
+	if got, err := repo.GetByID(ctx, uuid.New()); err != nil || got != nil {
+		t.Errorf("GetByID inexistente = %v, %v", got, err)
+	}
+
+	// La sesión activa es la más reciente no verificada y no expirada
+	if got, err := repo.GetActiveByUserID(ctx, user.ID); err != nil || got == nil || got.ID != newer.ID {
+		t.Errorf("GetActiveByUserID = %v, %v; se esperaba la sesión totp", got, err)
+	}
+
+	for range 2 {
+		if err := repo.IncrementAttempts(ctx, newer.ID); err != nil {
+			t.Fatalf("IncrementAttempts: %v", err)
+		}
+	}
+	if got, err := repo.GetByID(ctx, newer.ID); err != nil || got == nil || got.Attempts != 2 || got.MaxAttempts != 5 {
+		t.Errorf("GetByID tras IncrementAttempts = %+v, %v", got, err)
+	}
+	if err := repo.IncrementAttempts(ctx, uuid.New()); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("IncrementAttempts(inexistente) = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+
+	if err := repo.MarkAsVerified(ctx, newer.ID, now); err != nil {
+		t.Fatalf("MarkAsVerified: %v", err)
+	}
+	got, err = repo.GetByID(ctx, newer.ID)
+	if err != nil || got == nil || !got.IsVerified || got.VerifiedAt == nil || !got.VerifiedAt.Equal(now) {
+		t.Errorf("GetByID tras MarkAsVerified = %+v, %v", got, err)
+	}
+	if err := repo.MarkAsVerified(ctx, uuid.New(), now); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("MarkAsVerified(inexistente) = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+	if got, err := repo.GetActiveByUserID(ctx, user.ID); err != nil || got == nil || got.ID != older.ID {
+		t.Errorf("GetActiveByUserID tras verificar = %v, %v; se esperaba la sesión email_otp", got, err)
+	}
+
+	if deleted, err := repo.DeleteExpired(ctx, now); err != nil || deleted != 1 {
+		t.Errorf("DeleteExpired = %d, %v; se esperaba 1", deleted, err)
+	}
+	if got, err := repo.GetByID(ctx, expired.ID); err != nil || got != nil {
+		t.Errorf("GetByID tras DeleteExpired = %v, %v", got, err)
+	}
+} // fin testMFASessions
+
+func testMFAPolicies(t *testing.T, repo repositories.MFAEnforcementPolicyRepository) {
+	ctx := InternalContext()
+
+	// Las listas de métodos conservan su contenido y un false explícito se guarda como false
+	admin := &entities.MFAEnforcementPolicy{
+		RoleName:           "admin",
+		PrimaryMethods:     entities.StringList{"totp", "webauthn"},
+		AlternativeMethods: entities.StringList{`con "comillas"`, "con, coma"},
+		EnforcementLevel:   "mandatory",
+		GracePeriodDays:    7,
+	}
+	aprendiz := &entities.MFAEnforcementPolicy{
+		RoleName:           "aprendiz",
+		PrimaryMethods:     entities.StringList{"email_otp"},
+		EnforcementLevel:   "optional",
+		RequireBackupCodes: true,
+	}
+	for _, policy := range []*entities.MFAEnforcementPolicy{admin, aprendiz} {
+		if err := repo.Create(ctx, policy); err != nil {
+			t.Fatalf("Create(%s): %v", policy.RoleName, err)
+		}
+		if policy.ID == uuid.Nil {
+			t.Fatalf("Create(%s) no asignó ID", policy.RoleName)
+		}
+	}
+
+	got, err := repo.GetByRoleName(ctx, "admin")
+	if err != nil || got == nil {
+		t.Fatalf("GetByRoleName = %v, %v", got, err)
+	}
+	if !reflect.DeepEqual(got.PrimaryMethods, admin.PrimaryMethods) ||
+		!reflect.DeepEqual(got.AlternativeMethods, admin.AlternativeMethods) ||
+		got.RequireBackupCodes || got.GracePeriodDays != 7 || got.EnforcementLevel != "mandatory" {
+		t.Errorf("GetByRoleName = %+v", got)
+	}
+	if got, err := repo.GetByRoleName(ctx, "instructor"); err != nil || got != nil {
+		t.Errorf("GetByRoleName sin política = %v, %v", got, err)
+	}
+
+	// Las copias retornadas no comparten las listas con el repositorio
+	got.PrimaryMethods[0] = "modificado"
+	if again, err := repo.GetByRoleName(ctx, "admin"); err != nil || again == nil || again.PrimaryMethods[0] != "totp" {
+		t.Errorf("GetByRoleName tras modificar la copia = %v, %v", again, err)
+	}
+
+	admin.EnforcementLevel = "recommended"
+	admin.AlternativeMethods = nil
+	if err := repo.Update(ctx, admin); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	missing := &entities.MFAEnforcementPolicy{ID: uuid.New(), RoleName: "coordinador", PrimaryMethods: entities.StringList{"totp"}, EnforcementLevel: "optional"}
+	if err := repo.Update(ctx, missing); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("Update inexistente = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+
+	policies, err := repo.List(ctx)
+	if err != nil || len(policies) != 2 {
+		t.Fatalf("List = %v, %v", policies, err)
+	}
+	if policies[0].RoleName != "admin" || policies[1].RoleName != "aprendiz" {
+		t.Errorf("List = [%s %s], se esperaba orden por rol", policies[0].RoleName, policies[1].RoleName)
+	}
+	if policies[0].EnforcementLevel != "recommended" || len(policies[0].AlternativeMethods) != 0 || !policies[1].RequireBackupCodes {
+		t.Errorf("List tras Update = %+v, %+v", policies[0], policies[1])
+	}
+
+	if err := repo.Delete(ctx, aprendiz.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Delete(ctx, aprendiz.ID); !errors.Is(err, repositories.ErrMFARecordNotFound) {
+		t.Errorf("Delete repetido = %v, se esperaba ErrMFARecordNotFound", err)
+	}
+	if got, err := repo.GetByRoleName(ctx, "aprendiz"); err != nil || got != nil {
+		t.Errorf("GetByRoleName tras Delete = %v, %v", got, err)
+	}
+} // fin testMFAPolicies
+
+func testMFAPurgeCascade(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleInstructor)
+	other := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleInstructor)
+	mustCreate(t, repos.Users, user, other)
+
+	now := time.Now()
+	var sessionIDs []uuid.UUID
+	for _, owner := range []*entities.User{user, other} {
+		mustCreateMFAMethods(t, repos.MFAMethods, &entities.UserMFAMethod{UserID: owner.ID, MethodType: "totp", IsEnabled: true})
+		codes := []*entities.MFABackupCode{{UserID: owner.ID, CodeHash: "a", ExpiresAt: now.AddDate(1, 0, 0)}}
+		if err := repos.BackupCodes.CreateBatch(ctx, codes); err != nil {
+			t.Fatalf("CreateBatch: %v", err)
+		}
+		session := &entities.MFASession{UserID: owner.ID, MethodType: "totp", ExpiresAt: now.Add(5 * time.Minute)}
+		if err := repos.MFASessions.Create(ctx, session); err != nil {
+			t.Fatalf("Create(sesión): %v", err)
+		}
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+
+	// Purgar al usuario elimina en cascada sus métodos, códigos y sesiones, y solo los suyos
+	if err := repos.Users.Delete(ctx, user.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repos.Users.Purge(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+
+	if methods, err := repos.MFAMethods.GetByUserID(ctx, user.ID); err != nil || len(methods) != 0 {
+		t.Errorf("GetByUserID tras Purge = %v, %v", methods, err)
+	}
+	if count, err := repos.BackupCodes.CountUnusedByUserID(ctx, user.ID); err != nil || count != 0 {
+		t.Errorf("CountUnusedByUserID tras Purge = %d, %v", count, err)
+	}
+	if session, err := repos.MFASessions.GetByID(ctx, sessionIDs[0]); err != nil || session != nil {
+		t.Errorf("GetByID(sesión) tras Purge = %v, %v", session, err)
+	}
+
+	if methods, err := repos.MFAMethods.GetByUserID(ctx, other.ID); err != nil || len(methods) != 1 {
+		t.Errorf("Purge eliminó métodos de otro usuario: %v, %v", methods, err)
+	}
+	if count, err := repos.BackupCodes.CountUnusedByUserID(ctx, other.ID); err != nil || count != 1 {
+		t.Errorf("Purge eliminó códigos de otro usuario: %d, %v", count, err)
+	}
+	if session, err := repos.MFASessions.GetByID(ctx, sessionIDs[1]); err != nil || session == nil {
+		t.Errorf("Purge eliminó sesiones de otro usuario: %v, %v", session, err)
+	}
+} // fin testMFAPurgeCascade
@@ -0,0 +1,429 @@
+package repositorytest
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RunOrganizationRepositoryContract ejecuta la suite de contrato sobre la implementación dada
+// Verifica también la validación de SedeID y los filtros, conteos, tendencias y dashboard de usuarios por nivel de la jerarquía
+func RunOrganizationRepositoryContract(t *testing.T, newRepos RepositoriesFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) { testOrgCreateAndGet(t, newRepos(t).Organization) })
+	t.Run("Update", func(t *testing.T) { testOrgUpdate(t, newRepos(t).Organization) })
+	t.Run("List", func(t *testing.T) { testOrgList(t, newRepos(t).Organization) })
+	t.Run("SedeValidation", func(t *testing.T) { testSedeValidation(t, newRepos(t)) })
+	t.Run("UsersByOrgLevel", func(t *testing.T) { testUsersByOrgLevel(t, newRepos(t)) })
+	t.Run("RegistrationTrend", func(t *testing.T) { testRegistrationTrend(t, newRepos(t)) })
+	t.Run("AggregateDashboard", func(t *testing.T) { testAggregateDashboard(t, newRepos(t)) })
+	t.Run("ActivityMetrics", func(t *testing.T) { testActivityMetrics(t, newRepos(t)) })
+}
+
+func mustCreateRegional(t *testing.T, repo repositories.OrganizationRepository, code, name string) *entities.Regional {
+	t.Helper()
+	regional, err := entities.NewRegional(code, name)
+	if err != nil {
+		t.Fatalf("regional de prueba inválida: %v", err)
+	}
+	if err := repo.CreateRegional(InternalContext(), regional); err != nil {
+		t.Fatalf("CreateRegional(%s): %v", code, err)
+	}
+	return regional
+}
+
+func mustCreateCentro(t *testing.T, repo repositories.OrganizationRepository, regionalID uuid.UUID, code, name string) *entities.Centro {
+	t.Helper()
+	centro, err := entities.NewCentro(regionalID, code, name)
+	if err != nil {
+		t.Fatalf("centro de prueba inválido: %v", err)
+	}
+	if err := repo.CreateCentro(InternalContext(), centro); err != nil {
+		t.Fatalf("CreateCentro(%s): %v", code, err)
+	}
+	return centro
+}
+
+func mustCreateSede(t *testing.T, repo repositories.OrganizationRepository, centroID uuid.UUID, name string) *entities.Sede {
+	t.Helper()
+	sede, err := entities.NewSede(centroID, name, "")
+	if err != nil {
+		t.Fatalf("sede de prueba inválida: %v", err)
+	}
+	if err := repo.CreateSede(InternalContext(), sede); err != nil {
+		t.Fatalf("CreateSede(%s): %v", name, err)
+	}
+	return sede
+}
+
+// mustCreateSedePath registra una regional, un centro y una sede cuyos códigos derivan de code
+func mustCreateSedePath(t *testing.T, repo repositories.OrganizationRepository, code string) repositories.SedePath {
+	t.Helper()
+	regional := mustCreateRegional(t, repo, "R"+code, "Regional "+code)
+	centro := mustCreateCentro(t, repo, regional.ID, "C"+code, "Centro "+code)
+	sede := mustCreateSede(t, repo, centro.ID, "Sede "+code)
+	return repositories.SedePath{SedeID: sede.ID, CentroID: centro.ID, RegionalID: regional.ID}
+}
+
+func testOrgCreateAndGet(t *testing.T, repo repositories.OrganizationRepository) {
+	ctx := InternalContext()
+
+	regional := mustCreateRegional(t, repo, "11", "Regional Distrito Capital")
+	centro := mustCreateCentro(t, repo, regional.ID, "9229", "Centro de Gestión de Mercados, Logística y TI")
+	sede, err := entities.NewSede(centro.ID, "Sede Salitre", "Av. Calle 26 # 69-76")
+	if err != nil {
+		t.Fatalf("NewSede: %v", err)
+	}
+	if err := repo.CreateSede(ctx, sede); err != nil {
+		t.Fatalf("CreateSede: %v", err)
+	}
+
+	if got, err := repo.GetRegional(ctx, regional.ID); err != nil || got == nil || got.Code != "11" || got.Name != regional.Name || !got.IsActive {
+		t.Errorf("GetRegional = %+v, %v", got, err)
+	}
+	if got, err := repo.GetCentro(ctx, centro.ID); err != nil || got == nil || got.Code != "9229" || got.RegionalID != regional.ID {
+		t.Errorf("GetCentro = %+v, %v", got, err)
+	}
+	if got, err := repo.GetSede(ctx, sede.ID); err != nil || got == nil || got.Name != "Sede Salitre" ||
+		got.Address != sede.Address || got.CentroID != centro.ID {
+		t.Errorf("GetSede = %+v, %v", got, err)
+	}
+
+	path, err := repo.GetSedePath(ctx, sede.ID)
+	want := repositories.SedePath{SedeID: sede.ID, CentroID: centro.ID, RegionalID: regional.ID}
+	if err != nil || path == nil || *path != want {
+		t.Errorf("GetSedePath = %+v, %v; se esperaba %+v", path, err, want)
+	}
+
+	missing := uuid.New()
+	if got, err := repo.GetRegional(ctx, missing); err != nil || got != nil {
+		t.Errorf("GetRegional inexistente = %v, %v", got, err)
+	}
+	if got, err := repo.GetCentro(ctx, missing); err != nil || got != nil {
+		t.Errorf("GetCentro inexistente = %v, %v", got, err)
+	}
+	if got, err := repo.GetSede(ctx, missing); err != nil || got != nil {
+		t.Errorf("GetSede inexistente = %v, %v", got, err)
+	}
+	if got, err := repo.GetSedePath(ctx, missing); err != nil || got != nil {
+		t.Errorf("GetSedePath inexistente = %v, %v", got, err)
+	}
+
+	duplicateRegional, _ := entities.NewRegional("11", "Otra regional")
+	if err := repo.CreateRegional(ctx, duplicateRegional); !errors.Is(err, repositories.ErrDuplicateRegional) {
+		t.Errorf("CreateRegional con código repetido: se esperaba ErrDuplicateRegional, se obtuvo %v", err)
+	}
+	duplicateCentro, _ := entities.NewCentro(regional.ID, "9229", "Otro centro")
+	if err := repo.CreateCentro(ctx, duplicateCentro); !errors.Is(err, repositories.ErrDuplicateCentro) {
+		t.Errorf("CreateCentro con código repetido: se esperaba ErrDuplicateCentro, se obtuvo %v", err)
+	}
+	duplicateSede, _ := entities.NewSede(centro.ID, "Sede Salitre", "")
+	if err := repo.CreateSede(ctx, duplicateSede); !errors.Is(err, repositories.ErrDuplicateSede) {
+		t.Errorf("CreateSede con nombre repetido en el centro: se esperaba ErrDuplicateSede, se obtuvo %v", err)
+	}
+
+	// El nombre de la sede solo es único dentro de su centro
+	otherCentro := mustCreateCentro(t, repo, regional.ID, "9230", "Centro de Electricidad, Electrónica y Telecomunicaciones")
+	mustCreateSede(t, repo, otherCentro.ID, "Sede Salitre")
+
+	orphanCentro, _ := entities.NewCentro(missing, "9999", "Centro sin regional")
+	if err := repo.CreateCentro(ctx, orphanCentro); !errors.Is(err, repositories.ErrRegionalNotFound) {
+		t.Errorf("CreateCentro sin regional: se esperaba ErrRegionalNotFound, se obtuvo %v", err)
+	}
+	orphanSede, _ := entities.NewSede(missing, "Sede sin centro", "")
+	if err := repo.CreateSede(ctx, orphanSede); !errors.Is(err, repositories.ErrCentroNotFound) {
+		t.Errorf("CreateSede sin centro: se esperaba ErrCentroNotFound, se obtuvo %v", err)
+	}
+} // fin testOrgCreateAndGet
+
+func testOrgUpdate(t *testing.T, repo repositories.OrganizationRepository) {
+	ctx := InternalContext()
+
+	capital := mustCreateRegional(t, repo, "11", "Regional Distrito Capital")
+	antioquia := mustCreateRegional(t, repo, "05", "Regional Antioquia")
+	centro := mustCreateCentro(t, repo, capital.ID, "9229", "Centro de Gestión de Mercados")
+	other := mustCreateCentro(t, repo, capital.ID, "9230", "Centro de Electricidad")
+	sede := mustCreateSede(t, repo, centro.ID, "Sede Salitre")
+	mustCreateSede(t, repo, other.ID, "Sede Restrepo")
+
+	capital.Name = "Regional Bogotá D.C."
+	capital.IsActive = false
+	if err := repo.UpdateRegional(ctx, capital); err != nil {
+		t.Fatalf("UpdateRegional: %v", err)
+	}
+	if got, _ := repo.GetRegional(ctx, capital.ID); got == nil || got.Name != capital.Name || got.IsActive {
+		t.Errorf("UpdateRegional: se obtuvo %+v", got)
+	}
+	capital.Code = antioquia.Code
+	if err := repo.UpdateRegional(ctx, capital); !errors.Is(err, repositories.ErrDuplicateRegional) {
+		t.Errorf("UpdateRegional con código de otra regional: se esperaba ErrDuplicateRegional, se obtuvo %v", err)
+	}
+
+	// Mover el centro de regional cambia la ruta de sus sedes
+	centro.RegionalID = antioquia.ID
+	if err := repo.UpdateCentro(ctx, centro); err != nil {
+		t.Fatalf("UpdateCentro: %v", err)
+	}
+	if path, err := repo.GetSedePath(ctx, sede.ID); err != nil || path == nil || path.RegionalID != antioquia.ID {
+		t.Errorf("GetSedePath tras mover el centro = %+v, %v", path, err)
+	}
+	centro.Code = other.Code
+	if err := repo.UpdateCentro(ctx, centro); !errors.Is(err, repositories.ErrDuplicateCentro) {
+		t.Errorf("UpdateCentro con código de otro centro: se esperaba ErrDuplicateCentro, se obtuvo %v", err)
+	}
+	centro.Code, centro.RegionalID = "9229", uuid.New()
+	if err := repo.UpdateCentro(ctx, centro); !errors.Is(err, repositories.ErrRegionalNotFound) {
+		t.Errorf("UpdateCentro a regional inexistente: se esperaba ErrRegionalNotFound, se obtuvo %v", err)
+	}
+
+	sede.CentroID = other.ID
+	sede.Address = "Calle 17 Sur # 20-30"
+	if err := repo.UpdateSede(ctx, sede); err != nil {
+		t.Fatalf("UpdateSede: %v", err)
+	}
+	if path, err := repo.GetSedePath(ctx, sede.ID); err != nil || path == nil || path.CentroID != other.ID || path.RegionalID != capital.ID {
+		t.Errorf("GetSedePath tras mover la sede = %+v, %v", path, err)
+	}
+	sede.Name = "Sede Restrepo"
+	if err := repo.UpdateSede(ctx, sede); !errors.Is(err, repositories.ErrDuplicateSede) {
+		t.Errorf("UpdateSede con nombre de otra sede del centro: se esperaba ErrDuplicateSede, se obtuvo %v", err)
+	}
+	sede.Name, sede.CentroID = "Sede Salitre", uuid.New()
+	if err := repo.UpdateSede(ctx, sede); !errors.Is(err, repositories.ErrCentroNotFound) {
+		t.Errorf("UpdateSede a centro inexistente: se esperaba ErrCentroNotFound, se obtuvo %v", err)
+	}
+
+	missingRegional, _ := entities.NewRegional("99", "Regional inexistente")
+	if err := repo.UpdateRegional(ctx, missingRegional); !errors.Is(err, repositories.ErrRegionalNotFound) {
+		t.Errorf("UpdateRegional inexistente: se esperaba ErrRegionalNotFound, se obtuvo %v", err)
+	}
+	missingCentro, _ := entities.NewCentro(capital.ID, "9999", "Centro inexistente")
+	if err := repo.UpdateCentro(ctx, missingCentro); !errors.Is(err, repositories.ErrCentroNotFound) {
+		t.Errorf("UpdateCentro inexistente: se esperaba ErrCentroNotFound, se obtuvo %v", err)
+	}
+	missingSede, _ := entities.NewSede(other.ID, "Sede inexistente", "")
+	if err := repo.UpdateSede(ctx, missingSede); !errors.Is(err, repositories.ErrSedeNotFound) {
+		t.Errorf("UpdateSede inexistente: se esperaba ErrSedeNotFound, se obtuvo %v", err)
+	}
+} // fin testOrgUpdate
+
+func testOrgList(t *testing.T, repo repositories.OrganizationRepository) {
+	ctx := InternalContext()
+
+	capital := mustCreateRegional(t, repo, "11", "Regional Distrito Capital")
+	antioquia := mustCreateRegional(t, repo, "05", "Regional Antioquia")
+	retired := mustCreateRegional(t, repo, "99", "Regional Retirada")
+	retired.IsActive = false
+	if err := repo.UpdateRegional(ctx, retired); err != nil {
+		t.Fatalf("UpdateRegional: %v", err)
+	}
+	mercados := mustCreateCentro(t, repo, capital.ID, "9229", "Centro de Gestión de Mercados")
+	mustCreateCentro(t, repo, capital.ID, "9121", "Centro de Tecnologías del Transporte")
+	mustCreateCentro(t, repo, antioquia.ID, "9206", "Centro de Servicios y Gestión Empresarial")
+	mustCreateSede(t, repo, mercados.ID, "Sede Salitre")
+	mustCreateSede(t, repo, mercados.ID, "Sede Chapinero")
+	closed := mustCreateSede(t, repo, mercados.ID, "Sede Antigua")
+	closed.IsActive = false
+	if err := repo.UpdateSede(ctx, closed); err != nil {
+		t.Fatalf("UpdateSede: %v", err)
+	}
+
+	codes := func(name string, got, want []string) {
+		t.Helper()
+		if !slices.Equal(got, want) {
+			t.Errorf("%s = %v, se esperaba %v", name, got, want)
+		}
+	}
+
+	regionales, err := repo.ListRegionales(ctx, false)
+	if err != nil {
+		t.Fatalf("ListRegionales: %v", err)
+	}
+	var got []string
+	for _, regional := range regionales {
+		got = append(got, regional.Code)
+	}
+	codes("ListRegionales", got, []string{"05", "11", "99"})
+
+	regionales, _ = repo.ListRegionales(ctx, true)
+	got = got[:0]
+	for _, regional := range regionales {
+		got = append(got, regional.Code)
+	}
+	codes("ListRegionales activas", got, []string{"05", "11"})
+
+	for _, tc := range []struct {
+		name       string
+		regionalID *uuid.UUID
+		want       []string
+	}{
+		{"todos", nil, []string{"9121", "9206", "9229"}},
+		{"de la regional", &capital.ID, []string{"9121", "9229"}},
+		{"de regional sin centros", &retired.ID, nil},
+	} {
+		centros, err := repo.ListCentros(ctx, tc.regionalID, false)
+		if err != nil {
+			t.Fatalf("ListCentros %s: %v", tc.name, err)
+		}
+		got = got[:0]
+		for _, centro := range centros {
+			got = append(got, centro.Code)
+		}
+		codes("ListCentros "+tc.name, got, tc.want)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		activeOnly bool
+		want       []string
+	}{
+		{"todas", false, []string{"Sede Antigua", "Sede Chapinero", "Sede Salitre"}},
+		{"activas", true, []string{"Sede Chapinero", "Sede Salitre"}},
+	} {
+		sedes, err := repo.ListSedes(ctx, &mercados.ID, tc.activeOnly)
+		if err != nil {
+			t.Fatalf("ListSedes %s: %v", tc.name, err)
+		}
+		got = got[:0]
+		for _, sede := range sedes {
+			got = append(got, sede.Name)
+		}
+		codes("ListSedes "+tc.name, got, tc.want)
+	}
+} // fin testOrgList
+
+// testSedeValidation verifica que usuarios y fichas solo apunten a sedes registradas
+func testSedeValidation(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	path := mustCreateSedePath(t, repos.Organization, "11")
+	unknown := uuid.New()
+
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	user.SedeID = &unknown
+	if err := repos.Users.Create(ctx, user); !errors.Is(err, repositories.ErrSedeNotFound) {
+		t.Errorf("Create con sede inexistente: se esperaba ErrSedeNotFound, se obtuvo %v", err)
+	}
+
+	user = NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	user.SedeID = &path.SedeID
+	mustCreate(t, repos.Users, user)
+	if got := mustGet(t, repos.Users, user.ID); got.SedeID == nil || *got.SedeID != path.SedeID {
+		t.Errorf("GetByID: sede %v, se esperaba %s", got.SedeID, path.SedeID)
+	}
+
+	stale := mustGet(t, repos.Users, user.ID)
+	stale.SedeID = &unknown
+	if err := repos.Users.Update(ctx, stale); !errors.Is(err, repositories.ErrSedeNotFound) {
+		t.Errorf("Update con sede inexistente: se esperaba ErrSedeNotFound, se obtuvo %v", err)
+	}
+	current := mustGet(t, repos.Users, user.ID)
+	current.SedeID = nil
+	if err := repos.Users.Update(ctx, current); err != nil {
+		t.Errorf("Update sin sede: %v", err)
+	}
+
+	result, err := repos.Users.BulkCreate(ctx, []*entities.User{
+		NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz),
+		func() *entities.User {
+			user := NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleAprendiz)
+			user.SedeID = &unknown
+			return user
+		}(),
+	}, repositories.BulkBestEffort)
+	if err != nil || result.Success != 1 || result.Failed != 1 {
+		t.Errorf("BulkCreate con una sede inexistente = %+v, %v", result, err)
+	}
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repos.Programs, program)
+	ficha := newTestFicha(t, "2558104", program.ID, 30)
+	ficha.SedeID = &unknown
+	if err := repos.Fichas.Create(ctx, ficha); !errors.Is(err, repositories.ErrSedeNotFound) {
+		t.Errorf("Create ficha con sede inexistente: se esperaba ErrSedeNotFound, se obtuvo %v", err)
+	}
+	ficha.SedeID = &path.SedeID
+	mustCreateFichas(t, repos.Fichas, ficha)
+	ficha.SedeID = &unknown
+	if err := repos.Fichas.Update(ctx, ficha); !errors.Is(err, repositories.ErrSedeNotFound) {
+		t.Errorf("Update ficha con sede inexistente: se esperaba ErrSedeNotFound, se obtuvo %v", err)
+	}
+} // fin testSedeValidation
+
+// testUsersByOrgLevel verifica el filtro de usuarios y los conteos en cada nivel de la jerarquía
+func testUsersByOrgLevel(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+
+	// Dos sedes en el mismo centro de la regional 11 y una en la regional 05
+	salitre := mustCreateSedePath(t, repos.Organization, "11")
+	chapinero := mustCreateSede(t, repos.Organization, salitre.CentroID, "Sede Chapinero")
+	medellin := mustCreateSedePath(t, repos.Organization, "05")
+
+	sedes := []*uuid.UUID{&salitre.SedeID, &salitre.SedeID, &chapinero.ID, &medellin.SedeID, nil}
+	users := make([]*entities.User, len(sedes))
+	for i, sede := range sedes {
+		users[i] = NewTestUser(t, i+1, "Ana", "Gómez", entities.RoleAprendiz)
+		users[i].SedeID = sede
+		mustCreate(t, repos.Users, users[i])
+	}
+	deleted := NewTestUser(t, 10, "Marta", "Díaz", entities.RoleAprendiz)
+	deleted.SedeID = &salitre.SedeID
+	mustCreate(t, repos.Users, deleted)
+	if err := repos.Users.Delete(ctx, deleted.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		scope repositories.OrgFilter
+		want  []entities.Email
+	}{
+		{"sede", repositories.OrgFilter{SedeID: &salitre.SedeID}, []entities.Email{users[0].Email, users[1].Email}},
+		{"centro", repositories.OrgFilter{CentroID: &salitre.CentroID}, []entities.Email{users[0].Email, users[1].Email, users[2].Email}},
+		{"regional", repositories.OrgFilter{RegionalID: &medellin.RegionalID}, []entities.Email{users[3].Email}},
+		{"centro de otra regional", repositories.OrgFilter{CentroID: &salitre.CentroID, RegionalID: &medellin.RegionalID}, nil},
+	}
+	for _, tc := range cases {
+		result, err := repos.Users.List(ctx, repositories.UserFilters{OrgFilter: tc.scope})
+		if err != nil {
+			t.Fatalf("List(%s): %v", tc.name, err)
+		}
+		assertEmails(t, tc.name, result.Users, tc.want)
+	}
+
+	counts := []struct {
+		level repositories.OrgLevel
+		scope repositories.OrgFilter
+		want  map[string]int
+	}{
+		{repositories.OrgLevelSede, repositories.OrgFilter{}, map[string]int{
+			salitre.SedeID.String(): 2, chapinero.ID.String(): 1, medellin.SedeID.String(): 1,
+		}},
+		{repositories.OrgLevelCentro, repositories.OrgFilter{}, map[string]int{
+			salitre.CentroID.String(): 3, medellin.CentroID.String(): 1,
+		}},
+		{repositories.OrgLevelRegional, repositories.OrgFilter{}, map[string]int{
+			salitre.RegionalID.String(): 3, medellin.RegionalID.String(): 1,
+		}},
+		{repositories.OrgLevelSede, repositories.OrgFilter{RegionalID: &salitre.RegionalID}, map[string]int{
+			salitre.SedeID.String(): 2, chapinero.ID.String(): 1,
+		}},
+	}
+	for _, tc := range counts {
+		got, err := repos.Users.GetTotalUsersByOrgLevel(ctx, tc.level, tc.scope)
+		if err != nil {
+			t.Fatalf("GetTotalUsersByOrgLevel(%s): %v", tc.level, err)
+		}
+		if !maps.Equal(got, tc.want) {
+			t.Errorf("GetTotalUsersByOrgLevel(%s, %+v) = %v, se esperaba %v", tc.level, tc.scope, got, tc.want)
+		}
+	}
+
+	if _, err := repos.Users.GetTotalUsersByOrgLevel(ctx, "ficha", repositories.OrgFilter{}); !errors.Is(err, repositories.ErrInvalidOrgLevel) {
+		t.Errorf("GetTotalUsersByOrgLevel(ficha): se esperaba ErrInvalidOrgLevel, se obtuvo %v", err)
+	}
+} // fin testUsersByOrgLevel
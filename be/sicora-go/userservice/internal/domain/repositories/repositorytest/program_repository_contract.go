@@ -0,0 +1,218 @@
+package repositorytest
+
+import (
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RunProgramRepositoryContract ejecuta la suite de contrato sobre la implementación dada
+// Verifica también las consultas de usuarios por programa, que se resuelven a través de la ficha
+func RunProgramRepositoryContract(t *testing.T, newRepos RepositoriesFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) { testProgramCreateAndGet(t, newRepos(t).Programs) })
+	t.Run("Update", func(t *testing.T) { testProgramUpdate(t, newRepos(t).Programs) })
+	t.Run("List", func(t *testing.T) { testProgramList(t, newRepos(t).Programs) })
+	t.Run("UsersByProgram", func(t *testing.T) { testUsersByProgram(t, newRepos(t)) })
+}
+
+// newTestProgram construye un programa válido de nivel tecnólogo
+func newTestProgram(t *testing.T, code, name string) *entities.Program {
+	t.Helper()
+
+	program, err := entities.NewProgram(code, name, entities.ProgramLevelTecnologo, 27)
+	if err != nil {
+		t.Fatalf("programa de prueba inválido: %v", err)
+	}
+
+	program.CreatedAt = program.CreatedAt.Truncate(time.Microsecond)
+	program.UpdatedAt = program.CreatedAt
+	return program
+}
+
+func mustCreatePrograms(t *testing.T, repo repositories.ProgramRepository, programs ...*entities.Program) {
+	t.Helper()
+	for _, program := range programs {
+		if err := repo.Create(InternalContext(), program); err != nil {
+			t.Fatalf("Create(%s): %v", program.Code, err)
+		}
+	}
+}
+
+func testProgramCreateAndGet(t *testing.T, repo repositories.ProgramRepository) {
+	ctx := InternalContext()
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repo, program)
+
+	got, err := repo.GetByID(ctx, program.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetByID = %v, %v", got, err)
+	}
+	if got.Code != program.Code || got.Name != program.Name || got.Level != program.Level ||
+		got.DurationMonths != program.DurationMonths || !got.IsActive {
+		t.Errorf("GetByID = %+v, se esperaba %+v", got, program)
+	}
+
+	if got, err := repo.GetByCode(ctx, "228118"); err != nil || got == nil || got.ID != program.ID {
+		t.Errorf("GetByCode = %v, %v", got, err)
+	}
+	if got, err := repo.GetByCode(ctx, "999999"); err != nil || got != nil {
+		t.Errorf("GetByCode inexistente = %v, %v", got, err)
+	}
+	if got, err := repo.GetByID(ctx, uuid.New()); err != nil || got != nil {
+		t.Errorf("GetByID inexistente = %v, %v", got, err)
+	}
+
+	duplicate := newTestProgram(t, "228118", "Otro programa")
+	if err := repo.Create(ctx, duplicate); !errors.Is(err, repositories.ErrDuplicateProgram) {
+		t.Errorf("Create con código repetido: se esperaba ErrDuplicateProgram, se obtuvo %v", err)
+	}
+} // fin testProgramCreateAndGet
+
+func testProgramUpdate(t *testing.T, repo repositories.ProgramRepository) {
+	ctx := InternalContext()
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	other := newTestProgram(t, "233104", "Gestión de Redes de Datos")
+	mustCreatePrograms(t, repo, program, other)
+
+	program.Name = "Análisis y Desarrollo de Sistemas de Información"
+	program.IsActive = false
+	if err := repo.Update(ctx, program); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, _ := repo.GetByID(ctx, program.ID)
+	if got == nil || got.Name != program.Name || got.IsActive {
+		t.Errorf("Update: se obtuvo %+v", got)
+	}
+
+	program.Code = other.Code
+	if err := repo.Update(ctx, program); !errors.Is(err, repositories.ErrDuplicateProgram) {
+		t.Errorf("Update con código de otro programa: se esperaba ErrDuplicateProgram, se obtuvo %v", err)
+	}
+
+	missing := newTestProgram(t, "999999", "Programa inexistente")
+	if err := repo.Update(ctx, missing); !errors.Is(err, repositories.ErrProgramNotFound) {
+		t.Errorf("Update inexistente: se esperaba ErrProgramNotFound, se obtuvo %v", err)
+	}
+} // fin testProgramUpdate
+
+func testProgramList(t *testing.T, repo repositories.ProgramRepository) {
+	ctx := InternalContext()
+
+	redes := newTestProgram(t, "233104", "Gestión de Redes de Datos")
+	software := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	inactive := newTestProgram(t, "122115", "Programa Retirado")
+	inactive.IsActive = false
+	mustCreatePrograms(t, repo, redes, software, inactive)
+
+	all, err := repo.List(ctx, false)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	assertProgramCodes(t, "List", all, []string{"122115", "228118", "233104"})
+
+	active, err := repo.List(ctx, true)
+	if err != nil {
+		t.Fatalf("List activos: %v", err)
+	}
+	assertProgramCodes(t, "List activos", active, []string{"228118", "233104"})
+}
+
+// testUsersByProgram verifica que el filtro, los conteos, la tendencia y el dashboard
+// resuelven el programa de cada usuario a través de su ficha
+func testUsersByProgram(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	users := repos.Users
+
+	software := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	redes := newTestProgram(t, "233104", "Gestión de Redes de Datos")
+	mustCreatePrograms(t, repos.Programs, software, redes)
+	mustCreateFichas(t, repos.Fichas,
+		newTestFicha(t, "2558104", software.ID, 30),
+		newTestFicha(t, "2558105", software.ID, 30),
+		newTestFicha(t, "2558106", redes.ID, 30))
+
+	// La ficha 2558107 no existe y el último usuario no tiene ficha
+	fichas := []string{"2558104", "2558104", "2558105", "2558106", "2558107", ""}
+	created := make([]*entities.User, len(fichas))
+	for i, ficha := range fichas {
+		created[i] = NewTestUser(t, i+1, "Ana", "Gómez", entities.RoleAprendiz)
+		if ficha != "" {
+			created[i].FichaID = &fichas[i]
+		}
+	}
+	deleted := NewTestUser(t, 10, "Marta", "Díaz", entities.RoleAprendiz)
+	deleted.FichaID = &fichas[0]
+	mustCreate(t, users, append(created, deleted)...)
+	if err := users.Delete(ctx, deleted.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	softwareCode, redesCode := software.Code, redes.Code
+	page, err := users.List(ctx, repositories.UserFilters{Programa: &softwareCode})
+	if err != nil {
+		t.Fatalf("List por programa: %v", err)
+	}
+	assertEmails(t, "List por programa", page.Users, []entities.Email{created[0].Email, created[1].Email, created[2].Email})
+
+	var streamed []*entities.User
+	for user, err := range users.Stream(ctx, repositories.UserFilters{Programa: &redesCode}) {
+		if err != nil {
+			t.Fatalf("Stream por programa: %v", err)
+		}
+		streamed = append(streamed, user)
+	}
+	assertEmails(t, "Stream por programa", streamed, []entities.Email{created[3].Email})
+
+	// Los usuarios sin programa no se cuentan
+	counts, err := users.GetTotalUsersByProgram(ctx)
+	if err != nil {
+		t.Fatalf("GetTotalUsersByProgram: %v", err)
+	}
+	if want := map[string]int{"228118": 3, "233104": 1}; !maps.Equal(counts, want) {
+		t.Errorf("GetTotalUsersByProgram = %v, se esperaba %v", counts, want)
+	}
+
+	trend, err := users.GetUserRegistrationTrend(ctx, repositories.RegistrationTrendQuery{
+		From:      time.Now().Add(-time.Hour),
+		To:        time.Now().Add(time.Hour),
+		Bucket:    repositories.TrendByMonth,
+		Location:  time.UTC,
+		Breakdown: repositories.BreakdownProgram,
+	})
+	if err != nil {
+		t.Fatalf("GetUserRegistrationTrend por programa: %v", err)
+	}
+	for key, want := range map[string]int{"228118": 3, "233104": 1, repositories.TrendKeyNone: 2} {
+		if series := trend.SeriesByKey(key); series == nil || series.Total != want {
+			t.Errorf("tendencia del programa %q = %+v, se esperaban %d registros", key, series, want)
+		}
+	}
+
+	snapshot, err := users.AggregateDashboard(ctx)
+	if err != nil {
+		t.Fatalf("AggregateDashboard: %v", err)
+	}
+	if got, want := snapshot.CountBy(entities.DimensionProgram), map[string]int{"228118": 3, "233104": 1, entities.DashboardKeyNone: 2}; !maps.Equal(got, want) {
+		t.Errorf("AggregateDashboard por programa = %v, se esperaba %v", got, want)
+	}
+} // fin testUsersByProgram
+
+func assertProgramCodes(t *testing.T, name string, programs []*entities.Program, want []string) {
+	t.Helper()
+	if len(programs) != len(want) {
+		t.Fatalf("%s: %d programas, se esperaban %d", name, len(programs), len(want))
+	}
+	for i, program := range programs {
+		if program.Code != want[i] {
+			t.Errorf("%s[%d] = %s, se esperaba %s", name, i, program.Code, want[i])
+		}
+	}
+}
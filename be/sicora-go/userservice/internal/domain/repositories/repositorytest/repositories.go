@@ -0,0 +1,31 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"userservice/internal/domain/repositories"
+	"userservice/internal/domain/repositories/internal/tenantctx"
+)
+
+// Repositories agrupa repositorios que comparten datos, para las suites que cruzan agregados
+type Repositories struct {
+	Users        repositories.UserRepository
+	Programs     repositories.ProgramRepository
+	Fichas       repositories.FichaRepository
+	Assignments  repositories.InstructorAssignmentRepository
+	Organization repositories.OrganizationRepository
+	MFAMethods   repositories.MFAMethodRepository
+	BackupCodes  repositories.BackupCodeRepository
+	MFASessions  repositories.MFASessionRepository
+	MFAPolicies  repositories.MFAEnforcementPolicyRepository
+}
+
+// RepositoriesFactory crea repositorios vacíos y aislados para cada caso de prueba
+type RepositoriesFactory func(t *testing.T) Repositories
+
+// InternalContext retorna un contexto de proceso interno, que ve todas las sedes, para las suites y pruebas
+// que no prueban el alcance de sede. Solo es para pruebas: el servicio acota con WithTenant o GrantTenantBypass
+func InternalContext() context.Context {
+	return tenantctx.Without(context.Background())
+}
@@ -0,0 +1,767 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+)
+
+// RunTenantContract ejecuta la suite del alcance de sede sobre la implementación dada
+// Verifica que UserRepository, FichaRepository, InstructorAssignmentRepository y OrganizationRepository
+// respeten WithTenant y que solo un directivo nacional pueda omitirlo
+func RunTenantContract(t *testing.T, newRepos RepositoriesFactory) {
+	t.Run("Unscoped", func(t *testing.T) { testTenantUnscoped(t, newRepos(t)) })
+	t.Run("Reads", func(t *testing.T) { testTenantReads(t, newRepos(t)) })
+	t.Run("Writes", func(t *testing.T) { testTenantWrites(t, newRepos(t)) })
+	t.Run("Bulk", func(t *testing.T) { testTenantBulk(t, newRepos(t)) })
+	t.Run("Dashboards", func(t *testing.T) { testTenantDashboards(t, newRepos(t)) })
+	t.Run("Fichas", func(t *testing.T) { testTenantFichas(t, newRepos(t)) })
+	t.Run("Assignments", func(t *testing.T) { testTenantAssignments(t, newRepos(t)) })
+	t.Run("Organization", func(t *testing.T) { testTenantOrganization(t, newRepos(t)) })
+	t.Run("Bypass", func(t *testing.T) { testTenantBypass(t, newRepos(t)) })
+}
+
+// tenantFixture son dos sedes del mismo centro, una sede de otra regional y un usuario en cada una,
+// más un segundo usuario en norte y uno sin sede
+type tenantFixture struct {
+	norte, otra       repositories.SedePath
+	sur               *entities.Sede
+	ana, luis, carlos *entities.User // norte, norte, sur
+	marta, sinSede    *entities.User // otra, sin sede
+}
+
+func newTenantFixture(t *testing.T, repos Repositories) *tenantFixture {
+	t.Helper()
+	f := &tenantFixture{
+		norte: mustCreateSedePath(t, repos.Organization, "11"),
+		otra:  mustCreateSedePath(t, repos.Organization, "05"),
+	}
+	f.sur = mustCreateSede(t, repos.Organization, f.norte.CentroID, "Sede Sur")
+
+	f.ana = NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	f.ana.SedeID = &f.norte.SedeID
+	f.luis = NewTestUser(t, 2, "Luis", "Pérez", entities.RoleInstructor)
+	f.luis.SedeID = &f.norte.SedeID
+	f.carlos = NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleAprendiz)
+	f.carlos.SedeID = &f.sur.ID
+	f.marta = NewTestUser(t, 4, "Marta", "Díaz", entities.RoleAprendiz)
+	f.marta.SedeID = &f.otra.SedeID
+	f.sinSede = NewTestUser(t, 5, "Nadia", "Castro", entities.RoleAdmin)
+	mustCreate(t, repos.Users, f.ana, f.luis, f.carlos, f.marta, f.sinSede)
+
+	return f
+}
+
+// sede retorna un contexto acotado a la sede dada
+func (f *tenantFixture) sede(path repositories.SedePath) context.Context {
+	return repositories.WithTenant(context.Background(), repositories.OrgFilter{SedeID: &path.SedeID})
+}
+
+// testTenantUnscoped verifica que un contexto sin alcance, bypass ni marca interna se rechace en lugar de ver todas las sedes
+func testTenantUnscoped(t *testing.T, repos Repositories) {
+	f := newTenantFixture(t, repos)
+	ctx := context.Background()
+	now := time.Now()
+
+	nuevo := NewTestUser(t, 10, "Pedro", "Rojas", entities.RoleAprendiz)
+	nuevo.SedeID = &f.norte.SedeID
+	ana := mustGet(t, repos.Users, f.ana.ID)
+	ana.FirstName = "Ana María"
+
+	checks := map[string]func() error{
+		"GetByID":       func() error { _, err := repos.Users.GetByID(ctx, f.ana.ID); return err },
+		"GetByEmail":    func() error { _, err := repos.Users.GetByEmail(ctx, f.ana.Email); return err },
+		"ExistsByEmail": func() error { _, err := repos.Users.ExistsByEmail(ctx, f.ana.Email); return err },
+		"List":          func() error { _, err := repos.Users.List(ctx, repositories.UserFilters{}); return err },
+		"Stream": func() error {
+			for _, err := range repos.Users.Stream(ctx, repositories.UserFilters{}) {
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		"GetMultipleByEmails": func() error {
+			_, err := repos.Users.GetMultipleByEmails(ctx, []entities.Email{f.ana.Email})
+			return err
+		},
+		"GetFichaHistory": func() error { _, err := repos.Users.GetFichaHistory(ctx, f.ana.ID); return err },
+		"Create":          func() error { return repos.Users.Create(ctx, nuevo) },
+		"Update":          func() error { return repos.Users.Update(ctx, ana) },
+		"Delete":          func() error { return repos.Users.Delete(ctx, f.luis.ID, nil) },
+		"RecordLogin": func() error {
+			return repos.Users.RecordLogin(ctx, entities.NewLoginEvent(f.ana.ID, "127.0.0.1", "test"))
+		},
+		"Purge": func() error { _, err := repos.Users.Purge(ctx, now.Add(time.Minute)); return err },
+		"BulkCreate": func() error {
+			_, err := repos.Users.BulkCreate(ctx, []*entities.User{nuevo}, repositories.BulkBestEffort)
+			return err
+		},
+		"BulkStatusChange": func() error {
+			_, err := repos.Users.BulkStatusChange(ctx, []entities.Email{f.ana.Email}, false)
+			return err
+		},
+		"BulkDelete": func() error {
+			_, err := repos.Users.BulkDelete(ctx, []entities.Email{f.ana.Email}, nil)
+			return err
+		},
+		"GetTotalUsersByRole":    func() error { _, err := repos.Users.GetTotalUsersByRole(ctx); return err },
+		"GetActiveInactiveCount": func() error { _, _, err := repos.Users.GetActiveInactiveCount(ctx); return err },
+		"AggregateDashboard":     func() error { _, err := repos.Users.AggregateDashboard(ctx); return err },
+		"GetUserRegistrationTrend": func() error {
+			_, err := repos.Users.GetUserRegistrationTrend(ctx, repositories.RegistrationTrendQuery{
+				From: now.Add(-48 * time.Hour), To: now.Add(time.Hour),
+			})
+			return err
+		},
+		"GetActivityMetrics": func() error {
+			_, err := repos.Users.GetActivityMetrics(ctx, repositories.ActivityQuery{At: now})
+			return err
+		},
+		"ListTenantBypasses": func() error { _, err := repos.Users.ListTenantBypasses(ctx, time.Time{}); return err },
+		"Fichas.GetByNumber": func() error { _, err := repos.Fichas.GetByNumber(ctx, "2901001"); return err },
+		"Fichas.List":        func() error { _, err := repos.Fichas.List(ctx, repositories.FichaFilters{}); return err },
+		"Assignments.ListByInstructor": func() error {
+			_, err := repos.Assignments.ListByInstructor(ctx, f.luis.ID, time.Time{})
+			return err
+		},
+		"Organization.GetSede":     func() error { _, err := repos.Organization.GetSede(ctx, f.norte.SedeID); return err },
+		"Organization.ListCentros": func() error { _, err := repos.Organization.ListCentros(ctx, nil, false); return err },
+		"Organization.GetSedePath": func() error {
+			_, err := repos.Organization.GetSedePath(ctx, f.norte.SedeID)
+			return err
+		},
+	}
+	for name, check := range checks {
+		if err := check(); !errors.Is(err, repositories.ErrNoTenant) {
+			t.Errorf("%s sin alcance: se esperaba ErrNoTenant, se obtuvo %v", name, err)
+		}
+	}
+
+	// Nada de lo anterior llegó a escribir
+	internal := InternalContext()
+	if user, err := repos.Users.GetByEmail(internal, nuevo.Email); err != nil || user != nil {
+		t.Errorf("Create sin alcance creó al usuario: %v, %v", user, err)
+	}
+	if user := mustGet(t, repos.Users, f.ana.ID); user.FirstName != f.ana.FirstName || !user.IsActive || user.IsDeleted() {
+		t.Errorf("las escrituras sin alcance modificaron a %s: %+v", f.ana.Email, user)
+	}
+	if mustGet(t, repos.Users, f.luis.ID).IsDeleted() {
+		t.Error("Delete sin alcance eliminó al usuario")
+	}
+} // fin testTenantUnscoped
+
+// testTenantReads verifica que las consultas solo vean a los usuarios del alcance
+func testTenantReads(t *testing.T, repos Repositories) {
+	f := newTenantFixture(t, repos)
+	norte := f.sede(f.norte)
+
+	if user, err := repos.Users.GetByID(norte, f.ana.ID); err != nil || user == nil {
+		t.Errorf("GetByID en la sede = %v, %v", user, err)
+	}
+	if user, err := repos.Users.GetByID(norte, f.marta.ID); err != nil || user != nil {
+		t.Errorf("GetByID fuera de la sede = %v, %v; se esperaba nil", user, err)
+	}
+	if user, err := repos.Users.GetByEmail(norte, f.carlos.Email); err != nil || user != nil {
+		t.Errorf("GetByEmail fuera de la sede = %v, %v; se esperaba nil", user, err)
+	}
+	if user, err := repos.Users.GetByDocument(norte, f.sinSede.DocumentType, f.sinSede.DocumentNumber); err != nil || user != nil {
+		t.Errorf("GetByDocument sin sede = %v, %v; se esperaba nil", user, err)
+	}
+	if exists, err := repos.Users.ExistsByEmail(norte, f.marta.Email); err != nil || exists {
+		t.Errorf("ExistsByEmail fuera de la sede = %v, %v; se esperaba false", exists, err)
+	}
+
+	result, err := repos.Users.List(norte, repositories.UserFilters{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	assertEmails(t, "List en la sede", result.Users, []entities.Email{f.ana.Email, f.luis.Email})
+	if result.Total != 2 {
+		t.Errorf("List en la sede: total %d, se esperaba 2", result.Total)
+	}
+
+	// El filtro del llamador se combina con el alcance: no lo amplía
+	result, err = repos.Users.List(norte, repositories.UserFilters{
+		OrgFilter: repositories.OrgFilter{SedeID: &f.otra.SedeID},
+	})
+	if err != nil {
+		t.Fatalf("List con filtro de otra sede: %v", err)
+	}
+	assertEmails(t, "List con filtro de otra sede", result.Users, nil)
+
+	// Un alcance por centro ve las sedes del centro
+	centro := repositories.WithTenant(context.Background(), repositories.OrgFilter{CentroID: &f.norte.CentroID})
+	result, err = repos.Users.List(centro, repositories.UserFilters{})
+	if err != nil {
+		t.Fatalf("List en el centro: %v", err)
+	}
+	assertEmails(t, "List en el centro", result.Users, []entities.Email{f.ana.Email, f.luis.Email, f.carlos.Email})
+
+	var streamed []*entities.User
+	for user, err := range repos.Users.Stream(norte, repositories.UserFilters{}) {
+		if err != nil {
+			t.Fatalf("Stream: %v", err)
+		}
+		streamed = append(streamed, user)
+	}
+	assertEmails(t, "Stream en la sede", streamed, []entities.Email{f.ana.Email, f.luis.Email})
+
+	users, err := repos.Users.GetMultipleByEmails(norte, []entities.Email{f.ana.Email, f.carlos.Email, f.marta.Email})
+	if err != nil {
+		t.Fatalf("GetMultipleByEmails: %v", err)
+	}
+	assertEmails(t, "GetMultipleByEmails en la sede", users, []entities.Email{f.ana.Email})
+
+	if _, err := repos.Users.GetLoginHistory(norte, f.marta.ID, 0); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("GetLoginHistory fuera de la sede: se esperaba ErrUserNotFound, se obtuvo %v", err)
+	}
+	if _, err := repos.Users.GetFichaHistory(norte, f.marta.ID); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("GetFichaHistory fuera de la sede: se esperaba ErrUserNotFound, se obtuvo %v", err)
+	}
+
+	// Un alcance vacío no ve a nadie
+	nadie := repositories.WithTenant(context.Background(), repositories.OrgFilter{})
+	result, err = repos.Users.List(nadie, repositories.UserFilters{})
+	if err != nil {
+		t.Fatalf("List con alcance vacío: %v", err)
+	}
+	assertEmails(t, "List con alcance vacío", result.Users, nil)
+} // fin testTenantReads
+
+// testTenantWrites verifica que las modificaciones fuera del alcance se comporten como usuarios inexistentes
+// y que no se pueda crear ni mover a un usuario fuera del alcance
+func testTenantWrites(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	f := newTenantFixture(t, repos)
+	norte := f.sede(f.norte)
+
+	nuevo := NewTestUser(t, 10, "Pedro", "Rojas", entities.RoleAprendiz)
+	nuevo.SedeID = &f.otra.SedeID
+	if err := repos.Users.Create(norte, nuevo); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("Create en otra sede: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	nuevo.SedeID = nil
+	if err := repos.Users.Create(norte, nuevo); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("Create sin sede: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	nuevo.SedeID = &f.norte.SedeID
+	if err := repos.Users.Create(norte, nuevo); err != nil {
+		t.Errorf("Create en la sede: %v", err)
+	}
+
+	marta := mustGet(t, repos.Users, f.marta.ID)
+	marta.FirstName = "Martha"
+	if err := repos.Users.Update(norte, marta); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("Update fuera de la sede: se esperaba ErrUserNotFound, se obtuvo %v", err)
+	}
+
+	// Mover a un usuario propio a otra sede lo sacaría del alcance
+	ana := mustGet(t, repos.Users, f.ana.ID)
+	ana.SedeID = &f.sur.ID
+	if err := repos.Users.Update(norte, ana); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("Update a otra sede: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	ana = mustGet(t, repos.Users, f.ana.ID)
+	ana.FirstName = "Ana María"
+	if err := repos.Users.Update(norte, ana); err != nil {
+		t.Errorf("Update en la sede: %v", err)
+	}
+
+	if err := repos.Users.Delete(norte, f.marta.ID, nil); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("Delete fuera de la sede: se esperaba ErrUserNotFound, se obtuvo %v", err)
+	}
+	if mustGet(t, repos.Users, f.marta.ID).IsDeleted() {
+		t.Error("Delete fuera de la sede eliminó al usuario")
+	}
+
+	event := entities.NewLoginEvent(f.carlos.ID, "127.0.0.1", "test")
+	if err := repos.Users.RecordLogin(norte, event); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("RecordLogin fuera de la sede: se esperaba ErrUserNotFound, se obtuvo %v", err)
+	}
+
+	// Restore y Purge solo alcanzan a los eliminados de la sede
+	for _, user := range []*entities.User{f.luis, f.marta} {
+		if err := repos.Users.Delete(ctx, user.ID, nil); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	}
+	if err := repos.Users.Restore(norte, f.marta.ID); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("Restore fuera de la sede: se esperaba ErrUserNotFound, se obtuvo %v", err)
+	}
+	if purged, err := repos.Users.Purge(norte, time.Now().Add(time.Minute)); err != nil || purged != 1 {
+		t.Errorf("Purge en la sede = %d, %v; se esperaba 1", purged, err)
+	}
+	if err := repos.Users.Restore(ctx, f.marta.ID); err != nil {
+		t.Errorf("Restore sin alcance tras Purge en otra sede: %v", err)
+	}
+} // fin testTenantWrites
+
+// testTenantBulk verifica que las operaciones masivas reporten como fallidas las filas fuera del alcance
+func testTenantBulk(t *testing.T, repos Repositories) {
+	f := newTenantFixture(t, repos)
+	norte := f.sede(f.norte)
+
+	propio := NewTestUser(t, 10, "Pedro", "Rojas", entities.RoleAprendiz)
+	propio.SedeID = &f.norte.SedeID
+	ajeno := NewTestUser(t, 11, "Sofía", "Mora", entities.RoleAprendiz)
+	ajeno.SedeID = &f.otra.SedeID
+
+	result, err := repos.Users.BulkCreate(norte, []*entities.User{propio, ajeno}, repositories.BulkBestEffort)
+	if err != nil || result.Success != 1 || result.Failed != 1 {
+		t.Fatalf("BulkCreate = %+v, %v; se esperaba 1 creado y 1 fallido", result, err)
+	}
+	if rowErr := result.Errors[0]; rowErr.Index != 1 || rowErr.Error != repositories.ErrOutsideTenant.Error() {
+		t.Errorf("BulkCreate fila ajena = %+v, se esperaba ErrOutsideTenant en la fila 1", rowErr)
+	}
+
+	atomic := NewTestUser(t, 12, "Julián", "Vega", entities.RoleAprendiz)
+	atomic.SedeID = &f.norte.SedeID
+	result, err = repos.Users.BulkCreate(norte, []*entities.User{atomic, ajeno}, repositories.BulkAtomic)
+	if err != nil || result.Success != 0 {
+		t.Errorf("BulkCreate atómico con una fila ajena = %+v, %v; no debe crear nada", result, err)
+	}
+
+	marta := mustGet(t, repos.Users, f.marta.ID)
+	ana := mustGet(t, repos.Users, f.ana.ID)
+	ana.LastName = "Gómez Restrepo"
+	result, err = repos.Users.BulkUpdate(norte, map[entities.Email]*entities.User{ana.Email: ana, marta.Email: marta})
+	if err != nil || result.Success != 1 || result.Failed != 1 {
+		t.Errorf("BulkUpdate = %+v, %v; se esperaba 1 actualizado y 1 fallido", result, err)
+	}
+
+	result, err = repos.Users.BulkStatusChange(norte, []entities.Email{f.luis.Email, f.carlos.Email}, false)
+	if err != nil || result.Success != 1 || result.Failed != 1 {
+		t.Errorf("BulkStatusChange = %+v, %v; se esperaba 1 cambio y 1 fallido", result, err)
+	}
+	if !mustGet(t, repos.Users, f.carlos.ID).IsActive {
+		t.Error("BulkStatusChange desactivó a un usuario de otra sede")
+	}
+
+	result, err = repos.Users.BulkDelete(norte, []entities.Email{f.luis.Email, f.marta.Email}, nil)
+	if err != nil || result.Success != 1 || result.Failed != 1 {
+		t.Errorf("BulkDelete = %+v, %v; se esperaba 1 eliminado y 1 fallido", result, err)
+	}
+	if mustGet(t, repos.Users, f.marta.ID).IsDeleted() {
+		t.Error("BulkDelete eliminó a un usuario de otra sede")
+	}
+} // fin testTenantBulk
+
+// testTenantDashboards verifica que conteos, tendencias y métricas solo cuenten a los usuarios del alcance
+func testTenantDashboards(t *testing.T, repos Repositories) {
+	f := newTenantFixture(t, repos)
+	norte := f.sede(f.norte)
+
+	roles, err := repos.Users.GetTotalUsersByRole(norte)
+	if err != nil {
+		t.Fatalf("GetTotalUsersByRole: %v", err)
+	}
+	if len(roles) != 2 || roles[string(entities.RoleAprendiz)] != 1 || roles[string(entities.RoleInstructor)] != 1 {
+		t.Errorf("GetTotalUsersByRole en la sede = %v", roles)
+	}
+
+	if active, inactive, err := repos.Users.GetActiveInactiveCount(norte); err != nil || active != 2 || inactive != 0 {
+		t.Errorf("GetActiveInactiveCount en la sede = %d, %d, %v; se esperaba 2, 0", active, inactive, err)
+	}
+
+	levels, err := repos.Users.GetTotalUsersByOrgLevel(norte, repositories.OrgLevelSede, repositories.OrgFilter{})
+	if err != nil {
+		t.Fatalf("GetTotalUsersByOrgLevel: %v", err)
+	}
+	if len(levels) != 1 || levels[f.norte.SedeID.String()] != 2 {
+		t.Errorf("GetTotalUsersByOrgLevel en la sede = %v", levels)
+	}
+
+	snapshot, err := repos.Users.AggregateDashboard(norte)
+	if err != nil {
+		t.Fatalf("AggregateDashboard: %v", err)
+	}
+	total := 0
+	for _, cell := range snapshot.Cells {
+		if cell.Sede != f.norte.SedeID.String() {
+			t.Errorf("AggregateDashboard en la sede incluye la sede %s", cell.Sede)
+		}
+		total += cell.Count
+	}
+	if total != 2 {
+		t.Errorf("AggregateDashboard en la sede: %d usuarios, se esperaban 2", total)
+	}
+
+	now := time.Now()
+	trend, err := repos.Users.GetUserRegistrationTrend(norte, repositories.RegistrationTrendQuery{
+		From: now.Add(-48 * time.Hour), To: now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("GetUserRegistrationTrend: %v", err)
+	}
+	if trend.Total.Total != 2 {
+		t.Errorf("GetUserRegistrationTrend en la sede: %d registros, se esperaban 2", trend.Total.Total)
+	}
+
+	report, err := repos.Users.GetActivityMetrics(norte, repositories.ActivityQuery{At: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("GetActivityMetrics: %v", err)
+	}
+	if report.Total.Users != 2 {
+		t.Errorf("GetActivityMetrics en la sede: %d usuarios, se esperaban 2", report.Total.Users)
+	}
+
+	// Un filtro de otra regional no amplía el alcance
+	report, err = repos.Users.GetActivityMetrics(norte, repositories.ActivityQuery{
+		At:        now.Add(time.Minute),
+		OrgFilter: repositories.OrgFilter{RegionalID: &f.otra.RegionalID},
+	})
+	if err != nil {
+		t.Fatalf("GetActivityMetrics con filtro de otra regional: %v", err)
+	}
+	if report.Total.Users != 0 {
+		t.Errorf("GetActivityMetrics con filtro de otra regional: %d usuarios, se esperaban 0", report.Total.Users)
+	}
+} // fin testTenantDashboards
+
+// testTenantBypass verifica que solo un directivo nacional activo omita el alcance y que el bypass quede auditado
+func testTenantBypass(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	f := newTenantFixture(t, repos)
+	reason := "Consolidado nacional de matrículas"
+
+	coordinador := NewTestUser(t, 20, "Diana", "López", entities.RoleCoordinador)
+	coordinador.SedeID = &f.norte.SedeID
+	regional := NewTestUser(t, 21, "Jorge", "Mejía", entities.RoleDirectivo)
+	regional.SedeID = &f.otra.SedeID
+	nacional := NewTestUser(t, 22, "Elena", "Suárez", entities.RoleDirectivo)
+	mustCreate(t, repos.Users, coordinador, regional, nacional)
+
+	for _, actor := range []*entities.User{coordinador, regional} {
+		if _, err := repositories.GrantTenantBypass(ctx, repos.Users, actor.ID, reason); !errors.Is(err, repositories.ErrTenantBypassDenied) {
+			t.Errorf("GrantTenantBypass(%s): se esperaba ErrTenantBypassDenied, se obtuvo %v", actor.Role, err)
+		}
+	}
+
+	var domainErr *entities.DomainError
+	if _, err := repositories.GrantTenantBypass(ctx, repos.Users, nacional.ID, "porque sí"); !errors.As(err, &domainErr) {
+		t.Errorf("GrantTenantBypass con motivo corto: se esperaba *DomainError, se obtuvo %v", err)
+	}
+
+	// Sin auditoría no hay bypass: el actor debe existir en el repositorio
+	ghost := NewTestUser(t, 23, "Raúl", "Niño", entities.RoleDirectivo)
+	if _, err := repositories.GrantTenantBypass(ctx, repos.Users, ghost.ID, reason); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("GrantTenantBypass de un actor inexistente: se esperaba ErrUserNotFound, se obtuvo %v", err)
+	}
+
+	// Cuenta lo guardado, no lo que diga el llamador: un directivo inactivo no omite el alcance
+	inactivo := NewTestUser(t, 24, "Sergio", "Quintero", entities.RoleDirectivo)
+	inactivo.IsActive = false
+	mustCreate(t, repos.Users, inactivo)
+	if _, err := repositories.GrantTenantBypass(ctx, repos.Users, inactivo.ID, reason); !errors.Is(err, repositories.ErrTenantBypassDenied) {
+		t.Errorf("GrantTenantBypass de un directivo inactivo: se esperaba ErrTenantBypassDenied, se obtuvo %v", err)
+	}
+
+	// RecordTenantBypass repite la verificación: un bypass armado a mano para el directivo regional no se registra
+	forged, err := entities.NewTenantBypass(regional.ID, reason)
+	if err != nil {
+		t.Fatalf("NewTenantBypass: %v", err)
+	}
+	if err := repos.Users.RecordTenantBypass(ctx, forged); !errors.Is(err, repositories.ErrTenantBypassDenied) {
+		t.Errorf("RecordTenantBypass de un directivo regional: se esperaba ErrTenantBypassDenied, se obtuvo %v", err)
+	}
+
+	// El bypass reemplaza el alcance que trajera el contexto
+	before := time.Now().Add(-time.Second)
+	bypass, err := repositories.GrantTenantBypass(f.sede(f.norte), repos.Users, nacional.ID, reason)
+	if err != nil {
+		t.Fatalf("GrantTenantBypass: %v", err)
+	}
+	result, err := repos.Users.List(bypass, repositories.UserFilters{})
+	if err != nil {
+		t.Fatalf("List con bypass: %v", err)
+	}
+	if result.Total != 9 {
+		t.Errorf("List con bypass: total %d, se esperaban 9", result.Total)
+	}
+
+	audit, err := repos.Users.ListTenantBypasses(ctx, before)
+	if err != nil {
+		t.Fatalf("ListTenantBypasses: %v", err)
+	}
+	granted := repositories.TenantBypassFrom(bypass)
+	if len(audit) != 1 || audit[0].ID != granted.ID || audit[0].ActorID != nacional.ID || audit[0].Reason != reason {
+		t.Errorf("ListTenantBypasses = %+v, se esperaba el bypass de %s", audit, nacional.Email)
+	}
+	if audit, err := repos.Users.ListTenantBypasses(ctx, time.Now().Add(time.Hour)); err != nil || len(audit) != 0 {
+		t.Errorf("ListTenantBypasses futuro = %d, %v; se esperaba vacío", len(audit), err)
+	}
+
+	// Una sede solo ve los bypass de sus actores, y un directivo nacional no tiene sede
+	if audit, err := repos.Users.ListTenantBypasses(f.sede(f.norte), before); err != nil || len(audit) != 0 {
+		t.Errorf("ListTenantBypasses en la sede = %d, %v; se esperaba vacío", len(audit), err)
+	}
+} // fin testTenantBypass
+
+// tenantFichas crea un programa y tres fichas: una en norte, una en otra y una sin sede
+func tenantFichas(t *testing.T, repos Repositories, f *tenantFixture) (propia, ajena, sinSede *entities.Ficha) {
+	t.Helper()
+
+	program := newTestProgram(t, "228118", "Análisis y Desarrollo de Software")
+	mustCreatePrograms(t, repos.Programs, program)
+
+	propia = newTestFicha(t, "2901001", program.ID, 30)
+	propia.SedeID = &f.norte.SedeID
+	ajena = newTestFicha(t, "2901002", program.ID, 30)
+	ajena.SedeID = &f.otra.SedeID
+	sinSede = newTestFicha(t, "2901003", program.ID, 30)
+	mustCreateFichas(t, repos.Fichas, propia, ajena, sinSede)
+
+	return propia, ajena, sinSede
+}
+
+// testTenantFichas verifica que las fichas se acoten por su sede: las de otra sede o sin sede no existen
+func testTenantFichas(t *testing.T, repos Repositories) {
+	f := newTenantFixture(t, repos)
+	norte := f.sede(f.norte)
+	propia, ajena, sinSede := tenantFichas(t, repos, f)
+
+	if ficha, err := repos.Fichas.GetByNumber(norte, propia.Number); err != nil || ficha == nil {
+		t.Errorf("GetByNumber en la sede = %v, %v", ficha, err)
+	}
+	for _, number := range []string{ajena.Number, sinSede.Number} {
+		if ficha, err := repos.Fichas.GetByNumber(norte, number); err != nil || ficha != nil {
+			t.Errorf("GetByNumber(%s) fuera de la sede = %v, %v; se esperaba nil", number, ficha, err)
+		}
+	}
+
+	fichas, err := repos.Fichas.List(norte, repositories.FichaFilters{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	assertFichaNumbers(t, "List en la sede", fichas, []string{propia.Number})
+
+	// El filtro del llamador no amplía el alcance
+	fichas, err = repos.Fichas.List(norte, repositories.FichaFilters{SedeID: &f.otra.SedeID})
+	if err != nil {
+		t.Fatalf("List con filtro de otra sede: %v", err)
+	}
+	assertFichaNumbers(t, "List con filtro de otra sede", fichas, nil)
+
+	nueva := newTestFicha(t, "2901004", propia.ProgramID, 30)
+	nueva.SedeID = &f.otra.SedeID
+	if err := repos.Fichas.Create(norte, nueva); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("Create en otra sede: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	nueva.SedeID = nil
+	if err := repos.Fichas.Create(norte, nueva); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("Create sin sede: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	nueva.SedeID = &f.norte.SedeID
+	if err := repos.Fichas.Create(norte, nueva); err != nil {
+		t.Errorf("Create en la sede: %v", err)
+	}
+
+	ajena = mustGetFicha(t, repos.Fichas, ajena.Number)
+	ajena.Capacity = 40
+	if err := repos.Fichas.Update(norte, ajena); !errors.Is(err, repositories.ErrFichaNotFound) {
+		t.Errorf("Update fuera de la sede: se esperaba ErrFichaNotFound, se obtuvo %v", err)
+	}
+	if mustGetFicha(t, repos.Fichas, ajena.Number).Capacity != 30 {
+		t.Error("Update fuera de la sede modificó la ficha")
+	}
+
+	// Mover una ficha propia a otra sede la sacaría del alcance
+	propia = mustGetFicha(t, repos.Fichas, propia.Number)
+	propia.SedeID = &f.sur.ID
+	if err := repos.Fichas.Update(norte, propia); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("Update a otra sede: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	propia.SedeID = &f.norte.SedeID
+	propia.Capacity = 35
+	if err := repos.Fichas.Update(norte, propia); err != nil {
+		t.Errorf("Update en la sede: %v", err)
+	}
+
+	// Los aprendices solo ven, ingresan y se trasladan a fichas del alcance
+	for _, number := range []string{ajena.Number, sinSede.Number} {
+		if _, err := repos.Users.GetByFicha(norte, number, time.Time{}); !errors.Is(err, repositories.ErrFichaNotFound) {
+			t.Errorf("GetByFicha(%s) fuera de la sede: se esperaba ErrFichaNotFound, se obtuvo %v", number, err)
+		}
+		if err := repos.Users.AssignFicha(norte, f.ana.ID, number); !errors.Is(err, repositories.ErrFichaNotFound) {
+			t.Errorf("AssignFicha(%s) fuera de la sede: se esperaba ErrFichaNotFound, se obtuvo %v", number, err)
+		}
+	}
+
+	// Con alcance de centro la ficha es visible, pero debe ser de la sede del aprendiz
+	centro := repositories.WithTenant(context.Background(), repositories.OrgFilter{CentroID: &f.norte.CentroID})
+	if err := repos.Users.AssignFicha(centro, f.carlos.ID, propia.Number); !errors.Is(err, repositories.ErrFichaOtherSede) {
+		t.Errorf("AssignFicha a una ficha de otra sede: se esperaba ErrFichaOtherSede, se obtuvo %v", err)
+	}
+	if err := repos.Users.AssignFicha(norte, f.ana.ID, propia.Number); err != nil {
+		t.Fatalf("AssignFicha en la sede: %v", err)
+	}
+
+	sur := newTestFicha(t, "2901005", propia.ProgramID, 30)
+	sur.SedeID = &f.sur.ID
+	mustCreateFichas(t, repos.Fichas, sur)
+
+	transfer := func(to string) *entities.FichaMovement {
+		t.Helper()
+		movement, err := entities.NewFichaTransfer(f.ana.ID, to, "Cambio de sede", f.sinSede.ID, time.Now())
+		if err != nil {
+			t.Fatalf("NewFichaTransfer: %v", err)
+		}
+		return movement
+	}
+	if err := repos.Users.TransferFicha(norte, transfer(ajena.Number)); !errors.Is(err, repositories.ErrFichaNotFound) {
+		t.Errorf("TransferFicha fuera de la sede: se esperaba ErrFichaNotFound, se obtuvo %v", err)
+	}
+	if err := repos.Users.TransferFicha(centro, transfer(sur.Number)); !errors.Is(err, repositories.ErrFichaOtherSede) {
+		t.Errorf("TransferFicha a una ficha de otra sede: se esperaba ErrFichaOtherSede, se obtuvo %v", err)
+	}
+	if users, err := repos.Users.GetByFicha(norte, propia.Number, time.Time{}); err != nil || len(users) != 1 {
+		t.Errorf("GetByFicha en la sede = %d usuarios, %v; se esperaba a Ana", len(users), err)
+	}
+} // fin testTenantFichas
+
+// testTenantAssignments verifica que las asignaciones se acoten por la sede de su ficha y no por la del instructor
+func testTenantAssignments(t *testing.T, repos Repositories) {
+	f := newTenantFixture(t, repos)
+	norte := f.sede(f.norte)
+	propia, ajena, _ := tenantFichas(t, repos, f)
+
+	externo := NewTestUser(t, 10, "Jorge", "Mejía", entities.RoleInstructor)
+	externo.SedeID = &f.otra.SedeID
+	mustCreate(t, repos.Users, externo)
+
+	// Un instructor de otra sede puede asignarse a una ficha de la sede
+	enPropia := newTestAssignment(t, externo.ID, propia.Number, "Programación", day(time.February, 2))
+	if err := repos.Assignments.Create(norte, enPropia); err != nil {
+		t.Errorf("Create en una ficha de la sede: %v", err)
+	}
+
+	enAjena := newTestAssignment(t, f.luis.ID, ajena.Number, "Bases de datos", day(time.February, 2))
+	if err := repos.Assignments.Create(norte, enAjena); !errors.Is(err, repositories.ErrFichaNotFound) {
+		t.Errorf("Create en una ficha de otra sede: se esperaba ErrFichaNotFound, se obtuvo %v", err)
+	}
+	mustCreateAssignments(t, repos.Assignments, enAjena)
+
+	if assignment, err := repos.Assignments.GetByID(norte, enPropia.ID); err != nil || assignment == nil {
+		t.Errorf("GetByID en la sede = %v, %v", assignment, err)
+	}
+	if assignment, err := repos.Assignments.GetByID(norte, enAjena.ID); err != nil || assignment != nil {
+		t.Errorf("GetByID fuera de la sede = %v, %v; se esperaba nil", assignment, err)
+	}
+	if err := repos.Assignments.End(norte, enAjena.ID, day(time.June, 30)); !errors.Is(err, repositories.ErrAssignmentNotFound) {
+		t.Errorf("End fuera de la sede: se esperaba ErrAssignmentNotFound, se obtuvo %v", err)
+	}
+
+	if assignments, err := repos.Assignments.ListByInstructor(norte, f.luis.ID, time.Time{}); err != nil || len(assignments) != 0 {
+		t.Errorf("ListByInstructor con asignaciones en otra sede = %d, %v; se esperaba vacío", len(assignments), err)
+	}
+	if assignments, err := repos.Assignments.ListByInstructor(norte, externo.ID, time.Time{}); err != nil || len(assignments) != 1 {
+		t.Errorf("ListByInstructor de un instructor de otra sede = %d, %v; se esperaba 1", len(assignments), err)
+	}
+	if assignments, err := repos.Assignments.ListByFicha(norte, ajena.Number, time.Time{}); err != nil || len(assignments) != 0 {
+		t.Errorf("ListByFicha fuera de la sede = %d, %v; se esperaba vacío", len(assignments), err)
+	}
+	if assigned, err := repos.Assignments.IsAssigned(norte, f.luis.ID, ajena.Number, day(time.March, 2)); err != nil || assigned {
+		t.Errorf("IsAssigned fuera de la sede = %v, %v; se esperaba false", assigned, err)
+	}
+} // fin testTenantAssignments
+
+// testTenantOrganization verifica que la jerarquía solo muestre las sedes del alcance y los nodos que las contienen
+// y que con alcance solo se creen o modifiquen sedes del alcance
+func testTenantOrganization(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	f := newTenantFixture(t, repos)
+	norte := f.sede(f.norte)
+	centro := repositories.WithTenant(context.Background(), repositories.OrgFilter{CentroID: &f.norte.CentroID})
+
+	if sede, err := repos.Organization.GetSede(norte, f.norte.SedeID); err != nil || sede == nil {
+		t.Errorf("GetSede en la sede = %v, %v", sede, err)
+	}
+	if sede, err := repos.Organization.GetSede(norte, f.sur.ID); err != nil || sede != nil {
+		t.Errorf("GetSede de otra sede del centro = %v, %v; se esperaba nil", sede, err)
+	}
+	if path, err := repos.Organization.GetSedePath(norte, f.otra.SedeID); err != nil || path != nil {
+		t.Errorf("GetSedePath fuera de la sede = %v, %v; se esperaba nil", path, err)
+	}
+	if got, err := repos.Organization.GetCentro(norte, f.norte.CentroID); err != nil || got == nil {
+		t.Errorf("GetCentro de la sede = %v, %v", got, err)
+	}
+	if got, err := repos.Organization.GetCentro(norte, f.otra.CentroID); err != nil || got != nil {
+		t.Errorf("GetCentro de otra regional = %v, %v; se esperaba nil", got, err)
+	}
+	if got, err := repos.Organization.GetRegional(norte, f.otra.RegionalID); err != nil || got != nil {
+		t.Errorf("GetRegional de otra sede = %v, %v; se esperaba nil", got, err)
+	}
+
+	if regionales, err := repos.Organization.ListRegionales(norte, false); err != nil || len(regionales) != 1 || regionales[0].ID != f.norte.RegionalID {
+		t.Errorf("ListRegionales en la sede = %v, %v; se esperaba solo su regional", regionales, err)
+	}
+	if centros, err := repos.Organization.ListCentros(norte, nil, false); err != nil || len(centros) != 1 || centros[0].ID != f.norte.CentroID {
+		t.Errorf("ListCentros en la sede = %v, %v; se esperaba solo su centro", centros, err)
+	}
+	if sedes, err := repos.Organization.ListSedes(norte, nil, false); err != nil || len(sedes) != 1 || sedes[0].ID != f.norte.SedeID {
+		t.Errorf("ListSedes en la sede = %v, %v; se esperaba solo la sede", sedes, err)
+	}
+	if sedes, err := repos.Organization.ListSedes(centro, nil, false); err != nil || len(sedes) != 2 {
+		t.Errorf("ListSedes en el centro = %d, %v; se esperaban 2", len(sedes), err)
+	}
+
+	// Regionales y centros afectan a varias sedes: con alcance no se crean ni modifican
+	regional, _ := entities.NewRegional("R99", "Regional Nueva")
+	if err := repos.Organization.CreateRegional(centro, regional); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("CreateRegional con alcance: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	nuevoCentro, _ := entities.NewCentro(f.norte.RegionalID, "C99", "Centro Nuevo")
+	if err := repos.Organization.CreateCentro(centro, nuevoCentro); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("CreateCentro con alcance: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	propio, err := repos.Organization.GetCentro(ctx, f.norte.CentroID)
+	if err != nil || propio == nil {
+		t.Fatalf("GetCentro = %v, %v", propio, err)
+	}
+	propio.Name = "Centro Renombrado"
+	if err := repos.Organization.UpdateCentro(centro, propio); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("UpdateCentro con alcance: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+
+	// Una sede nueva solo queda en el alcance de su centro, no en el de otra sede
+	nueva, _ := entities.NewSede(f.norte.CentroID, "Sede Nueva", "")
+	if err := repos.Organization.CreateSede(norte, nueva); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("CreateSede con alcance de otra sede: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	if err := repos.Organization.CreateSede(centro, nueva); err != nil {
+		t.Errorf("CreateSede en el centro: %v", err)
+	}
+	ajena, _ := entities.NewSede(f.otra.CentroID, "Sede Ajena", "")
+	if err := repos.Organization.CreateSede(centro, ajena); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("CreateSede en otro centro: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+
+	otra, err := repos.Organization.GetSede(ctx, f.otra.SedeID)
+	if err != nil || otra == nil {
+		t.Fatalf("GetSede = %v, %v", otra, err)
+	}
+	otra.Address = "Calle 1 # 2-3"
+	if err := repos.Organization.UpdateSede(norte, otra); !errors.Is(err, repositories.ErrSedeNotFound) {
+		t.Errorf("UpdateSede fuera de la sede: se esperaba ErrSedeNotFound, se obtuvo %v", err)
+	}
+
+	// Mover la sede a otro centro la sacaría del alcance del centro
+	sede, err := repos.Organization.GetSede(ctx, f.norte.SedeID)
+	if err != nil || sede == nil {
+		t.Fatalf("GetSede = %v, %v", sede, err)
+	}
+	sede.CentroID = f.otra.CentroID
+	if err := repos.Organization.UpdateSede(centro, sede); !errors.Is(err, repositories.ErrOutsideTenant) {
+		t.Errorf("UpdateSede a otro centro: se esperaba ErrOutsideTenant, se obtuvo %v", err)
+	}
+	sede.CentroID = f.norte.CentroID
+	sede.Address = "Carrera 7 # 8-9"
+	if err := repos.Organization.UpdateSede(norte, sede); err != nil {
+		t.Errorf("UpdateSede en la sede: %v", err)
+	}
+} // fin testTenantOrganization
@@ -0,0 +1,1833 @@
+// Package repositorytest contiene la suite de contrato que toda implementación
+// de los repositorios del dominio debe superar (Postgres, SQLite, decoradores, memoria)
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/filter"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	apperrors "sicora-be-go/pkg/errors"
+)
+
+// UserRepositoryFactory crea un repositorio vacío y aislado para cada caso de prueba
+type UserRepositoryFactory func(t *testing.T) repositories.UserRepository
+
+// RunUserRepositoryContract ejecuta la suite de contrato sobre la implementación dada
+func RunUserRepositoryContract(t *testing.T, newRepo UserRepositoryFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) { testCreateAndGet(t, newRepo(t)) })
+	t.Run("CreateDuplicate", func(t *testing.T) { testCreateDuplicate(t, newRepo(t)) })
+	t.Run("EmailCaseInsensitive", func(t *testing.T) { testEmailCaseInsensitive(t, newRepo(t)) })
+	t.Run("Documents", func(t *testing.T) { testDocuments(t, newRepo(t)) })
+	t.Run("StructuredErrors", func(t *testing.T) { testStructuredErrors(t, newRepo(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newRepo(t)) })
+	t.Run("OptimisticLocking", func(t *testing.T) { testOptimisticLocking(t, newRepo(t)) })
+	t.Run("SoftDelete", func(t *testing.T) { testSoftDelete(t, newRepo(t)) })
+	t.Run("Restore", func(t *testing.T) { testRestore(t, newRepo(t)) })
+	t.Run("Purge", func(t *testing.T) { testPurge(t, newRepo(t)) })
+	t.Run("Exists", func(t *testing.T) { testExists(t, newRepo(t)) })
+	t.Run("ListPagination", func(t *testing.T) { testListPagination(t, newRepo(t)) })
+	t.Run("ListCursorPagination", func(t *testing.T) { testListCursorPagination(t, newRepo(t)) })
+	t.Run("ListFilters", func(t *testing.T) { testListFilters(t, newRepo(t)) })
+	t.Run("ListFilterExpression", func(t *testing.T) { testListFilterExpression(t, newRepo(t)) })
+	t.Run("ListMultiColumnSort", func(t *testing.T) { testListMultiColumnSort(t, newRepo(t)) })
+	t.Run("ListSearch", func(t *testing.T) { testListSearch(t, newRepo(t)) })
+	t.Run("Stream", func(t *testing.T) { testStream(t, newRepo(t)) })
+	t.Run("BulkCreate", func(t *testing.T) { testBulkCreate(t, newRepo(t)) })
+	t.Run("BulkCreateBestEffort", func(t *testing.T) { testBulkCreateBestEffort(t, newRepo(t)) })
+	t.Run("BulkCreateAtomic", func(t *testing.T) { testBulkCreateAtomic(t, newRepo(t)) })
+	t.Run("BulkUpdate", func(t *testing.T) { testBulkUpdate(t, newRepo(t)) })
+	t.Run("BulkDelete", func(t *testing.T) { testBulkDelete(t, newRepo(t)) })
+	t.Run("BulkStatusChange", func(t *testing.T) { testBulkStatusChange(t, newRepo(t)) })
+	t.Run("GetMultipleByEmails", func(t *testing.T) { testGetMultipleByEmails(t, newRepo(t)) })
+	t.Run("DashboardQueries", func(t *testing.T) { testDashboardQueries(t, newRepo(t)) })
+	t.Run("LoginHistory", func(t *testing.T) { testLoginHistory(t, newRepo(t)) })
+} // fin RunUserRepositoryContract
+
+// NewTestUser construye un usuario válido cuyo email y documento derivan de seq
+func NewTestUser(t *testing.T, seq int, firstName, lastName string, role entities.UserRole) *entities.User {
+	t.Helper()
+
+	user, err := entities.NewUser(
+		firstName,
+		lastName,
+		fmt.Sprintf("usuario%03d@sena.edu.co", seq),
+		fmt.Sprintf("10%08d", seq),
+		"CC",
+		role,
+	)
+	if err != nil {
+		t.Fatalf("usuario de prueba inválido: %v", err)
+	}
+
+	// Postgres conserva microsegundos: se trunca para comparar sin ruido
+	user.CreatedAt = user.CreatedAt.Truncate(time.Microsecond)
+	user.UpdatedAt = user.CreatedAt
+	return user
+}
+
+func mustCreate(t *testing.T, repo repositories.UserRepository, users ...*entities.User) {
+	t.Helper()
+	for _, user := range users {
+		if err := repo.Create(InternalContext(), user); err != nil {
+			t.Fatalf("Create(%s): %v", user.Email, err)
+		}
+	}
+}
+
+func mustGet(t *testing.T, repo repositories.UserRepository, id uuid.UUID) *entities.User {
+	t.Helper()
+	user, err := repo.GetByID(InternalContext(), id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if user == nil {
+		t.Fatalf("GetByID(%s): usuario no encontrado", id)
+	}
+	return user
+}
+
+func testCreateAndGet(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	ficha := "2558104"
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	user.FichaID = &ficha
+	mustCreate(t, repo, user)
+
+	byID := mustGet(t, repo, user.ID)
+	if byID.Email != user.Email || byID.FirstName != "Ana" || byID.Role != entities.RoleAprendiz {
+		t.Errorf("GetByID retornó datos inesperados: %+v", byID)
+	}
+	if byID.FichaID == nil || *byID.FichaID != ficha {
+		t.Errorf("FichaID = %v, se esperaba %s", byID.FichaID, ficha)
+	}
+
+	byEmail, err := repo.GetByEmail(ctx, user.Email)
+	if err != nil || byEmail == nil || byEmail.ID != user.ID {
+		t.Errorf("GetByEmail = %v, %v", byEmail, err)
+	}
+
+	byDocument, err := repo.GetByDocument(ctx, user.DocumentType, user.DocumentNumber)
+	if err != nil || byDocument == nil || byDocument.ID != user.ID {
+		t.Errorf("GetByDocument = %v, %v", byDocument, err)
+	}
+
+	// El número se busca normalizado: los puntos de miles y los espacios no cuentan
+	formatted := " 1.000.000.001 "
+	if byDocument, err := repo.GetByDocument(ctx, user.DocumentType, formatted); err != nil || byDocument == nil || byDocument.ID != user.ID {
+		t.Errorf("GetByDocument(%q) = %v, %v", formatted, byDocument, err)
+	}
+	if exists, err := repo.ExistsByDocument(ctx, user.DocumentType, formatted); err != nil || !exists {
+		t.Errorf("ExistsByDocument(%q) = %v, %v", formatted, exists, err)
+	}
+
+	missing, err := repo.GetByID(ctx, uuid.New())
+	if err != nil || missing != nil {
+		t.Errorf("GetByID de un ID inexistente debe retornar nil, nil; se obtuvo %v, %v", missing, err)
+	}
+
+	missing, err = repo.GetByEmail(ctx, "nadie@sena.edu.co")
+	if err != nil || missing != nil {
+		t.Errorf("GetByEmail de un email inexistente debe retornar nil, nil; se obtuvo %v, %v", missing, err)
+	}
+} // fin testCreateAndGet
+
+func testCreateDuplicate(t *testing.T, repo repositories.UserRepository) {
+	original := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repo, original)
+
+	sameEmail := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	sameEmail.Email = original.Email
+	err := repo.Create(InternalContext(), sameEmail)
+	if !errors.Is(err, repositories.ErrDuplicateUser) {
+		t.Errorf("Create con email duplicado = %v, se esperaba ErrDuplicateUser", err)
+	}
+	var duplicate *repositories.DuplicateUserError
+	if !errors.As(err, &duplicate) || duplicate.Field != "email" {
+		t.Errorf("Create con email duplicado debe indicar el campo email: %#v", err)
+	}
+
+	sameDocument := NewTestUser(t, 3, "Luis", "Pérez", entities.RoleAprendiz)
+	sameDocument.DocumentNumber = original.DocumentNumber
+	err = repo.Create(InternalContext(), sameDocument)
+	if !errors.As(err, &duplicate) || duplicate.Field != "document_number" {
+		t.Errorf("Create con documento duplicado debe indicar el campo document_number: %#v", err)
+	}
+}
+
+// testEmailCaseInsensitive verifica que los emails se guarden en forma canónica y se comparen sin distinguir mayúsculas
+func testEmailCaseInsensitive(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+
+	juan, err := entities.NewUser("Juan", "Rojas", "  Juan.Rojas@SENA.edu.co ", "1012345678", "CC", entities.RoleAprendiz)
+	if err != nil {
+		t.Fatalf("NewUser: %v", err)
+	}
+	if juan.Email != "juan.rojas@sena.edu.co" {
+		t.Errorf("NewUser debe guardar el email en forma canónica, se obtuvo %q", juan.Email)
+	}
+	mustCreate(t, repo, juan)
+
+	// Un Email construido por conversión directa también se guarda en forma canónica
+	ana := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	ana.Email = "Ana.Gomez@Sena.Edu.Co"
+	mustCreate(t, repo, ana)
+	if stored := mustGet(t, repo, ana.ID); stored.Email != "ana.gomez@sena.edu.co" {
+		t.Errorf("Create debe guardar el email en forma canónica, se obtuvo %q", stored.Email)
+	}
+
+	for _, email := range []entities.Email{"JUAN.ROJAS@sena.edu.co", " juan.rojas@Sena.edu.co"} {
+		if user, err := repo.GetByEmail(ctx, email); err != nil || user == nil || user.ID != juan.ID {
+			t.Errorf("GetByEmail(%q) = %v, %v", email, user, err)
+		}
+		if exists, err := repo.ExistsByEmail(ctx, email); err != nil || !exists {
+			t.Errorf("ExistsByEmail(%q) = %v, %v", email, exists, err)
+		}
+	}
+
+	// Un email que solo difiere en mayúsculas es el mismo usuario
+	other := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	other.Email = "Juan.Rojas@SENA.edu.co"
+	err = repo.Create(ctx, other)
+	var duplicate *repositories.DuplicateUserError
+	if !errors.As(err, &duplicate) || duplicate.Field != "email" {
+		t.Errorf("Create con email en otras mayúsculas debe indicar el campo email: %#v", err)
+	}
+
+	users, err := repo.GetMultipleByEmails(ctx, []entities.Email{"ANA.GOMEZ@sena.edu.co", "juan.rojas@SENA.EDU.CO", "Nadie@sena.edu.co"})
+	if err != nil {
+		t.Fatalf("GetMultipleByEmails: %v", err)
+	}
+	assertEmails(t, "GetMultipleByEmails sin distinguir mayúsculas", users, []entities.Email{"ana.gomez@sena.edu.co", "juan.rojas@sena.edu.co"})
+
+	result, err := repo.BulkDelete(ctx, []entities.Email{"Juan.Rojas@Sena.Edu.Co"}, nil)
+	if err != nil {
+		t.Fatalf("BulkDelete: %v", err)
+	}
+	assertBulkResult(t, "BulkDelete sin distinguir mayúsculas", result, 1, 1, nil)
+
+	// Eliminado el usuario, el email queda libre para un nuevo registro
+	other.Email = "JUAN.ROJAS@sena.edu.co"
+	mustCreate(t, repo, other)
+} // fin testEmailCaseInsensitive
+
+// testDocuments verifica las reglas por tipo de documento, la unicidad por tipo y número y el cambio de TI a CC
+func testDocuments(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+
+	invalid := []struct {
+		number  string
+		docType entities.DocumentType
+	}{
+		{"1012345678", "XX"},
+		{"10123A", entities.DocumentTypeCC},
+		{"123456789", entities.DocumentTypeTI},
+		{"12345", entities.DocumentTypePEP},
+		{"899999034-2", entities.DocumentTypeNIT},
+	}
+	for _, tc := range invalid {
+		if _, err := entities.NewUser("Ana", "Gómez", "ana@sena.edu.co", tc.number, tc.docType, entities.RoleAprendiz); err == nil {
+			t.Errorf("NewUser(%s %s) debe fallar", tc.docType, tc.number)
+		}
+	}
+
+	// El número se normaliza: sin puntos de miles, espacios ni minúsculas
+	cedula, err := entities.NewUser("Ana", "Gómez", "ana@sena.edu.co", " 1.012.345.678 ", entities.DocumentTypeCC, entities.RoleAprendiz)
+	if err != nil {
+		t.Fatalf("NewUser(CC): %v", err)
+	}
+	if cedula.DocumentNumber != "1012345678" {
+		t.Errorf("NewUser debe normalizar el número, se obtuvo %q", cedula.DocumentNumber)
+	}
+	passport, err := entities.NewUser("John", "Smith", "john@sena.edu.co", "1012345678", entities.DocumentTypePasaporte, entities.RoleInstructor)
+	if err != nil {
+		t.Fatalf("NewUser(PA): %v", err)
+	}
+	mustCreate(t, repo, cedula, passport)
+
+	// El mismo número con otro tipo es otro documento
+	for _, want := range []*entities.User{cedula, passport} {
+		if user, err := repo.GetByDocument(ctx, want.DocumentType, want.DocumentNumber); err != nil || user == nil || user.ID != want.ID {
+			t.Errorf("GetByDocument(%s) = %v, %v", want.DocumentType, user, err)
+		}
+	}
+	if user, err := repo.GetByDocument(ctx, entities.DocumentTypeTI, "1012345678"); err != nil || user != nil {
+		t.Errorf("GetByDocument(TI) = %v, %v; se esperaba nil", user, err)
+	}
+
+	sameDocument := NewTestUser(t, 3, "Luis", "Pérez", entities.RoleAprendiz)
+	sameDocument.DocumentNumber = cedula.DocumentNumber
+	err = repo.Create(ctx, sameDocument)
+	var duplicate *repositories.DuplicateUserError
+	if !errors.As(err, &duplicate) || duplicate.Field != "document_number" {
+		t.Errorf("Create con el mismo tipo y número debe indicar el campo document_number: %#v", err)
+	}
+
+	// La edad debe corresponder al tipo: TI solo para menores, CC solo para mayores
+	today := entities.CivilDate(time.Now())
+	if err := cedula.SetBirthDate(today.AddDate(-16, 0, 0)); err == nil {
+		t.Errorf("SetBirthDate de un menor con CC debe fallar")
+	}
+	teen, err := entities.NewUser("Sofía", "Rojas", "sofia@sena.edu.co", "1098765432", entities.DocumentTypeTI, entities.RoleAprendiz)
+	if err != nil {
+		t.Fatalf("NewUser(TI): %v", err)
+	}
+	if err := teen.SetBirthDate(today.AddDate(-20, 0, 0)); err == nil {
+		t.Errorf("SetBirthDate de un mayor con TI debe fallar")
+	}
+	if err := teen.SetBirthDate(today.AddDate(-17, 0, 0)); err != nil {
+		t.Fatalf("SetBirthDate(TI, 17 años): %v", err)
+	}
+	if err := teen.ChangeDocument(entities.DocumentTypeCC, teen.DocumentNumber); err == nil {
+		t.Errorf("ChangeDocument a CC antes de los 18 años debe fallar")
+	}
+
+	// Cumplidos los 18 años sigue con TI hasta que registre su cédula, que conserva el número
+	adult := today.AddDate(-entities.AdultAge, 0, -1)
+	teen.BirthDate = &adult
+	mustCreate(t, repo, teen)
+
+	expr, err := repositories.ParseUserFilter("document_type eq 'TI' and birth_date le " + today.AddDate(-entities.AdultAge, 0, 0).Format(time.DateOnly))
+	if err != nil {
+		t.Fatalf("ParseUserFilter: %v", err)
+	}
+	pending, err := repo.List(ctx, repositories.UserFilters{Filter: expr})
+	if err != nil {
+		t.Fatalf("List(TI mayores de edad): %v", err)
+	}
+	assertEmails(t, "TI mayores de edad", pending.Users, []entities.Email{teen.Email})
+
+	stored := mustGet(t, repo, teen.ID)
+	if !stored.NeedsCedula() {
+		t.Errorf("NeedsCedula debe ser true para un TI de 18 años")
+	}
+	if stored.BirthDate == nil || !stored.BirthDate.Equal(adult) {
+		t.Errorf("BirthDate = %v, se esperaba %v", stored.BirthDate, adult)
+	}
+	if err := stored.ChangeDocument(entities.DocumentTypePasaporte, "AB123456"); err == nil {
+		t.Errorf("ChangeDocument de TI a pasaporte debe fallar")
+	}
+	if err := stored.ChangeDocument(entities.DocumentTypeCC, stored.DocumentNumber); err != nil {
+		t.Fatalf("ChangeDocument(CC): %v", err)
+	}
+	if err := repo.Update(ctx, stored); err != nil {
+		t.Fatalf("Update tras ChangeDocument: %v", err)
+	}
+
+	if user, err := repo.GetByDocument(ctx, entities.DocumentTypeCC, "1098765432"); err != nil || user == nil || user.ID != teen.ID {
+		t.Errorf("GetByDocument(CC) tras el cambio = %v, %v", user, err)
+	}
+	if exists, err := repo.ExistsByDocument(ctx, entities.DocumentTypeTI, "1098765432"); err != nil || exists {
+		t.Errorf("ExistsByDocument(TI) tras el cambio = %v, %v; se esperaba false", exists, err)
+	}
+} // fin testDocuments
+
+// testStructuredErrors verifica que los errores del repositorio se reporten con su tipo y código
+func testStructuredErrors(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repo, user)
+
+	duplicate := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	duplicate.Email = user.Email
+	if structured := apperrors.From(repo.Create(ctx, duplicate)); structured == nil ||
+		structured.Kind != apperrors.KindConflict || structured.Code != repositories.CodeDuplicateUser || structured.Field != "email" {
+		t.Errorf("Create duplicado: se esperaba conflicto sobre email, se obtuvo %+v", structured)
+	}
+
+	missing := NewTestUser(t, 3, "Eva", "Ríos", entities.RoleAprendiz)
+	problem := apperrors.NewProblem(repo.Update(ctx, missing), "en")
+	if problem.Status != http.StatusNotFound || problem.Code != repositories.CodeUserNotFound || problem.Detail != "User not found" {
+		t.Errorf("Update de un usuario inexistente: problema %+v", problem)
+	}
+
+	// Una consulta inválida se traduce completa; el detalle del problema queda en el parámetro detail
+	_, err := repo.GetUserRegistrationTrend(ctx, repositories.RegistrationTrendQuery{Bucket: "hora"})
+	problem = apperrors.NewProblem(err, "en")
+	if problem.Status != http.StatusBadRequest || problem.Code != repositories.CodeInvalidTrend ||
+		problem.Detail != "The registration trend query is not valid" {
+		t.Errorf("tendencia con intervalo inválido: problema %+v", problem)
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Params["detail"] != `intervalo "hora"` {
+		t.Errorf("tendencia con intervalo inválido: errores %+v", problem.Errors)
+	}
+} // fin testStructuredErrors
+
+func testUpdate(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repo, user)
+
+	user.FirstName = "Andrea"
+	user.Deactivate()
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	stored := mustGet(t, repo, user.ID)
+	if stored.FirstName != "Andrea" || stored.IsActive {
+		t.Errorf("Update no persistió los cambios: %+v", stored)
+	}
+
+	// Un usuario leído sin credenciales se guarda sin borrar su contraseña
+	credentials := repositories.WithCredentials(ctx)
+	withPassword, err := repo.GetByID(credentials, user.ID)
+	if err != nil || withPassword == nil {
+		t.Fatalf("GetByID con credenciales = %v, %v", withPassword, err)
+	}
+	withPassword.Password = "$2a$10$hash-de-prueba"
+	if err := repo.Update(ctx, withPassword); err != nil {
+		t.Fatalf("Update con contraseña: %v", err)
+	}
+	withoutPassword := mustGet(t, repo, user.ID)
+	withoutPassword.Password = ""
+	withoutPassword.FirstName = "Ana"
+	if err := repo.Update(ctx, withoutPassword); err != nil {
+		t.Fatalf("Update sin contraseña: %v", err)
+	}
+	if stored, err := repo.GetByID(credentials, user.ID); err != nil || stored.FirstName != "Ana" || stored.Password != withPassword.Password {
+		t.Errorf("Update sin contraseña = %+v, %v; se esperaba conservar la contraseña", stored, err)
+	}
+
+	ghost := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	if err := repo.Update(ctx, ghost); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("Update de un usuario inexistente = %v, se esperaba ErrUserNotFound", err)
+	}
+}
+
+func testOptimisticLocking(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repo, user)
+
+	if user.Version != 1 {
+		t.Fatalf("un usuario nuevo debe iniciar en la versión 1, tiene %d", user.Version)
+	}
+
+	// Dos coordinadores leen la misma versión
+	first := mustGet(t, repo, user.ID)
+	second := mustGet(t, repo, user.ID)
+
+	first.FirstName = "Andrea"
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("Update debe incrementar la versión a 2, quedó en %d", first.Version)
+	}
+
+	second.LastName = "Pardo"
+	err := repo.Update(ctx, second)
+	if !errors.Is(err, repositories.ErrVersionConflict) {
+		t.Fatalf("Update con versión obsoleta = %v, se esperaba ErrVersionConflict", err)
+	}
+
+	var conflict *repositories.VersionConflictError
+	if !errors.As(err, &conflict) || conflict.ExpectedVersion != 1 || conflict.CurrentVersion != 2 || conflict.UserID != user.ID {
+		t.Errorf("se esperaba *VersionConflictError{expected: 1, current: 2}, se obtuvo %#v", err)
+	}
+	if second.Version != 1 {
+		t.Errorf("un Update fallido no debe alterar la versión del llamador: %d", second.Version)
+	}
+
+	stored := mustGet(t, repo, user.ID)
+	if stored.FirstName != "Andrea" || stored.LastName != "Gómez" || stored.Version != 2 {
+		t.Errorf("el Update en conflicto no debe sobrescribir: %+v", stored)
+	}
+
+	// Las operaciones masivas también incrementan la versión
+	if _, err := repo.BulkStatusChange(ctx, []entities.Email{user.Email}, false); err != nil {
+		t.Fatalf("BulkStatusChange: %v", err)
+	}
+	if stored := mustGet(t, repo, user.ID); stored.Version != 3 {
+		t.Errorf("BulkStatusChange debe incrementar la versión a 3, quedó en %d", stored.Version)
+	}
+
+	stale := *first
+	stale.FirstName = "Otra"
+	result, err := repo.BulkUpdate(ctx, map[entities.Email]*entities.User{user.Email: &stale})
+	if err != nil {
+		t.Fatalf("BulkUpdate: %v", err)
+	}
+	if result.Failed != 1 || len(result.Errors) != 1 || result.Errors[0].Field != "version" || result.Errors[0].Index != 0 {
+		t.Errorf("BulkUpdate debe reportar el conflicto de versión por fila: %+v", result)
+	}
+} // fin testOptimisticLocking
+
+func testSoftDelete(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	admin := uuid.New()
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	other := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	mustCreate(t, repo, user, other)
+
+	if err := repo.Delete(ctx, user.ID, &admin); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	// Por defecto los usuarios eliminados no son visibles
+	if found, err := repo.GetByID(ctx, user.ID); err != nil || found != nil {
+		t.Errorf("GetByID de un usuario eliminado = %v, %v; se esperaba nil", found, err)
+	}
+	if found, err := repo.GetByEmail(ctx, user.Email); err != nil || found != nil {
+		t.Errorf("GetByEmail de un usuario eliminado = %v, %v; se esperaba nil", found, err)
+	}
+	if ok, err := repo.ExistsByEmail(ctx, user.Email); err != nil || ok {
+		t.Errorf("ExistsByEmail de un usuario eliminado = %v, %v", ok, err)
+	}
+
+	listed, err := repo.List(ctx, repositories.UserFilters{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	assertEmails(t, "List(default)", listed.Users, []entities.Email{other.Email})
+
+	listed, err = repo.List(ctx, repositories.UserFilters{Deleted: repositories.DeletedInclude})
+	if err != nil {
+		t.Fatalf("List(include): %v", err)
+	}
+	assertEmails(t, "List(include)", listed.Users, []entities.Email{user.Email, other.Email})
+
+	listed, err = repo.List(ctx, repositories.UserFilters{Deleted: repositories.DeletedOnly})
+	if err != nil {
+		t.Fatalf("List(only): %v", err)
+	}
+	assertEmails(t, "List(only)", listed.Users, []entities.Email{user.Email})
+	if deleted := listed.Users[0]; deleted.DeletedAt == nil || deleted.DeletedBy == nil || *deleted.DeletedBy != admin ||
+		deleted.IsActive || deleted.Status != entities.UserStatusDeleted {
+		t.Errorf("el usuario eliminado debe registrar DeletedAt/DeletedBy y quedar inactivo: %+v", deleted)
+	}
+
+	if err := repo.Delete(ctx, user.ID, &admin); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("Delete de un usuario ya eliminado = %v, se esperaba ErrUserNotFound", err)
+	}
+	if err := repo.Delete(ctx, uuid.New(), nil); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("Delete de un ID inexistente = %v, se esperaba ErrUserNotFound", err)
+	}
+
+	user.FirstName = "Andrea"
+	if err := repo.Update(ctx, user); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("Update de un usuario eliminado = %v, se esperaba ErrUserNotFound", err)
+	}
+} // fin testSoftDelete
+
+func testRestore(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repo, user)
+
+	if err := repo.Restore(ctx, user.ID); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("Restore de un usuario no eliminado = %v, se esperaba ErrUserNotFound", err)
+	}
+
+	if err := repo.Delete(ctx, user.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Restore(ctx, user.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	restored := mustGet(t, repo, user.ID)
+	if restored.IsDeleted() || restored.DeletedBy != nil || !restored.IsActive || restored.Status != entities.UserStatusActive {
+		t.Errorf("Restore debe limpiar el soft delete y reactivar: %+v", restored)
+	}
+
+	// El email de un usuario eliminado queda libre; restaurarlo luego genera conflicto
+	if err := repo.Delete(ctx, user.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	replacement := NewTestUser(t, 2, "Ana", "Gómez", entities.RoleAprendiz)
+	replacement.Email = user.Email
+	mustCreate(t, repo, replacement)
+
+	if err := repo.Restore(ctx, user.ID); !errors.Is(err, repositories.ErrDuplicateUser) {
+		t.Errorf("Restore con email ocupado = %v, se esperaba ErrDuplicateUser", err)
+	}
+} // fin testRestore
+
+func testPurge(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	deleted := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	alive := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	mustCreate(t, repo, deleted, alive)
+
+	if err := repo.Delete(ctx, deleted.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	purged, err := repo.Purge(ctx, time.Now().Add(-time.Hour))
+	if err != nil || purged != 0 {
+		t.Errorf("Purge antes de la retención = %d, %v; no debe eliminar nada", purged, err)
+	}
+
+	purged, err = repo.Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil || purged != 1 {
+		t.Errorf("Purge = %d, %v; se esperaba 1", purged, err)
+	}
+
+	listed, err := repo.List(ctx, repositories.UserFilters{Deleted: repositories.DeletedInclude})
+	if err != nil {
+		t.Fatalf("List(include): %v", err)
+	}
+	assertEmails(t, "List después de Purge", listed.Users, []entities.Email{alive.Email})
+
+	if err := repo.Restore(ctx, deleted.ID); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("Restore de un usuario purgado = %v, se esperaba ErrUserNotFound", err)
+	}
+} // fin testPurge
+
+func testExists(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repo, user)
+
+	if ok, err := repo.ExistsByEmail(ctx, user.Email); err != nil || !ok {
+		t.Errorf("ExistsByEmail(existente) = %v, %v", ok, err)
+	}
+	if ok, err := repo.ExistsByEmail(ctx, "nadie@sena.edu.co"); err != nil || ok {
+		t.Errorf("ExistsByEmail(inexistente) = %v, %v", ok, err)
+	}
+	if ok, err := repo.ExistsByDocument(ctx, user.DocumentType, user.DocumentNumber); err != nil || !ok {
+		t.Errorf("ExistsByDocument(existente) = %v, %v", ok, err)
+	}
+	if ok, err := repo.ExistsByDocument(ctx, entities.DocumentTypeCC, "99999999"); err != nil || ok {
+		t.Errorf("ExistsByDocument(inexistente) = %v, %v", ok, err)
+	}
+}
+
+func testListPagination(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	for i := 1; i <= 5; i++ {
+		user := NewTestUser(t, i, "Ana", "Gómez", entities.RoleAprendiz)
+		user.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		mustCreate(t, repo, user)
+	}
+
+	cases := []struct {
+		page, pageSize, wantLen, wantTotalPages int
+		wantNext, wantPrevious                  bool
+	}{
+		{page: 1, pageSize: 2, wantLen: 2, wantTotalPages: 3, wantNext: true, wantPrevious: false},
+		{page: 2, pageSize: 2, wantLen: 2, wantTotalPages: 3, wantNext: true, wantPrevious: true},
+		{page: 3, pageSize: 2, wantLen: 1, wantTotalPages: 3, wantNext: false, wantPrevious: true},
+		{page: 4, pageSize: 2, wantLen: 0, wantTotalPages: 3, wantNext: false, wantPrevious: true},
+		{page: 1, pageSize: 5, wantLen: 5, wantTotalPages: 1, wantNext: false, wantPrevious: false},
+	}
+
+	for _, tc := range cases {
+		result, err := repo.List(ctx, repositories.UserFilters{Page: tc.page, PageSize: tc.pageSize})
+		if err != nil {
+			t.Fatalf("List(page=%d, size=%d): %v", tc.page, tc.pageSize, err)
+		}
+
+		if result.Total != 5 || len(result.Users) != tc.wantLen || result.TotalPages != tc.wantTotalPages ||
+			result.HasNext != tc.wantNext || result.HasPrevious != tc.wantPrevious {
+			t.Errorf("List(page=%d, size=%d) = total=%d len=%d pages=%d next=%v prev=%v",
+				tc.page, tc.pageSize, result.Total, len(result.Users), result.TotalPages, result.HasNext, result.HasPrevious)
+		}
+	}
+
+	// Sin página ni tamaño se aplican los valores por defecto
+	result, err := repo.List(ctx, repositories.UserFilters{})
+	if err != nil {
+		t.Fatalf("List(defaults): %v", err)
+	}
+	if result.Page != 1 || result.PageSize != repositories.DefaultPageSize {
+		t.Errorf("List(defaults) page=%d size=%d", result.Page, result.PageSize)
+	}
+
+	// Por defecto se ordena por fecha de creación descendente
+	if result.Users[0].Email != "usuario005@sena.edu.co" {
+		t.Errorf("el primer usuario debe ser el más reciente, se obtuvo %s", result.Users[0].Email)
+	}
+
+	result, err = repo.List(ctx, repositories.UserFilters{Sort: []filter.SortField{{Field: "email"}}})
+	if err != nil {
+		t.Fatalf("List(sort email asc): %v", err)
+	}
+	if result.Users[0].Email != "usuario001@sena.edu.co" {
+		t.Errorf("orden por email ascendente inválido, primero %s", result.Users[0].Email)
+	}
+
+	empty, err := newEmptyList(ctx, repo)
+	if err != nil {
+		t.Fatalf("List(sin resultados): %v", err)
+	}
+	if empty.Total != 0 || empty.TotalPages != 0 || empty.HasNext || empty.HasPrevious || empty.Users == nil {
+		t.Errorf("List sin resultados = %+v", empty)
+	}
+} // fin testListPagination
+
+func testListCursorPagination(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	for i := 1; i <= 5; i++ {
+		user := NewTestUser(t, i, "Ana", "Gómez", entities.RoleAprendiz)
+		user.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		mustCreate(t, repo, user)
+	}
+
+	list := func(name, cursor string) *repositories.PaginatedUsers {
+		t.Helper()
+		result, err := repo.List(ctx, repositories.UserFilters{
+			Pagination: repositories.PaginationCursor,
+			Cursor:     cursor,
+			PageSize:   2,
+		})
+		if err != nil {
+			t.Fatalf("List(%s): %v", name, err)
+		}
+		return result
+	}
+
+	first := list("primera página", "")
+	assertEmails(t, "primera página", first.Users, []entities.Email{"usuario005@sena.edu.co", "usuario004@sena.edu.co"})
+	if !first.HasNext || first.HasPrevious || first.NextCursor == "" || first.PrevCursor != "" || first.Total != 0 {
+		t.Errorf("primera página = next=%v prev=%v total=%d", first.HasNext, first.HasPrevious, first.Total)
+	}
+
+	// Un usuario creado durante el recorrido no desplaza las páginas siguientes
+	newer := NewTestUser(t, 6, "Ana", "Gómez", entities.RoleAprendiz)
+	newer.CreatedAt = base.Add(10 * time.Minute)
+	mustCreate(t, repo, newer)
+
+	second := list("segunda página", first.NextCursor)
+	assertEmails(t, "segunda página", second.Users, []entities.Email{"usuario003@sena.edu.co", "usuario002@sena.edu.co"})
+	if !second.HasNext || !second.HasPrevious {
+		t.Errorf("segunda página = next=%v prev=%v", second.HasNext, second.HasPrevious)
+	}
+
+	last := list("última página", second.NextCursor)
+	assertEmails(t, "última página", last.Users, []entities.Email{"usuario001@sena.edu.co"})
+	if last.HasNext || !last.HasPrevious || last.NextCursor != "" {
+		t.Errorf("última página = next=%v prev=%v", last.HasNext, last.HasPrevious)
+	}
+
+	back := list("página anterior", last.PrevCursor)
+	assertEmails(t, "página anterior", back.Users, []entities.Email{"usuario003@sena.edu.co", "usuario002@sena.edu.co"})
+	if !back.HasNext || !back.HasPrevious {
+		t.Errorf("página anterior = next=%v prev=%v", back.HasNext, back.HasPrevious)
+	}
+
+	// Al volver al inicio aparece el usuario creado durante el recorrido
+	start := list("inicio", back.PrevCursor)
+	assertEmails(t, "inicio", start.Users, []entities.Email{"usuario005@sena.edu.co", "usuario004@sena.edu.co"})
+	if !start.HasNext || !start.HasPrevious {
+		t.Errorf("inicio = next=%v prev=%v", start.HasNext, start.HasPrevious)
+	}
+	top := list("tope", start.PrevCursor)
+	assertEmails(t, "tope", top.Users, []entities.Email{"usuario006@sena.edu.co"})
+	if top.HasPrevious || top.PrevCursor != "" {
+		t.Errorf("tope = prev=%v", top.HasPrevious)
+	}
+
+	// El cursor solo es válido con el ordenamiento con que se generó
+	_, err := repo.List(ctx, repositories.UserFilters{Cursor: first.NextCursor, Sort: []filter.SortField{{Field: "email"}}, PageSize: 2})
+	if !errors.Is(err, repositories.ErrInvalidCursor) {
+		t.Errorf("cursor con otro ordenamiento: se esperaba ErrInvalidCursor, se obtuvo %v", err)
+	}
+	_, err = repo.List(ctx, repositories.UserFilters{Cursor: "no-es-un-cursor"})
+	if !errors.Is(err, repositories.ErrInvalidCursor) {
+		t.Errorf("cursor corrupto: se esperaba ErrInvalidCursor, se obtuvo %v", err)
+	}
+
+	// Ordenamiento por texto ascendente
+	byEmail := repositories.UserFilters{Pagination: repositories.PaginationCursor, Sort: []filter.SortField{{Field: "email"}}, PageSize: 4}
+	page, err := repo.List(ctx, byEmail)
+	if err != nil {
+		t.Fatalf("List(email asc): %v", err)
+	}
+	byEmail.Cursor = page.NextCursor
+	page, err = repo.List(ctx, byEmail)
+	if err != nil {
+		t.Fatalf("List(email asc, cursor): %v", err)
+	}
+	assertEmails(t, "email asc", page.Users, []entities.Email{"usuario005@sena.edu.co", "usuario006@sena.edu.co"})
+} // fin testListCursorPagination
+
+func newEmptyList(ctx context.Context, repo repositories.UserRepository) (*repositories.PaginatedUsers, error) {
+	role := entities.RoleDirectivo
+	return repo.List(ctx, repositories.UserFilters{Rol: &role})
+}
+
+func testListFilters(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	ficha := "2558104"
+	aprendiz := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	aprendiz.FichaID = &ficha
+	inactive := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	inactive.Deactivate()
+	instructor := NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleInstructor)
+	mustCreate(t, repo, aprendiz, inactive, instructor)
+
+	role := entities.RoleAprendiz
+	active := true
+
+	cases := []struct {
+		name    string
+		filters repositories.UserFilters
+		want    []entities.Email
+	}{
+		{"rol", repositories.UserFilters{Rol: &role}, []entities.Email{aprendiz.Email, inactive.Email}},
+		{"ficha", repositories.UserFilters{FichaID: &ficha}, []entities.Email{aprendiz.Email}},
+		{"activo", repositories.UserFilters{IsActive: &active}, []entities.Email{aprendiz.Email, instructor.Email}},
+		{"rol y activo", repositories.UserFilters{Rol: &role, IsActive: &active}, []entities.Email{aprendiz.Email}},
+	}
+
+	for _, tc := range cases {
+		result, err := repo.List(ctx, tc.filters)
+		if err != nil {
+			t.Fatalf("List(%s): %v", tc.name, err)
+		}
+		assertEmails(t, tc.name, result.Users, tc.want)
+	}
+} // fin testListFilters
+
+func testListFilterExpression(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	base := time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC)
+	oldLogin := base.AddDate(0, 0, 10)
+	recentLogin := base.AddDate(0, 0, 45)
+	ficha := "2558104"
+
+	dormant := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	dormant.CreatedAt = base
+	dormant.LastLogin = &oldLogin
+	dormant.FichaID = &ficha
+	recent := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	recent.CreatedAt = base.AddDate(0, 0, 1)
+	recent.LastLogin = &recentLogin
+	never := NewTestUser(t, 3, "Marta", "Ríos", entities.RoleAprendiz)
+	never.CreatedAt = base.AddDate(0, 0, 2)
+	verified := NewTestUser(t, 4, "Eva", "Díaz", entities.RoleAprendiz)
+	verified.CreatedAt = base.AddDate(0, 0, 3)
+	verified.EmailVerified = true
+	verified.LastLogin = &oldLogin
+	instructor := NewTestUser(t, 5, "Carlos", "Mora", entities.RoleInstructor)
+	instructor.CreatedAt = base.AddDate(0, 0, 4)
+	instructor.LastLogin = &oldLogin
+	mustCreate(t, repo, dormant, recent, never, verified, instructor)
+
+	cases := []struct {
+		expr string
+		want []entities.Email
+	}{
+		{"role eq 'aprendiz' and email_verified eq false and last_login lt 2026-09-01", []entities.Email{dormant.Email}},
+		{"role eq 'aprendiz' and last_login eq null", []entities.Email{never.Email}},
+		{"created_at ge 2026-08-02 and created_at lt 2026-08-04", []entities.Email{recent.Email, never.Email}},
+		{"role in ('instructor', 'coordinador') or email_verified eq true", []entities.Email{verified.Email, instructor.Email}},
+		{"not ficha_id eq '2558104'", []entities.Email{recent.Email, never.Email, verified.Email, instructor.Email}},
+		{"ficha_id ne '2558104'", nil},
+		{"not (last_login ge 2026-09-01) and role eq 'aprendiz'", []entities.Email{dormant.Email, never.Email, verified.Email}},
+		{"email startswith 'USUARIO00' and email contains '5'", []entities.Email{instructor.Email}},
+		{"id eq '" + never.ID.String() + "'", []entities.Email{never.Email}},
+	}
+
+	for _, tc := range cases {
+		expr, err := repositories.ParseUserFilter(tc.expr)
+		if err != nil {
+			t.Fatalf("ParseUserFilter(%q): %v", tc.expr, err)
+		}
+
+		result, err := repo.List(ctx, repositories.UserFilters{Filter: expr})
+		if err != nil {
+			t.Fatalf("List(filter=%q): %v", tc.expr, err)
+		}
+		assertEmails(t, "filter="+tc.expr, result.Users, tc.want)
+	}
+
+	// El filtro se combina con los campos fijos
+	role := entities.RoleAprendiz
+	expr, _ := repositories.ParseUserFilter("last_login lt 2026-09-01")
+	result, err := repo.List(ctx, repositories.UserFilters{Rol: &role, Filter: expr})
+	if err != nil {
+		t.Fatalf("List(rol + filter): %v", err)
+	}
+	assertEmails(t, "rol + filter", result.Users, []entities.Email{dormant.Email, verified.Email})
+
+	// Los campos fuera de la lista blanca se rechazan, también en un AST construido a mano
+	if _, err := repositories.ParseUserFilter("password eq 'x'"); !errors.Is(err, repositories.ErrInvalidFilter) {
+		t.Errorf("ParseUserFilter(password): se esperaba ErrInvalidFilter, se obtuvo %v", err)
+	}
+	manual := &filter.Comparison{Field: "password", Op: filter.OpEq, Values: []filter.Value{filter.String("x")}}
+	if _, err := repo.List(ctx, repositories.UserFilters{Filter: manual}); !errors.Is(err, repositories.ErrInvalidFilter) {
+		t.Errorf("List(AST inválido): se esperaba ErrInvalidFilter, se obtuvo %v", err)
+	}
+} // fin testListFilterExpression
+
+func testListMultiColumnSort(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	users := []*entities.User{
+		NewTestUser(t, 1, "Ana", "Gómez", entities.RoleInstructor),
+		NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz),
+		NewTestUser(t, 3, "Marta", "Ríos", entities.RoleInstructor),
+		NewTestUser(t, 4, "Eva", "Díaz", entities.RoleAprendiz),
+		NewTestUser(t, 5, "Carlos", "Mora", entities.RoleAprendiz),
+	}
+	for i, user := range users {
+		user.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+	}
+	mustCreate(t, repo, users...)
+
+	sort, err := repositories.ParseUserSort("role asc, created_at desc")
+	if err != nil {
+		t.Fatalf("ParseUserSort: %v", err)
+	}
+	want := []entities.Email{users[4].Email, users[3].Email, users[1].Email, users[2].Email, users[0].Email}
+
+	result, err := repo.List(ctx, repositories.UserFilters{Sort: sort})
+	if err != nil {
+		t.Fatalf("List(sort): %v", err)
+	}
+	assertOrder(t, "role asc, created_at desc", result.Users, want)
+
+	// El recorrido por cursor respeta el ordenamiento de varias columnas en ambos sentidos
+	filters := repositories.UserFilters{Pagination: repositories.PaginationCursor, Sort: sort, PageSize: 2}
+	var got []*entities.User
+	var cursors []string
+	for page := 0; page < 5; page++ {
+		result, err := repo.List(ctx, filters)
+		if err != nil {
+			t.Fatalf("List(cursor %d): %v", page, err)
+		}
+		got = append(got, result.Users...)
+		cursors = append(cursors, result.PrevCursor)
+		if !result.HasNext {
+			break
+		}
+		filters.Cursor = result.NextCursor
+	}
+	assertOrder(t, "cursor hacia adelante", got, want)
+
+	filters.Cursor = cursors[len(cursors)-1]
+	result, err = repo.List(ctx, filters)
+	if err != nil {
+		t.Fatalf("List(cursor hacia atrás): %v", err)
+	}
+	assertOrder(t, "cursor hacia atrás", result.Users, want[2:4])
+
+	// La relevancia solo aplica sola y last_login admite nulos, así que no pagina por cursor
+	if _, err := repositories.ParseUserSort("relevance, email"); !errors.Is(err, repositories.ErrInvalidFilter) {
+		t.Errorf("ParseUserSort(relevance, email): se esperaba ErrInvalidFilter, se obtuvo %v", err)
+	}
+	byLogin, _ := repositories.ParseUserSort("last_login")
+	_, err = repo.List(ctx, repositories.UserFilters{Pagination: repositories.PaginationCursor, Sort: byLogin})
+	if !errors.Is(err, repositories.ErrUnsupportedFilter) {
+		t.Errorf("cursor por last_login: se esperaba ErrUnsupportedFilter, se obtuvo %v", err)
+	}
+} // fin testListMultiColumnSort
+
+func testListSearch(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	ana := NewTestUser(t, 1, "Ana María", "Gómez", entities.RoleAprendiz)
+	luis := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	luis.Email = "lperez@misena.edu.co"
+	carlos := NewTestUser(t, 3, "Carlos", "Gomezjurado", entities.RoleInstructor)
+	jose := NewTestUser(t, 4, "José", "Núñez", entities.RoleAprendiz)
+	joseph := NewTestUser(t, 5, "Joseph", "Nuñezca", entities.RoleAprendiz)
+	joseph.DocumentNumber = "5550001"
+	mustCreate(t, repo, ana, luis, carlos, jose, joseph)
+
+	cases := []struct {
+		term string
+		want []entities.Email
+	}{
+		{"maría", []entities.Email{ana.Email}},
+		{"GÓMEZ", []entities.Email{ana.Email, carlos.Email}},
+		{"gomez", []entities.Email{ana.Email, carlos.Email}},
+		{"Jose Nunez", []entities.Email{jose.Email, joseph.Email}},
+		{"  NÚÑEZ   josé ", []entities.Email{jose.Email, joseph.Email}},
+		{"ana gomez", []entities.Email{ana.Email}},
+		{"misena", []entities.Email{luis.Email}},
+		{"555", []entities.Email{joseph.Email}},
+		{"0001", nil}, // El documento solo coincide por prefijo
+		{"  ", []entities.Email{ana.Email, luis.Email, carlos.Email, jose.Email, joseph.Email}},
+		{"inexistente", nil},
+		{"jose inexistente", nil},
+	}
+
+	for _, tc := range cases {
+		term := tc.term
+		result, err := repo.List(ctx, repositories.UserFilters{Search: &term})
+		if err != nil {
+			t.Fatalf("List(search=%q): %v", tc.term, err)
+		}
+		assertEmails(t, "search="+tc.term, result.Users, tc.want)
+	}
+
+	// Por defecto se ordena por relevancia: palabra completa antes que coincidencia parcial
+	relevance := []struct {
+		term string
+		want []entities.Email
+	}{
+		{"gomez", []entities.Email{ana.Email, carlos.Email}},
+		{"jose nunez", []entities.Email{jose.Email, joseph.Email}},
+		{"nunezc", []entities.Email{joseph.Email}},
+		{"5550 joseph", []entities.Email{joseph.Email}},
+	}
+
+	for _, tc := range relevance {
+		term := tc.term
+		result, err := repo.List(ctx, repositories.UserFilters{Search: &term})
+		if err != nil {
+			t.Fatalf("List(search=%q): %v", tc.term, err)
+		}
+		assertOrder(t, "relevancia="+tc.term, result.Users, tc.want)
+	}
+
+	// "ez" es parcial para todos; a igual relevancia se ordena por apellido sin tildes
+	term := "ez"
+	result, err := repo.List(ctx, repositories.UserFilters{Search: &term})
+	if err != nil {
+		t.Fatalf("List(search=%q): %v", term, err)
+	}
+	assertOrder(t, "desempate por apellido", result.Users, []entities.Email{ana.Email, carlos.Email, jose.Email, joseph.Email, luis.Email})
+
+	// Un ordenamiento explícito reemplaza la relevancia
+	result, err = repo.List(ctx, repositories.UserFilters{Search: &term, Sort: []filter.SortField{{Field: "email", Desc: true}}})
+	if err != nil {
+		t.Fatalf("List(search=%q, sort=email): %v", term, err)
+	}
+	assertOrder(t, "orden explícito", result.Users, []entities.Email{joseph.Email, jose.Email, carlos.Email, ana.Email, luis.Email})
+
+	// La relevancia no admite paginación por cursor
+	_, err = repo.List(ctx, repositories.UserFilters{Search: &term, Pagination: repositories.PaginationCursor})
+	if !errors.Is(err, repositories.ErrUnsupportedFilter) {
+		t.Errorf("cursor con relevancia: se esperaba ErrUnsupportedFilter, se obtuvo %v", err)
+	}
+} // fin testListSearch
+
+func testStream(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	var want []entities.Email
+	for i := 1; i <= 12; i++ {
+		role := entities.RoleAprendiz
+		if i%4 == 0 {
+			role = entities.RoleInstructor
+		}
+		user := NewTestUser(t, i, "Ana", "Gómez", role)
+		user.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		mustCreate(t, repo, user)
+		if role == entities.RoleAprendiz {
+			want = append([]entities.Email{user.Email}, want...)
+		}
+	}
+
+	// Se recorren todos los que cumplen el filtro en el orden del listado, sin importar PageSize
+	expr, _ := repositories.ParseUserFilter("role eq 'aprendiz'")
+	filters := repositories.UserFilters{Filter: expr, PageSize: 2, Page: 3}
+	var got []*entities.User
+	for user, err := range repo.Stream(ctx, filters) {
+		if err != nil {
+			t.Fatalf("Stream: %v", err)
+		}
+		got = append(got, user)
+	}
+	assertOrder(t, "stream", got, want)
+
+	// Cortar el recorrido libera el cursor sin errores
+	count := 0
+	for _, err := range repo.Stream(ctx, filters) {
+		if err != nil {
+			t.Fatalf("Stream(corte): %v", err)
+		}
+		count++
+		if count == 3 {
+			break
+		}
+	}
+
+	// La cancelación del contexto se entrega como último elemento
+	cancelled, cancel := context.WithCancel(ctx)
+	var streamErr error
+	count = 0
+	for _, err := range repo.Stream(cancelled, filters) {
+		if err != nil {
+			streamErr = err
+			break
+		}
+		count++
+		cancel()
+	}
+	cancel()
+	if !errors.Is(streamErr, context.Canceled) || count != 1 {
+		t.Errorf("Stream(cancelado) = %d usuarios, error %v", count, streamErr)
+	}
+
+	// Los filtros inválidos se reportan como error
+	invalid := &filter.Comparison{Field: "password", Op: filter.OpEq, Values: []filter.Value{filter.String("x")}}
+	for _, err := range repo.Stream(ctx, repositories.UserFilters{Filter: invalid}) {
+		if !errors.Is(err, repositories.ErrInvalidFilter) {
+			t.Errorf("Stream(filtro inválido): se esperaba ErrInvalidFilter, se obtuvo %v", err)
+		}
+	}
+} // fin testStream
+
+func testBulkCreate(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	users := []*entities.User{
+		NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz),
+		NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz),
+		NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleAprendiz),
+	}
+	// El ID y la versión asignados en el lote llegan a los usuarios del llamador
+	users[1].ID, users[1].Version = uuid.Nil, 0
+
+	result, err := repo.BulkCreate(ctx, users, repositories.BulkAtomic)
+	if err != nil {
+		t.Fatalf("BulkCreate: %v", err)
+	}
+	assertBulkResult(t, "BulkCreate", result, 3, 3, nil)
+
+	for _, user := range users {
+		if user.Version != 1 {
+			t.Errorf("BulkCreate(atomic) debe retornar la versión asignada, %s tiene %d", user.Email, user.Version)
+		}
+		mustGet(t, repo, user.ID)
+	}
+
+	result, err = repo.BulkCreate(ctx, nil, repositories.BulkAtomic)
+	if err != nil || result.Total != 0 {
+		t.Errorf("BulkCreate vacío = %+v, %v", result, err)
+	}
+} // fin testBulkCreate
+
+func testBulkCreateBestEffort(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	existing := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repo, existing)
+
+	valid := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	sameDocument := NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleAprendiz)
+	sameDocument.DocumentNumber = existing.DocumentNumber
+	sameEmail := NewTestUser(t, 4, "Marta", "Díaz", entities.RoleAprendiz)
+	sameEmail.Email = existing.Email
+	alsoValid := NewTestUser(t, 5, "Jorge", "Mora", entities.RoleAprendiz)
+
+	result, err := repo.BulkCreate(ctx, []*entities.User{valid, sameDocument, sameEmail, alsoValid}, repositories.BulkBestEffort)
+	if err != nil {
+		t.Fatalf("BulkCreate: %v", err)
+	}
+
+	assertBulkResult(t, "BulkCreate(best effort)", result, 4, 2, map[int]string{
+		1: existing.DocumentNumber,
+		2: existing.Email.String(),
+	})
+	assertBulkFields(t, "BulkCreate(best effort)", result, map[int]string{1: "document_number", 2: "email"})
+
+	if result.RolledBack {
+		t.Errorf("BulkCreate(best effort) no debe revertir")
+	}
+	mustGet(t, repo, valid.ID)
+	mustGet(t, repo, alsoValid.ID)
+} // fin testBulkCreateBestEffort
+
+func testBulkCreateAtomic(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	first := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	second := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	repeated := NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleAprendiz)
+	repeated.DocumentNumber = first.DocumentNumber
+	second.Email = "Luis.Perez@SENA.edu.co"
+	second.ID, second.Version = uuid.Nil, 0
+
+	// Los usuarios del llamador no cambian si el lote se revierte
+	users := []*entities.User{first, second, repeated}
+	before := make([]entities.User, len(users))
+	for i, user := range users {
+		before[i] = *user
+	}
+
+	result, err := repo.BulkCreate(ctx, users, repositories.BulkAtomic)
+	if err != nil {
+		t.Fatalf("BulkCreate: %v", err)
+	}
+	for i, user := range users {
+		if !reflect.DeepEqual(*user, before[i]) {
+			t.Errorf("BulkCreate(atomic) revertido modificó la fila %d: %+v, antes %+v", i, *user, before[i])
+		}
+	}
+
+	if !result.RolledBack || result.Success != 0 || result.Failed != 3 || len(result.Errors) != 1 {
+		t.Fatalf("BulkCreate(atomic) con una fila duplicada debe revertir todo: %+v", result)
+	}
+	if rowErr := result.Errors[0]; rowErr.Index != 2 || rowErr.Field != "document_number" || rowErr.User != first.DocumentNumber {
+		t.Errorf("BulkCreate(atomic) debe señalar la fila 2 por documento: %+v", rowErr)
+	}
+
+	listed, err := repo.List(ctx, repositories.UserFilters{Deleted: repositories.DeletedInclude})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if listed.Total != 0 {
+		t.Errorf("BulkCreate(atomic) revertido no debe dejar usuarios, hay %d", listed.Total)
+	}
+} // fin testBulkCreateAtomic
+
+func testBulkUpdate(t *testing.T, repo repositories.UserRepository) {
+	ana := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	luis := NewTestUser(t, 3, "Luis", "Pérez", entities.RoleAprendiz)
+	mustCreate(t, repo, ana, luis)
+
+	anaChanges := *ana
+	anaChanges.FirstName = "Andrea"
+	luisChanges := *luis
+	luisChanges.LastName = "Pardo"
+	missing := NewTestUser(t, 2, "Nadie", "Nunca", entities.RoleAprendiz)
+
+	// Las llaves se procesan en orden lexicográfico: usuario001, usuario002, usuario003
+	result, err := repo.BulkUpdate(InternalContext(), map[entities.Email]*entities.User{
+		ana.Email:     &anaChanges,
+		missing.Email: missing,
+		luis.Email:    &luisChanges,
+	})
+	if err != nil {
+		t.Fatalf("BulkUpdate: %v", err)
+	}
+
+	assertBulkResult(t, "BulkUpdate", result, 3, 2, map[int]string{1: missing.Email.String()})
+
+	if stored := mustGet(t, repo, ana.ID); stored.FirstName != "Andrea" {
+		t.Errorf("BulkUpdate no persistió el nombre: %s", stored.FirstName)
+	}
+	if stored := mustGet(t, repo, luis.ID); stored.LastName != "Pardo" {
+		t.Errorf("BulkUpdate no persistió el apellido: %s", stored.LastName)
+	}
+}
+
+func testBulkDelete(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	admin := uuid.New()
+	ana := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	luis := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	mustCreate(t, repo, ana, luis)
+
+	result, err := repo.BulkDelete(ctx, []entities.Email{ana.Email, "nadie@sena.edu.co", luis.Email}, &admin)
+	if err != nil {
+		t.Fatalf("BulkDelete: %v", err)
+	}
+
+	assertBulkResult(t, "BulkDelete", result, 3, 2, map[int]string{1: "nadie@sena.edu.co"})
+
+	deleted, err := repo.List(ctx, repositories.UserFilters{Deleted: repositories.DeletedOnly})
+	if err != nil {
+		t.Fatalf("List(only): %v", err)
+	}
+	assertEmails(t, "BulkDelete debe hacer soft delete", deleted.Users, []entities.Email{ana.Email, luis.Email})
+
+	// Un usuario ya eliminado se reporta como no encontrado
+	result, err = repo.BulkDelete(ctx, []entities.Email{ana.Email}, &admin)
+	if err != nil {
+		t.Fatalf("BulkDelete: %v", err)
+	}
+	assertBulkResult(t, "BulkDelete repetido", result, 1, 0, map[int]string{0: ana.Email.String()})
+} // fin testBulkDelete
+
+func testBulkStatusChange(t *testing.T, repo repositories.UserRepository) {
+	ana := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	luis := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	mustCreate(t, repo, ana, luis)
+
+	result, err := repo.BulkStatusChange(InternalContext(), []entities.Email{"nadie@sena.edu.co", ana.Email, luis.Email}, false)
+	if err != nil {
+		t.Fatalf("BulkStatusChange: %v", err)
+	}
+
+	assertBulkResult(t, "BulkStatusChange", result, 3, 2, map[int]string{0: "nadie@sena.edu.co"})
+
+	if stored := mustGet(t, repo, ana.ID); stored.IsActive {
+		t.Errorf("BulkStatusChange no desactivó al usuario")
+	}
+}
+
+func testGetMultipleByEmails(t *testing.T, repo repositories.UserRepository) {
+	ana := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	luis := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	carlos := NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleAprendiz)
+	mustCreate(t, repo, ana, luis, carlos)
+
+	users, err := repo.GetMultipleByEmails(InternalContext(), []entities.Email{ana.Email, carlos.Email, "nadie@sena.edu.co"})
+	if err != nil {
+		t.Fatalf("GetMultipleByEmails: %v", err)
+	}
+	assertEmails(t, "GetMultipleByEmails", users, []entities.Email{ana.Email, carlos.Email})
+
+	users, err = repo.GetMultipleByEmails(InternalContext(), nil)
+	if err != nil || len(users) != 0 {
+		t.Errorf("GetMultipleByEmails(nil) = %v, %v", users, err)
+	}
+}
+
+func testDashboardQueries(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	old := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	old.CreatedAt = time.Now().AddDate(0, 0, -60).Truncate(time.Microsecond)
+	recent := NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	recent.Deactivate()
+	instructor := NewTestUser(t, 3, "Carlos", "Ruiz", entities.RoleInstructor)
+	mustCreate(t, repo, old, recent, instructor)
+
+	// Los usuarios eliminados no cuentan en el dashboard
+	deleted := NewTestUser(t, 4, "Marta", "Díaz", entities.RoleInstructor)
+	mustCreate(t, repo, deleted)
+	if err := repo.Delete(ctx, deleted.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	byRole, err := repo.GetTotalUsersByRole(ctx)
+	if err != nil {
+		t.Fatalf("GetTotalUsersByRole: %v", err)
+	}
+	if byRole[string(entities.RoleAprendiz)] != 2 || byRole[string(entities.RoleInstructor)] != 1 {
+		t.Errorf("GetTotalUsersByRole = %v", byRole)
+	}
+
+	active, inactive, err := repo.GetActiveInactiveCount(ctx)
+	if err != nil || active != 2 || inactive != 1 {
+		t.Errorf("GetActiveInactiveCount = %d, %d, %v", active, inactive, err)
+	}
+
+	// El último día de la serie es el actual y contiene los registros recientes
+	trend, err := repo.GetUserRegistrationTrend(ctx, repositories.LastDays(30, filter.Location))
+	if err != nil {
+		t.Fatalf("GetUserRegistrationTrend: %v", err)
+	}
+	if len(trend.Total.Points) != 30 || trend.Total.Total != 2 {
+		t.Errorf("GetUserRegistrationTrend(30 días) = %d puntos, total %d; se esperaban 30 y 2", len(trend.Total.Points), trend.Total.Total)
+	}
+	if last := trend.Total.Points[len(trend.Total.Points)-1]; last.Count != 2 || !last.Start.Equal(repositories.LastDays(1, filter.Location).From) {
+		t.Errorf("GetUserRegistrationTrend: último punto = %+v", last)
+	}
+} // fin testDashboardQueries
+
+// testRegistrationTrend corre en RunOrganizationRepositoryContract: los desgloses por sede, centro y regional
+// necesitan sedes registradas
+func testRegistrationTrend(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	repo := repos.Users
+	bogota := filter.Location
+	path := mustCreateSedePath(t, repos.Organization, "11")
+	sede := path.SedeID
+
+	// Bogotá está en UTC-5: el primero se registra el domingo 1 de marzo en Bogotá y el lunes 2 en UTC
+	fixtures := []struct {
+		role      entities.UserRole
+		createdAt time.Time
+		sede      *uuid.UUID
+	}{
+		{entities.RoleAprendiz, time.Date(2026, 3, 2, 4, 30, 0, 0, time.UTC), &sede},
+		{entities.RoleInstructor, time.Date(2026, 3, 2, 5, 30, 0, 0, time.UTC), nil},
+		{entities.RoleAprendiz, time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC), &sede},
+		{entities.RoleAprendiz, time.Date(2026, 4, 10, 15, 0, 0, 0, time.UTC), nil},
+		{entities.RoleAprendiz, time.Date(2026, 2, 27, 12, 0, 0, 0, time.UTC), nil},
+	}
+	for i, fixture := range fixtures {
+		user := NewTestUser(t, i+1, "Ana", "Gómez", fixture.role)
+		user.CreatedAt = fixture.createdAt
+		user.SedeID = fixture.sede
+		mustCreate(t, repo, user)
+	}
+
+	// Los usuarios eliminados no cuentan
+	deleted := NewTestUser(t, 10, "Marta", "Díaz", entities.RoleAprendiz)
+	deleted.CreatedAt = time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	mustCreate(t, repo, deleted)
+	if err := repo.Delete(ctx, deleted.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	week := repositories.RegistrationTrendQuery{
+		From:     time.Date(2026, 3, 1, 0, 0, 0, 0, bogota),
+		To:       time.Date(2026, 3, 8, 0, 0, 0, 0, bogota),
+		Location: bogota,
+	}
+	utc := week
+	utc.From, utc.To, utc.Location = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), time.UTC
+
+	cases := []struct {
+		name   string
+		query  repositories.RegistrationTrendQuery
+		starts []time.Time
+		counts []int
+	}{
+		{
+			name:   "días en Bogotá con huecos en cero",
+			query:  week,
+			starts: []time.Time{time.Date(2026, 3, 1, 0, 0, 0, 0, bogota), time.Date(2026, 3, 7, 0, 0, 0, 0, bogota)},
+			counts: []int{1, 1, 0, 1, 0, 0, 0},
+		},
+		{
+			name:   "días en UTC",
+			query:  utc,
+			starts: []time.Time{time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)},
+			counts: []int{0, 2, 0, 1, 0, 0, 0},
+		},
+		{
+			// From cae un miércoles y se alinea al lunes de su semana
+			name: "semanas",
+			query: repositories.RegistrationTrendQuery{
+				From:     time.Date(2026, 2, 25, 10, 0, 0, 0, bogota),
+				To:       time.Date(2026, 3, 16, 0, 0, 0, 0, bogota),
+				Bucket:   repositories.TrendByWeek,
+				Location: bogota,
+			},
+			starts: []time.Time{time.Date(2026, 2, 23, 0, 0, 0, 0, bogota), time.Date(2026, 3, 9, 0, 0, 0, 0, bogota)},
+			counts: []int{2, 2, 0},
+		},
+		{
+			name: "meses",
+			query: repositories.RegistrationTrendQuery{
+				From:     time.Date(2026, 2, 1, 0, 0, 0, 0, bogota),
+				To:       time.Date(2026, 5, 1, 0, 0, 0, 0, bogota),
+				Bucket:   repositories.TrendByMonth,
+				Location: bogota,
+			},
+			starts: []time.Time{time.Date(2026, 2, 1, 0, 0, 0, 0, bogota), time.Date(2026, 4, 1, 0, 0, 0, 0, bogota)},
+			counts: []int{1, 3, 1},
+		},
+	}
+
+	for _, tc := range cases {
+		trend, err := repo.GetUserRegistrationTrend(ctx, tc.query)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+
+		counts := make([]int, len(trend.Total.Points))
+		for i, point := range trend.Total.Points {
+			counts[i] = point.Count
+		}
+		if !slices.Equal(counts, tc.counts) {
+			t.Errorf("%s: conteos = %v, se esperaban %v", tc.name, counts, tc.counts)
+			continue
+		}
+		first, last := trend.Total.Points[0].Start, trend.Total.Points[len(counts)-1].Start
+		if !first.Equal(tc.starts[0]) || !last.Equal(tc.starts[1]) {
+			t.Errorf("%s: intervalos de %v a %v, se esperaban de %v a %v", tc.name, first, last, tc.starts[0], tc.starts[1])
+		}
+		if trend.TimeZone != tc.query.Location.String() || len(trend.Series) != 0 {
+			t.Errorf("%s: zona %q, %d series", tc.name, trend.TimeZone, len(trend.Series))
+		}
+	}
+
+	// Desglose por rol: una serie por rol, ordenadas y con la misma cantidad de puntos
+	byRole := week
+	byRole.Breakdown = repositories.BreakdownRole
+	trend, err := repo.GetUserRegistrationTrend(ctx, byRole)
+	if err != nil {
+		t.Fatalf("GetUserRegistrationTrend(rol): %v", err)
+	}
+	keys := make([]string, 0, len(trend.Series))
+	for _, series := range trend.Series {
+		keys = append(keys, series.Key)
+		if len(series.Points) != len(trend.Total.Points) {
+			t.Errorf("serie %q con %d puntos", series.Key, len(series.Points))
+		}
+	}
+	if !slices.Equal(keys, []string{string(entities.RoleAprendiz), string(entities.RoleInstructor)}) {
+		t.Errorf("series por rol = %v", keys)
+	}
+	if aprendices := trend.SeriesByKey(string(entities.RoleAprendiz)); aprendices == nil || aprendices.Total != 2 || aprendices.Points[3].Count != 1 {
+		t.Errorf("serie de aprendices = %+v", aprendices)
+	}
+
+	// Desglose por sede: los usuarios sin sede van en la serie TrendKeyNone
+	bySede := week
+	bySede.Breakdown = repositories.BreakdownSede
+	trend, err = repo.GetUserRegistrationTrend(ctx, bySede)
+	if err != nil {
+		t.Fatalf("GetUserRegistrationTrend(sede): %v", err)
+	}
+	withSede, withoutSede := trend.SeriesByKey(sede.String()), trend.SeriesByKey(repositories.TrendKeyNone)
+	if withSede == nil || withSede.Total != 2 || withoutSede == nil || withoutSede.Total != 1 {
+		t.Errorf("series por sede = %+v", trend.Series)
+	}
+
+	// Los desgloses por centro y regional agrupan por la ruta de la sede
+	for breakdown, key := range map[repositories.TrendBreakdown]string{
+		repositories.BreakdownCentro:   path.CentroID.String(),
+		repositories.BreakdownRegional: path.RegionalID.String(),
+	} {
+		byLevel := week
+		byLevel.Breakdown = breakdown
+		trend, err = repo.GetUserRegistrationTrend(ctx, byLevel)
+		if err != nil {
+			t.Fatalf("GetUserRegistrationTrend(%s): %v", breakdown, err)
+		}
+		withNode, withoutNode := trend.SeriesByKey(key), trend.SeriesByKey(repositories.TrendKeyNone)
+		if withNode == nil || withNode.Total != 2 || withoutNode == nil || withoutNode.Total != 1 {
+			t.Errorf("series por %s = %+v", breakdown, trend.Series)
+		}
+	}
+
+	// Acotada a la regional solo cuenta los usuarios de sus sedes
+	inRegional := week
+	inRegional.RegionalID = &path.RegionalID
+	trend, err = repo.GetUserRegistrationTrend(ctx, inRegional)
+	if err != nil {
+		t.Fatalf("GetUserRegistrationTrend(regional): %v", err)
+	}
+	if trend.Total.Total != 2 || trend.Total.Points[0].Count != 1 || trend.Total.Points[3].Count != 1 {
+		t.Errorf("GetUserRegistrationTrend(regional) = %+v", trend.Total)
+	}
+
+	invalid := []repositories.RegistrationTrendQuery{
+		{Bucket: "year"},
+		{Breakdown: "ficha"},
+		{From: week.To, To: week.From},
+		{From: time.Date(2000, 1, 1, 0, 0, 0, 0, bogota), To: week.To},
+		{Location: time.Local},
+	}
+	for _, query := range invalid {
+		if _, err := repo.GetUserRegistrationTrend(ctx, query); !errors.Is(err, repositories.ErrInvalidTrendQuery) {
+			t.Errorf("GetUserRegistrationTrend(%+v) = %v, se esperaba ErrInvalidTrendQuery", query, err)
+		}
+	}
+} // fin testRegistrationTrend
+
+// testAggregateDashboard corre en RunOrganizationRepositoryContract, con una sede registrada
+func testAggregateDashboard(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	repo := repos.Users
+	sede := mustCreateSedePath(t, repos.Organization, "11").SedeID
+
+	fixtures := []struct {
+		role   entities.UserRole
+		sede   *uuid.UUID
+		active bool
+	}{
+		{entities.RoleAprendiz, &sede, true},
+		{entities.RoleAprendiz, &sede, true},
+		{entities.RoleAprendiz, &sede, false},
+		{entities.RoleAprendiz, nil, true},
+		{entities.RoleInstructor, &sede, true},
+		{entities.RoleAdmin, nil, false},
+	}
+	for i, fixture := range fixtures {
+		user := NewTestUser(t, i+1, "Ana", "Gómez", fixture.role)
+		user.SedeID = fixture.sede
+		if !fixture.active {
+			user.Deactivate()
+		}
+		mustCreate(t, repo, user)
+	}
+
+	deleted := NewTestUser(t, 10, "Marta", "Díaz", entities.RoleInstructor)
+	mustCreate(t, repo, deleted)
+	if err := repo.Delete(ctx, deleted.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	before := time.Now()
+	snapshot, err := repo.AggregateDashboard(ctx)
+	if err != nil {
+		t.Fatalf("AggregateDashboard: %v", err)
+	}
+	if snapshot.TakenAt.Before(before.Add(-time.Second)) || snapshot.Total() != len(fixtures) {
+		t.Errorf("AggregateDashboard: tomada en %v con %d usuarios", snapshot.TakenAt, snapshot.Total())
+	}
+
+	// Una celda por combinación, en el orden del dominio
+	want := []entities.DashboardCell{
+		{Role: "admin", Sede: entities.DashboardKeyNone, Status: entities.DashboardStatusInactive, Count: 1},
+		{Role: "aprendiz", Sede: entities.DashboardKeyNone, Status: entities.DashboardStatusActive, Count: 1},
+		{Role: "aprendiz", Sede: sede.String(), Status: entities.DashboardStatusActive, Count: 2},
+		{Role: "aprendiz", Sede: sede.String(), Status: entities.DashboardStatusInactive, Count: 1},
+		{Role: "instructor", Sede: sede.String(), Status: entities.DashboardStatusActive, Count: 1},
+	}
+	if len(snapshot.Cells) != len(want) {
+		t.Fatalf("AggregateDashboard: %d celdas, se esperaban %d: %+v", len(snapshot.Cells), len(want), snapshot.Cells)
+	}
+	for i, cell := range snapshot.Cells {
+		want[i].SnapshotID = snapshot.ID
+		if cell != want[i] {
+			t.Errorf("celda %d = %+v, se esperaba %+v", i, cell, want[i])
+		}
+	}
+
+	// Los conteos planos del dashboard coinciden con los márgenes del cruce
+	byRole, err := repo.GetTotalUsersByRole(ctx)
+	if err != nil {
+		t.Fatalf("GetTotalUsersByRole: %v", err)
+	}
+	for role, count := range snapshot.CountBy(entities.DimensionRole) {
+		if byRole[role] != count {
+			t.Errorf("rol %s: %d en el cruce, %d en GetTotalUsersByRole", role, count, byRole[role])
+		}
+	}
+
+	table := snapshot.CrossTab(entities.DimensionRole, entities.DimensionStatus)
+	if table.Cell("aprendiz", entities.DashboardStatusActive) != 3 || table.Cell("aprendiz", entities.DashboardStatusInactive) != 1 ||
+		table.Cell("instructor", entities.DashboardStatusInactive) != 0 || table.Total != len(fixtures) {
+		t.Errorf("CrossTab(rol, estado) = %+v", table)
+	}
+	if !slices.Equal(table.ColumnTotals, []int{4, 2}) || !slices.Equal(table.RowKeys, []string{"admin", "aprendiz", "instructor"}) {
+		t.Errorf("CrossTab(rol, estado): columnas %v = %v, filas %v", table.ColumnKeys, table.ColumnTotals, table.RowKeys)
+	}
+
+	inSede := snapshot.Filter(entities.DimensionSede, sede.String()).CrossTab(entities.DimensionRole, entities.DimensionStatus)
+	if inSede.Total != 4 || !slices.Equal(inSede.RowTotals, []int{3, 1}) {
+		t.Errorf("CrossTab en la sede = %+v", inSede)
+	}
+} // fin testAggregateDashboard
+
+func testLoginHistory(t *testing.T, repo repositories.UserRepository) {
+	ctx := InternalContext()
+	user := NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	mustCreate(t, repo, user)
+	before := mustGet(t, repo, user.ID)
+
+	latest := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	older := latest.AddDate(0, 0, -3)
+	for _, at := range []time.Time{latest, older} {
+		event := entities.NewLoginEvent(user.ID, "10.0.0.1", "Mozilla/5.0")
+		event.OccurredAt = at
+		if err := repo.RecordLogin(ctx, event); err != nil {
+			t.Fatalf("RecordLogin: %v", err)
+		}
+		if event.ID == uuid.Nil {
+			t.Errorf("RecordLogin no asignó ID")
+		}
+	}
+
+	// Un inicio de sesión anterior al último no retrocede LastLogin, y registrarlo no es una edición
+	after := mustGet(t, repo, user.ID)
+	if after.LastLogin == nil || !after.LastLogin.Equal(latest) {
+		t.Errorf("LastLogin = %v, se esperaba %v", after.LastLogin, latest)
+	}
+	if after.Version != before.Version || !after.UpdatedAt.Equal(before.UpdatedAt) {
+		t.Errorf("RecordLogin cambió la versión (%d → %d) o UpdatedAt", before.Version, after.Version)
+	}
+
+	// Como la versión no cambia, guardar la copia leída antes del inicio de sesión no debe borrar LastLogin
+	before.FirstName = "Andrea"
+	if err := repo.Update(ctx, before); err != nil {
+		t.Fatalf("Update con la copia anterior al inicio de sesión: %v", err)
+	}
+	if updated := mustGet(t, repo, user.ID); updated.FirstName != "Andrea" || updated.LastLogin == nil || !updated.LastLogin.Equal(latest) {
+		t.Errorf("Update con la copia anterior: FirstName %q, LastLogin %v; se esperaba conservar %v", updated.FirstName, updated.LastLogin, latest)
+	}
+
+	history, err := repo.GetLoginHistory(ctx, user.ID, 0)
+	if err != nil || len(history) != 2 {
+		t.Fatalf("GetLoginHistory = %v, %v", history, err)
+	}
+	if !history[0].OccurredAt.Equal(latest) || !history[1].OccurredAt.Equal(older) {
+		t.Errorf("GetLoginHistory debe ordenar del más reciente al más antiguo: %v, %v", history[0].OccurredAt, history[1].OccurredAt)
+	}
+	if history[0].IPAddress == nil || *history[0].IPAddress != "10.0.0.1" || history[0].UserAgent == nil {
+		t.Errorf("GetLoginHistory no conserva IP y agente: %+v", history[0])
+	}
+	if history, err := repo.GetLoginHistory(ctx, user.ID, 1); err != nil || len(history) != 1 {
+		t.Errorf("GetLoginHistory(limit 1) = %d eventos, %v", len(history), err)
+	}
+
+	if err := repo.RecordLogin(ctx, entities.NewLoginEvent(uuid.New(), "", "")); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("RecordLogin(inexistente) = %v, se esperaba ErrUserNotFound", err)
+	}
+	if err := repo.Delete(ctx, user.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.RecordLogin(ctx, entities.NewLoginEvent(user.ID, "", "")); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("RecordLogin(eliminado) = %v, se esperaba ErrUserNotFound", err)
+	}
+	if _, err := repo.GetLoginHistory(ctx, user.ID, 0); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Errorf("GetLoginHistory(eliminado) = %v, se esperaba ErrUserNotFound", err)
+	}
+} // fin testLoginHistory
+
+// testActivityMetrics corre en RunOrganizationRepositoryContract: los segmentos por sede, centro y regional
+// necesitan sedes registradas
+func testActivityMetrics(t *testing.T, repos Repositories) {
+	ctx := InternalContext()
+	repo := repos.Users
+	at := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	path := mustCreateSedePath(t, repos.Organization, "11")
+	sede := path.SedeID
+	ficha := "2758800"
+
+	// setup ajusta al usuario antes de crearlo; logins se registran después
+	newUser := func(seq int, role entities.UserRole, setup func(*entities.User), logins ...time.Time) *entities.User {
+		user := NewTestUser(t, seq, "Ana", "Gómez", role)
+		user.CreatedAt = at.AddDate(0, 0, -200)
+		if setup != nil {
+			setup(user)
+		}
+		mustCreate(t, repo, user)
+		for _, login := range logins {
+			event := entities.NewLoginEvent(user.ID, "", "")
+			event.OccurredAt = login
+			if err := repo.RecordLogin(ctx, event); err != nil {
+				t.Fatalf("RecordLogin: %v", err)
+			}
+		}
+		return user
+	}
+
+	inFicha := func(user *entities.User) { user.FichaID = &ficha }
+	newUser(1, entities.RoleAprendiz, func(user *entities.User) {
+		user.SedeID, user.FichaID = &sede, &ficha
+	}, at.Add(-2*time.Hour), at.AddDate(0, 0, -40))
+	newUser(2, entities.RoleInstructor, nil, at.AddDate(0, 0, -3))
+	newUser(3, entities.RoleAprendiz, inFicha, at.AddDate(0, 0, -20))
+
+	// Un inicio de sesión anterior al historial solo se conoce por LastLogin
+	newUser(4, entities.RoleAprendiz, func(user *entities.User) {
+		legacyLogin := at.AddDate(0, 0, -100)
+		user.LastLogin = &legacyLogin
+	})
+
+	// Las cuentas deshabilitadas no cuentan como nunca usadas
+	newUser(5, entities.RoleAprendiz, inFicha)
+	newUser(6, entities.RoleCoordinador, (*entities.User).Deactivate)
+
+	// Un inicio de sesión posterior a la fecha de corte no cuenta
+	newUser(7, entities.RoleAprendiz, nil, at.Add(time.Hour))
+
+	// Los usuarios registrados después de la fecha de corte y los eliminados no cuentan
+	late := NewTestUser(t, 8, "Luis", "Pérez", entities.RoleAprendiz)
+	late.CreatedAt = at.AddDate(0, 0, 1)
+	mustCreate(t, repo, late)
+	deleted := newUser(9, entities.RoleAprendiz, nil, at.Add(-time.Hour))
+	if err := repo.Delete(ctx, deleted.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	report, err := repo.GetActivityMetrics(ctx, repositories.ActivityQuery{At: at})
+	if err != nil {
+		t.Fatalf("GetActivityMetrics: %v", err)
+	}
+	want := repositories.ActivityCounts{Users: 7, Daily: 1, Weekly: 2, Monthly: 3, NeverLoggedIn: 2, Dormant: 1}
+	if report.Total != want || report.DormantDays != repositories.DefaultDormantDays || len(report.Segments) != 0 {
+		t.Errorf("GetActivityMetrics = %+v, se esperaba %+v", report, want)
+	}
+
+	// Con un umbral mayor la cuenta de hace 100 días deja de estar inactiva
+	report, err = repo.GetActivityMetrics(ctx, repositories.ActivityQuery{At: at, DormantDays: 120})
+	if err != nil || report.Total.Dormant != 0 {
+		t.Errorf("GetActivityMetrics(120 días) = %+v, %v", report, err)
+	}
+
+	segments := []struct {
+		segment repositories.ActivitySegment
+		key     string
+		want    repositories.ActivityCounts
+	}{
+		{repositories.SegmentRole, string(entities.RoleAprendiz), repositories.ActivityCounts{Users: 5, Daily: 1, Weekly: 1, Monthly: 2, NeverLoggedIn: 2, Dormant: 1}},
+		{repositories.SegmentRole, string(entities.RoleCoordinador), repositories.ActivityCounts{Users: 1}},
+		{repositories.SegmentSede, sede.String(), repositories.ActivityCounts{Users: 1, Daily: 1, Weekly: 1, Monthly: 1}},
+		{repositories.SegmentSede, repositories.TrendKeyNone, repositories.ActivityCounts{Users: 6, Weekly: 1, Monthly: 2, NeverLoggedIn: 2, Dormant: 1}},
+		{repositories.SegmentCentro, path.CentroID.String(), repositories.ActivityCounts{Users: 1, Daily: 1, Weekly: 1, Monthly: 1}},
+		{repositories.SegmentRegional, path.RegionalID.String(), repositories.ActivityCounts{Users: 1, Daily: 1, Weekly: 1, Monthly: 1}},
+		{repositories.SegmentFicha, ficha, repositories.ActivityCounts{Users: 3, Daily: 1, Weekly: 1, Monthly: 2, NeverLoggedIn: 1}},
+	}
+	for _, tc := range segments {
+		report, err := repo.GetActivityMetrics(ctx, repositories.ActivityQuery{At: at, Segment: tc.segment})
+		if err != nil {
+			t.Errorf("GetActivityMetrics(%s): %v", tc.segment, err)
+			continue
+		}
+		if report.Total != want {
+			t.Errorf("GetActivityMetrics(%s): total = %+v", tc.segment, report.Total)
+		}
+		if got := report.SegmentByKey(tc.key); got == nil || got.ActivityCounts != tc.want {
+			t.Errorf("GetActivityMetrics(%s)[%q] = %+v, se esperaba %+v", tc.segment, tc.key, got, tc.want)
+		}
+	}
+
+	// Acotado al centro solo cuenta a los usuarios de sus sedes
+	scoped, err := repo.GetActivityMetrics(ctx, repositories.ActivityQuery{At: at, OrgFilter: repositories.OrgFilter{CentroID: &path.CentroID}})
+	if err != nil {
+		t.Fatalf("GetActivityMetrics(centro): %v", err)
+	}
+	if want := (repositories.ActivityCounts{Users: 1, Daily: 1, Weekly: 1, Monthly: 1}); scoped.Total != want {
+		t.Errorf("GetActivityMetrics(centro) = %+v, se esperaba %+v", scoped.Total, want)
+	}
+
+	for _, query := range []repositories.ActivityQuery{{DormantDays: -1}, {Segment: "program"}} {
+		if _, err := repo.GetActivityMetrics(ctx, query); !errors.Is(err, repositories.ErrInvalidActivityQuery) {
+			t.Errorf("GetActivityMetrics(%+v) = %v, se esperaba ErrInvalidActivityQuery", query, err)
+		}
+	}
+} // fin testActivityMetrics
+
+// assertEmails compara los emails obtenidos con los esperados sin importar el orden
+func assertEmails(t *testing.T, name string, users []*entities.User, want []entities.Email) {
+	t.Helper()
+
+	got := make(map[entities.Email]bool, len(users))
+	for _, user := range users {
+		got[user.Email] = true
+	}
+
+	if len(users) != len(want) {
+		t.Errorf("%s: se obtuvieron %d usuarios, se esperaban %d (%v)", name, len(users), len(want), want)
+		return
+	}
+
+	for _, email := range want {
+		if !got[email] {
+			t.Errorf("%s: falta el usuario %s", name, email)
+		}
+	}
+}
+
+// assertOrder verifica los usuarios y su orden por email
+func assertOrder(t *testing.T, name string, users []*entities.User, want []entities.Email) {
+	t.Helper()
+
+	got := make([]entities.Email, len(users))
+	for i, user := range users {
+		got[i] = user.Email
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("%s: orden %v, se esperaba %v", name, got, want)
+	}
+}
+
+// assertBulkFields verifica el campo reportado en cada error por índice
+func assertBulkFields(t *testing.T, name string, result *repositories.BulkOperationResult, fields map[int]string) {
+	t.Helper()
+
+	for _, bulkErr := range result.Errors {
+		if want, ok := fields[bulkErr.Index]; ok && bulkErr.Field != want {
+			t.Errorf("%s: fila %d con campo %q, se esperaba %q", name, bulkErr.Index, bulkErr.Field, want)
+		}
+	}
+}
+
+// assertBulkResult verifica los contadores y el índice/identificador de cada error
+func assertBulkResult(t *testing.T, name string, result *repositories.BulkOperationResult, total, success int, failures map[int]string) {
+	t.Helper()
+
+	if result.Total != total || result.Success != success || result.Failed != total-success {
+		t.Errorf("%s: total=%d success=%d failed=%d", name, result.Total, result.Success, result.Failed)
+	}
+
+	if len(result.Errors) != len(failures) {
+		t.Fatalf("%s: %d errores reportados, se esperaban %d: %+v", name, len(result.Errors), len(failures), result.Errors)
+	}
+
+	for _, bulkErr := range result.Errors {
+		identifier, ok := failures[bulkErr.Index]
+		if !ok || bulkErr.User != identifier || bulkErr.Error == "" {
+			t.Errorf("%s: error inesperado %+v", name, bulkErr)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories/internal/tenantctx"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOutsideTenant indica que la sede del usuario a crear o modificar está fuera del alcance del contexto
+	ErrOutsideTenant = errors.New("la sede está fuera del alcance permitido")
+
+	// ErrNoTenant indica que el contexto no tiene alcance de sede, bypass ni marca de proceso interno
+	// Los repositorios rechazan ese contexto en lugar de ver todas las sedes
+	ErrNoTenant = errors.New("el contexto no tiene alcance de sede")
+
+	// ErrTenantBypassDenied indica que solo un directivo de nivel nacional puede omitir el alcance de sede
+	ErrTenantBypassDenied = errors.New("solo un directivo de nivel nacional puede consultar todas las sedes")
+)
+
+// tenant es el alcance que viaja en el contexto bajo tenantctx.Key; con bypass no acota
+// Un proceso interno guarda tenantctx.Internal en su lugar
+type tenant struct {
+	scope  OrgFilter
+	bypass *entities.TenantBypass
+}
+
+// WithTenant acota a scope todas las operaciones de UserRepository que reciban el contexto resultante
+//
+// Las lecturas, conteos y tendencias solo ven a los usuarios de las sedes del alcance; los que están
+// fuera se comportan como inexistentes (nil, ErrUserNotFound o una fila fallida en Bulk*). Crear o
+// mover un usuario a una sede fuera del alcance retorna ErrOutsideTenant. Un alcance vacío no ve a nadie
+func WithTenant(ctx context.Context, scope OrgFilter) context.Context {
+	if scope.IsZero() {
+		none := uuid.Nil
+		scope.SedeID = &none
+	}
+	return context.WithValue(ctx, tenantctx.Key{}, tenant{scope: scope})
+}
+
+// WithUserTenant acota el contexto a la sede del usuario; sin sede no ve a nadie
+func WithUserTenant(ctx context.Context, user *entities.User) context.Context {
+	return WithTenant(ctx, OrgFilter{SedeID: user.SedeID})
+}
+
+// GrantTenantBypass retorna un contexto sin alcance de sede para el directivo de nivel nacional actorID
+// El actor se carga del repositorio: su rol, sede y estado son los guardados, no los que traiga el llamador
+// El bypass se registra con RecordTenantBypass antes de retornar: si no se puede auditar, no se concede
+// Retorna ErrUserNotFound si el actor no existe, ErrTenantBypassDenied si no es un directivo nacional activo
+// y *entities.DomainError si el motivo no es válido
+func GrantTenantBypass(ctx context.Context, users UserRepository, actorID uuid.UUID, reason string) (context.Context, error) {
+	// El actor puede ser de cualquier sede, o de ninguna, sin importar el alcance del llamador
+	actor, err := users.GetByID(tenantctx.Without(ctx), actorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckTenantBypassActor(actor); err != nil {
+		return nil, err
+	}
+
+	bypass, err := entities.NewTenantBypass(actor.ID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// El registro no depende del alcance que traiga el contexto
+	audit := context.WithValue(ctx, tenantctx.Key{}, tenant{bypass: bypass})
+	if err := users.RecordTenantBypass(audit, bypass); err != nil {
+		return nil, err
+	}
+
+	return audit, nil
+} // fin GrantTenantBypass
+
+// CheckTenantBypassActor verifica que el actor guardado pueda omitir el alcance de sede
+// Las implementaciones de UserRepository.RecordTenantBypass la usan para que todas reporten los mismos errores
+func CheckTenantBypassActor(actor *entities.User) error {
+	if actor == nil || actor.IsDeleted() {
+		return ErrUserNotFound
+	}
+	if !actor.IsNationalDirectivo() || !actor.IsActive {
+		return ErrTenantBypassDenied
+	}
+	return nil
+}
+
+// TenantScope retorna el alcance del contexto; scoped es false con bypass o en un proceso interno
+// Retorna ErrNoTenant si el contexto no pasó por WithTenant, GrantTenantBypass ni un proceso interno
+// como RefreshDashboardSnapshot
+func TenantScope(ctx context.Context) (scope OrgFilter, scoped bool, err error) {
+	switch t := ctx.Value(tenantctx.Key{}).(type) {
+	case tenantctx.Internal:
+		return OrgFilter{}, false, nil
+	case tenant:
+		if t.bypass != nil {
+			return OrgFilter{}, false, nil
+		}
+		return t.scope, true, nil
+	}
+	return OrgFilter{}, false, ErrNoTenant
+}
+
+// RequireTenant retorna ErrNoTenant si el contexto no tiene alcance, bypass ni marca de proceso interno
+func RequireTenant(ctx context.Context) error {
+	_, _, err := TenantScope(ctx)
+	return err
+}
+
+// CheckTenantSede retorna ErrOutsideTenant si la sede, dada su ruta, está fuera del alcance del contexto
+// path es nil sin sede o si la sede no existe, y entonces solo se cumple sin alcance. Retorna ErrNoTenant como TenantScope
+func CheckTenantSede(ctx context.Context, path *SedePath) error {
+	scope, scoped, err := TenantScope(ctx)
+	if err != nil || !scoped {
+		return err
+	}
+	if !scope.Matches(path) {
+		return ErrOutsideTenant
+	}
+	return nil
+}
+
+// RequireAllSedes retorna nil solo si el contexto ve todas las sedes, con bypass o en un proceso interno
+// Con alcance retorna ErrOutsideTenant y sin él ErrNoTenant; lo usan las operaciones que afectan a varias sedes
+func RequireAllSedes(ctx context.Context) error {
+	_, scoped, err := TenantScope(ctx)
+	if err == nil && scoped {
+		return ErrOutsideTenant
+	}
+	return err
+}
+
+// TenantBypassFrom retorna el bypass concedido al contexto, nil si no tiene
+func TenantBypassFrom(ctx context.Context) *entities.TenantBypass {
+	t, _ := ctx.Value(tenantctx.Key{}).(tenant)
+	return t.bypass
+}
+
+// ScopeOrgFilter combina el filtro con el alcance del contexto: el resultado solo cumple las sedes de ambos
+// Retorna ErrNoTenant como TenantScope
+func ScopeOrgFilter(ctx context.Context, filter OrgFilter) (OrgFilter, error) {
+	scope, scoped, err := TenantScope(ctx)
+	if err != nil || !scoped {
+		return filter, err
+	}
+	return filter.Intersect(scope), nil
+}
+
+// Intersect retorna el filtro que cumplen solo las sedes que cumplen ambos
+// Si los dos fijan el mismo nivel con IDs distintos ninguna sede lo cumple
+func (f OrgFilter) Intersect(other OrgFilter) OrgFilter {
+	return OrgFilter{
+		RegionalID: intersectID(f.RegionalID, other.RegionalID),
+		CentroID:   intersectID(f.CentroID, other.CentroID),
+		SedeID:     intersectID(f.SedeID, other.SedeID),
+	}
+}
+
+// intersectID retorna el ID que fija alguno de los dos, uuid.Nil si fijan IDs distintos
+func intersectID(a, b *uuid.UUID) *uuid.UUID {
+	switch {
+	case a == nil:
+		return b
+	case b == nil || *a == *b:
+		return a
+	}
+	none := uuid.Nil
+	return &none
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"userservice/internal/domain/entities"
+)
+
+// ErrInvalidActivityQuery indica que la fecha de corte, el umbral de inactividad o la segmentación no son válidos
+var ErrInvalidActivityQuery = errors.New("consulta de actividad inválida")
+
+// ActivitySegment es la dimensión por la que se segmentan las métricas de actividad
+type ActivitySegment string
+
+const (
+	SegmentNone     ActivitySegment = ""
+	SegmentRole     ActivitySegment = "role"
+	SegmentSede     ActivitySegment = "sede"
+	SegmentCentro   ActivitySegment = "centro"
+	SegmentRegional ActivitySegment = "regional"
+	SegmentFicha    ActivitySegment = "ficha"
+)
+
+// Ventanas móviles de usuarios activos, terminan en ActivityQuery.At
+const (
+	DailyActiveWindow   = 24 * time.Hour
+	WeeklyActiveWindow  = 7 * 24 * time.Hour
+	MonthlyActiveWindow = 30 * 24 * time.Hour
+)
+
+// DefaultDormantDays son los días sin iniciar sesión tras los que una cuenta se considera inactiva
+const DefaultDormantDays = 90
+
+// ActivityQuery define la fecha de corte y la segmentación de las métricas de actividad
+type ActivityQuery struct {
+	At          time.Time       `json:"at"`                     // Fecha de corte; por defecto ahora
+	DormantDays int             `json:"dormant_days,omitempty"` // Por defecto DefaultDormantDays
+	Segment     ActivitySegment `json:"segment,omitempty"`
+	OrgFilter                   // Solo cuenta los usuarios de la sede, el centro o la regional indicados
+}
+
+// Normalize aplica los valores por defecto y valida la consulta
+func (q *ActivityQuery) Normalize() error {
+	if q.At.IsZero() {
+		q.At = time.Now()
+	}
+
+	if q.DormantDays == 0 {
+		q.DormantDays = DefaultDormantDays
+	}
+	if q.DormantDays < 1 {
+		return fmt.Errorf("%w: días de inactividad %d", ErrInvalidActivityQuery, q.DormantDays)
+	}
+
+	if !slices.Contains([]ActivitySegment{SegmentNone, SegmentRole, SegmentSede, SegmentCentro, SegmentRegional, SegmentFicha}, q.Segment) {
+		return fmt.Errorf("%w: segmento %q", ErrInvalidActivityQuery, q.Segment)
+	}
+
+	return nil
+}
+
+// ActivityThresholds son los límites de cada métrica: un usuario cuenta en una ventana
+// si su último inicio de sesión hasta At es posterior al límite, y está inactivo si no lo es a Dormant
+type ActivityThresholds struct {
+	Daily   time.Time
+	Weekly  time.Time
+	Monthly time.Time
+	Dormant time.Time
+}
+
+// Thresholds calcula los límites de las métricas a partir de At
+func (q ActivityQuery) Thresholds() ActivityThresholds {
+	return ActivityThresholds{
+		Daily:   q.At.Add(-DailyActiveWindow),
+		Weekly:  q.At.Add(-WeeklyActiveWindow),
+		Monthly: q.At.Add(-MonthlyActiveWindow),
+		Dormant: q.At.AddDate(0, 0, -q.DormantDays),
+	}
+}
+
+// Classify clasifica a un usuario según su último inicio de sesión hasta At, nil si nunca inició sesión
+// Las cuentas nunca usadas e inactivas solo cuentan si el usuario está habilitado (IsActive)
+func (q ActivityQuery) Classify(lastSeen *time.Time, enabled bool) ActivityCounts {
+	counts := ActivityCounts{Users: 1}
+	if lastSeen == nil {
+		if enabled {
+			counts.NeverLoggedIn = 1
+		}
+		return counts
+	}
+
+	limits := q.Thresholds()
+	if lastSeen.After(limits.Daily) {
+		counts.Daily = 1
+	}
+	if lastSeen.After(limits.Weekly) {
+		counts.Weekly = 1
+	}
+	if lastSeen.After(limits.Monthly) {
+		counts.Monthly = 1
+	}
+	if enabled && !lastSeen.After(limits.Dormant) {
+		counts.Dormant = 1
+	}
+
+	return counts
+} // fin Classify
+
+// ActivityCounts son las métricas de actividad de un grupo de usuarios
+type ActivityCounts struct {
+	Users         int `json:"users"` // Usuarios registrados hasta At
+	Daily         int `json:"daily_active"`
+	Weekly        int `json:"weekly_active"`
+	Monthly       int `json:"monthly_active"`
+	NeverLoggedIn int `json:"never_logged_in"`
+	Dormant       int `json:"dormant"`
+}
+
+// add suma las métricas de otro grupo
+func (c *ActivityCounts) add(other ActivityCounts) {
+	c.Users += other.Users
+	c.Daily += other.Daily
+	c.Weekly += other.Weekly
+	c.Monthly += other.Monthly
+	c.NeverLoggedIn += other.NeverLoggedIn
+	c.Dormant += other.Dormant
+}
+
+// SegmentActivity son las métricas de un valor de la dimensión
+type SegmentActivity struct {
+	Key string `json:"key"`
+	ActivityCounts
+}
+
+// ActivityReport son las métricas de actividad a una fecha de corte, en total y por segmento
+type ActivityReport struct {
+	At          time.Time         `json:"at"`
+	DormantDays int               `json:"dormant_days"`
+	Segment     ActivitySegment   `json:"segment,omitempty"`
+	Total       ActivityCounts    `json:"total"`
+	Segments    []SegmentActivity `json:"segments,omitempty"` // Ordenados por Key
+}
+
+// NewActivityReport crea el reporte vacío de una consulta ya normalizada
+func NewActivityReport(q ActivityQuery) *ActivityReport {
+	return &ActivityReport{At: q.At, DormantDays: q.DormantDays, Segment: q.Segment}
+}
+
+// Add suma las métricas de un grupo de usuarios al total y al segmento key; sin segmentación key se ignora
+func (r *ActivityReport) Add(key string, counts ActivityCounts) {
+	r.Total.add(counts)
+	if r.Segment == SegmentNone {
+		return
+	}
+
+	position, found := slices.BinarySearchFunc(r.Segments, key, func(segment SegmentActivity, key string) int {
+		return strings.Compare(segment.Key, key)
+	})
+	if !found {
+		r.Segments = slices.Insert(r.Segments, position, SegmentActivity{Key: key})
+	}
+	r.Segments[position].add(counts)
+}
+
+// SegmentByKey retorna las métricas de un valor de la dimensión, nil si no tiene usuarios
+func (r *ActivityReport) SegmentByKey(key string) *SegmentActivity {
+	for i := range r.Segments {
+		if r.Segments[i].Key == key {
+			return &r.Segments[i]
+		}
+	}
+	return nil
+}
+
+// UserActivityKey retorna el valor del usuario en la dimensión; TrendKeyNone si no lo tiene
+// SegmentCentro y SegmentRegional dependen de la sede del usuario: cada implementación los resuelve con SedePath.Key
+func UserActivityKey(user *entities.User, segment ActivitySegment) string {
+	switch segment {
+	case SegmentRole:
+		return string(user.Role)
+	case SegmentSede:
+		if user.SedeID != nil {
+			return user.SedeID.String()
+		}
+	case SegmentFicha:
+		if user.FichaID != nil {
+			return *user.FichaID
+		}
+	}
+	return TrendKeyNone
+}
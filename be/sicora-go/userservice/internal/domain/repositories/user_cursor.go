@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/filter"
+
+	"github.com/google/uuid"
+)
+
+// PaginationMode define cómo se pagina el listado de usuarios
+type PaginationMode string
+
+const (
+	// PaginationOffset usa Page/PageSize (por defecto), adecuado para tablas administrativas pequeñas
+	PaginationOffset PaginationMode = "offset"
+	// PaginationCursor usa un cursor opaco sobre la llave de ordenamiento + ID (keyset)
+	PaginationCursor PaginationMode = "cursor"
+)
+
+// UserCursor es la posición de un usuario dentro de un listado ordenado
+// Se serializa como un token opaco en PaginatedUsers.NextCursor/PrevCursor
+type UserCursor struct {
+	Sort     string    `json:"s"` // filter.FormatSort del listado que generó el cursor
+	Values   []string  `json:"v"` // Valor de cada columna de ordenamiento (filter.Value.Encode)
+	ID       uuid.UUID `json:"i"`
+	Backward bool      `json:"b,omitempty"` // La página solicitada está antes del cursor
+
+	keys []filter.Value
+}
+
+// NewUserCursor construye el cursor que apunta al usuario dado dentro del listado
+func NewUserCursor(user *entities.User, filters UserFilters, backward bool) UserCursor {
+	cursor := UserCursor{
+		Sort:     filter.FormatSort(filters.Sort),
+		Values:   make([]string, len(filters.Sort)),
+		ID:       user.ID,
+		Backward: backward,
+		keys:     make([]filter.Value, len(filters.Sort)),
+	}
+
+	for i, field := range filters.Sort {
+		cursor.keys[i] = UserFieldValue(user, field.Field)
+		cursor.Values[i] = cursor.keys[i].Encode()
+	}
+
+	return cursor
+} // fin NewUserCursor
+
+// Encode serializa el cursor como token opaco
+func (c UserCursor) Encode() string {
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// Keys retorna los valores tipados de cada columna de ordenamiento
+func (c UserCursor) Keys() []filter.Value {
+	return c.keys
+}
+
+// DecodeUserCursor interpreta un token y verifica que corresponda al ordenamiento de los filtros
+// Los filtros deben estar normalizados
+func DecodeUserCursor(token string, filters UserFilters) (*UserCursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor UserCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if cursor.Sort != filter.FormatSort(filters.Sort) || len(cursor.Values) != len(filters.Sort) {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor.keys = make([]filter.Value, len(filters.Sort))
+	for i, field := range filters.Sort {
+		cursor.keys[i], err = filter.ParseValue(UserFilterSchema[field.Field].Kind, cursor.Values[i])
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return &cursor, nil
+} // fin DecodeUserCursor
+
+// NewCursorPage construye el resultado de una página por cursor
+// users contiene hasta PageSize+1 filas en el orden del listado; la fila extra indica que hay más
+func NewCursorPage(users []*entities.User, filters UserFilters, cursor *UserCursor) *PaginatedUsers {
+	hasMore := len(users) > filters.PageSize
+	if hasMore {
+		if cursor != nil && cursor.Backward {
+			users = users[1:]
+		} else {
+			users = users[:filters.PageSize]
+		}
+	}
+
+	page := &PaginatedUsers{
+		Users:    users,
+		PageSize: filters.PageSize,
+	}
+	if page.Users == nil {
+		page.Users = []*entities.User{}
+	}
+
+	switch {
+	case cursor == nil:
+		page.HasNext = hasMore
+	case cursor.Backward:
+		page.HasPrevious = hasMore
+		page.HasNext = true
+	default:
+		page.HasNext = hasMore
+		page.HasPrevious = true
+	}
+
+	if len(page.Users) > 0 {
+		if page.HasNext {
+			page.NextCursor = NewUserCursor(page.Users[len(page.Users)-1], filters, false).Encode()
+		}
+		if page.HasPrevious {
+			page.PrevCursor = NewUserCursor(page.Users[0], filters, true).Encode()
+		}
+	}
+
+	return page
+} // fin NewCursorPage
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/filter"
+)
+
+// UserFilterSchema es la lista blanca de campos de User que se pueden filtrar y ordenar
+// Los datos sensibles (password, teléfono, consentimientos) no se exponen
+var UserFilterSchema = filter.Schema{
+	"id":                {Kind: filter.KindUUID},
+	"first_name":        {Kind: filter.KindString, Sortable: true},
+	"last_name":         {Kind: filter.KindString, Sortable: true},
+	"email":             {Kind: filter.KindString, Sortable: true},
+	"document_number":   {Kind: filter.KindString, Sortable: true},
+	"document_type":     {Kind: filter.KindString},
+	"birth_date":        {Kind: filter.KindTime, Nullable: true},
+	"role":              {Kind: filter.KindString, Sortable: true},
+	"status":            {Kind: filter.KindString},
+	"is_active":         {Kind: filter.KindBool},
+	"email_verified":    {Kind: filter.KindBool},
+	"email_verified_at": {Kind: filter.KindTime, Nullable: true},
+	"ficha_id":          {Kind: filter.KindString, Nullable: true},
+	"sede_id":           {Kind: filter.KindUUID, Nullable: true},
+	"created_at":        {Kind: filter.KindTime, Sortable: true},
+	"updated_at":        {Kind: filter.KindTime, Sortable: true},
+	"last_login":        {Kind: filter.KindTime, Nullable: true, Sortable: true},
+}
+
+// DefaultUserSort es el ordenamiento por defecto: más recientes primero
+var DefaultUserSort = []filter.SortField{{Field: "created_at", Desc: true}}
+
+// ParseUserFilter interpreta una expresión de filtro sobre los campos de UserFilterSchema
+func ParseUserFilter(input string) (filter.Expr, error) {
+	return filter.Parse(input, UserFilterSchema)
+}
+
+// ParseUserSort interpreta el ordenamiento; "relevance" solo se admite como única columna
+func ParseUserSort(input string) ([]filter.SortField, error) {
+	if strings.EqualFold(strings.TrimSpace(input), SortByRelevance) {
+		return []filter.SortField{{Field: SortByRelevance}}, nil
+	}
+
+	return filter.ParseSort(input, UserFilterSchema)
+}
+
+// UserFieldValue retorna el valor de un campo de UserFilterSchema para el usuario
+func UserFieldValue(user *entities.User, field string) filter.Value {
+	switch field {
+	case "id":
+		return filter.UUID(user.ID)
+	case "first_name":
+		return filter.String(user.FirstName)
+	case "last_name":
+		return filter.String(user.LastName)
+	case "email":
+		return filter.String(user.Email.String())
+	case "document_number":
+		return filter.String(user.DocumentNumber)
+	case "document_type":
+		return filter.String(string(user.DocumentType))
+	case "birth_date":
+		return optionalTime(user.BirthDate)
+	case "role":
+		return filter.String(string(user.Role))
+	case "status":
+		return filter.String(user.Status)
+	case "is_active":
+		return filter.Bool(user.IsActive)
+	case "email_verified":
+		return filter.Bool(user.EmailVerified)
+	case "email_verified_at":
+		return optionalTime(user.EmailVerifiedAt)
+	case "ficha_id":
+		if user.FichaID == nil {
+			return filter.Null
+		}
+		return filter.String(*user.FichaID)
+	case "sede_id":
+		if user.SedeID == nil {
+			return filter.Null
+		}
+		return filter.UUID(*user.SedeID)
+	case "created_at":
+		return filter.Time(user.CreatedAt)
+	case "updated_at":
+		return filter.Time(user.UpdatedAt)
+	case "last_login":
+		return optionalTime(user.LastLogin)
+	default:
+		return filter.Null
+	}
+} // fin UserFieldValue
+
+func optionalTime(t *time.Time) filter.Value {
+	if t == nil {
+		return filter.Null
+	}
+	return filter.Time(*t)
+}
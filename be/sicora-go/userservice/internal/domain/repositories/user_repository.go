@@ -2,88 +2,193 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
 
 	"userservice/internal/domain/entities"
+	"userservice/internal/domain/filter"
 
 	"github.com/google/uuid"
 )
 
 // UserRepository define las operaciones de persistencia para usuarios
 // Esta es la interfaz del dominio que será implementada en la capa de infraestructura
+// Los emails se comparan en su forma canónica (entities.Email), sin distinguir mayúsculas
+// Los usuarios eliminados (soft delete) se excluyen de todas las consultas y modificaciones,
+// salvo en List cuando UserFilters.Deleted lo indica
+// Todas las operaciones respetan el alcance de sede del contexto (WithTenant), salvo con GrantTenantBypass o en un
+// proceso interno como RefreshDashboardSnapshot; un contexto sin ninguno de los tres se rechaza con ErrNoTenant
+// Las lecturas pueden retornar los usuarios sin Password salvo con WithCredentials, y Update y BulkUpdate
+// conservan la contraseña guardada si Password está vacío, así un usuario leído sin ella se puede guardar
 type UserRepository interface {
-	// Create crea un nuevo usuario en el repositorio
+	// Create crea un nuevo usuario en el repositorio, retorna ErrDuplicateUser si el email o documento ya existen
+	// y ErrSedeNotFound si SedeID no es una sede registrada. Con alcance de sede retorna ErrOutsideTenant si SedeID está fuera de él
 	Create(ctx context.Context, user *entities.User) error
 
-	// GetByID obtiene un usuario por su ID
+	// GetByID obtiene un usuario por su ID, retorna nil si no existe
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
 
-	// GetByEmail obtiene un usuario por su email
-	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+	// GetByEmail obtiene un usuario por su email sin distinguir mayúsculas, retorna nil si no existe
+	GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error)
 
-	// GetByDocumentNumber obtiene un usuario por su número de documento
-	GetByDocumentNumber(ctx context.Context, documentNumber string) (*entities.User, error)
+	// GetByDocument obtiene un usuario por su tipo y número de documento, retorna nil si no existe
+	// El documento es único por tipo: una CC y un pasaporte pueden tener el mismo número
+	// El número se busca normalizado con entities.NormalizeDocumentNumber ("1.012.345.678" encuentra "1012345678")
+	GetByDocument(ctx context.Context, documentType entities.DocumentType, documentNumber string) (*entities.User, error)
 
-	// Update actualiza los datos de un usuario existente
+	// Update actualiza los datos de un usuario existente, retorna ErrUserNotFound si no existe
+	// Solo guarda si user.Version coincide con la versión almacenada; de lo contrario retorna
+	// *VersionConflictError. Al guardar incrementa user.Version
+	// Retorna ErrSedeNotFound si cambia SedeID por una sede no registrada y ErrOutsideTenant si SedeID queda fuera del alcance
+	// LastLogin no se guarda: solo RecordLogin lo cambia, así una copia anterior al inicio de sesión no lo borra
 	Update(ctx context.Context, user *entities.User) error
 
-	// Delete elimina un usuario (soft delete)
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Delete elimina un usuario (soft delete): registra DeletedAt/DeletedBy y lo desactiva
+	// deletedBy es nil cuando la eliminación la realiza un proceso del sistema
+	Delete(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error
+
+	// Restore revierte el soft delete de un usuario, retorna ErrUserNotFound si no está eliminado
+	// y ErrDuplicateUser si otro usuario activo ya tomó su email o documento
+	Restore(ctx context.Context, id uuid.UUID) error
+
+	// Purge elimina definitivamente los usuarios eliminados antes de la fecha dada (retención)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 
 	// List obtiene una lista paginada de usuarios con filtros opcionales
 	List(ctx context.Context, filters UserFilters) (*PaginatedUsers, error)
 
+	// Stream recorre todos los usuarios que cumplen los filtros en el orden de List, sin paginar
+	// (Page, PageSize y Cursor se ignoran) y con memoria constante. El recorrido se detiene al
+	// primer error, que se entrega como último elemento, incluido el de cancelación del contexto
+	Stream(ctx context.Context, filters UserFilters) iter.Seq2[*entities.User, error]
+
 	// GetByFicha obtiene todos los aprendices de una ficha específica
-	GetByFicha(ctx context.Context, fichaID string) ([]*entities.User, error)
+	// Con on en cero retorna los aprendices actuales; con una fecha, los que pertenecían a la ficha ese día
+	// según el historial de movimientos. Retorna ErrFichaNotFound si la ficha no existe o está fuera del alcance de sede
+	GetByFicha(ctx context.Context, fichaID string, on time.Time) ([]*entities.User, error)
+
+	// AssignFicha asigna el aprendiz sin ficha a la ficha, incrementando Version, y registra su ingreso con fecha de hoy
+	// Retorna ErrUserNotFound si el usuario no existe, *entities.DomainError si no es aprendiz,
+	// ErrAlreadyInFicha si ya pertenece a otra ficha, ErrFichaNotFound si la ficha no existe o está fuera del alcance,
+	// ErrFichaOtherSede si la ficha es de otra sede que el aprendiz, ErrFichaClosed si no admite aprendices y ErrFichaFull si ya alcanzó su capacidad. Asignar la ficha actual no hace nada
+	AssignFicha(ctx context.Context, userID uuid.UUID, fichaID string) error
 
-	// ExistsByEmail verifica si existe un usuario con el email dado
-	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	// TransferFicha traslada al aprendiz a transfer.ToFichaID, incrementando Version, y registra el traslado
+	// con su ficha de origen. Además de los errores de AssignFicha retorna ErrNotInFicha si el aprendiz no tiene ficha,
+	// ErrInvalidApprover si quien aprueba no es coordinador ni administrador y ErrBackdatedMovement
+	// si la fecha efectiva es anterior a su último movimiento
+	TransferFicha(ctx context.Context, transfer *entities.FichaMovement) error
 
-	// ExistsByDocumentNumber verifica si existe un usuario con el documento dado
-	ExistsByDocumentNumber(ctx context.Context, documentNumber string) (bool, error)
+	// GetFichaHistory obtiene los movimientos de ficha del aprendiz del más antiguo al más reciente
+	// Retorna ErrUserNotFound si el usuario no existe o fue eliminado
+	GetFichaHistory(ctx context.Context, userID uuid.UUID) ([]*entities.FichaMovement, error)
 
-	// BulkCreate crea múltiples usuarios en una operación
-	BulkCreate(ctx context.Context, users []*entities.User) error
+	// ExistsByEmail verifica si existe un usuario con el email dado, sin distinguir mayúsculas
+	ExistsByEmail(ctx context.Context, email entities.Email) (bool, error)
 
-	// BulkUpdate actualiza múltiples usuarios en una operación
-	BulkUpdate(ctx context.Context, updates map[string]*entities.User) (*BulkOperationResult, error)
+	// ExistsByDocument verifica si existe un usuario con el tipo y número de documento dados, normalizado como en GetByDocument
+	ExistsByDocument(ctx context.Context, documentType entities.DocumentType, documentNumber string) (bool, error)
 
-	// BulkDelete elimina múltiples usuarios por emails
-	BulkDelete(ctx context.Context, emails []string) (*BulkOperationResult, error)
+	// BulkCreate crea múltiples usuarios en una operación y reporta el resultado por fila
+	// Con BulkAtomic no se crea ninguno si alguna fila falla (RolledBack) y los usuarios dados quedan sin cambios;
+	// con BulkBestEffort se crean las filas válidas. Los errores de fila no se retornan como error
+	BulkCreate(ctx context.Context, users []*entities.User, mode BulkMode) (*BulkOperationResult, error)
+
+	// BulkUpdate actualiza múltiples usuarios en una operación con la misma verificación de
+	// versión de Update; los conflictos se reportan por fila con Field "version"
+	// Las llaves del mapa son los emails actuales de los usuarios a actualizar y se procesan
+	// en orden lexicográfico, que es el que determina BulkOperationError.Index
+	BulkUpdate(ctx context.Context, updates map[entities.Email]*entities.User) (*BulkOperationResult, error)
+
+	// BulkDelete elimina (soft delete) múltiples usuarios por emails
+	BulkDelete(ctx context.Context, emails []entities.Email, deletedBy *uuid.UUID) (*BulkOperationResult, error)
 
 	// BulkStatusChange cambia el estado de múltiples usuarios
-	BulkStatusChange(ctx context.Context, emails []string, isActive bool) (*BulkOperationResult, error)
+	BulkStatusChange(ctx context.Context, emails []entities.Email, isActive bool) (*BulkOperationResult, error)
+
+	// GetMultipleByEmails obtiene múltiples usuarios por sus emails; los que solo difieren en mayúsculas son el mismo
+	GetMultipleByEmails(ctx context.Context, emails []entities.Email) ([]*entities.User, error)
 
-	// GetMultipleByEmails obtiene múltiples usuarios por sus emails
-	GetMultipleByEmails(ctx context.Context, emails []string) ([]*entities.User, error)
+	// RecordLogin guarda el inicio de sesión en el historial y actualiza LastLogin si es el más reciente
+	// No modifica Version ni UpdatedAt. Retorna ErrUserNotFound si el usuario no existe o fue eliminado
+	RecordLogin(ctx context.Context, event *entities.LoginEvent) error
+
+	// GetLoginHistory obtiene los inicios de sesión del usuario del más reciente al más antiguo
+	// limit <= 0 retorna todos. Retorna ErrUserNotFound si el usuario no existe o fue eliminado
+	GetLoginHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.LoginEvent, error)
+
+	// RecordTenantBypass guarda el bypass de sede en la bitácora de auditoría, sin importar el alcance del contexto
+	// Verifica al actor guardado en la misma transacción con CheckTenantBypassActor: retorna ErrUserNotFound
+	// si no existe o fue eliminado y ErrTenantBypassDenied si no es un directivo nacional activo. Lo usa GrantTenantBypass
+	RecordTenantBypass(ctx context.Context, bypass *entities.TenantBypass) error
+
+	// ListTenantBypasses obtiene los bypass de sede concedidos desde since, del más reciente al más antiguo
+	// Con alcance de sede solo ve los de actores del alcance
+	ListTenantBypasses(ctx context.Context, since time.Time) ([]*entities.TenantBypass, error)
 
 	// Dashboard Directivo Queries
 
 	// GetTotalUsersByRole obtiene el conteo de usuarios por rol
 	GetTotalUsersByRole(ctx context.Context) (map[string]int, error)
 
-	// GetTotalUsersByProgram obtiene el conteo de usuarios por programa
+	// GetTotalUsersByProgram obtiene el conteo de usuarios por código de programa, según el programa de su ficha
+	// Los usuarios sin ficha o cuya ficha no tiene programa no se cuentan
 	GetTotalUsersByProgram(ctx context.Context) (map[string]int, error)
 
-	// GetUserRegistrationTrend obtiene el registro de usuarios en los últimos N días
-	GetUserRegistrationTrend(ctx context.Context, days int) (map[string]int, error)
+	// GetTotalUsersByOrgLevel obtiene el conteo de usuarios por ID del nodo del nivel al que pertenece su sede,
+	// solo de los usuarios que cumplen scope. Los usuarios sin sede no se cuentan
+	// Retorna ErrInvalidOrgLevel si el nivel no es regional, centro ni sede
+	GetTotalUsersByOrgLevel(ctx context.Context, level OrgLevel, scope OrgFilter) (map[string]int, error)
+
+	// GetUserRegistrationTrend obtiene la serie de registros por intervalo, con los intervalos vacíos en cero
+	// Retorna ErrInvalidTrendQuery si la consulta no es válida
+	GetUserRegistrationTrend(ctx context.Context, query RegistrationTrendQuery) (*RegistrationTrend, error)
 
 	// GetActiveInactiveCount obtiene el conteo de usuarios activos e inactivos
 	GetActiveInactiveCount(ctx context.Context) (active int, inactive int, err error)
+
+	// AggregateDashboard cruza los usuarios no eliminados por rol, programa, sede y estado en una sola consulta
+	// La foto resultante aún no está guardada; DashboardSnapshotRepository la conserva
+	AggregateDashboard(ctx context.Context) (*entities.DashboardSnapshot, error)
+
+	// GetActivityMetrics obtiene los usuarios activos por día, semana y mes, las cuentas nunca usadas
+	// y las inactivas a la fecha de corte, según el historial de inicios de sesión y LastLogin
+	// Retorna ErrInvalidActivityQuery si la consulta no es válida
+	GetActivityMetrics(ctx context.Context, query ActivityQuery) (*ActivityReport, error)
 }
 
 // UserFilters define los filtros disponibles para buscar usuarios
 type UserFilters struct {
-	Rol           *entities.UserRole `json:"rol,omitempty"`
-	FichaID       *string            `json:"ficha_id,omitempty"`
-	Programa      *string            `json:"programa,omitempty"`
-	IsActive      *bool              `json:"is_active,omitempty"`
-	Search        *string            `json:"search,omitempty"` // Búsqueda por nombre, apellido o email
-	Page          int                `json:"page"`
-	PageSize      int                `json:"page_size"`
-	SortBy        string             `json:"sort_by"`
-	SortDirection string             `json:"sort_direction"` // "asc" o "desc"
+	Rol        *entities.UserRole `json:"rol,omitempty"`
+	FichaID    *string            `json:"ficha_id,omitempty"`
+	Programa   *string            `json:"programa,omitempty"` // Código del programa al que pertenece la ficha del usuario
+	OrgFilter                     // Sede, centro o regional a la que pertenece la sede del usuario
+	IsActive   *bool              `json:"is_active,omitempty"`
+	Search     *string            `json:"search,omitempty"` // Términos sin tildes ni mayúsculas sobre nombre, apellido, email y prefijo del documento
+	Deleted    DeletedScope       `json:"deleted,omitempty"`
+	Pagination PaginationMode     `json:"pagination,omitempty"` // "offset" (por defecto) o "cursor"
+	Cursor     string             `json:"cursor,omitempty"`     // Token de NextCursor/PrevCursor; implica paginación por cursor
+	Filter     filter.Expr        `json:"-"`                    // Expresión de ParseUserFilter; se combina con los filtros anteriores
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	Sort       []filter.SortField `json:"sort,omitempty"` // Columnas de ParseUserSort; por defecto relevancia al buscar o created_at descendente
 }
 
+// DeletedScope indica cómo tratar a los usuarios eliminados en un listado
+type DeletedScope string
+
+const (
+	// DeletedExclude excluye a los usuarios eliminados (valor por defecto)
+	DeletedExclude DeletedScope = ""
+	// DeletedInclude incluye usuarios eliminados y no eliminados
+	DeletedInclude DeletedScope = "include"
+	// DeletedOnly lista solo usuarios eliminados
+	DeletedOnly DeletedScope = "only"
+)
+
 // PaginatedUsers representa el resultado paginado de usuarios
 type PaginatedUsers struct {
 	Users       []*entities.User `json:"users"`
@@ -93,14 +198,27 @@ type PaginatedUsers struct {
 	TotalPages  int              `json:"total_pages"`
 	HasNext     bool             `json:"has_next"`
 	HasPrevious bool             `json:"has_previous"`
+	NextCursor  string           `json:"next_cursor,omitempty"` // Solo en paginación por cursor
+	PrevCursor  string           `json:"prev_cursor,omitempty"` // Solo en paginación por cursor
 }
 
+// BulkMode define la atomicidad de una operación masiva
+type BulkMode string
+
+const (
+	// BulkAtomic aplica todas las filas o ninguna
+	BulkAtomic BulkMode = "atomic"
+	// BulkBestEffort aplica las filas válidas y reporta las fallidas
+	BulkBestEffort BulkMode = "best_effort"
+)
+
 // BulkOperationResult representa el resultado de una operación masiva
 type BulkOperationResult struct {
-	Total   int                  `json:"total"`
-	Success int                  `json:"success"`
-	Failed  int                  `json:"failed"`
-	Errors  []BulkOperationError `json:"errors,omitempty"`
+	Total      int                  `json:"total"`
+	Success    int                  `json:"success"`
+	Failed     int                  `json:"failed"`
+	RolledBack bool                 `json:"rolled_back,omitempty"` // Operación atómica revertida
+	Errors     []BulkOperationError `json:"errors,omitempty"`
 }
 
 // BulkOperationError representa un error en una operación masiva
@@ -109,4 +227,146 @@ type BulkOperationError struct {
 	User  string `json:"user"` // Email o documento para identificar
 	Error string `json:"error"`
 	Field string `json:"field,omitempty"`
-}
\ No newline at end of file
+}
+
+// Record acumula el resultado de una fila en la operación masiva
+func (r *BulkOperationResult) Record(index int, identifier string, err error) {
+	if err == nil {
+		r.Success++
+		return
+	}
+
+	bulkErr := BulkOperationError{
+		Index: index,
+		User:  identifier,
+		Error: err.Error(),
+	}
+
+	var duplicate *DuplicateUserError
+	switch {
+	case errors.Is(err, ErrVersionConflict):
+		bulkErr.Field = "version"
+	case errors.As(err, &duplicate):
+		bulkErr.Field = duplicate.Field
+	}
+
+	r.Failed++
+	r.Errors = append(r.Errors, bulkErr)
+} // fin Record
+
+// RecordUser acumula el resultado de una fila identificando al usuario por el campo en conflicto:
+// el documento si falló el documento, de lo contrario el email
+func (r *BulkOperationResult) RecordUser(index int, user *entities.User, err error) {
+	identifier := user.Email.String()
+	var duplicate *DuplicateUserError
+	if errors.As(err, &duplicate) && duplicate.Field == "document_number" {
+		identifier = user.DocumentNumber
+	}
+
+	r.Record(index, identifier, err)
+}
+
+// RollBack marca la operación atómica como revertida: ninguna fila quedó aplicada
+func (r *BulkOperationResult) RollBack() {
+	r.RolledBack = true
+	r.Success = 0
+	r.Failed = r.Total
+}
+
+const (
+	// DefaultPageSize es el tamaño de página usado cuando no se especifica uno
+	DefaultPageSize = 20
+
+	// MaxPageSize es el tamaño máximo de página permitido
+	MaxPageSize = 100
+)
+
+// Normalize aplica los valores por defecto de paginación y ordenamiento
+// y valida el filtro y el ordenamiento contra UserFilterSchema
+func (f *UserFilters) Normalize() error {
+	if f.Cursor != "" {
+		f.Pagination = PaginationCursor
+	}
+
+	if f.Pagination != PaginationCursor {
+		f.Pagination = PaginationOffset
+	}
+
+	if f.Page < 1 {
+		f.Page = 1
+	}
+
+	if f.PageSize < 1 {
+		f.PageSize = DefaultPageSize
+	}
+
+	if f.PageSize > MaxPageSize {
+		f.PageSize = MaxPageSize
+	}
+
+	searching := len(f.SearchTokens()) > 0
+	switch {
+	case searching && (len(f.Sort) == 0 || f.SortsByRelevance()):
+		f.Sort = []filter.SortField{{Field: SortByRelevance}}
+	case len(f.Sort) == 0 || f.SortsByRelevance():
+		f.Sort = slices.Clone(DefaultUserSort)
+	default:
+		if err := filter.ValidateSort(f.Sort, UserFilterSchema); err != nil {
+			return err
+		}
+	}
+
+	return filter.Validate(f.Filter, UserFilterSchema)
+} // fin Normalize
+
+// SortsByRelevance indica si el listado se ordena por relevancia de la búsqueda
+func (f UserFilters) SortsByRelevance() bool {
+	return len(f.Sort) == 1 && f.Sort[0].Field == SortByRelevance
+}
+
+// ValidateCursorMode verifica que el ordenamiento admita paginación por cursor
+// y decodifica el cursor recibido (nil para la primera página). Los filtros deben estar normalizados
+func (f UserFilters) ValidateCursorMode() (*UserCursor, error) {
+	// La relevancia depende de la búsqueda y los campos con NULL no tienen un orden total para keyset
+	if f.SortsByRelevance() {
+		return nil, fmt.Errorf("%w: paginación por cursor sobre %s", ErrUnsupportedFilter, SortByRelevance)
+	}
+	for _, field := range f.Sort {
+		if UserFilterSchema[field.Field].Nullable {
+			return nil, fmt.Errorf("%w: paginación por cursor sobre %s", ErrUnsupportedFilter, field.Field)
+		}
+	}
+
+	if f.Cursor == "" {
+		return nil, nil
+	}
+
+	return DecodeUserCursor(f.Cursor, f)
+} // fin ValidateCursorMode
+
+// Offset retorna la cantidad de registros a omitir para la página actual
+func (f UserFilters) Offset() int {
+	return (f.Page - 1) * f.PageSize
+}
+
+// NewPaginatedUsers construye el resultado paginado calculando los metadatos de navegación
+func NewPaginatedUsers(users []*entities.User, total int64, page, pageSize int) *PaginatedUsers {
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+
+	if users == nil {
+		users = []*entities.User{}
+	}
+
+	return &PaginatedUsers{
+		Users:       users,
+		Total:       total,
+		Page:        page,
+		PageSize:    pageSize,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+} // fin NewPaginatedUsers
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"strings"
+	"unicode"
+
+	"userservice/internal/domain/entities"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// SortByRelevance ordena por relevancia de la búsqueda; solo aplica cuando UserFilters.Search tiene términos
+// Es el ordenamiento por defecto al buscar y no admite paginación por cursor
+const SortByRelevance = "relevance"
+
+// MaxSearchTokens es la cantidad máxima de términos de búsqueda considerados
+const MaxSearchTokens = 8
+
+// Puntaje de relevancia de un término según dónde coincide; se toma el mayor por término
+const (
+	// SearchScoreContains el término aparece dentro del nombre, apellido o email
+	SearchScoreContains = 1
+	// SearchScoreNamePrefix una palabra del nombre o apellido empieza por el término
+	SearchScoreNamePrefix = 2
+	// SearchScoreNameWord el término es una palabra completa del nombre o apellido
+	SearchScoreNameWord = 3
+	// SearchScoreDocumentPrefix el número de documento empieza por el término
+	SearchScoreDocumentPrefix = 4
+)
+
+// FoldSearchText normaliza un texto para búsqueda: minúsculas y sin tildes ni diéresis ("Núñez" → "nunez")
+// Debe coincidir con userservice.search_fold() de migrations/005_add_user_search.sql
+func FoldSearchText(text string) string {
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folder, text)
+	if err != nil {
+		folded = text
+	}
+
+	return strings.ToLower(folded)
+}
+
+// SearchTokens retorna los términos normalizados de Search, sin repetidos
+// Todos los términos deben coincidir para que un usuario sea parte del resultado
+func (f UserFilters) SearchTokens() []string {
+	if f.Search == nil {
+		return nil
+	}
+
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, token := range strings.Fields(FoldSearchText(*f.Search)) {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		tokens = append(tokens, token)
+
+		if len(tokens) == MaxSearchTokens {
+			break
+		}
+	}
+
+	return tokens
+} // fin SearchTokens
+
+// SearchScore calcula la relevancia del usuario para los términos dados
+// Retorna 0 si algún término no coincide con nombre, apellido, email ni prefijo del documento
+func SearchScore(user *entities.User, tokens []string) int {
+	firstName := FoldSearchText(user.FirstName)
+	lastName := FoldSearchText(user.LastName)
+	words := " " + firstName + " " + lastName + " "
+	email := user.Email.Canonical().String()
+
+	total := 0
+	for _, token := range tokens {
+		switch {
+		case strings.HasPrefix(user.DocumentNumber, token):
+			total += SearchScoreDocumentPrefix
+		case strings.Contains(words, " "+token+" "):
+			total += SearchScoreNameWord
+		case strings.Contains(words, " "+token):
+			total += SearchScoreNamePrefix
+		case strings.Contains(firstName, token) || strings.Contains(lastName, token) || strings.Contains(email, token):
+			total += SearchScoreContains
+		default:
+			return 0
+		}
+	}
+
+	return total
+} // fin SearchScore
@@ -0,0 +1,247 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/filter"
+)
+
+// ErrInvalidTrendQuery indica que el rango, el intervalo, la zona o el desglose de la tendencia no son válidos
+var ErrInvalidTrendQuery = errors.New("consulta de tendencia inválida")
+
+// TrendBucket es el intervalo en que se agrupan los registros
+type TrendBucket string
+
+const (
+	TrendByDay   TrendBucket = "day"
+	TrendByWeek  TrendBucket = "week" // Semanas ISO: inician el lunes
+	TrendByMonth TrendBucket = "month"
+)
+
+// TrendBreakdown es la dimensión por la que se desglosa la tendencia
+type TrendBreakdown string
+
+const (
+	BreakdownNone     TrendBreakdown = ""
+	BreakdownRole     TrendBreakdown = "role"
+	BreakdownSede     TrendBreakdown = "sede"
+	BreakdownCentro   TrendBreakdown = "centro"   // Centro de formación de la sede del usuario
+	BreakdownRegional TrendBreakdown = "regional" // Regional del centro de la sede del usuario
+	BreakdownProgram  TrendBreakdown = "program"
+)
+
+// TrendKeyNone es la llave de la serie de usuarios sin valor en la dimensión (sin sede, sin programa)
+const TrendKeyNone = ""
+
+// MaxTrendBuckets limita los intervalos de una consulta, ya que todos se rellenan con ceros
+const MaxTrendBuckets = 1000
+
+// DefaultTrendDays es el rango por defecto cuando la consulta no indica From
+const DefaultTrendDays = 30
+
+// RegistrationTrendQuery define el rango y la agrupación de la tendencia de registros
+type RegistrationTrendQuery struct {
+	From      time.Time      `json:"from"`             // Se alinea al inicio de su intervalo; por defecto DefaultTrendDays antes de To
+	To        time.Time      `json:"to"`               // Exclusivo; por defecto ahora
+	Bucket    TrendBucket    `json:"bucket,omitempty"` // Por defecto TrendByDay
+	Location  *time.Location `json:"-"`                // Zona IANA en que se cortan los intervalos; por defecto filter.Location
+	Breakdown TrendBreakdown `json:"breakdown,omitempty"`
+	OrgFilter                // Solo cuenta los usuarios de la sede, el centro o la regional indicados
+}
+
+// LastDays construye la consulta diaria de los últimos days días, incluido el actual, en la zona dada
+func LastDays(days int, loc *time.Location) RegistrationTrendQuery {
+	if loc == nil {
+		loc = filter.Location
+	}
+	days = max(days, 1)
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	return RegistrationTrendQuery{
+		From:     today.AddDate(0, 0, 1-days),
+		To:       today.AddDate(0, 0, 1),
+		Bucket:   TrendByDay,
+		Location: loc,
+	}
+}
+
+// Normalize aplica los valores por defecto, alinea From y valida la consulta
+func (q *RegistrationTrendQuery) Normalize() error {
+	if q.Location == nil {
+		q.Location = filter.Location
+	}
+	// La zona debe tener nombre IANA para que las bases de datos la reconozcan
+	if q.Location == time.Local {
+		return fmt.Errorf("%w: la zona debe ser una zona IANA, no Local", ErrInvalidTrendQuery)
+	}
+
+	if q.Bucket == "" {
+		q.Bucket = TrendByDay
+	}
+	if !slices.Contains([]TrendBucket{TrendByDay, TrendByWeek, TrendByMonth}, q.Bucket) {
+		return fmt.Errorf("%w: intervalo %q", ErrInvalidTrendQuery, q.Bucket)
+	}
+
+	if !slices.Contains([]TrendBreakdown{BreakdownNone, BreakdownRole, BreakdownSede, BreakdownCentro, BreakdownRegional, BreakdownProgram}, q.Breakdown) {
+		return fmt.Errorf("%w: desglose %q", ErrInvalidTrendQuery, q.Breakdown)
+	}
+
+	if q.To.IsZero() {
+		q.To = time.Now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.AddDate(0, 0, -DefaultTrendDays)
+	}
+	q.To = q.To.In(q.Location)
+	q.From = q.BucketStart(q.From)
+
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("%w: From debe ser anterior a To", ErrInvalidTrendQuery)
+	}
+
+	count := 0
+	for start := q.From; start.Before(q.To); start = q.nextBucket(start) {
+		if count++; count > MaxTrendBuckets {
+			return fmt.Errorf("%w: el rango supera %d intervalos", ErrInvalidTrendQuery, MaxTrendBuckets)
+		}
+	}
+
+	return nil
+} // fin Normalize
+
+// BucketStart retorna el inicio del intervalo que contiene a t, en la zona de la consulta
+func (q RegistrationTrendQuery) BucketStart(t time.Time) time.Time {
+	t = t.In(q.Location)
+	switch q.Bucket {
+	case TrendByMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, q.Location)
+	case TrendByWeek:
+		sinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-sinceMonday, 0, 0, 0, 0, q.Location)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, q.Location)
+	}
+}
+
+// nextBucket retorna el inicio del intervalo siguiente; time.Date normaliza los cambios de horario
+func (q RegistrationTrendQuery) nextBucket(start time.Time) time.Time {
+	switch q.Bucket {
+	case TrendByMonth:
+		return time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, q.Location)
+	case TrendByWeek:
+		return time.Date(start.Year(), start.Month(), start.Day()+7, 0, 0, 0, 0, q.Location)
+	default:
+		return time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, q.Location)
+	}
+}
+
+// TrendPoint es el conteo de un intervalo
+type TrendPoint struct {
+	Start time.Time `json:"start"` // Inicio del intervalo en la zona de la consulta
+	Count int       `json:"count"`
+}
+
+// TrendSeries es la serie de un valor de la dimensión, con un punto por intervalo
+type TrendSeries struct {
+	Key    string       `json:"key"`
+	Total  int          `json:"total"`
+	Points []TrendPoint `json:"points"`
+}
+
+// RegistrationTrend es la tendencia de registros con todos los intervalos del rango, en cero si no hubo registros
+type RegistrationTrend struct {
+	Bucket    TrendBucket    `json:"bucket"`
+	TimeZone  string         `json:"time_zone"`
+	From      time.Time      `json:"from"`
+	To        time.Time      `json:"to"`
+	Breakdown TrendBreakdown `json:"breakdown,omitempty"`
+	Total     TrendSeries    `json:"total"`
+	Series    []TrendSeries  `json:"series,omitempty"` // Una por valor de la dimensión, ordenadas por Key
+
+	query   RegistrationTrendQuery
+	buckets map[int64]int // Inicio del intervalo (Unix) → posición
+}
+
+// NewRegistrationTrend crea la tendencia vacía de una consulta ya normalizada
+func NewRegistrationTrend(q RegistrationTrendQuery) *RegistrationTrend {
+	trend := &RegistrationTrend{
+		Bucket:    q.Bucket,
+		TimeZone:  q.Location.String(),
+		From:      q.From,
+		To:        q.To,
+		Breakdown: q.Breakdown,
+		query:     q,
+		buckets:   make(map[int64]int),
+	}
+
+	for start := q.From; start.Before(q.To); start = q.nextBucket(start) {
+		trend.buckets[start.Unix()] = len(trend.Total.Points)
+		trend.Total.Points = append(trend.Total.Points, TrendPoint{Start: start})
+	}
+
+	return trend
+}
+
+// Add suma count registros ocurridos en at a la serie key; los instantes fuera del rango se ignoran
+// Sin desglose key se ignora
+func (t *RegistrationTrend) Add(at time.Time, key string, count int) {
+	if at.Before(t.From) || !at.Before(t.To) {
+		return
+	}
+
+	index, ok := t.buckets[t.query.BucketStart(at).Unix()]
+	if !ok {
+		return
+	}
+
+	t.Total.Total += count
+	t.Total.Points[index].Count += count
+	if t.Breakdown == BreakdownNone {
+		return
+	}
+
+	position, found := slices.BinarySearchFunc(t.Series, key, func(series TrendSeries, key string) int {
+		return strings.Compare(series.Key, key)
+	})
+	if !found {
+		points := make([]TrendPoint, len(t.Total.Points))
+		for i, point := range t.Total.Points {
+			points[i] = TrendPoint{Start: point.Start}
+		}
+		t.Series = slices.Insert(t.Series, position, TrendSeries{Key: key, Points: points})
+	}
+
+	t.Series[position].Total += count
+	t.Series[position].Points[index].Count += count
+} // fin Add
+
+// SeriesByKey retorna la serie de un valor de la dimensión, nil si no tuvo registros
+func (t *RegistrationTrend) SeriesByKey(key string) *TrendSeries {
+	for i := range t.Series {
+		if t.Series[i].Key == key {
+			return &t.Series[i]
+		}
+	}
+	return nil
+}
+
+// UserTrendKey retorna el valor del usuario en la dimensión del desglose
+// BreakdownProgram, BreakdownCentro y BreakdownRegional dependen de la ficha o la sede del usuario:
+// cada implementación los resuelve con el programa de la ficha o con SedePath.Key
+func UserTrendKey(user *entities.User, breakdown TrendBreakdown) string {
+	switch breakdown {
+	case BreakdownRole:
+		return string(user.Role)
+	case BreakdownSede:
+		if user.SedeID != nil {
+			return user.SedeID.String()
+		}
+	}
+	return TrendKeyNone
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// Config agrupa la configuración del servicio cargada desde el entorno
+type Config struct {
+	Database  DatabaseConfig
+	Cache     CacheConfig
+	Dashboard DashboardConfig
+}
+
+// DatabaseConfig contiene los parámetros de conexión a la base de datos
+type DatabaseConfig struct {
+	Driver          string // postgres o sqlite
+	Path            string // archivo de la base de datos SQLite
+	Host            string
+	Port            string
+	User            string
+	Password        string
+	DBName          string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime int // minutos
+}
+
+// CacheConfig selecciona el respaldo de la caché de usuarios
+type CacheConfig struct {
+	Backend       string // none, lru o redis
+	LRUSize       int
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+	TTL           int // segundos
+}
+
+// DashboardConfig controla el refresco periódico de las fotos del dashboard directivo
+type DashboardConfig struct {
+	RefreshInterval int // minutos; 0 desactiva el refresco
+	Retention       int // días que se conservan las fotos anteriores
+}
+
+// Load carga la configuración desde variables de entorno con valores por defecto
+func Load() *Config {
+	return &Config{
+		Database: DatabaseConfig{
+			Driver:          getEnv("DB_DRIVER", "postgres"),
+			Path:            getEnv("DB_PATH", "sicora.db"),
+			Host:            getEnv("DB_HOST", "localhost"),
+			Port:            getEnv("DB_PORT", "5432"),
+			User:            getEnv("DB_USER", "postgres"),
+			Password:        getEnv("DB_PASSWORD", "postgres"),
+			DBName:          getEnv("DB_NAME", "sicora"),
+			SSLMode:         getEnv("DB_SSLMODE", "disable"),
+			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime: getEnvAsInt("DB_CONN_MAX_LIFETIME", 30),
+		},
+		Cache: CacheConfig{
+			Backend:       getEnv("CACHE_BACKEND", "lru"),
+			LRUSize:       getEnvAsInt("CACHE_LRU_SIZE", 10000),
+			RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
+			RedisPassword: getEnv("REDIS_PASSWORD", ""),
+			RedisDB:       getEnvAsInt("REDIS_DB", 0),
+			TTL:           getEnvAsInt("CACHE_TTL", 300),
+		},
+		Dashboard: DashboardConfig{
+			RefreshInterval: getEnvAsInt("DASHBOARD_REFRESH_INTERVAL", 15),
+			Retention:       getEnvAsInt("DASHBOARD_SNAPSHOT_RETENTION", 30),
+		},
+	}
+} // fin Load
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
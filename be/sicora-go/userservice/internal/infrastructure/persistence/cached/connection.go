@@ -0,0 +1,38 @@
+package cached
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"userservice/internal/infrastructure/config"
+
+	"sicora-go/pkg/cache"
+)
+
+// NewCache crea el respaldo configurado; retorna nil si la caché está deshabilitada
+func NewCache(ctx context.Context, cfg config.CacheConfig) (cache.Cache, error) {
+	switch cfg.Backend {
+	case "", "none":
+		return nil, nil
+	case "lru":
+		return cache.NewLRU(cfg.LRUSize), nil
+	case "redis":
+		c, err := cache.NewRedisFromConfig(ctx, cache.RedisConfig{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		}, "userservice:")
+		if err != nil {
+			return nil, fmt.Errorf("error conectando a redis: %w", err)
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("respaldo de caché desconocido: %q", cfg.Backend)
+	}
+}
+
+// OptionsFromConfig traduce la configuración a las opciones del decorador
+func OptionsFromConfig(cfg config.CacheConfig) Options {
+	return Options{TTL: time.Duration(cfg.TTL) * time.Second}
+}
@@ -0,0 +1,542 @@
+// Package cached implementa un decorador de lectura con caché para repositories.UserRepository
+package cached
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"iter"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"sicora-go/pkg/cache"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.UserRepository = (*UserRepository)(nil)
+
+// DefaultTTL es la vigencia por defecto de un usuario en caché
+const DefaultTTL = 5 * time.Minute
+
+// Options configura el decorador
+type Options struct {
+	TTL time.Duration // Vigencia de las entradas; DefaultTTL si es cero
+
+	// OnError recibe las fallas de la caché, que nunca se propagan al llamador:
+	// una lectura fallida consulta el repositorio y una invalidación fallida expira con el TTL
+	OnError func(error)
+}
+
+// UserRepository cachea las consultas por ID, email y documento del repositorio envuelto
+//
+// Solo la llave por ID guarda al usuario; las llaves por email y documento guardan el ID y
+// se verifican contra el usuario leído, así un cambio de email o documento nunca deja una
+// entrada vieja que responda por el valor anterior. Toda escritura invalida la llave por ID
+// de los usuarios afectados antes y después de escribir, para descartar también lo que una
+// lectura concurrente haya cacheado mientras tanto. Los usuarios inexistentes no se cachean.
+// Con alcance de sede (repositories.WithTenant) la caché solo responde por usuarios de esa sede
+//
+// La caché nunca guarda el hash de la contraseña: las lecturas por ID, email y documento retornan
+// los usuarios sin Password, salvo con repositories.WithCredentials, que consulta siempre el repositorio
+type UserRepository struct {
+	inner   repositories.UserRepository
+	cache   cache.Cache
+	ttl     time.Duration
+	onError func(error)
+}
+
+// NewUserRepository envuelve el repositorio con la caché dada
+func NewUserRepository(inner repositories.UserRepository, c cache.Cache, opts Options) *UserRepository {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+
+	return &UserRepository{
+		inner:   inner,
+		cache:   c,
+		ttl:     opts.TTL,
+		onError: opts.OnError,
+	}
+}
+
+// Create crea el usuario e invalida su entrada y sus llaves por email y documento,
+// que pueden apuntar a un usuario eliminado con los mismos datos
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	return r.evict(ctx, userKeys(user), func() error {
+		return r.inner.Create(ctx, user)
+	})
+}
+
+// GetByID obtiene un usuario por su ID desde la caché o el repositorio
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	return r.read(ctx, func() *entities.User {
+		return r.cachedByID(ctx, id)
+	}, func() (*entities.User, error) {
+		return r.inner.GetByID(ctx, id)
+	})
+}
+
+// GetByEmail obtiene un usuario por su email desde la caché o el repositorio
+func (r *UserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return r.read(ctx, func() *entities.User {
+		return r.cachedByIndex(ctx, emailKey(email), func(user *entities.User) bool {
+			return user.Email == email.Canonical()
+		})
+	}, func() (*entities.User, error) {
+		return r.inner.GetByEmail(ctx, email)
+	})
+}
+
+// GetByDocument obtiene un usuario por su documento desde la caché o el repositorio
+// El número se compara normalizado, como se guarda
+func (r *UserRepository) GetByDocument(ctx context.Context, documentType entities.DocumentType, documentNumber string) (*entities.User, error) {
+	number := entities.NormalizeDocumentNumber(documentNumber)
+	return r.read(ctx, func() *entities.User {
+		return r.cachedByIndex(ctx, documentKey(documentType, documentNumber), func(user *entities.User) bool {
+			return user.DocumentType == documentType && user.DocumentNumber == number
+		})
+	}, func() (*entities.User, error) {
+		return r.inner.GetByDocument(ctx, documentType, documentNumber)
+	})
+}
+
+// Update actualiza el usuario e invalida su entrada, también si hubo conflicto de versión
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	return r.evict(ctx, idKeys(user.ID), func() error {
+		return r.inner.Update(ctx, user)
+	})
+}
+
+// Delete elimina el usuario e invalida su entrada
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error {
+	return r.evict(ctx, idKeys(id), func() error {
+		return r.inner.Delete(ctx, id, deletedBy)
+	})
+}
+
+// Restore restaura el usuario e invalida su entrada
+func (r *UserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return r.evict(ctx, idKeys(id), func() error {
+		return r.inner.Restore(ctx, id)
+	})
+}
+
+// Purge elimina definitivamente los usuarios e invalida las entradas de los purgados
+// Las lecturas no retornan usuarios eliminados, pero una lectura concurrente con Delete pudo cachear alguno
+func (r *UserRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.evict(ctx, idKeys(r.resolveDeleted(ctx, deletedBefore)...), func() error {
+		var err error
+		purged, err = r.inner.Purge(ctx, deletedBefore)
+		return err
+	})
+	return purged, err
+}
+
+// List delega en el repositorio envuelto: las páginas no se cachean
+func (r *UserRepository) List(ctx context.Context, filters repositories.UserFilters) (*repositories.PaginatedUsers, error) {
+	return r.inner.List(ctx, filters)
+}
+
+// Stream delega en el repositorio envuelto
+func (r *UserRepository) Stream(ctx context.Context, filters repositories.UserFilters) iter.Seq2[*entities.User, error] {
+	return r.inner.Stream(ctx, filters)
+}
+
+// GetByFicha delega en el repositorio envuelto
+func (r *UserRepository) GetByFicha(ctx context.Context, fichaID string, on time.Time) ([]*entities.User, error) {
+	return r.inner.GetByFicha(ctx, fichaID, on)
+}
+
+// AssignFicha asigna la ficha e invalida la entrada del usuario
+func (r *UserRepository) AssignFicha(ctx context.Context, userID uuid.UUID, fichaID string) error {
+	return r.evict(ctx, idKeys(userID), func() error {
+		return r.inner.AssignFicha(ctx, userID, fichaID)
+	})
+}
+
+// TransferFicha traslada al aprendiz e invalida la entrada del usuario
+func (r *UserRepository) TransferFicha(ctx context.Context, transfer *entities.FichaMovement) error {
+	return r.evict(ctx, idKeys(transfer.UserID), func() error {
+		return r.inner.TransferFicha(ctx, transfer)
+	})
+}
+
+// GetFichaHistory delega en el repositorio envuelto
+func (r *UserRepository) GetFichaHistory(ctx context.Context, userID uuid.UUID) ([]*entities.FichaMovement, error) {
+	return r.inner.GetFichaHistory(ctx, userID)
+}
+
+// ExistsByEmail delega en el repositorio envuelto
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email entities.Email) (bool, error) {
+	return r.inner.ExistsByEmail(ctx, email)
+}
+
+// ExistsByDocument delega en el repositorio envuelto
+func (r *UserRepository) ExistsByDocument(ctx context.Context, documentType entities.DocumentType, documentNumber string) (bool, error) {
+	return r.inner.ExistsByDocument(ctx, documentType, documentNumber)
+}
+
+// BulkCreate crea los usuarios e invalida sus entradas y sus llaves por email y documento
+func (r *UserRepository) BulkCreate(ctx context.Context, users []*entities.User, mode repositories.BulkMode) (*repositories.BulkOperationResult, error) {
+	keys := make([]string, 0, len(users)*3)
+	for _, user := range users {
+		if user != nil {
+			keys = append(keys, userKeys(user)...)
+		}
+	}
+
+	var result *repositories.BulkOperationResult
+	err := r.evict(ctx, keys, func() error {
+		var err error
+		result, err = r.inner.BulkCreate(ctx, users, mode)
+		return err
+	})
+	return result, err
+}
+
+// BulkUpdate actualiza los usuarios e invalida las entradas de los afectados
+func (r *UserRepository) BulkUpdate(ctx context.Context, updates map[entities.Email]*entities.User) (*repositories.BulkOperationResult, error) {
+	emails := make([]entities.Email, 0, len(updates))
+	for email := range updates {
+		emails = append(emails, email)
+	}
+
+	var result *repositories.BulkOperationResult
+	err := r.evict(ctx, idKeys(r.resolveEmails(ctx, emails)...), func() error {
+		var err error
+		result, err = r.inner.BulkUpdate(ctx, updates)
+		return err
+	})
+	return result, err
+}
+
+// BulkDelete elimina los usuarios e invalida las entradas de los afectados
+func (r *UserRepository) BulkDelete(ctx context.Context, emails []entities.Email, deletedBy *uuid.UUID) (*repositories.BulkOperationResult, error) {
+	// Los IDs se resuelven antes de modificar: después los usuarios eliminados ya no se encuentran
+	var result *repositories.BulkOperationResult
+	err := r.evict(ctx, idKeys(r.resolveEmails(ctx, emails)...), func() error {
+		var err error
+		result, err = r.inner.BulkDelete(ctx, emails, deletedBy)
+		return err
+	})
+	return result, err
+}
+
+// BulkStatusChange cambia el estado de los usuarios e invalida las entradas de los afectados
+func (r *UserRepository) BulkStatusChange(ctx context.Context, emails []entities.Email, isActive bool) (*repositories.BulkOperationResult, error) {
+	var result *repositories.BulkOperationResult
+	err := r.evict(ctx, idKeys(r.resolveEmails(ctx, emails)...), func() error {
+		var err error
+		result, err = r.inner.BulkStatusChange(ctx, emails, isActive)
+		return err
+	})
+	return result, err
+}
+
+// GetMultipleByEmails resuelve desde la caché los emails que pueda y consulta el resto en una sola llamada
+// Con repositories.WithCredentials consulta todos los emails en el repositorio
+func (r *UserRepository) GetMultipleByEmails(ctx context.Context, emails []entities.Email) ([]*entities.User, error) {
+	credentials := repositories.NeedsCredentials(ctx)
+	users := []*entities.User{}
+	seen := make(map[uuid.UUID]bool, len(emails))
+	var missing []entities.Email
+
+	for _, email := range emails {
+		var user *entities.User
+		if !credentials {
+			user = r.cachedByIndex(ctx, emailKey(email), func(user *entities.User) bool {
+				return user.Email == email.Canonical()
+			})
+		}
+		if user == nil {
+			missing = append(missing, email)
+			continue
+		}
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			users = append(users, user)
+		}
+	}
+
+	if len(missing) == 0 {
+		return users, nil
+	}
+
+	fetched, err := r.inner.GetMultipleByEmails(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range fetched {
+		r.store(ctx, user)
+		if !credentials {
+			user = withoutCredentials(user)
+		}
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+} // fin GetMultipleByEmails
+
+// RecordLogin registra el inicio de sesión e invalida la entrada del usuario, cuyo LastLogin cambia
+func (r *UserRepository) RecordLogin(ctx context.Context, event *entities.LoginEvent) error {
+	return r.evict(ctx, idKeys(event.UserID), func() error {
+		return r.inner.RecordLogin(ctx, event)
+	})
+}
+
+// GetLoginHistory delega en el repositorio envuelto
+func (r *UserRepository) GetLoginHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.LoginEvent, error) {
+	return r.inner.GetLoginHistory(ctx, userID, limit)
+}
+
+// RecordTenantBypass delega en el repositorio envuelto: la bitácora no cambia a ningún usuario
+func (r *UserRepository) RecordTenantBypass(ctx context.Context, bypass *entities.TenantBypass) error {
+	return r.inner.RecordTenantBypass(ctx, bypass)
+}
+
+// ListTenantBypasses delega en el repositorio envuelto
+func (r *UserRepository) ListTenantBypasses(ctx context.Context, since time.Time) ([]*entities.TenantBypass, error) {
+	return r.inner.ListTenantBypasses(ctx, since)
+}
+
+// GetTotalUsersByRole delega en el repositorio envuelto: los conteos no se cachean
+func (r *UserRepository) GetTotalUsersByRole(ctx context.Context) (map[string]int, error) {
+	return r.inner.GetTotalUsersByRole(ctx)
+}
+
+// GetTotalUsersByProgram delega en el repositorio envuelto
+func (r *UserRepository) GetTotalUsersByProgram(ctx context.Context) (map[string]int, error) {
+	return r.inner.GetTotalUsersByProgram(ctx)
+}
+
+// GetTotalUsersByOrgLevel delega en el repositorio envuelto
+func (r *UserRepository) GetTotalUsersByOrgLevel(ctx context.Context, level repositories.OrgLevel, scope repositories.OrgFilter) (map[string]int, error) {
+	return r.inner.GetTotalUsersByOrgLevel(ctx, level, scope)
+}
+
+// GetUserRegistrationTrend delega en el repositorio envuelto
+func (r *UserRepository) GetUserRegistrationTrend(ctx context.Context, query repositories.RegistrationTrendQuery) (*repositories.RegistrationTrend, error) {
+	return r.inner.GetUserRegistrationTrend(ctx, query)
+}
+
+// GetActiveInactiveCount delega en el repositorio envuelto
+func (r *UserRepository) GetActiveInactiveCount(ctx context.Context) (active int, inactive int, err error) {
+	return r.inner.GetActiveInactiveCount(ctx)
+}
+
+// AggregateDashboard delega en el repositorio envuelto
+func (r *UserRepository) AggregateDashboard(ctx context.Context) (*entities.DashboardSnapshot, error) {
+	return r.inner.AggregateDashboard(ctx)
+}
+
+// GetActivityMetrics delega en el repositorio envuelto
+func (r *UserRepository) GetActivityMetrics(ctx context.Context, query repositories.ActivityQuery) (*repositories.ActivityReport, error) {
+	return r.inner.GetActivityMetrics(ctx, query)
+}
+
+// evict invalida las llaves antes y después de la escritura, aunque la escritura falle
+// La primera invalidación evita responder con la entrada vieja mientras se escribe; la segunda
+// descarta la que una lectura concurrente haya guardado con la fila anterior a la escritura
+func (r *UserRepository) evict(ctx context.Context, keys []string, write func() error) error {
+	r.delete(ctx, keys...)
+	defer r.delete(ctx, keys...)
+	return write()
+}
+
+// read obtiene el usuario con cached o, si no está en la caché, con load, y lo guarda en la caché
+// Con repositories.WithCredentials consulta siempre load y retorna el usuario con su contraseña
+func (r *UserRepository) read(ctx context.Context, cached func() *entities.User, load func() (*entities.User, error)) (*entities.User, error) {
+	credentials := repositories.NeedsCredentials(ctx)
+	if !credentials {
+		if user := cached(); user != nil {
+			return user, nil
+		}
+	}
+
+	user, err := load()
+	if err != nil || user == nil {
+		return user, err
+	}
+
+	r.store(ctx, user)
+	if !credentials {
+		user = withoutCredentials(user)
+	}
+	return user, nil
+} // fin read
+
+// cachedByIndex sigue una llave secundaria hasta la entrada por ID y verifica que siga correspondiendo
+func (r *UserRepository) cachedByIndex(ctx context.Context, key string, matches func(*entities.User) bool) *entities.User {
+	raw, err := r.cache.Get(ctx, key)
+	if err != nil {
+		r.report(err)
+		return nil
+	}
+
+	id, err := uuid.FromBytes(raw)
+	if err != nil {
+		r.delete(ctx, key)
+		return nil
+	}
+
+	user := r.cachedByID(ctx, id)
+	if user == nil || !matches(user) {
+		return nil
+	}
+
+	return user
+}
+
+// cachedByID retorna el usuario cacheado o nil, también si está fuera del alcance de sede del contexto
+func (r *UserRepository) cachedByID(ctx context.Context, id uuid.UUID) *entities.User {
+	raw, err := r.cache.Get(ctx, idKey(id))
+	if err != nil {
+		r.report(err)
+		return nil
+	}
+
+	var user entities.User
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&user); err != nil {
+		r.delete(ctx, idKey(id))
+		return nil
+	}
+
+	if !inTenant(ctx, &user) {
+		return nil
+	}
+
+	return &user
+}
+
+// inTenant indica si la caché puede responder por el usuario en el alcance de sede del contexto
+// Los alcances por centro o regional los resuelve el repositorio envuelto, que conoce la jerarquía,
+// igual que un contexto sin alcance, que el repositorio envuelto rechaza con ErrNoTenant
+func inTenant(ctx context.Context, user *entities.User) bool {
+	scope, scoped, err := repositories.TenantScope(ctx)
+	if err != nil {
+		return false
+	}
+	if !scoped {
+		return true
+	}
+	return scope.RegionalID == nil && scope.CentroID == nil && scope.SedeID != nil &&
+		user.SedeID != nil && *user.SedeID == *scope.SedeID
+}
+
+// store guarda al usuario sin su contraseña por ID y las llaves por email y documento que apuntan a él
+func (r *UserRepository) store(ctx context.Context, user *entities.User) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(withoutCredentials(user)); err != nil {
+		r.report(err)
+		return
+	}
+
+	id := user.ID[:]
+	r.report(r.cache.Set(ctx, idKey(user.ID), buf.Bytes(), r.ttl))
+	r.report(r.cache.Set(ctx, emailKey(user.Email), id, r.ttl))
+	r.report(r.cache.Set(ctx, documentKey(user.DocumentType, user.DocumentNumber), id, r.ttl))
+}
+
+// withoutCredentials retorna una copia del usuario sin el hash de la contraseña
+func withoutCredentials(user *entities.User) *entities.User {
+	clone := *user
+	clone.Password = ""
+	return &clone
+}
+
+// resolveDeleted obtiene los IDs de los usuarios eliminados antes de la fecha dada, que Purge eliminará
+func (r *UserRepository) resolveDeleted(ctx context.Context, deletedBefore time.Time) []uuid.UUID {
+	var ids []uuid.UUID
+	for user, err := range r.inner.Stream(ctx, repositories.UserFilters{Deleted: repositories.DeletedOnly}) {
+		if err != nil {
+			r.report(err)
+			break
+		}
+		if user.DeletedAt != nil && user.DeletedAt.Before(deletedBefore) {
+			ids = append(ids, user.ID)
+		}
+	}
+	return ids
+}
+
+// resolveEmails obtiene los IDs de los usuarios con los emails dados antes de modificarlos
+// Un usuario cacheado siempre tiene su llave por email, salvo que la caché la haya descartado;
+// los emails sin llave se consultan en el repositorio para no dejar una entrada por ID vigente
+func (r *UserRepository) resolveEmails(ctx context.Context, emails []entities.Email) []uuid.UUID {
+	var ids []uuid.UUID
+	var unresolved []entities.Email
+	for _, email := range emails {
+		raw, err := r.cache.Get(ctx, emailKey(email))
+		if err == nil {
+			if id, err := uuid.FromBytes(raw); err == nil {
+				ids = append(ids, id)
+				continue
+			}
+		}
+		r.report(err)
+		unresolved = append(unresolved, email)
+	}
+
+	if len(unresolved) > 0 {
+		users, err := r.inner.GetMultipleByEmails(ctx, unresolved)
+		r.report(err)
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+	}
+
+	return ids
+} // fin resolveEmails
+
+func (r *UserRepository) delete(ctx context.Context, keys ...string) {
+	if len(keys) > 0 {
+		r.report(r.cache.Delete(ctx, keys...))
+	}
+}
+
+// report notifica las fallas de la caché; un ErrMiss no es una falla
+func (r *UserRepository) report(err error) {
+	if err != nil && !errors.Is(err, cache.ErrMiss) {
+		r.onError(err)
+	}
+}
+
+func idKey(id uuid.UUID) string {
+	return "user:id:" + id.String()
+}
+
+// idKeys retorna las llaves por ID de los usuarios dados
+func idKeys(ids ...uuid.UUID) []string {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = idKey(id)
+	}
+	return keys
+}
+
+// userKeys retorna la llave por ID y las llaves por email y documento del usuario
+func userKeys(user *entities.User) []string {
+	return []string{idKey(user.ID), emailKey(user.Email), documentKey(user.DocumentType, user.DocumentNumber)}
+}
+
+// emailKey usa la forma canónica para que todas las variantes del email compartan la llave
+func emailKey(email entities.Email) string {
+	return "user:email:" + email.Canonical().String()
+}
+
+// documentKey normaliza el número para que todas sus variantes compartan la llave, como emailKey con el email
+func documentKey(documentType entities.DocumentType, documentNumber string) string {
+	return "user:document:" + string(documentType) + ":" + entities.NormalizeDocumentNumber(documentNumber)
+}
@@ -0,0 +1,356 @@
+package cached
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+	"userservice/internal/domain/repositories/repositorytest"
+	"userservice/internal/infrastructure/persistence/memory"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"sicora-go/pkg/cache"
+)
+
+func newRedisCache(t *testing.T) cache.Cache {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return cache.NewRedis(client, "userservice:")
+}
+
+func TestUserRepositoryContractLRU(t *testing.T) {
+	repositorytest.RunUserRepositoryContract(t, func(t *testing.T) repositories.UserRepository {
+		return NewUserRepository(memory.NewUserRepository(), cache.NewLRU(100), Options{OnError: failOnError(t)})
+	})
+}
+
+func TestUserRepositoryContractRedis(t *testing.T) {
+	repositorytest.RunUserRepositoryContract(t, func(t *testing.T) repositories.UserRepository {
+		return NewUserRepository(memory.NewUserRepository(), newRedisCache(t), Options{OnError: failOnError(t)})
+	})
+}
+
+func failOnError(t *testing.T) func(error) {
+	return func(err error) {
+		t.Errorf("falla de caché: %v", err)
+	}
+}
+
+// countingRepository cuenta las lecturas que llegan al repositorio envuelto
+type countingRepository struct {
+	repositories.UserRepository
+	reads int
+}
+
+func (r *countingRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	r.reads++
+	return r.UserRepository.GetByID(ctx, id)
+}
+
+func (r *countingRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	r.reads++
+	return r.UserRepository.GetByEmail(ctx, email)
+}
+
+func (r *countingRepository) GetByDocument(ctx context.Context, documentType entities.DocumentType, documentNumber string) (*entities.User, error) {
+	r.reads++
+	return r.UserRepository.GetByDocument(ctx, documentType, documentNumber)
+}
+
+func (r *countingRepository) GetMultipleByEmails(ctx context.Context, emails []entities.Email) ([]*entities.User, error) {
+	r.reads++
+	return r.UserRepository.GetMultipleByEmails(ctx, emails)
+}
+
+func TestReadThrough(t *testing.T) {
+	for name, newCache := range map[string]func(*testing.T) cache.Cache{
+		"LRU":   func(*testing.T) cache.Cache { return cache.NewLRU(100) },
+		"Redis": newRedisCache,
+	} {
+		t.Run(name, func(t *testing.T) { testReadThrough(t, newCache(t)) })
+	}
+}
+
+func testReadThrough(t *testing.T, c cache.Cache) {
+	ctx := repositorytest.InternalContext()
+	inner := &countingRepository{UserRepository: memory.NewUserRepository()}
+	repo := NewUserRepository(inner, c, Options{OnError: failOnError(t)})
+
+	ana := repositorytest.NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	luis := repositorytest.NewTestUser(t, 2, "Luis", "Pérez", entities.RoleAprendiz)
+	for _, user := range []*entities.User{ana, luis} {
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	expectReads := func(name string, want int) {
+		t.Helper()
+		if inner.reads != want {
+			t.Errorf("%s: %d lecturas al repositorio, se esperaban %d", name, inner.reads, want)
+		}
+	}
+
+	// La primera lectura llena la caché; las siguientes por cualquier llave, o el email en otra forma, no consultan el repositorio
+	if user, err := repo.GetByID(ctx, ana.ID); err != nil || user.Email != ana.Email {
+		t.Fatalf("GetByID = %v, %v", user, err)
+	}
+	repo.GetByID(ctx, ana.ID)
+	repo.GetByEmail(ctx, ana.Email)
+	repo.GetByEmail(ctx, entities.Email(strings.ToUpper(ana.Email.String())))
+	repo.GetByDocument(ctx, ana.DocumentType, ana.DocumentNumber)
+	expectReads("lecturas de ana", 1)
+
+	// GetMultipleByEmails solo consulta los que faltan
+	users, err := repo.GetMultipleByEmails(ctx, []entities.Email{ana.Email, luis.Email, "nadie@sena.edu.co"})
+	if err != nil || len(users) != 2 {
+		t.Fatalf("GetMultipleByEmails = %v, %v", users, err)
+	}
+	repo.GetByEmail(ctx, luis.Email)
+	expectReads("GetMultipleByEmails", 2)
+
+	// Los inexistentes no se cachean
+	repo.GetByEmail(ctx, "nadie@sena.edu.co")
+	repo.GetByEmail(ctx, "nadie@sena.edu.co")
+	expectReads("inexistentes", 4)
+
+	// Update invalida; el email anterior deja de responder aunque su llave siga en caché
+	cached, _ := repo.GetByID(ctx, ana.ID)
+	oldEmail := cached.Email
+	cached.Email = "ana.gomez@sena.edu.co"
+	if err := repo.Update(ctx, cached); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if user, _ := repo.GetByEmail(ctx, oldEmail); user != nil {
+		t.Errorf("GetByEmail(email anterior) = %s, se esperaba nil", user.Email)
+	}
+	if user, _ := repo.GetByID(ctx, ana.ID); user == nil || user.Email != cached.Email || user.Version != cached.Version {
+		t.Errorf("GetByID tras Update = %+v", user)
+	}
+
+	// Delete invalida
+	if err := repo.Delete(ctx, ana.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if user, _ := repo.GetByID(ctx, ana.ID); user != nil {
+		t.Errorf("GetByID tras Delete = %s, se esperaba nil", user.Email)
+	}
+
+	// Las operaciones masivas invalidan a los afectados
+	repo.GetByEmail(ctx, luis.Email)
+	if _, err := repo.BulkStatusChange(ctx, []entities.Email{luis.Email}, false); err != nil {
+		t.Fatalf("BulkStatusChange: %v", err)
+	}
+	if user, _ := repo.GetByEmail(ctx, luis.Email); user == nil || user.IsActive {
+		t.Errorf("GetByEmail tras BulkStatusChange = %+v", user)
+	}
+
+	if _, err := repo.BulkDelete(ctx, []entities.Email{luis.Email}, nil); err != nil {
+		t.Fatalf("BulkDelete: %v", err)
+	}
+	if user, _ := repo.GetByID(ctx, luis.ID); user != nil {
+		t.Errorf("GetByID tras BulkDelete = %s, se esperaba nil", user.Email)
+	}
+} // fin testReadThrough
+
+func TestCredentialsNotCached(t *testing.T) {
+	for name, newCache := range map[string]func(*testing.T) cache.Cache{
+		"LRU":   func(*testing.T) cache.Cache { return cache.NewLRU(100) },
+		"Redis": newRedisCache,
+	} {
+		t.Run(name, func(t *testing.T) { testCredentialsNotCached(t, newCache(t)) })
+	}
+}
+
+// testCredentialsNotCached verifica que el hash de la contraseña no llegue a la caché ni se lea de ella
+func testCredentialsNotCached(t *testing.T, c cache.Cache) {
+	ctx := repositorytest.InternalContext()
+	inner := &countingRepository{UserRepository: memory.NewUserRepository()}
+	repo := NewUserRepository(inner, c, Options{OnError: failOnError(t)})
+
+	const hash = "$2a$10$hash-de-prueba"
+	ana := repositorytest.NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	ana.Password = hash
+	if err := repo.Create(ctx, ana); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Sin credenciales ni la lectura que llena la caché ni la que responde desde ella traen la contraseña
+	for i := range 2 {
+		if user, err := repo.GetByEmail(ctx, ana.Email); err != nil || user == nil || user.Password != "" {
+			t.Errorf("GetByEmail %d sin credenciales = %+v, %v; se esperaba sin contraseña", i+1, user, err)
+		}
+	}
+	raw, err := c.Get(ctx, idKey(ana.ID))
+	if err != nil || len(raw) == 0 {
+		t.Fatalf("el usuario no quedó en la caché: %v", err)
+	}
+	if bytes.Contains(raw, []byte(hash)) {
+		t.Error("la caché guarda el hash de la contraseña")
+	}
+
+	// Con credenciales se consulta el repositorio aunque el usuario esté en la caché
+	credentials := repositories.WithCredentials(ctx)
+	reads := inner.reads
+	if user, err := repo.GetByID(credentials, ana.ID); err != nil || user == nil || user.Password != hash {
+		t.Errorf("GetByID con credenciales = %+v, %v", user, err)
+	}
+	if user, err := repo.GetByEmail(credentials, ana.Email); err != nil || user == nil || user.Password != hash {
+		t.Errorf("GetByEmail con credenciales = %+v, %v", user, err)
+	}
+	if users, err := repo.GetMultipleByEmails(credentials, []entities.Email{ana.Email}); err != nil || len(users) != 1 || users[0].Password != hash {
+		t.Errorf("GetMultipleByEmails con credenciales = %v, %v", users, err)
+	}
+	if inner.reads != reads+3 {
+		t.Errorf("con credenciales hubo %d lecturas al repositorio, se esperaban 3", inner.reads-reads)
+	}
+} // fin testCredentialsNotCached
+
+func TestBulkInvalidationWithoutIndex(t *testing.T) {
+	ctx := repositorytest.InternalContext()
+	lru := cache.NewLRU(100)
+	repo := NewUserRepository(memory.NewUserRepository(), lru, Options{OnError: failOnError(t)})
+
+	ana := repositorytest.NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	if err := repo.Create(ctx, ana); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	repo.GetByID(ctx, ana.ID)
+
+	// Si la caché descartó la llave por email, se resuelve el ID en el repositorio antes de modificar
+	lru.Delete(ctx, emailKey(ana.Email))
+	if _, err := repo.BulkDelete(ctx, []entities.Email{ana.Email}, nil); err != nil {
+		t.Fatalf("BulkDelete: %v", err)
+	}
+	if user, _ := repo.GetByID(ctx, ana.ID); user != nil {
+		t.Errorf("GetByID tras BulkDelete = %s, se esperaba nil", user.Email)
+	}
+}
+
+func TestTenantScopedReads(t *testing.T) {
+	ctx := repositorytest.InternalContext()
+	org := memory.NewOrganizationRepository()
+	inner := &countingRepository{UserRepository: memory.NewUserRepository(memory.WithOrganization(org))}
+	repo := NewUserRepository(inner, cache.NewLRU(100), Options{OnError: failOnError(t)})
+
+	regional, _ := entities.NewRegional("R1", "Regional Antioquia")
+	centro, _ := entities.NewCentro(regional.ID, "C1", "Centro de Servicios")
+	norte, _ := entities.NewSede(centro.ID, "Sede Norte", "")
+	sur, _ := entities.NewSede(centro.ID, "Sede Sur", "")
+	for _, err := range []error{
+		org.CreateRegional(ctx, regional), org.CreateCentro(ctx, centro),
+		org.CreateSede(ctx, norte), org.CreateSede(ctx, sur),
+	} {
+		if err != nil {
+			t.Fatalf("creando la jerarquía: %v", err)
+		}
+	}
+
+	ana := repositorytest.NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	ana.SedeID = &norte.ID
+	if err := repo.Create(ctx, ana); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Sin alcance se llena la caché
+	if user, err := repo.GetByID(ctx, ana.ID); err != nil || user == nil {
+		t.Fatalf("GetByID = %v, %v", user, err)
+	}
+
+	// Otra sede no la ve aunque esté en caché, ni por ID ni por las llaves secundarias
+	other := repositories.WithTenant(ctx, repositories.OrgFilter{SedeID: &sur.ID})
+	if user, _ := repo.GetByID(other, ana.ID); user != nil {
+		t.Errorf("GetByID desde otra sede = %s, se esperaba nil", user.Email)
+	}
+	if user, _ := repo.GetByEmail(other, ana.Email); user != nil {
+		t.Errorf("GetByEmail desde otra sede = %s, se esperaba nil", user.Email)
+	}
+	if users, _ := repo.GetMultipleByEmails(other, []entities.Email{ana.Email}); len(users) != 0 {
+		t.Errorf("GetMultipleByEmails desde otra sede = %d usuarios, se esperaban 0", len(users))
+	}
+
+	// Su sede la obtiene de la caché; su centro va al repositorio, que resuelve la jerarquía
+	inner.reads = 0
+	if user, _ := repo.GetByID(repositories.WithTenant(ctx, repositories.OrgFilter{SedeID: &norte.ID}), ana.ID); user == nil {
+		t.Error("GetByID desde su sede = nil")
+	}
+	if inner.reads != 0 {
+		t.Errorf("GetByID desde su sede: %d lecturas al repositorio, se esperaban 0", inner.reads)
+	}
+	if user, _ := repo.GetByID(repositories.WithTenant(ctx, repositories.OrgFilter{CentroID: &centro.ID}), ana.ID); user == nil {
+		t.Error("GetByID desde su centro = nil")
+	}
+	if inner.reads != 1 {
+		t.Errorf("GetByID desde su centro: %d lecturas al repositorio, se esperaba 1", inner.reads)
+	}
+} // fin TestTenantScopedReads
+
+// racingRepository ejecuta beforeWrite dentro de Update, antes de escribir, como una lectura concurrente
+type racingRepository struct {
+	repositories.UserRepository
+	beforeWrite func()
+}
+
+func (r *racingRepository) Update(ctx context.Context, user *entities.User) error {
+	r.beforeWrite()
+	return r.UserRepository.Update(ctx, user)
+}
+
+func TestConcurrentReadDuringWrite(t *testing.T) {
+	ctx := repositorytest.InternalContext()
+	inner := &racingRepository{UserRepository: memory.NewUserRepository()}
+	repo := NewUserRepository(inner, cache.NewLRU(100), Options{OnError: failOnError(t)})
+
+	ana := repositorytest.NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	if err := repo.Create(ctx, ana); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Una lectura que falla en la caché mientras se escribe guarda la fila anterior; la escritura debe descartarla
+	inner.beforeWrite = func() {
+		if user, _ := repo.GetByID(ctx, ana.ID); user == nil || user.FirstName != "Ana" {
+			t.Errorf("lectura concurrente = %+v", user)
+		}
+	}
+	changed := *ana
+	changed.FirstName = "Andrea"
+	if err := repo.Update(ctx, &changed); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	if user, _ := repo.GetByID(ctx, ana.ID); user == nil || user.FirstName != "Andrea" {
+		t.Errorf("GetByID tras Update = %+v, se esperaba el nombre nuevo", user)
+	}
+}
+
+func TestPurgeInvalidates(t *testing.T) {
+	ctx := repositorytest.InternalContext()
+	repo := NewUserRepository(memory.NewUserRepository(), cache.NewLRU(100), Options{OnError: failOnError(t)})
+
+	ana := repositorytest.NewTestUser(t, 1, "Ana", "Gómez", entities.RoleAprendiz)
+	if err := repo.Create(ctx, ana); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Delete(ctx, ana.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	// Entrada que una lectura concurrente con Delete dejó en caché
+	repo.store(ctx, ana)
+	if purged, err := repo.Purge(ctx, time.Now().Add(time.Minute)); err != nil || purged != 1 {
+		t.Fatalf("Purge = %d, %v", purged, err)
+	}
+	if user, _ := repo.GetByID(ctx, ana.ID); user != nil {
+		t.Errorf("GetByID tras Purge = %s, se esperaba nil", user.Email)
+	}
+}
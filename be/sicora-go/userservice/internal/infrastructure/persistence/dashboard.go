@@ -0,0 +1,45 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+	"userservice/internal/infrastructure/config"
+)
+
+// RefreshDashboard toma una foto nacional del cruce de usuarios, la guarda y elimina las anteriores a la retención
+// Ver repositories.RefreshDashboardSnapshot
+func (r *Repositories) RefreshDashboard(ctx context.Context, retention time.Duration) (*entities.DashboardSnapshot, error) {
+	return repositories.RefreshDashboardSnapshot(ctx, r.Users, r.Dashboards, retention)
+}
+
+// RunDashboardRefresher refresca la foto al iniciar y luego cada cfg.RefreshInterval minutos hasta que ctx termine
+// Un ciclo fallido se reporta en onError y no detiene el refresco; sin intervalo no hace nada
+func (r *Repositories) RunDashboardRefresher(ctx context.Context, cfg config.DashboardConfig, onError func(error)) {
+	if cfg.RefreshInterval <= 0 {
+		return
+	}
+	retention := time.Duration(cfg.Retention) * 24 * time.Hour
+
+	refresh := func() {
+		if _, err := r.RefreshDashboard(ctx, retention); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+	}
+
+	refresh()
+
+	ticker := time.NewTicker(time.Duration(cfg.RefreshInterval) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+} // fin RunDashboardRefresher
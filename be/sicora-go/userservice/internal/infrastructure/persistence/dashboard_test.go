@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories/repositorytest"
+	"userservice/internal/infrastructure/config"
+)
+
+func TestRunDashboardRefresher(t *testing.T) {
+	ctx, cancel := context.WithCancel(repositorytest.InternalContext())
+	defer cancel()
+
+	repos, err := Open(ctx, &config.Config{
+		Database: config.DatabaseConfig{Driver: DriverSQLite, Path: filepath.Join(t.TempDir(), "sicora.db")},
+		Cache:    config.CacheConfig{Backend: "none"},
+	})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repos.Close()
+
+	for i, role := range []entities.UserRole{entities.RoleAprendiz, entities.RoleAprendiz, entities.RoleInstructor} {
+		if err := repos.Users.Create(ctx, repositorytest.NewTestUser(t, i+1, "Ana", "Gómez", role)); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	// El refresco guarda la foto y elimina las anteriores a la retención
+	stale := entities.NewDashboardSnapshot(time.Now().AddDate(0, 0, -10))
+	if err := repos.Dashboards.Save(ctx, stale); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	refreshed, err := repos.RefreshDashboard(ctx, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("RefreshDashboard: %v", err)
+	}
+	if refreshed.Total() != 3 || refreshed.CountBy(entities.DimensionRole)[string(entities.RoleAprendiz)] != 2 {
+		t.Errorf("foto = %+v", refreshed.Cells)
+	}
+	if pruned, err := repos.Dashboards.Prune(ctx, time.Now()); err != nil || pruned != 0 {
+		t.Errorf("la foto vencida debió eliminarse en el refresco: Prune = %d, %v", pruned, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		repos.RunDashboardRefresher(ctx, config.DashboardConfig{RefreshInterval: 60, Retention: 7}, func(err error) {
+			t.Errorf("refresco: %v", err)
+		})
+	}()
+
+	// El primer refresco ocurre al iniciar, sin esperar el intervalo
+	var latest *entities.DashboardSnapshot
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if latest, err = repos.Dashboards.Latest(ctx); err != nil || latest.ID != refreshed.ID {
+			break
+		}
+	}
+	if err != nil || latest.ID == refreshed.ID {
+		t.Errorf("el refresco inicial no guardó una foto: %v", err)
+	}
+
+	cancel()
+	<-done
+} // fin TestRunDashboardRefresher
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.DashboardSnapshotRepository = (*DashboardSnapshotRepository)(nil)
+
+// DashboardSnapshotRepository es la implementación en memoria de repositories.DashboardSnapshotRepository
+type DashboardSnapshotRepository struct {
+	mu        sync.RWMutex
+	snapshots []*entities.DashboardSnapshot // Copias en el orden en que se guardaron
+}
+
+// NewDashboardSnapshotRepository crea un repositorio de fotos vacío en memoria
+func NewDashboardSnapshotRepository() *DashboardSnapshotRepository {
+	return &DashboardSnapshotRepository{}
+}
+
+// Save guarda una copia de la foto
+func (r *DashboardSnapshotRepository) Save(ctx context.Context, snapshot *entities.DashboardSnapshot) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+
+	if snapshot.ID == uuid.Nil {
+		snapshot.ID = uuid.New()
+	}
+	for i := range snapshot.Cells {
+		snapshot.Cells[i].SnapshotID = snapshot.ID
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.snapshots = append(r.snapshots, cloneSnapshot(snapshot))
+	return nil
+}
+
+// Latest obtiene una copia de la foto más reciente, nil si no hay ninguna
+func (r *DashboardSnapshotRepository) Latest(ctx context.Context) (*entities.DashboardSnapshot, error) {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if latest := r.latest(); latest >= 0 {
+		return cloneSnapshot(r.snapshots[latest]), nil
+	}
+	return nil, nil
+}
+
+// Prune elimina las fotos anteriores a la fecha dada salvo la más reciente
+func (r *DashboardSnapshotRepository) Prune(ctx context.Context, takenBefore time.Time) (int64, error) {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var latest *entities.DashboardSnapshot
+	if index := r.latest(); index >= 0 {
+		latest = r.snapshots[index]
+	}
+
+	before := len(r.snapshots)
+	r.snapshots = slices.DeleteFunc(r.snapshots, func(snapshot *entities.DashboardSnapshot) bool {
+		return snapshot != latest && snapshot.TakenAt.Before(takenBefore)
+	})
+
+	return int64(before - len(r.snapshots)), nil
+}
+
+// latest retorna la posición de la foto más reciente, -1 si no hay ninguna
+func (r *DashboardSnapshotRepository) latest() int {
+	latest := -1
+	for i, snapshot := range r.snapshots {
+		if latest < 0 || snapshot.TakenAt.After(r.snapshots[latest].TakenAt) {
+			latest = i
+		}
+	}
+	return latest
+}
+
+// cloneSnapshot copia la foto y sus celdas
+func cloneSnapshot(snapshot *entities.DashboardSnapshot) *entities.DashboardSnapshot {
+	clone := *snapshot
+	clone.Cells = slices.Clone(snapshot.Cells)
+	return &clone
+}
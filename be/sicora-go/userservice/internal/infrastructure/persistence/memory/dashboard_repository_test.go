@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories"
+	"userservice/internal/domain/repositories/repositorytest"
+)
+
+func TestDashboardSnapshotRepositoryContract(t *testing.T) {
+	repositorytest.RunDashboardSnapshotRepositoryContract(t, func(t *testing.T) repositories.DashboardSnapshotRepository {
+		return NewDashboardSnapshotRepository()
+	})
+}
@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.FichaRepository = (*FichaRepository)(nil)
+
+// FichaRepository es la implementación en memoria de repositories.FichaRepository
+type FichaRepository struct {
+	mu       sync.RWMutex
+	fichas   map[string]*entities.Ficha // Por número
+	programs *ProgramRepository
+	org      *OrganizationRepository // Sedes existentes; con nil ninguna sede existe
+	users    *UserRepository         // Lo relaciona WithFichas para validar al instructor líder
+}
+
+// NewFichaRepository crea un repositorio de fichas vacío en memoria sobre los programas y la jerarquía dados
+func NewFichaRepository(programs *ProgramRepository, org *OrganizationRepository) *FichaRepository {
+	return &FichaRepository{
+		fichas:   make(map[string]*entities.Ficha),
+		programs: programs,
+		org:      org,
+	}
+}
+
+// Create registra una copia de la ficha tras verificar su programa, sede e instructor líder y el alcance de su sede
+func (r *FichaRepository) Create(ctx context.Context, ficha *entities.Ficha) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+	if err := r.checkReferences(ctx, ficha); err != nil {
+		return err
+	}
+	if err := repositories.CheckTenantSede(ctx, r.org.path(ficha.SedeID)); err != nil {
+		return err
+	}
+
+	if ficha.ID == uuid.Nil {
+		ficha.ID = uuid.New()
+	}
+	now := time.Now()
+	if ficha.CreatedAt.IsZero() {
+		ficha.CreatedAt = now
+	}
+	if ficha.UpdatedAt.IsZero() {
+		ficha.UpdatedAt = now
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.fichas[ficha.Number]; found {
+		return repositories.ErrDuplicateFicha
+	}
+
+	stored := *ficha
+	r.fichas[ficha.Number] = &stored
+	return nil
+} // fin Create
+
+// GetByNumber obtiene una ficha por su número, retorna nil si no existe o está fuera del alcance
+func (r *FichaRepository) GetByNumber(ctx context.Context, number string) (*entities.Ficha, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	if ficha := r.get(number); ficha != nil && r.visible(ctx, ficha) {
+		return ficha, nil
+	}
+	return nil, nil
+}
+
+// Update reemplaza los datos de la ficha conservando su número y fecha de creación
+// La ficha debe estar en el alcance antes y después del cambio
+func (r *FichaRepository) Update(ctx context.Context, ficha *entities.Ficha) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+	if err := r.checkReferences(ctx, ficha); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, current := range r.fichas {
+		if current.ID == ficha.ID && r.visible(ctx, current) {
+			if err := repositories.CheckTenantSede(ctx, r.org.path(ficha.SedeID)); err != nil {
+				return err
+			}
+			ficha.Number = current.Number
+			ficha.CreatedAt = current.CreatedAt
+			ficha.UpdatedAt = time.Now()
+			stored := *ficha
+			r.fichas[ficha.Number] = &stored
+			return nil
+		}
+	}
+
+	return repositories.ErrFichaNotFound
+} // fin Update
+
+// List obtiene las fichas que cumplen los filtros ordenadas por número
+func (r *FichaRepository) List(ctx context.Context, filters repositories.FichaFilters) ([]*entities.Ficha, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fichas := []*entities.Ficha{}
+	for _, ficha := range r.fichas {
+		if matchesFichaFilters(ficha, filters) && r.visible(ctx, ficha) {
+			clone := *ficha
+			fichas = append(fichas, &clone)
+		}
+	}
+	slices.SortFunc(fichas, func(a, b *entities.Ficha) int {
+		return strings.Compare(a.Number, b.Number)
+	})
+
+	return fichas, nil
+}
+
+// checkReferences verifica que el programa y la sede existan y que el instructor líder sea un instructor
+// Se llama sin el lock tomado: consulta los repositorios de programas, de la jerarquía y de usuarios
+func (r *FichaRepository) checkReferences(ctx context.Context, ficha *entities.Ficha) error {
+	if r.programs.code(ficha.ProgramID) == entities.DashboardKeyNone {
+		return repositories.ErrProgramNotFound
+	}
+
+	if !r.org.hasSede(ficha.SedeID) {
+		return repositories.ErrSedeNotFound
+	}
+
+	if ficha.LeadInstructorID == nil || r.users == nil {
+		return nil
+	}
+	if instructor := r.users.get(*ficha.LeadInstructorID); instructor == nil || !instructor.IsInstructor() {
+		return repositories.ErrInvalidLeadInstructor
+	}
+	return nil
+} // fin checkReferences
+
+// get retorna una copia de la ficha, nil si no existe o el repositorio es nil
+func (r *FichaRepository) get(number string) *entities.Ficha {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ficha, found := r.fichas[number]
+	if !found {
+		return nil
+	}
+	clone := *ficha
+	return &clone
+}
+
+// visible indica si la sede de la ficha está en el alcance de sede del contexto; sin sede solo se ve sin alcance
+func (r *FichaRepository) visible(ctx context.Context, ficha *entities.Ficha) bool {
+	return inTenant(ctx, r.org.path(ficha.SedeID))
+}
+
+// lookup obtiene una copia de la ficha si existe y está en el alcance de sede del contexto, nil si no
+// Lo usa UserRepository para que los aprendices solo se asignen o trasladen a fichas del alcance
+func (r *FichaRepository) lookup(ctx context.Context, number string) *entities.Ficha {
+	ficha := r.get(number)
+	if ficha == nil || !r.visible(ctx, ficha) {
+		return nil
+	}
+	return ficha
+}
+
+// visibleNumber indica si la ficha existe y está en el alcance de sede del contexto
+// Lo usa InstructorAssignmentRepository, que acota las asignaciones por la sede de su ficha
+func (r *FichaRepository) visibleNumber(ctx context.Context, number string) bool {
+	return r.lookup(ctx, number) != nil
+}
+
+// programCode retorna el código del programa de la ficha, DashboardKeyNone si no tiene
+// Lo usa UserRepository para el filtro y los conteos por programa
+func (r *FichaRepository) programCode(fichaID *string) string {
+	if fichaID == nil {
+		return entities.DashboardKeyNone
+	}
+	if ficha := r.get(*fichaID); ficha != nil {
+		return r.programs.code(ficha.ProgramID)
+	}
+	return entities.DashboardKeyNone
+}
+
+// matchesFichaFilters evalúa los filtros de FichaFilters sobre una ficha
+func matchesFichaFilters(ficha *entities.Ficha, filters repositories.FichaFilters) bool {
+	if filters.ProgramID != nil && ficha.ProgramID != *filters.ProgramID {
+		return false
+	}
+	if filters.SedeID != nil && (ficha.SedeID == nil || *ficha.SedeID != *filters.SedeID) {
+		return false
+	}
+	if filters.Status != nil && ficha.Status != *filters.Status {
+		return false
+	}
+	if filters.LeadInstructorID != nil && (ficha.LeadInstructorID == nil || *ficha.LeadInstructorID != *filters.LeadInstructorID) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,11 @@
+package memory
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories/repositorytest"
+)
+
+func TestFichaRepositoryContract(t *testing.T) {
+	repositorytest.RunFichaRepositoryContract(t, newTestRepositories)
+}
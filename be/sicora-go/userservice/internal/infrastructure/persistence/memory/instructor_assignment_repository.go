@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.InstructorAssignmentRepository = (*InstructorAssignmentRepository)(nil)
+
+// InstructorAssignmentRepository es la implementación en memoria de repositories.InstructorAssignmentRepository
+type InstructorAssignmentRepository struct {
+	mu          sync.RWMutex
+	assignments map[uuid.UUID]*entities.InstructorAssignment
+	fichas      *FichaRepository // Valida la ficha y, con sus usuarios, al instructor
+}
+
+// NewInstructorAssignmentRepository crea un repositorio de asignaciones vacío en memoria sobre las fichas dadas
+func NewInstructorAssignmentRepository(fichas *FichaRepository) *InstructorAssignmentRepository {
+	return &InstructorAssignmentRepository{
+		assignments: make(map[uuid.UUID]*entities.InstructorAssignment),
+		fichas:      fichas,
+	}
+}
+
+// Create registra una copia de la asignación tras verificar al instructor, la ficha y que no se cruce con otra
+// Una ficha fuera del alcance de sede del contexto no existe
+func (r *InstructorAssignmentRepository) Create(ctx context.Context, assignment *entities.InstructorAssignment) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	if r.fichas.users != nil {
+		if instructor := r.fichas.users.get(assignment.InstructorID); instructor == nil || !instructor.IsInstructor() {
+			return repositories.ErrInvalidInstructor
+		}
+	}
+	if !r.fichas.visibleNumber(ctx, assignment.FichaID) {
+		return repositories.ErrFichaNotFound
+	}
+	if assignment.ID == uuid.Nil {
+		assignment.ID = uuid.New()
+	}
+	if assignment.CreatedAt.IsZero() {
+		assignment.CreatedAt = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.assignments {
+		if existing.Overlaps(assignment) {
+			return repositories.ErrOverlappingAssignment
+		}
+	}
+
+	r.assignments[assignment.ID] = cloneAssignment(assignment)
+	return nil
+} // fin Create
+
+// GetByID obtiene una asignación por su ID, retorna nil si no existe o su ficha está fuera del alcance
+func (r *InstructorAssignmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.InstructorAssignment, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if assignment, found := r.assignments[id]; found && r.fichas.visibleNumber(ctx, assignment.FichaID) {
+		return cloneAssignment(assignment), nil
+	}
+	return nil, nil
+}
+
+// End cierra la asignación en endDate
+func (r *InstructorAssignmentRepository) End(ctx context.Context, id uuid.UUID, endDate time.Time) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assignment, found := r.assignments[id]
+	if !found || !r.fichas.visibleNumber(ctx, assignment.FichaID) {
+		return repositories.ErrAssignmentNotFound
+	}
+	return assignment.EndOn(endDate)
+}
+
+// ListByInstructor obtiene las asignaciones del instructor ordenadas por ficha e inicio
+func (r *InstructorAssignmentRepository) ListByInstructor(ctx context.Context, instructorID uuid.UUID, activeOn time.Time) ([]*entities.InstructorAssignment, error) {
+	assignments, err := r.matching(ctx, activeOn, func(a *entities.InstructorAssignment) bool {
+		return a.InstructorID == instructorID
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(assignments, func(a, b *entities.InstructorAssignment) int {
+		return cmp.Or(
+			strings.Compare(a.FichaID, b.FichaID),
+			a.StartDate.Compare(b.StartDate),
+			strings.Compare(a.Competency, b.Competency),
+		)
+	})
+	return assignments, nil
+}
+
+// ListByFicha obtiene las asignaciones de la ficha ordenadas por inicio e instructor
+func (r *InstructorAssignmentRepository) ListByFicha(ctx context.Context, fichaID string, activeOn time.Time) ([]*entities.InstructorAssignment, error) {
+	assignments, err := r.matching(ctx, activeOn, func(a *entities.InstructorAssignment) bool {
+		return a.FichaID == fichaID
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(assignments, func(a, b *entities.InstructorAssignment) int {
+		return cmp.Or(
+			a.StartDate.Compare(b.StartDate),
+			strings.Compare(a.InstructorID.String(), b.InstructorID.String()),
+			strings.Compare(a.Competency, b.Competency),
+		)
+	})
+	return assignments, nil
+}
+
+// IsAssigned indica si el instructor tiene alguna asignación vigente en la ficha en la fecha dada
+func (r *InstructorAssignmentRepository) IsAssigned(ctx context.Context, instructorID uuid.UUID, fichaID string, on time.Time) (bool, error) {
+	assignments, err := r.matching(ctx, on, func(a *entities.InstructorAssignment) bool {
+		return a.InstructorID == instructorID && a.FichaID == fichaID
+	})
+	return len(assignments) > 0, err
+}
+
+// matching retorna copias de las asignaciones que cumplen la condición, vigentes en activeOn si no es cero,
+// de las fichas del alcance de sede del contexto
+func (r *InstructorAssignmentRepository) matching(ctx context.Context, activeOn time.Time, keep func(*entities.InstructorAssignment) bool) ([]*entities.InstructorAssignment, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	assignments := []*entities.InstructorAssignment{}
+	for _, assignment := range r.assignments {
+		if keep(assignment) && (activeOn.IsZero() || assignment.ActiveOn(activeOn)) &&
+			r.fichas.visibleNumber(ctx, assignment.FichaID) {
+			assignments = append(assignments, cloneAssignment(assignment))
+		}
+	}
+	return assignments, nil
+}
+
+func cloneAssignment(assignment *entities.InstructorAssignment) *entities.InstructorAssignment {
+	if assignment == nil {
+		return nil
+	}
+	clone := *assignment
+	if assignment.EndDate != nil {
+		end := *assignment.EndDate
+		clone.EndDate = &end
+	}
+	return &clone
+}
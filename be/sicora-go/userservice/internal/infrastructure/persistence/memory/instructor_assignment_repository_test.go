@@ -0,0 +1,11 @@
+package memory
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories/repositorytest"
+)
+
+func TestInstructorAssignmentRepositoryContract(t *testing.T) {
+	repositorytest.RunInstructorAssignmentRepositoryContract(t, newTestRepositories)
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.BackupCodeRepository = (*BackupCodeRepository)(nil)
+
+// BackupCodeRepository es la implementación en memoria de repositories.BackupCodeRepository
+type BackupCodeRepository struct {
+	mu    sync.RWMutex
+	codes map[uuid.UUID]*entities.MFABackupCode
+}
+
+// NewBackupCodeRepository crea un repositorio de códigos de recuperación vacío en memoria
+func NewBackupCodeRepository() *BackupCodeRepository {
+	return &BackupCodeRepository{codes: make(map[uuid.UUID]*entities.MFABackupCode)}
+}
+
+// CreateBatch guarda copias de los códigos de recuperación
+func (r *BackupCodeRepository) CreateBatch(ctx context.Context, codes []*entities.MFABackupCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, code := range codes {
+		if code.ID == uuid.Nil {
+			code.ID = uuid.New()
+		}
+		if code.CreatedAt.IsZero() {
+			code.CreatedAt = now
+		}
+		stored := *code
+		r.codes[code.ID] = &stored
+	}
+	return nil
+}
+
+// GetUnusedByUserID obtiene los códigos no usados y no expirados de un usuario
+func (r *BackupCodeRepository) GetUnusedByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.MFABackupCode, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	codes := []*entities.MFABackupCode{}
+	for _, code := range r.codes {
+		if code.UserID == userID && !code.IsUsed && code.ExpiresAt.After(now) {
+			clone := *code
+			codes = append(codes, &clone)
+		}
+	}
+	slices.SortFunc(codes, func(a, b *entities.MFABackupCode) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return codes, nil
+}
+
+// CountUnusedByUserID cuenta los códigos no usados y no expirados de un usuario
+func (r *BackupCodeRepository) CountUnusedByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	codes, err := r.GetUnusedByUserID(ctx, userID)
+	return int64(len(codes)), err
+}
+
+// MarkAsUsed marca un código como usado solo si aún no lo estaba
+func (r *BackupCodeRepository) MarkAsUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	code, found := r.codes[id]
+	if !found || code.IsUsed {
+		return repositories.ErrMFARecordNotFound
+	}
+	code.IsUsed = true
+	code.UsedAt = &usedAt
+	return nil
+}
+
+// DeleteByUserID elimina todos los códigos de un usuario
+func (r *BackupCodeRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, code := range r.codes {
+		if code.UserID == userID {
+			delete(r.codes, id)
+		}
+	}
+	return nil
+}
+
+// DeleteExpired elimina los códigos expirados antes de la fecha dada
+func (r *BackupCodeRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, code := range r.codes {
+		if code.ExpiresAt.Before(before) {
+			delete(r.codes, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.MFAMethodRepository = (*MFAMethodRepository)(nil)
+
+// MFAMethodRepository es la implementación en memoria de repositories.MFAMethodRepository
+type MFAMethodRepository struct {
+	mu      sync.RWMutex
+	methods map[uuid.UUID]*entities.UserMFAMethod
+}
+
+// NewMFAMethodRepository crea un repositorio de métodos MFA vacío en memoria
+func NewMFAMethodRepository() *MFAMethodRepository {
+	return &MFAMethodRepository{methods: make(map[uuid.UUID]*entities.UserMFAMethod)}
+}
+
+// Create registra una copia del método MFA
+func (r *MFAMethodRepository) Create(ctx context.Context, method *entities.UserMFAMethod) error {
+	if method.ID == uuid.Nil {
+		method.ID = uuid.New()
+	}
+	now := time.Now()
+	if method.CreatedAt.IsZero() {
+		method.CreatedAt = now
+	}
+	if method.UpdatedAt.IsZero() {
+		method.UpdatedAt = now
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *method
+	r.methods[method.ID] = &stored
+	return nil
+}
+
+// GetByID obtiene un método MFA por su ID, retorna nil si no existe
+func (r *MFAMethodRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.UserMFAMethod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return cloneMFAMethod(r.methods[id]), nil
+}
+
+// GetByUserID obtiene todos los métodos MFA configurados por un usuario
+func (r *MFAMethodRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.UserMFAMethod, error) {
+	return r.find(func(m *entities.UserMFAMethod) bool { return m.UserID == userID }), nil
+}
+
+// GetEnabledByUserID obtiene los métodos MFA habilitados de un usuario, el primario primero
+func (r *MFAMethodRepository) GetEnabledByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.UserMFAMethod, error) {
+	return r.find(func(m *entities.UserMFAMethod) bool { return m.UserID == userID && m.IsEnabled }), nil
+}
+
+// GetPrimaryByUserID obtiene el método MFA primario habilitado, retorna nil si no tiene
+func (r *MFAMethodRepository) GetPrimaryByUserID(ctx context.Context, userID uuid.UUID) (*entities.UserMFAMethod, error) {
+	methods := r.find(func(m *entities.UserMFAMethod) bool { return m.UserID == userID && m.IsPrimary && m.IsEnabled })
+	if len(methods) == 0 {
+		return nil, nil
+	}
+	return methods[0], nil
+}
+
+// GetByUserAndType obtiene el método de un tipo dado, retorna nil si no existe
+func (r *MFAMethodRepository) GetByUserAndType(ctx context.Context, userID uuid.UUID, methodType string) (*entities.UserMFAMethod, error) {
+	methods := r.find(func(m *entities.UserMFAMethod) bool { return m.UserID == userID && m.MethodType == methodType })
+	if len(methods) == 0 {
+		return nil, nil
+	}
+	return methods[0], nil
+}
+
+// Update reemplaza los datos del método conservando su usuario y fecha de creación
+func (r *MFAMethodRepository) Update(ctx context.Context, method *entities.UserMFAMethod) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, found := r.methods[method.ID]
+	if !found {
+		return repositories.ErrMFARecordNotFound
+	}
+
+	method.UpdatedAt = time.Now()
+	stored := *method
+	stored.UserID = current.UserID
+	stored.CreatedAt = current.CreatedAt
+	r.methods[method.ID] = &stored
+	return nil
+}
+
+// SetPrimary marca el método como primario y desmarca los demás del usuario
+func (r *MFAMethodRepository) SetPrimary(ctx context.Context, userID, methodID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	target, found := r.methods[methodID]
+	if !found || target.UserID != userID {
+		return repositories.ErrMFARecordNotFound
+	}
+
+	now := time.Now()
+	for _, method := range r.methods {
+		if method.UserID == userID && method.IsPrimary != (method.ID == methodID) {
+			method.IsPrimary = method.ID == methodID
+			method.UpdatedAt = now
+		}
+	}
+	return nil
+}
+
+// MarkAsUsed registra el último uso exitoso del método
+func (r *MFAMethodRepository) MarkAsUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	method, found := r.methods[id]
+	if !found {
+		return repositories.ErrMFARecordNotFound
+	}
+	method.LastUsedAt = &usedAt
+	method.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete elimina un método MFA
+func (r *MFAMethodRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.methods[id]; !found {
+		return repositories.ErrMFARecordNotFound
+	}
+	delete(r.methods, id)
+	return nil
+}
+
+// DeleteByUserID elimina todos los métodos MFA de un usuario
+func (r *MFAMethodRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, method := range r.methods {
+		if method.UserID == userID {
+			delete(r.methods, id)
+		}
+	}
+	return nil
+}
+
+// find retorna copias de los métodos que cumplen keep, el primario primero y luego por fecha de creación
+func (r *MFAMethodRepository) find(keep func(*entities.UserMFAMethod) bool) []*entities.UserMFAMethod {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	methods := []*entities.UserMFAMethod{}
+	for _, method := range r.methods {
+		if keep(method) {
+			methods = append(methods, cloneMFAMethod(method))
+		}
+	}
+	slices.SortFunc(methods, func(a, b *entities.UserMFAMethod) int {
+		if a.IsPrimary != b.IsPrimary {
+			if a.IsPrimary {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return methods
+} // fin find
+
+func cloneMFAMethod(method *entities.UserMFAMethod) *entities.UserMFAMethod {
+	if method == nil {
+		return nil
+	}
+	clone := *method
+	return &clone
+}
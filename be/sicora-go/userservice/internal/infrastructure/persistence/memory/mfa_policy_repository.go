@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.MFAEnforcementPolicyRepository = (*MFAEnforcementPolicyRepository)(nil)
+
+// MFAEnforcementPolicyRepository es la implementación en memoria de repositories.MFAEnforcementPolicyRepository
+type MFAEnforcementPolicyRepository struct {
+	mu       sync.RWMutex
+	policies map[uuid.UUID]*entities.MFAEnforcementPolicy
+}
+
+// NewMFAEnforcementPolicyRepository crea un repositorio de políticas MFA vacío en memoria
+func NewMFAEnforcementPolicyRepository() *MFAEnforcementPolicyRepository {
+	return &MFAEnforcementPolicyRepository{policies: make(map[uuid.UUID]*entities.MFAEnforcementPolicy)}
+}
+
+// Create registra una copia de la política
+func (r *MFAEnforcementPolicyRepository) Create(ctx context.Context, policy *entities.MFAEnforcementPolicy) error {
+	if policy.ID == uuid.Nil {
+		policy.ID = uuid.New()
+	}
+	now := time.Now()
+	if policy.CreatedAt.IsZero() {
+		policy.CreatedAt = now
+	}
+	if policy.UpdatedAt.IsZero() {
+		policy.UpdatedAt = now
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.policies[policy.ID] = cloneMFAPolicy(policy)
+	return nil
+}
+
+// GetByRoleName obtiene la política de un rol, retorna nil si el rol no tiene política
+func (r *MFAEnforcementPolicyRepository) GetByRoleName(ctx context.Context, roleName string) (*entities.MFAEnforcementPolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, policy := range r.policies {
+		if policy.RoleName == roleName {
+			return cloneMFAPolicy(policy), nil
+		}
+	}
+	return nil, nil
+}
+
+// List obtiene todas las políticas ordenadas por rol
+func (r *MFAEnforcementPolicyRepository) List(ctx context.Context) ([]*entities.MFAEnforcementPolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	policies := []*entities.MFAEnforcementPolicy{}
+	for _, policy := range r.policies {
+		policies = append(policies, cloneMFAPolicy(policy))
+	}
+	slices.SortFunc(policies, func(a, b *entities.MFAEnforcementPolicy) int {
+		return strings.Compare(a.RoleName, b.RoleName)
+	})
+	return policies, nil
+}
+
+// Update reemplaza los datos de la política conservando su fecha de creación
+func (r *MFAEnforcementPolicyRepository) Update(ctx context.Context, policy *entities.MFAEnforcementPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, found := r.policies[policy.ID]
+	if !found {
+		return repositories.ErrMFARecordNotFound
+	}
+
+	policy.UpdatedAt = time.Now()
+	stored := cloneMFAPolicy(policy)
+	stored.CreatedAt = current.CreatedAt
+	r.policies[policy.ID] = stored
+	return nil
+}
+
+// Delete elimina una política
+func (r *MFAEnforcementPolicyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.policies[id]; !found {
+		return repositories.ErrMFARecordNotFound
+	}
+	delete(r.policies, id)
+	return nil
+}
+
+// cloneMFAPolicy copia la política con sus listas de métodos
+func cloneMFAPolicy(policy *entities.MFAEnforcementPolicy) *entities.MFAEnforcementPolicy {
+	if policy == nil {
+		return nil
+	}
+	clone := *policy
+	clone.PrimaryMethods = slices.Clone(policy.PrimaryMethods)
+	clone.AlternativeMethods = slices.Clone(policy.AlternativeMethods)
+	return &clone
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.MFASessionRepository = (*MFASessionRepository)(nil)
+
+// defaultMFAMaxAttempts es el default de max_attempts_mfa_session en la base de datos
+const defaultMFAMaxAttempts = 3
+
+// MFASessionRepository es la implementación en memoria de repositories.MFASessionRepository
+type MFASessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[uuid.UUID]*entities.MFASession
+}
+
+// NewMFASessionRepository crea un repositorio de sesiones MFA vacío en memoria
+func NewMFASessionRepository() *MFASessionRepository {
+	return &MFASessionRepository{sessions: make(map[uuid.UUID]*entities.MFASession)}
+}
+
+// Create registra una copia de la sesión de verificación
+func (r *MFASessionRepository) Create(ctx context.Context, session *entities.MFASession) error {
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+	if session.MaxAttempts == 0 {
+		session.MaxAttempts = defaultMFAMaxAttempts
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *session
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+// GetByID obtiene una sesión por su ID, retorna nil si no existe
+func (r *MFASessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.MFASession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return cloneMFASession(r.sessions[id]), nil
+}
+
+// GetActiveByUserID obtiene la sesión más reciente no verificada y no expirada
+func (r *MFASessionRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*entities.MFASession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var active *entities.MFASession
+	for _, session := range r.sessions {
+		if session.UserID != userID || session.IsVerified || !session.ExpiresAt.After(now) {
+			continue
+		}
+		if active == nil || session.CreatedAt.After(active.CreatedAt) {
+			active = session
+		}
+	}
+	return cloneMFASession(active), nil
+}
+
+// IncrementAttempts suma un intento fallido a la sesión
+func (r *MFASessionRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, found := r.sessions[id]
+	if !found {
+		return repositories.ErrMFARecordNotFound
+	}
+	session.Attempts++
+	return nil
+}
+
+// MarkAsVerified marca la sesión como verificada
+func (r *MFASessionRepository) MarkAsVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, found := r.sessions[id]
+	if !found {
+		return repositories.ErrMFARecordNotFound
+	}
+	session.IsVerified = true
+	session.VerifiedAt = &verifiedAt
+	return nil
+}
+
+// DeleteExpired elimina las sesiones expiradas antes de la fecha dada
+func (r *MFASessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, session := range r.sessions {
+		if session.ExpiresAt.Before(before) {
+			delete(r.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// deleteByUserID elimina las sesiones del usuario; lo usa UserRepository.Purge
+func (r *MFASessionRepository) deleteByUserID(userID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, session := range r.sessions {
+		if session.UserID == userID {
+			delete(r.sessions, id)
+		}
+	}
+}
+
+func cloneMFASession(session *entities.MFASession) *entities.MFASession {
+	if session == nil {
+		return nil
+	}
+	clone := *session
+	return &clone
+}
@@ -0,0 +1,477 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.OrganizationRepository = (*OrganizationRepository)(nil)
+
+// OrganizationRepository es la implementación en memoria de repositories.OrganizationRepository
+type OrganizationRepository struct {
+	mu         sync.RWMutex
+	regionales map[uuid.UUID]*entities.Regional
+	centros    map[uuid.UUID]*entities.Centro
+	sedes      map[uuid.UUID]*entities.Sede
+}
+
+// NewOrganizationRepository crea una jerarquía vacía en memoria
+func NewOrganizationRepository() *OrganizationRepository {
+	return &OrganizationRepository{
+		regionales: make(map[uuid.UUID]*entities.Regional),
+		centros:    make(map[uuid.UUID]*entities.Centro),
+		sedes:      make(map[uuid.UUID]*entities.Sede),
+	}
+}
+
+// CreateRegional registra una copia de la regional; requiere un contexto que vea todas las sedes
+func (r *OrganizationRepository) CreateRegional(ctx context.Context, regional *entities.Regional) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+
+	if regional.ID == uuid.Nil {
+		regional.ID = uuid.New()
+	}
+	now := time.Now()
+	if regional.CreatedAt.IsZero() {
+		regional.CreatedAt = now
+	}
+	if regional.UpdatedAt.IsZero() {
+		regional.UpdatedAt = now
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.regionalCodeTaken(regional) {
+		return repositories.ErrDuplicateRegional
+	}
+
+	stored := *regional
+	r.regionales[regional.ID] = &stored
+	return nil
+} // fin CreateRegional
+
+// CreateCentro registra una copia del centro tras verificar su regional; requiere un contexto que vea todas las sedes
+func (r *OrganizationRepository) CreateCentro(ctx context.Context, centro *entities.Centro) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+
+	if centro.ID == uuid.Nil {
+		centro.ID = uuid.New()
+	}
+	now := time.Now()
+	if centro.CreatedAt.IsZero() {
+		centro.CreatedAt = now
+	}
+	if centro.UpdatedAt.IsZero() {
+		centro.UpdatedAt = now
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.regionales[centro.RegionalID]; !found {
+		return repositories.ErrRegionalNotFound
+	}
+	if r.centroCodeTaken(centro) {
+		return repositories.ErrDuplicateCentro
+	}
+
+	stored := *centro
+	r.centros[centro.ID] = &stored
+	return nil
+} // fin CreateCentro
+
+// CreateSede registra una copia de la sede tras verificar su centro y que quede en el alcance del contexto
+func (r *OrganizationRepository) CreateSede(ctx context.Context, sede *entities.Sede) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	if sede.ID == uuid.Nil {
+		sede.ID = uuid.New()
+	}
+	now := time.Now()
+	if sede.CreatedAt.IsZero() {
+		sede.CreatedAt = now
+	}
+	if sede.UpdatedAt.IsZero() {
+		sede.UpdatedAt = now
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkSedeCentro(ctx, sede); err != nil {
+		return err
+	}
+	if r.sedeNameTaken(sede) {
+		return repositories.ErrDuplicateSede
+	}
+
+	stored := *sede
+	r.sedes[sede.ID] = &stored
+	return nil
+} // fin CreateSede
+
+// GetRegional obtiene una regional por su ID, retorna nil si no existe o no tiene sedes del alcance
+func (r *OrganizationRepository) GetRegional(ctx context.Context, id uuid.UUID) (*entities.Regional, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.regionalInTenant(ctx, id) {
+		return nil, nil
+	}
+	return cloneRegional(r.regionales[id]), nil
+}
+
+// GetCentro obtiene un centro de formación por su ID, retorna nil si no existe o no tiene sedes del alcance
+func (r *OrganizationRepository) GetCentro(ctx context.Context, id uuid.UUID) (*entities.Centro, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.centroInTenant(ctx, id) {
+		return nil, nil
+	}
+	return cloneCentro(r.centros[id]), nil
+}
+
+// GetSede obtiene una sede por su ID, retorna nil si no existe o está fuera del alcance
+func (r *OrganizationRepository) GetSede(ctx context.Context, id uuid.UUID) (*entities.Sede, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.sedeInTenant(ctx, id) {
+		return nil, nil
+	}
+	return cloneSede(r.sedes[id]), nil
+}
+
+// UpdateRegional reemplaza los datos de la regional conservando su fecha de creación
+// Requiere un contexto que vea todas las sedes
+func (r *OrganizationRepository) UpdateRegional(ctx context.Context, regional *entities.Regional) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, found := r.regionales[regional.ID]
+	if !found {
+		return repositories.ErrRegionalNotFound
+	}
+	if r.regionalCodeTaken(regional) {
+		return repositories.ErrDuplicateRegional
+	}
+
+	regional.CreatedAt = current.CreatedAt
+	regional.UpdatedAt = time.Now()
+	stored := *regional
+	r.regionales[regional.ID] = &stored
+	return nil
+}
+
+// UpdateCentro reemplaza los datos del centro conservando su fecha de creación
+// Requiere un contexto que vea todas las sedes
+func (r *OrganizationRepository) UpdateCentro(ctx context.Context, centro *entities.Centro) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, found := r.centros[centro.ID]
+	if !found {
+		return repositories.ErrCentroNotFound
+	}
+	if _, found := r.regionales[centro.RegionalID]; !found {
+		return repositories.ErrRegionalNotFound
+	}
+	if r.centroCodeTaken(centro) {
+		return repositories.ErrDuplicateCentro
+	}
+
+	centro.CreatedAt = current.CreatedAt
+	centro.UpdatedAt = time.Now()
+	stored := *centro
+	r.centros[centro.ID] = &stored
+	return nil
+} // fin UpdateCentro
+
+// UpdateSede reemplaza los datos de la sede conservando su fecha de creación
+// La sede debe estar en el alcance del contexto antes y después del cambio
+func (r *OrganizationRepository) UpdateSede(ctx context.Context, sede *entities.Sede) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, found := r.sedes[sede.ID]
+	if !found || !r.sedeInTenant(ctx, sede.ID) {
+		return repositories.ErrSedeNotFound
+	}
+	if err := r.checkSedeCentro(ctx, sede); err != nil {
+		return err
+	}
+	if r.sedeNameTaken(sede) {
+		return repositories.ErrDuplicateSede
+	}
+
+	sede.CreatedAt = current.CreatedAt
+	sede.UpdatedAt = time.Now()
+	stored := *sede
+	r.sedes[sede.ID] = &stored
+	return nil
+} // fin UpdateSede
+
+// ListRegionales obtiene las regionales ordenadas por código
+func (r *OrganizationRepository) ListRegionales(ctx context.Context, activeOnly bool) ([]*entities.Regional, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	regionales := []*entities.Regional{}
+	for _, regional := range r.regionales {
+		if (!activeOnly || regional.IsActive) && r.regionalInTenant(ctx, regional.ID) {
+			regionales = append(regionales, cloneRegional(regional))
+		}
+	}
+	slices.SortFunc(regionales, func(a, b *entities.Regional) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+
+	return regionales, nil
+}
+
+// ListCentros obtiene los centros ordenados por código, solo los de la regional si regionalID no es nil
+func (r *OrganizationRepository) ListCentros(ctx context.Context, regionalID *uuid.UUID, activeOnly bool) ([]*entities.Centro, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	centros := []*entities.Centro{}
+	for _, centro := range r.centros {
+		if (regionalID == nil || centro.RegionalID == *regionalID) && (!activeOnly || centro.IsActive) &&
+			r.centroInTenant(ctx, centro.ID) {
+			centros = append(centros, cloneCentro(centro))
+		}
+	}
+	slices.SortFunc(centros, func(a, b *entities.Centro) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+
+	return centros, nil
+}
+
+// ListSedes obtiene las sedes ordenadas por nombre, solo las del centro si centroID no es nil
+func (r *OrganizationRepository) ListSedes(ctx context.Context, centroID *uuid.UUID, activeOnly bool) ([]*entities.Sede, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sedes := []*entities.Sede{}
+	for _, sede := range r.sedes {
+		if (centroID == nil || sede.CentroID == *centroID) && (!activeOnly || sede.IsActive) &&
+			inTenant(ctx, r.sedePath(sede)) {
+			sedes = append(sedes, cloneSede(sede))
+		}
+	}
+	slices.SortFunc(sedes, func(a, b *entities.Sede) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return sedes, nil
+}
+
+// GetSedePath obtiene la ubicación de la sede en la jerarquía, retorna nil si no existe o está fuera del alcance
+func (r *OrganizationRepository) GetSedePath(ctx context.Context, sedeID uuid.UUID) (*repositories.SedePath, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	if path := r.path(&sedeID); inTenant(ctx, path) {
+		return path, nil
+	}
+	return nil, nil
+}
+
+// path retorna la ubicación de la sede, nil si sedeID es nil, la sede no existe o el repositorio es nil
+// Lo usan UserRepository y FichaRepository para validar sedes y resolver filtros y conteos por nivel
+func (r *OrganizationRepository) path(sedeID *uuid.UUID) *repositories.SedePath {
+	if r == nil || sedeID == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sede, found := r.sedes[*sedeID]
+	if !found {
+		return nil
+	}
+	return r.sedePath(sede)
+}
+
+// sedePath retorna la ubicación de la sede registrada; requiere el lock tomado
+func (r *OrganizationRepository) sedePath(sede *entities.Sede) *repositories.SedePath {
+	path := &repositories.SedePath{SedeID: sede.ID, CentroID: sede.CentroID}
+	if centro, found := r.centros[sede.CentroID]; found {
+		path.RegionalID = centro.RegionalID
+	}
+	return path
+}
+
+// sedeInTenant, centroInTenant y regionalInTenant indican si el nodo existe y el contexto lo ve:
+// una sede si está en el alcance y un centro o una regional si contiene alguna sede del alcance
+// Requieren el lock tomado
+func (r *OrganizationRepository) sedeInTenant(ctx context.Context, id uuid.UUID) bool {
+	sede, found := r.sedes[id]
+	return found && inTenant(ctx, r.sedePath(sede))
+}
+
+func (r *OrganizationRepository) centroInTenant(ctx context.Context, id uuid.UUID) bool {
+	_, found := r.centros[id]
+	return found && r.containsTenantSede(ctx, func(path *repositories.SedePath) bool { return path.CentroID == id })
+}
+
+func (r *OrganizationRepository) regionalInTenant(ctx context.Context, id uuid.UUID) bool {
+	_, found := r.regionales[id]
+	return found && r.containsTenantSede(ctx, func(path *repositories.SedePath) bool { return path.RegionalID == id })
+}
+
+// containsTenantSede indica si alguna sede que cumple contains está en el alcance; sin alcance siempre se cumple
+// Requiere el lock tomado
+func (r *OrganizationRepository) containsTenantSede(ctx context.Context, contains func(*repositories.SedePath) bool) bool {
+	if _, scoped, err := repositories.TenantScope(ctx); err == nil && !scoped {
+		return true
+	}
+	for _, sede := range r.sedes {
+		if path := r.sedePath(sede); contains(path) && inTenant(ctx, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSedeCentro verifica que el centro de la sede exista y que con él la sede quede en el alcance del contexto
+// Con alcance, un centro inexistente deja la sede fuera de él, como a un usuario con una sede inexistente
+// Requiere el lock tomado
+func (r *OrganizationRepository) checkSedeCentro(ctx context.Context, sede *entities.Sede) error {
+	centro, found := r.centros[sede.CentroID]
+
+	var path *repositories.SedePath
+	if found {
+		path = &repositories.SedePath{SedeID: sede.ID, CentroID: centro.ID, RegionalID: centro.RegionalID}
+	}
+	if err := repositories.CheckTenantSede(ctx, path); err != nil {
+		return err
+	}
+
+	if !found {
+		return repositories.ErrCentroNotFound
+	}
+	return nil
+} // fin checkSedeCentro
+
+// inTenant indica si la sede, dada su ruta, está en el alcance de sede del contexto; path es nil sin sede
+// Sin alcance, bypass ni marca de proceso interno no ve ninguna: los métodos públicos ya retornaron ErrNoTenant
+// Lo usan todos los repositorios en memoria que se acotan por sede
+func inTenant(ctx context.Context, path *repositories.SedePath) bool {
+	return repositories.CheckTenantSede(ctx, path) == nil
+}
+
+// hasSede indica si la sede existe; una sede nil no se valida
+func (r *OrganizationRepository) hasSede(sedeID *uuid.UUID) bool {
+	return sedeID == nil || r.path(sedeID) != nil
+}
+
+// regionalCodeTaken indica si otra regional usa el código; requiere el lock tomado
+func (r *OrganizationRepository) regionalCodeTaken(regional *entities.Regional) bool {
+	for _, other := range r.regionales {
+		if other.ID != regional.ID && other.Code == regional.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// centroCodeTaken indica si otro centro usa el código; requiere el lock tomado
+func (r *OrganizationRepository) centroCodeTaken(centro *entities.Centro) bool {
+	for _, other := range r.centros {
+		if other.ID != centro.ID && other.Code == centro.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// sedeNameTaken indica si otra sede del mismo centro usa el nombre; requiere el lock tomado
+func (r *OrganizationRepository) sedeNameTaken(sede *entities.Sede) bool {
+	for _, other := range r.sedes {
+		if other.ID != sede.ID && other.CentroID == sede.CentroID && other.Name == sede.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneRegional(regional *entities.Regional) *entities.Regional {
+	if regional == nil {
+		return nil
+	}
+	clone := *regional
+	return &clone
+}
+
+func cloneCentro(centro *entities.Centro) *entities.Centro {
+	if centro == nil {
+		return nil
+	}
+	clone := *centro
+	return &clone
+}
+
+func cloneSede(sede *entities.Sede) *entities.Sede {
+	if sede == nil {
+		return nil
+	}
+	clone := *sede
+	return &clone
+}
@@ -0,0 +1,11 @@
+package memory
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories/repositorytest"
+)
+
+func TestOrganizationRepositoryContract(t *testing.T) {
+	repositorytest.RunOrganizationRepositoryContract(t, newTestRepositories)
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.ProgramRepository = (*ProgramRepository)(nil)
+
+// ProgramRepository es la implementación en memoria de repositories.ProgramRepository
+type ProgramRepository struct {
+	mu       sync.RWMutex
+	programs map[uuid.UUID]*entities.Program
+}
+
+// NewProgramRepository crea un repositorio de programas vacío en memoria
+func NewProgramRepository() *ProgramRepository {
+	return &ProgramRepository{programs: make(map[uuid.UUID]*entities.Program)}
+}
+
+// Create registra una copia del programa
+func (r *ProgramRepository) Create(ctx context.Context, program *entities.Program) error {
+	if program.ID == uuid.Nil {
+		program.ID = uuid.New()
+	}
+	now := time.Now()
+	if program.CreatedAt.IsZero() {
+		program.CreatedAt = now
+	}
+	if program.UpdatedAt.IsZero() {
+		program.UpdatedAt = now
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.findByCode(program.Code) != nil {
+		return repositories.ErrDuplicateProgram
+	}
+
+	stored := *program
+	r.programs[program.ID] = &stored
+	return nil
+} // fin Create
+
+// GetByID obtiene un programa por su ID, retorna nil si no existe
+func (r *ProgramRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Program, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return cloneProgram(r.programs[id]), nil
+}
+
+// GetByCode obtiene un programa por su código, retorna nil si no existe
+func (r *ProgramRepository) GetByCode(ctx context.Context, code string) (*entities.Program, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return cloneProgram(r.findByCode(code)), nil
+}
+
+// Update reemplaza los datos del programa conservando su fecha de creación
+func (r *ProgramRepository) Update(ctx context.Context, program *entities.Program) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, found := r.programs[program.ID]
+	if !found {
+		return repositories.ErrProgramNotFound
+	}
+	if other := r.findByCode(program.Code); other != nil && other.ID != program.ID {
+		return repositories.ErrDuplicateProgram
+	}
+
+	program.CreatedAt = current.CreatedAt
+	program.UpdatedAt = time.Now()
+	stored := *program
+	r.programs[program.ID] = &stored
+	return nil
+} // fin Update
+
+// List obtiene los programas ordenados por código
+func (r *ProgramRepository) List(ctx context.Context, activeOnly bool) ([]*entities.Program, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	programs := []*entities.Program{}
+	for _, program := range r.programs {
+		if !activeOnly || program.IsActive {
+			programs = append(programs, cloneProgram(program))
+		}
+	}
+	slices.SortFunc(programs, func(a, b *entities.Program) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+
+	return programs, nil
+}
+
+// code retorna el código del programa, DashboardKeyNone si no existe
+// Lo usa FichaRepository para resolver el programa de cada usuario
+func (r *ProgramRepository) code(id uuid.UUID) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if program, found := r.programs[id]; found {
+		return program.Code
+	}
+	return entities.DashboardKeyNone
+}
+
+// findByCode busca el programa con el código dado; requiere el lock tomado
+func (r *ProgramRepository) findByCode(code string) *entities.Program {
+	for _, program := range r.programs {
+		if program.Code == code {
+			return program
+		}
+	}
+	return nil
+}
+
+func cloneProgram(program *entities.Program) *entities.Program {
+	if program == nil {
+		return nil
+	}
+	clone := *program
+	return &clone
+}
@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories/repositorytest"
+)
+
+// newTestRepositories crea repositorios en memoria relacionados entre sí
+func newTestRepositories(t *testing.T) repositorytest.Repositories {
+	programs := NewProgramRepository()
+	org := NewOrganizationRepository()
+	fichas := NewFichaRepository(programs, org)
+	methods, codes, sessions := NewMFAMethodRepository(), NewBackupCodeRepository(), NewMFASessionRepository()
+	return repositorytest.Repositories{
+		Users:        NewUserRepository(WithFichas(fichas), WithOrganization(org), WithMFA(methods, codes, sessions)),
+		Programs:     programs,
+		Fichas:       fichas,
+		Assignments:  NewInstructorAssignmentRepository(fichas),
+		Organization: org,
+		MFAMethods:   methods,
+		BackupCodes:  codes,
+		MFASessions:  sessions,
+		MFAPolicies:  NewMFAEnforcementPolicyRepository(),
+	}
+}
+
+func TestProgramRepositoryContract(t *testing.T) {
+	repositorytest.RunProgramRepositoryContract(t, newTestRepositories)
+}
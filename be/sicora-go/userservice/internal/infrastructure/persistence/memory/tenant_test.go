@@ -0,0 +1,11 @@
+package memory
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories/repositorytest"
+)
+
+func TestTenantContract(t *testing.T) {
+	repositorytest.RunTenantContract(t, newTestRepositories)
+}
@@ -0,0 +1,1226 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/filter"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.UserRepository = (*UserRepository)(nil)
+
+// UserRepository es la implementación de referencia en memoria de repositories.UserRepository
+// Útil para pruebas y para documentar la semántica esperada del contrato
+type UserRepository struct {
+	mu        sync.RWMutex
+	users     map[uuid.UUID]*entities.User
+	logins    map[uuid.UUID][]entities.LoginEvent     // Historial por usuario en orden de registro
+	movements map[uuid.UUID][]*entities.FichaMovement // Movimientos de ficha por usuario en orden de registro
+	bypasses  []*entities.TenantBypass                // Bitácora de bypass de sede en orden de registro
+	fichas    *FichaRepository                        // Fichas existentes; nil si no se relacionó con WithFichas
+	org       *OrganizationRepository                 // Sedes existentes; nil si no se relacionó con WithOrganization
+	mfa       *mfaRepositories                        // Datos MFA que Purge elimina en cascada; nil si no se relacionó con WithMFA
+}
+
+// mfaRepositories son los repositorios MFA cuyos registros pertenecen a un usuario
+type mfaRepositories struct {
+	methods  *MFAMethodRepository
+	codes    *BackupCodeRepository
+	sessions *MFASessionRepository
+}
+
+// Option configura el repositorio de usuarios en memoria
+type Option func(*UserRepository)
+
+// WithFichas relaciona los usuarios con el repositorio de fichas, del que derivan su programa,
+// la validación de GetByFicha y la capacidad en AssignFicha; las fichas validan a su instructor líder con los usuarios
+// Sin él ninguna ficha existe
+func WithFichas(fichas *FichaRepository) Option {
+	return func(r *UserRepository) {
+		r.fichas = fichas
+		fichas.users = r
+	}
+}
+
+// WithOrganization relaciona los usuarios con la jerarquía Regional -> Centro -> Sede,
+// con la que se valida SedeID y se resuelven los filtros y conteos por centro y regional
+// Sin ella ninguna sede existe
+func WithOrganization(org *OrganizationRepository) Option {
+	return func(r *UserRepository) {
+		r.org = org
+	}
+}
+
+// WithMFA relaciona los usuarios con sus métodos, códigos de recuperación y sesiones MFA,
+// que Purge elimina con el usuario como lo hace la llave foránea ON DELETE CASCADE
+func WithMFA(methods *MFAMethodRepository, codes *BackupCodeRepository, sessions *MFASessionRepository) Option {
+	return func(r *UserRepository) {
+		r.mfa = &mfaRepositories{methods: methods, codes: codes, sessions: sessions}
+	}
+}
+
+// NewUserRepository crea un repositorio de usuarios vacío en memoria
+func NewUserRepository(opts ...Option) *UserRepository {
+	r := &UserRepository{
+		users:     make(map[uuid.UUID]*entities.User),
+		logins:    make(map[uuid.UUID][]entities.LoginEvent),
+		movements: make(map[uuid.UUID][]*entities.FichaMovement),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Create crea un nuevo usuario en el repositorio
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.insert(ctx, user)
+}
+
+// GetByID obtiene un usuario por su ID, retorna nil si no existe
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return cloneUser(r.findByID(ctx, id)), nil
+}
+
+// GetByEmail obtiene un usuario por su email, retorna nil si no existe
+func (r *UserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return cloneUser(r.findByEmail(ctx, email)), nil
+}
+
+// GetByDocument obtiene un usuario por su tipo y número de documento, retorna nil si no existe
+func (r *UserRepository) GetByDocument(ctx context.Context, documentType entities.DocumentType, documentNumber string) (*entities.User, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	documentNumber = entities.NormalizeDocumentNumber(documentNumber)
+	for _, user := range r.users {
+		if !user.IsDeleted() && user.DocumentType == documentType && user.DocumentNumber == documentNumber && r.visible(ctx, user) {
+			return cloneUser(user), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Update actualiza los datos de un usuario existente
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.replace(ctx, user)
+}
+
+// Delete marca un usuario como eliminado (soft delete) y lo desactiva
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.findByID(ctx, id)
+	if user == nil {
+		return repositories.ErrUserNotFound
+	}
+
+	user.MarkAsDeleted(deletedBy)
+	user.Version++
+	return nil
+}
+
+// Restore revierte el soft delete de un usuario y lo reactiva
+func (r *UserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || !user.IsDeleted() || !r.visible(ctx, user) {
+		return repositories.ErrUserNotFound
+	}
+
+	if err := r.conflicts(user); err != nil {
+		return err
+	}
+
+	user.Restore()
+	user.Version++
+	return nil
+}
+
+// Purge elimina definitivamente los usuarios eliminados antes de la fecha dada
+// El historial de inicios de sesión, los movimientos de ficha y los datos MFA se eliminan con el usuario
+func (r *UserRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for id, user := range r.users {
+		if user.IsDeleted() && user.DeletedAt.Before(deletedBefore) && r.visible(ctx, user) {
+			delete(r.users, id)
+			delete(r.logins, id)
+			delete(r.movements, id)
+			r.purgeMFA(ctx, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// purgeMFA elimina los métodos, códigos y sesiones MFA del usuario purgado
+func (r *UserRepository) purgeMFA(ctx context.Context, userID uuid.UUID) {
+	if r.mfa == nil {
+		return
+	}
+	_ = r.mfa.methods.DeleteByUserID(ctx, userID)
+	_ = r.mfa.codes.DeleteByUserID(ctx, userID)
+	r.mfa.sessions.deleteByUserID(userID)
+}
+
+// List obtiene una lista paginada de usuarios con filtros opcionales
+func (r *UserRepository) List(ctx context.Context, filters repositories.UserFilters) (*repositories.PaginatedUsers, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := filters.Normalize(); err != nil {
+		return nil, err
+	}
+
+	matched := r.matching(ctx, filters)
+
+	if filters.Pagination == repositories.PaginationCursor {
+		return cursorPage(matched, filters)
+	}
+
+	total := int64(len(matched))
+	start := min(filters.Offset(), len(matched))
+	end := min(start+filters.PageSize, len(matched))
+
+	return repositories.NewPaginatedUsers(matched[start:end], total, filters.Page, filters.PageSize), nil
+} // fin List
+
+// Stream recorre los usuarios que cumplen los filtros sobre una copia tomada al inicio
+func (r *UserRepository) Stream(ctx context.Context, filters repositories.UserFilters) iter.Seq2[*entities.User, error] {
+	return func(yield func(*entities.User, error) bool) {
+		if err := repositories.RequireTenant(ctx); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		filters.Pagination, filters.Cursor = repositories.PaginationOffset, ""
+		if err := filters.Normalize(); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for _, user := range r.matching(ctx, filters) {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(user, nil) {
+				return
+			}
+		}
+	}
+} // fin Stream
+
+// matching retorna copias de los usuarios del alcance de sede que cumplen los filtros normalizados, en el orden del listado
+func (r *UserRepository) matching(ctx context.Context, filters repositories.UserFilters) []*entities.User {
+	tokens := filters.SearchTokens()
+	scores := make(map[uuid.UUID]int)
+
+	r.mu.RLock()
+	matched := make([]*entities.User, 0, len(r.users))
+	for _, user := range r.users {
+		if !matchesFilters(user, filters) {
+			continue
+		}
+
+		if filters.Programa != nil && !r.inProgram(user, *filters.Programa) {
+			continue
+		}
+
+		if !filters.OrgFilter.Matches(r.org.path(user.SedeID)) || !r.visible(ctx, user) {
+			continue
+		}
+
+		if len(tokens) > 0 {
+			score := repositories.SearchScore(user, tokens)
+			if score == 0 {
+				continue
+			}
+			scores[user.ID] = score
+		}
+
+		matched = append(matched, cloneUser(user))
+	}
+	r.mu.RUnlock()
+
+	if filters.SortsByRelevance() {
+		sortByRelevance(matched, scores)
+	} else {
+		sortUsers(matched, filters.Sort)
+	}
+
+	return matched
+} // fin matching
+
+// GetByFicha obtiene todos los aprendices de una ficha específica, actuales o en la fecha dada
+func (r *UserRepository) GetByFicha(ctx context.Context, fichaID string, on time.Time) ([]*entities.User, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	if r.fichas.lookup(ctx, fichaID) == nil {
+		return nil, repositories.ErrFichaNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []*entities.User{}
+	for _, user := range r.users {
+		if user.IsDeleted() || !user.IsAprendiz() || !r.visible(ctx, user) {
+			continue
+		}
+
+		current := user.FichaID
+		if !on.IsZero() {
+			current = nil
+			if movement := r.lastMovement(user.ID, on); movement != nil {
+				current = &movement.ToFichaID
+			}
+		}
+
+		if current != nil && *current == fichaID {
+			users = append(users, cloneUser(user))
+		}
+	}
+
+	sortUsers(users, []filter.SortField{{Field: "last_name"}, {Field: "first_name"}})
+	return users, nil
+} // fin GetByFicha
+
+// AssignFicha asigna el aprendiz sin ficha a la ficha respetando su estado y capacidad
+func (r *UserRepository) AssignFicha(ctx context.Context, userID uuid.UUID, fichaID string) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.findByID(ctx, userID)
+	if user != nil && user.FichaID != nil {
+		if *user.FichaID == fichaID {
+			return nil
+		}
+		return repositories.ErrAlreadyInFicha
+	}
+
+	if err := repositories.CheckFichaAssignment(user, r.fichas.lookup(ctx, fichaID), r.countMembers(fichaID)); err != nil {
+		return err
+	}
+
+	enrollment := entities.NewFichaEnrollment(userID, fichaID, time.Now())
+	r.movements[userID] = append(r.movements[userID], enrollment)
+	r.moveToFicha(user, fichaID)
+	return nil
+} // fin AssignFicha
+
+// TransferFicha traslada al aprendiz a otra ficha y registra el traslado en su historial
+func (r *UserRepository) TransferFicha(ctx context.Context, transfer *entities.FichaMovement) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Quien aprueba puede ser de otra sede, p. ej. un coordinador del centro
+	var approver *entities.User
+	if transfer.ApprovedBy != nil {
+		if found, ok := r.users[*transfer.ApprovedBy]; ok && !found.IsDeleted() {
+			approver = found
+		}
+	}
+
+	var last *entities.FichaMovement
+	if history := r.movements[transfer.UserID]; len(history) > 0 {
+		last = history[len(history)-1]
+	}
+
+	user := r.findByID(ctx, transfer.UserID)
+	err := repositories.CheckFichaTransfer(transfer, user, approver, last,
+		r.fichas.lookup(ctx, transfer.ToFichaID), r.countMembers(transfer.ToFichaID))
+	if err != nil {
+		return err
+	}
+
+	stored := *transfer
+	r.movements[user.ID] = append(r.movements[user.ID], &stored)
+	r.moveToFicha(user, transfer.ToFichaID)
+	return nil
+} // fin TransferFicha
+
+// GetFichaHistory obtiene los movimientos de ficha del aprendiz del más antiguo al más reciente
+func (r *UserRepository) GetFichaHistory(ctx context.Context, userID uuid.UUID) ([]*entities.FichaMovement, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.findByID(ctx, userID) == nil {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	history := make([]*entities.FichaMovement, 0, len(r.movements[userID]))
+	for _, movement := range r.movements[userID] {
+		clone := *movement
+		history = append(history, &clone)
+	}
+	return history, nil
+} // fin GetFichaHistory
+
+// countMembers cuenta los aprendices actuales de la ficha
+func (r *UserRepository) countMembers(fichaID string) int {
+	members := 0
+	for _, member := range r.users {
+		if !member.IsDeleted() && member.IsAprendiz() && member.FichaID != nil && *member.FichaID == fichaID {
+			members++
+		}
+	}
+	return members
+}
+
+// moveToFicha cambia la ficha actual del usuario incrementando Version
+func (r *UserRepository) moveToFicha(user *entities.User, fichaID string) {
+	user.FichaID = &fichaID
+	user.UpdatedAt = time.Now()
+	user.Version++
+}
+
+// lastMovement retorna el último movimiento del usuario efectivo en la fecha dada, nil si no tiene
+// El historial no admite fechas efectivas anteriores al último movimiento, así que el orden de registro es cronológico
+func (r *UserRepository) lastMovement(userID uuid.UUID, on time.Time) *entities.FichaMovement {
+	day := entities.CivilDate(on)
+	var last *entities.FichaMovement
+	for _, movement := range r.movements[userID] {
+		if movement.EffectiveDate.After(day) {
+			break
+		}
+		last = movement
+	}
+	return last
+}
+
+// ExistsByEmail verifica si existe un usuario con el email dado
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email entities.Email) (bool, error) {
+	user, err := r.GetByEmail(ctx, email)
+	return user != nil, err
+}
+
+// ExistsByDocument verifica si existe un usuario con el tipo y número de documento dados
+func (r *UserRepository) ExistsByDocument(ctx context.Context, documentType entities.DocumentType, documentNumber string) (bool, error) {
+	user, err := r.GetByDocument(ctx, documentType, documentNumber)
+	return user != nil, err
+}
+
+// BulkCreate crea múltiples usuarios reportando el resultado por fila
+func (r *UserRepository) BulkCreate(ctx context.Context, users []*entities.User, mode repositories.BulkMode) (*repositories.BulkOperationResult, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// En modo atómico se insertan copias: si el lote se revierte, los usuarios dados quedan sin cambios
+	rows := users
+	if mode == repositories.BulkAtomic {
+		rows = make([]*entities.User, len(users))
+		for i, user := range users {
+			rows[i] = cloneUser(user)
+		}
+	}
+
+	result := &repositories.BulkOperationResult{Total: len(users)}
+	created := make([]uuid.UUID, 0, len(users))
+	for index, user := range rows {
+		err := r.insert(ctx, user)
+		if err == nil {
+			created = append(created, user.ID)
+		}
+		result.RecordUser(index, user, err)
+	}
+
+	if mode != repositories.BulkAtomic {
+		return result, nil
+	}
+
+	if result.Failed > 0 {
+		for _, id := range created {
+			delete(r.users, id)
+		}
+		result.RollBack()
+		return result, nil
+	}
+
+	for i, user := range rows {
+		*users[i] = *user
+	}
+	return result, nil
+} // fin BulkCreate
+
+// BulkUpdate actualiza múltiples usuarios identificados por email
+func (r *UserRepository) BulkUpdate(ctx context.Context, updates map[entities.Email]*entities.User) (*repositories.BulkOperationResult, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]entities.Email, 0, len(updates))
+	for email := range updates {
+		keys = append(keys, email)
+	}
+	slices.Sort(keys)
+
+	result := &repositories.BulkOperationResult{Total: len(keys)}
+	for index, email := range keys {
+		var err error
+		if existing := r.findByEmail(ctx, email); existing == nil {
+			err = repositories.ErrUserNotFound
+		} else {
+			user := updates[email]
+			user.ID = existing.ID
+			user.CreatedAt = existing.CreatedAt
+			err = r.replace(ctx, user)
+		}
+
+		result.Record(index, email.String(), err)
+	}
+
+	return result, nil
+} // fin BulkUpdate
+
+// BulkDelete elimina (soft delete) múltiples usuarios por emails
+func (r *UserRepository) BulkDelete(ctx context.Context, emails []entities.Email, deletedBy *uuid.UUID) (*repositories.BulkOperationResult, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.bulkApply(ctx, emails, func(user *entities.User) {
+		user.MarkAsDeleted(deletedBy)
+	}), nil
+}
+
+// BulkStatusChange cambia el estado de múltiples usuarios
+func (r *UserRepository) BulkStatusChange(ctx context.Context, emails []entities.Email, isActive bool) (*repositories.BulkOperationResult, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.bulkApply(ctx, emails, func(user *entities.User) {
+		user.IsActive = isActive
+		user.UpdatedAt = time.Now()
+	}), nil
+}
+
+// GetMultipleByEmails obtiene múltiples usuarios por sus emails
+func (r *UserRepository) GetMultipleByEmails(ctx context.Context, emails []entities.Email) ([]*entities.User, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []*entities.User{}
+	seen := make(map[uuid.UUID]bool, len(emails))
+	for _, email := range emails {
+		if user := r.findByEmail(ctx, email); user != nil && !seen[user.ID] {
+			seen[user.ID] = true
+			users = append(users, cloneUser(user))
+		}
+	}
+
+	return users, nil
+}
+
+// RecordLogin guarda el inicio de sesión y actualiza LastLogin si es el más reciente
+func (r *UserRepository) RecordLogin(ctx context.Context, event *entities.LoginEvent) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.findByID(ctx, event.UserID)
+	if user == nil {
+		return repositories.ErrUserNotFound
+	}
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	r.logins[user.ID] = append(r.logins[user.ID], *event)
+	if user.LastLogin == nil || event.OccurredAt.After(*user.LastLogin) {
+		at := event.OccurredAt
+		user.LastLogin = &at
+	}
+
+	return nil
+} // fin RecordLogin
+
+// GetLoginHistory obtiene los inicios de sesión del usuario del más reciente al más antiguo
+func (r *UserRepository) GetLoginHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.LoginEvent, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.findByID(ctx, userID) == nil {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	stored := r.logins[userID]
+	history := make([]*entities.LoginEvent, 0, len(stored))
+	for i := range stored {
+		event := stored[i]
+		history = append(history, &event)
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].OccurredAt.After(history[j].OccurredAt)
+	})
+
+	if limit > 0 && len(history) > limit {
+		history = history[:limit]
+	}
+	return history, nil
+} // fin GetLoginHistory
+
+// RecordTenantBypass guarda una copia del bypass de sede en la bitácora tras verificar el rol, la sede y el estado del actor
+// El actor se busca sin el alcance del contexto: un directivo nacional no tiene sede
+func (r *UserRepository) RecordTenantBypass(ctx context.Context, bypass *entities.TenantBypass) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := repositories.CheckTenantBypassActor(r.users[bypass.ActorID]); err != nil {
+		return err
+	}
+
+	if bypass.ID == uuid.Nil {
+		bypass.ID = uuid.New()
+	}
+	if bypass.GrantedAt.IsZero() {
+		bypass.GrantedAt = time.Now()
+	}
+
+	stored := *bypass
+	r.bypasses = append(r.bypasses, &stored)
+	return nil
+}
+
+// ListTenantBypasses obtiene los bypass de sede concedidos desde since, del más reciente al más antiguo
+// Con alcance de sede solo ve los de actores del alcance
+func (r *UserRepository) ListTenantBypasses(ctx context.Context, since time.Time) ([]*entities.TenantBypass, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bypasses := []*entities.TenantBypass{}
+	for _, bypass := range r.bypasses {
+		// Un actor purgado solo se ve sin alcance, como un usuario sin sede
+		var sedeID *uuid.UUID
+		if actor, found := r.users[bypass.ActorID]; found {
+			sedeID = actor.SedeID
+		}
+		if !bypass.GrantedAt.Before(since) && inTenant(ctx, r.org.path(sedeID)) {
+			clone := *bypass
+			bypasses = append(bypasses, &clone)
+		}
+	}
+	sort.SliceStable(bypasses, func(i, j int) bool {
+		return bypasses[i].GrantedAt.After(bypasses[j].GrantedAt)
+	})
+
+	return bypasses, nil
+}
+
+// GetTotalUsersByRole obtiene el conteo de usuarios por rol
+func (r *UserRepository) GetTotalUsersByRole(ctx context.Context) (map[string]int, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, user := range r.users {
+		if !user.IsDeleted() && r.visible(ctx, user) {
+			counts[string(user.Role)]++
+		}
+	}
+
+	return counts, nil
+}
+
+// GetTotalUsersByProgram obtiene el conteo de usuarios por código del programa de su ficha
+func (r *UserRepository) GetTotalUsersByProgram(ctx context.Context) (map[string]int, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, user := range r.users {
+		if user.IsDeleted() || !r.visible(ctx, user) {
+			continue
+		}
+		if code := r.fichas.programCode(user.FichaID); code != entities.DashboardKeyNone {
+			counts[code]++
+		}
+	}
+
+	return counts, nil
+}
+
+// GetTotalUsersByOrgLevel obtiene el conteo de usuarios por nodo del nivel al que pertenece su sede
+func (r *UserRepository) GetTotalUsersByOrgLevel(ctx context.Context, level repositories.OrgLevel, scope repositories.OrgFilter) (map[string]int, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	if !level.Valid() {
+		return nil, fmt.Errorf("%w: %q", repositories.ErrInvalidOrgLevel, level)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, user := range r.users {
+		if user.IsDeleted() || !scope.Matches(r.org.path(user.SedeID)) || !r.visible(ctx, user) {
+			continue
+		}
+		if key := r.orgKey(user, level); key != repositories.TrendKeyNone {
+			counts[key]++
+		}
+	}
+
+	return counts, nil
+} // fin GetTotalUsersByOrgLevel
+
+// GetUserRegistrationTrend obtiene la serie de registros por intervalo en la zona de la consulta
+func (r *UserRepository) GetUserRegistrationTrend(ctx context.Context, query repositories.RegistrationTrendQuery) (*repositories.RegistrationTrend, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	trend := repositories.NewRegistrationTrend(query)
+	for _, user := range r.users {
+		if user.IsDeleted() || !query.OrgFilter.Matches(r.org.path(user.SedeID)) || !r.visible(ctx, user) {
+			continue
+		}
+
+		key := repositories.UserTrendKey(user, query.Breakdown)
+		switch query.Breakdown {
+		case repositories.BreakdownProgram:
+			key = r.fichas.programCode(user.FichaID)
+		case repositories.BreakdownCentro:
+			key = r.orgKey(user, repositories.OrgLevelCentro)
+		case repositories.BreakdownRegional:
+			key = r.orgKey(user, repositories.OrgLevelRegional)
+		}
+		trend.Add(user.CreatedAt, key, 1)
+	}
+
+	return trend, nil
+} // fin GetUserRegistrationTrend
+
+// GetActiveInactiveCount obtiene el conteo de usuarios activos e inactivos
+func (r *UserRepository) GetActiveInactiveCount(ctx context.Context) (active int, inactive int, err error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return 0, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		switch {
+		case user.IsDeleted() || !r.visible(ctx, user):
+		case user.IsActive:
+			active++
+		default:
+			inactive++
+		}
+	}
+
+	return active, inactive, nil
+}
+
+// AggregateDashboard cruza los usuarios no eliminados por rol, programa, sede y estado
+// El programa es el de la ficha del usuario; sin ficha o sin programa la celda queda con DashboardKeyNone
+func (r *UserRepository) AggregateDashboard(ctx context.Context) (*entities.DashboardSnapshot, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := entities.NewDashboardSnapshot(time.Now())
+	for _, user := range r.users {
+		if !user.IsDeleted() && r.visible(ctx, user) {
+			sede := repositories.UserTrendKey(user, repositories.BreakdownSede)
+			program := r.fichas.programCode(user.FichaID)
+			snapshot.Add(string(user.Role), program, sede, entities.DashboardStatus(user), 1)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// GetActivityMetrics obtiene las métricas de actividad a la fecha de corte
+func (r *UserRepository) GetActivityMetrics(ctx context.Context, query repositories.ActivityQuery) (*repositories.ActivityReport, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	report := repositories.NewActivityReport(query)
+	for _, user := range r.users {
+		if user.IsDeleted() || user.CreatedAt.After(query.At) || !query.OrgFilter.Matches(r.org.path(user.SedeID)) ||
+			!r.visible(ctx, user) {
+			continue
+		}
+
+		key := repositories.UserActivityKey(user, query.Segment)
+		switch query.Segment {
+		case repositories.SegmentCentro:
+			key = r.orgKey(user, repositories.OrgLevelCentro)
+		case repositories.SegmentRegional:
+			key = r.orgKey(user, repositories.OrgLevelRegional)
+		}
+		counts := query.Classify(r.lastSeen(user, query.At), user.IsActive)
+		report.Add(key, counts)
+	}
+
+	return report, nil
+} // fin GetActivityMetrics
+
+// lastSeen retorna el último inicio de sesión del usuario hasta at, según el historial y LastLogin
+func (r *UserRepository) lastSeen(user *entities.User, at time.Time) *time.Time {
+	var last *time.Time
+	if user.LastLogin != nil && !user.LastLogin.After(at) {
+		last = user.LastLogin
+	}
+
+	for i, event := range r.logins[user.ID] {
+		if !event.OccurredAt.After(at) && (last == nil || event.OccurredAt.After(*last)) {
+			last = &r.logins[user.ID][i].OccurredAt
+		}
+	}
+
+	return last
+}
+
+// insert guarda una copia del usuario validando unicidad y alcance de sede, requiere el lock de escritura
+func (r *UserRepository) insert(ctx context.Context, user *entities.User) error {
+	user.Email = user.Email.Canonical()
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+
+	if _, ok := r.users[user.ID]; ok {
+		return &repositories.DuplicateUserError{Field: "id"}
+	}
+
+	if err := r.conflicts(user); err != nil {
+		return err
+	}
+
+	if err := r.checkTenantSede(ctx, user.SedeID); err != nil {
+		return err
+	}
+
+	if !r.org.hasSede(user.SedeID) {
+		return repositories.ErrSedeNotFound
+	}
+
+	if user.Version < 1 {
+		user.Version = 1
+	}
+
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+
+	r.users[user.ID] = cloneUser(user)
+	return nil
+} // fin insert
+
+// replace reemplaza un usuario existente conservando su fecha de creación
+// Falla con *VersionConflictError si la versión no coincide con la almacenada
+// y con ErrOutsideTenant si la nueva sede no está en el alcance de sede del contexto
+func (r *UserRepository) replace(ctx context.Context, user *entities.User) error {
+	user.Email = user.Email.Canonical()
+	existing := r.findByID(ctx, user.ID)
+	if existing == nil {
+		return repositories.ErrUserNotFound
+	}
+
+	if err := r.checkTenantSede(ctx, user.SedeID); err != nil {
+		return err
+	}
+
+	if existing.Version != user.Version {
+		return &repositories.VersionConflictError{
+			UserID:          user.ID,
+			ExpectedVersion: user.Version,
+			CurrentVersion:  existing.Version,
+		}
+	}
+
+	if err := r.conflicts(user); err != nil {
+		return err
+	}
+
+	// Solo se valida la sede si cambia: puede haber sedes anteriores a la jerarquía
+	if !sameSede(existing.SedeID, user.SedeID) && !r.org.hasSede(user.SedeID) {
+		return repositories.ErrSedeNotFound
+	}
+
+	user.Version++
+	user.CreatedAt = existing.CreatedAt
+	user.UpdatedAt = time.Now()
+	user.DeletedAt = nil
+	user.DeletedBy = nil
+
+	// LastLogin solo lo cambia RecordLogin, que no cambia la versión
+	stored := cloneUser(user)
+	stored.LastLogin = existing.LastLogin
+	if stored.Password == "" {
+		// Un usuario leído sin credenciales conserva su contraseña
+		stored.Password = existing.Password
+	}
+	r.users[user.ID] = stored
+	return nil
+}
+
+// conflicts retorna *DuplicateUserError si otro usuario no eliminado ya usa el email o documento
+func (r *UserRepository) conflicts(user *entities.User) error {
+	for id, other := range r.users {
+		if id == user.ID || other.IsDeleted() {
+			continue
+		}
+		if other.Email == user.Email {
+			return &repositories.DuplicateUserError{Field: "email"}
+		}
+		if other.DocumentType == user.DocumentType && other.DocumentNumber == user.DocumentNumber {
+			return &repositories.DuplicateUserError{Field: "document_number"}
+		}
+	}
+	return nil
+}
+
+// get retorna una copia del usuario no eliminado sin el alcance de sede, nil si no existe
+// La usan fichas y asignaciones para validar instructores, que no se acotan por sede
+func (r *UserRepository) get(id uuid.UUID) *entities.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if user, ok := r.users[id]; ok && !user.IsDeleted() {
+		return cloneUser(user)
+	}
+	return nil
+}
+
+// findByID retorna el usuario no eliminado del alcance de sede con el ID dado
+func (r *UserRepository) findByID(ctx context.Context, id uuid.UUID) *entities.User {
+	if user, ok := r.users[id]; ok && !user.IsDeleted() && r.visible(ctx, user) {
+		return user
+	}
+	return nil
+}
+
+// findByEmail retorna el usuario no eliminado del alcance de sede con el email dado
+func (r *UserRepository) findByEmail(ctx context.Context, email entities.Email) *entities.User {
+	email = email.Canonical()
+	for _, user := range r.users {
+		if !user.IsDeleted() && user.Email == email && r.visible(ctx, user) {
+			return user
+		}
+	}
+	return nil
+}
+
+// visible indica si la sede del usuario está en el alcance de sede del contexto
+// Sin alcance, bypass ni marca de proceso interno no ve a nadie; los métodos públicos ya retornaron ErrNoTenant
+func (r *UserRepository) visible(ctx context.Context, user *entities.User) bool {
+	return inTenant(ctx, r.org.path(user.SedeID))
+}
+
+// checkTenantSede retorna ErrOutsideTenant si la sede no está en el alcance de sede del contexto
+func (r *UserRepository) checkTenantSede(ctx context.Context, sedeID *uuid.UUID) error {
+	return repositories.CheckTenantSede(ctx, r.org.path(sedeID))
+}
+
+// bulkApply aplica la mutación a cada email y reporta el resultado por fila
+func (r *UserRepository) bulkApply(ctx context.Context, emails []entities.Email, mutate func(*entities.User)) *repositories.BulkOperationResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &repositories.BulkOperationResult{Total: len(emails)}
+	for index, email := range emails {
+		var err error
+		if user := r.findByEmail(ctx, email); user != nil {
+			mutate(user)
+			user.Version++
+		} else {
+			err = repositories.ErrUserNotFound
+		}
+
+		result.Record(index, email.String(), err)
+	}
+
+	return result
+}
+
+// inProgram indica si la ficha del usuario pertenece al programa con el código dado
+func (r *UserRepository) inProgram(user *entities.User, code string) bool {
+	program := r.fichas.programCode(user.FichaID)
+	return program != entities.DashboardKeyNone && program == code
+}
+
+// orgKey retorna el ID del nodo del nivel al que pertenece la sede del usuario, TrendKeyNone sin sede
+// En el nivel sede es el SedeID guardado, como en UserTrendKey, aunque la sede no esté registrada
+func (r *UserRepository) orgKey(user *entities.User, level repositories.OrgLevel) string {
+	if level == repositories.OrgLevelSede {
+		return repositories.UserTrendKey(user, repositories.BreakdownSede)
+	}
+	return r.org.path(user.SedeID).Key(level)
+}
+
+// sameSede indica si ambas referencias apuntan a la misma sede o ninguna tiene sede
+func sameSede(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// matchesFilters evalúa los filtros de UserFilters sobre un usuario, salvo la búsqueda que se puntúa aparte
+func matchesFilters(user *entities.User, filters repositories.UserFilters) bool {
+	switch filters.Deleted {
+	case repositories.DeletedInclude:
+	case repositories.DeletedOnly:
+		if !user.IsDeleted() {
+			return false
+		}
+	default:
+		if user.IsDeleted() {
+			return false
+		}
+	}
+
+	if filters.Rol != nil && user.Role != *filters.Rol {
+		return false
+	}
+
+	if filters.FichaID != nil && (user.FichaID == nil || *user.FichaID != *filters.FichaID) {
+		return false
+	}
+
+	if filters.IsActive != nil && user.IsActive != *filters.IsActive {
+		return false
+	}
+
+	if filters.Filter != nil {
+		return filter.Evaluate(filters.Filter, func(field string) filter.Value {
+			return repositories.UserFieldValue(user, field)
+		})
+	}
+
+	return true
+} // fin matchesFilters
+
+// sortByRelevance ordena por puntaje descendente y luego por apellido, nombre (normalizados) e ID
+func sortByRelevance(users []*entities.User, scores map[uuid.UUID]int) {
+	sort.SliceStable(users, func(i, j int) bool {
+		a, b := users[i], users[j]
+		if scores[a.ID] != scores[b.ID] {
+			return scores[a.ID] > scores[b.ID]
+		}
+		if cmp := strings.Compare(repositories.FoldSearchText(a.LastName), repositories.FoldSearchText(b.LastName)); cmp != 0 {
+			return cmp < 0
+		}
+		if cmp := strings.Compare(repositories.FoldSearchText(a.FirstName), repositories.FoldSearchText(b.FirstName)); cmp != 0 {
+			return cmp < 0
+		}
+		return a.ID.String() < b.ID.String()
+	})
+}
+
+// sortUsers ordena por las columnas indicadas usando el ID como desempate
+func sortUsers(users []*entities.User, fields []filter.SortField) {
+	sort.SliceStable(users, func(i, j int) bool {
+		return compareInOrder(users[i], users[j].ID, userSortKeys(users[j], fields), fields) < 0
+	})
+}
+
+// userSortKeys retorna los valores del usuario para cada columna de ordenamiento
+func userSortKeys(user *entities.User, fields []filter.SortField) []filter.Value {
+	keys := make([]filter.Value, len(fields))
+	for i, field := range fields {
+		keys[i] = repositories.UserFieldValue(user, field.Field)
+	}
+	return keys
+}
+
+// compareInOrder compara al usuario contra una posición (llaves + ID) según el orden del listado
+// Negativo si el usuario va antes; el ID desempata en la dirección de la última columna
+func compareInOrder(user *entities.User, id uuid.UUID, keys []filter.Value, fields []filter.SortField) int {
+	desc := false
+	for i, field := range fields {
+		desc = field.Desc
+		cmp := repositories.UserFieldValue(user, field.Field).Compare(keys[i])
+		if cmp != 0 {
+			if desc {
+				return -cmp
+			}
+			return cmp
+		}
+	}
+
+	cmp := strings.Compare(user.ID.String(), id.String())
+	if desc {
+		return -cmp
+	}
+	return cmp
+} // fin compareInOrder
+
+// cursorPage recorta la página por cursor de un listado ya ordenado
+func cursorPage(sorted []*entities.User, filters repositories.UserFilters) (*repositories.PaginatedUsers, error) {
+	cursor, err := filters.ValidateCursorMode()
+	if err != nil {
+		return nil, err
+	}
+
+	limit := filters.PageSize + 1
+	if cursor == nil {
+		return repositories.NewCursorPage(sorted[:min(limit, len(sorted))], filters, nil), nil
+	}
+
+	// Posición del primer usuario que va después del cursor en el orden del listado
+	split := sort.Search(len(sorted), func(i int) bool {
+		return compareInOrder(sorted[i], cursor.ID, cursor.Keys(), filters.Sort) > 0
+	})
+
+	if cursor.Backward {
+		// Antes del cursor quedan las filas estrictamente anteriores; el propio cursor se excluye
+		end := split
+		if end > 0 && compareInOrder(sorted[end-1], cursor.ID, cursor.Keys(), filters.Sort) == 0 {
+			end--
+		}
+		return repositories.NewCursorPage(sorted[max(0, end-limit):end], filters, cursor), nil
+	}
+
+	return repositories.NewCursorPage(sorted[split:min(split+limit, len(sorted))], filters, cursor), nil
+} // fin cursorPage
+
+// cloneUser retorna una copia para que los llamadores no muten el estado interno
+func cloneUser(user *entities.User) *entities.User {
+	if user == nil {
+		return nil
+	}
+
+	clone := *user
+	return &clone
+}
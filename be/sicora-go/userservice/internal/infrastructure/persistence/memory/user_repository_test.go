@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories"
+	"userservice/internal/domain/repositories/repositorytest"
+)
+
+func TestUserRepositoryContract(t *testing.T) {
+	repositorytest.RunUserRepositoryContract(t, func(t *testing.T) repositories.UserRepository {
+		return NewUserRepository()
+	})
+}
@@ -0,0 +1,53 @@
+// Package postgres conecta los repositorios de relational con PostgreSQL, el motor de producción
+package postgres
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"userservice/internal/infrastructure/config"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// NewConnection abre la conexión a PostgreSQL y configura el pool de conexiones
+func NewConnection(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(buildDSN(cfg)), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("error conectando a postgres: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("error verificando conexión a postgres: %w", err)
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
+
+	return db, nil
+} // fin NewConnection
+
+// buildDSN arma la URL de conexión; url.UserPassword y url.Values escapan los valores,
+// así una contraseña con espacios, comillas o "=" no rompe la conexión ni agrega parámetros
+func buildDSN(cfg config.DatabaseConfig) string {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(cfg.User, cfg.Password),
+		Host:   net.JoinHostPort(cfg.Host, cfg.Port),
+		Path:   "/" + cfg.DBName,
+		RawQuery: url.Values{
+			"sslmode":  {cfg.SSLMode},
+			"TimeZone": {"America/Bogota"},
+		}.Encode(),
+	}
+	return dsn.String()
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"testing"
+
+	"userservice/internal/infrastructure/config"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestBuildDSNEscapesValues(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		Host:     "db.sena.local",
+		Port:     "5433",
+		User:     "sicora app",
+		Password: `p@ss word='x' sslmode=disable&dbname=otra/#?`,
+		DBName:   "userservice",
+		SSLMode:  "require",
+	}
+
+	parsed, err := pgconn.ParseConfig(buildDSN(cfg))
+	if err != nil {
+		t.Fatalf("ParseConfig(%q): %v", buildDSN(cfg), err)
+	}
+
+	if parsed.Host != cfg.Host || parsed.Port != 5433 || parsed.User != cfg.User || parsed.Database != cfg.DBName {
+		t.Errorf("conexión = %s:%d %s/%s", parsed.Host, parsed.Port, parsed.User, parsed.Database)
+	}
+	if parsed.Password != cfg.Password {
+		t.Errorf("contraseña = %q, se esperaba %q", parsed.Password, cfg.Password)
+	}
+	if parsed.TLSConfig == nil {
+		t.Error("sslmode=require: se esperaba TLS; la contraseña no debe poder cambiar sslmode")
+	}
+	if got := parsed.RuntimeParams["TimeZone"]; got != "America/Bogota" {
+		t.Errorf("TimeZone = %q", got)
+	}
+} // fin TestBuildDSNEscapesValues
@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories"
+	"userservice/internal/domain/repositories/repositorytest"
+	"userservice/internal/infrastructure/persistence/relational"
+)
+
+func TestDashboardSnapshotRepositoryContract(t *testing.T) {
+	db := openTestDB(t)
+
+	repositorytest.RunDashboardSnapshotRepositoryContract(t, func(t *testing.T) repositories.DashboardSnapshotRepository {
+		if err := db.Exec("TRUNCATE userservice.dashboard_snapshots CASCADE").Error; err != nil {
+			t.Fatalf("limpiando fotos: %v", err)
+		}
+		return relational.NewDashboardSnapshotRepository(db)
+	})
+}
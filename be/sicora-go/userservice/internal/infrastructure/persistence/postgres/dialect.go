@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"errors"
+
+	"userservice/internal/infrastructure/persistence/relational"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Verificar que implementa la interfaz
+var _ relational.Dialect = Dialect{}
+
+// SQLSTATE de PostgreSQL para violaciones de unicidad y de llave foránea
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+)
+
+// uniqueConstraintFields mapea los índices únicos de usuarios al campo del dominio
+var uniqueConstraintFields = map[string]string{
+	"users_pkey":                  "id",
+	"uq_users_email_lower_active": "email",
+	"uq_users_document_active":    "document_number",
+}
+
+// Dialect implementa relational.Dialect para PostgreSQL
+type Dialect struct{}
+
+// UniqueViolation identifica el índice violado por el nombre de la restricción
+func (Dialect) UniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return uniqueConstraintFields[pgErr.ConstraintName], true
+	}
+	return "", false
+}
+
+// SedeViolation identifica la llave foránea por el nombre de la restricción
+func (Dialect) SedeViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode && pgErr.ConstraintName == "fk_users_sede"
+}
+
+// SearchFold usa la función de la migración 005, que vive en el esquema del servicio
+func (Dialect) SearchFold(expr string) string {
+	return "userservice.search_fold(" + expr + ")"
+}
+
+// Lower usa LOWER, que en PostgreSQL convierte todo Unicode según la collation de la base
+func (Dialect) Lower(expr string) string {
+	return "LOWER(" + expr + ")"
+}
+
+// TrendBucket corta el intervalo con date_trunc en la zona pedida, sin depender de la zona de la sesión
+func (Dialect) TrendBucket(column string) string {
+	return "TO_CHAR(date_trunc(?, " + column + " AT TIME ZONE ?), 'YYYY-MM-DD')"
+}
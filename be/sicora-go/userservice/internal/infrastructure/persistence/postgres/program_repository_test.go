@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories/repositorytest"
+	"userservice/internal/infrastructure/persistence/relational"
+
+	"gorm.io/gorm"
+)
+
+// newTestRepositories retorna la fábrica de repositorios que vacía usuarios, programas, fichas, asignaciones,
+// jerarquía y datos MFA en cada caso
+func newTestRepositories(db *gorm.DB) repositorytest.RepositoriesFactory {
+	return func(t *testing.T) repositorytest.Repositories {
+		if err := db.Exec("TRUNCATE userservice.users, userservice.programs, userservice.regionales, userservice.mfa_enforcement_policies CASCADE").Error; err != nil {
+			t.Fatalf("limpiando usuarios, programas, fichas, asignaciones, jerarquía y MFA: %v", err)
+		}
+		return repositorytest.Repositories{
+			Users:        relational.NewUserRepository(db, Dialect{}),
+			Programs:     relational.NewProgramRepository(db, Dialect{}),
+			Fichas:       relational.NewFichaRepository(db, Dialect{}),
+			Assignments:  relational.NewInstructorAssignmentRepository(db),
+			Organization: relational.NewOrganizationRepository(db, Dialect{}),
+			MFAMethods:   relational.NewMFAMethodRepository(db),
+			BackupCodes:  relational.NewBackupCodeRepository(db),
+			MFASessions:  relational.NewMFASessionRepository(db),
+			MFAPolicies:  relational.NewMFAEnforcementPolicyRepository(db),
+		}
+	}
+}
+
+func TestProgramRepositoryContract(t *testing.T) {
+	repositorytest.RunProgramRepositoryContract(t, newTestRepositories(openTestDB(t)))
+}
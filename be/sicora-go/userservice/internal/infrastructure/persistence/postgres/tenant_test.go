@@ -0,0 +1,11 @@
+package postgres
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories/repositorytest"
+)
+
+func TestTenantContract(t *testing.T) {
+	repositorytest.RunTenantContract(t, newTestRepositories(openTestDB(t)))
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"userservice/internal/domain/repositories"
+	"userservice/internal/domain/repositories/repositorytest"
+	"userservice/internal/infrastructure/persistence/relational"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// openTestDB abre la base de datos de integración indicada en USERSERVICE_TEST_DATABASE_DSN
+// Las pruebas se omiten cuando la variable no está definida
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("USERSERVICE_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("USERSERVICE_TEST_DATABASE_DSN no definido: se omiten las pruebas de integración con PostgreSQL")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("conectando a postgres: %v", err)
+	}
+
+	// El esquema se crea desde cero con todas las migraciones en orden, como en un despliegue nuevo
+	if err := db.Exec("DROP SCHEMA IF EXISTS userservice CASCADE").Error; err != nil {
+		t.Fatalf("eliminando esquema: %v", err)
+	}
+
+	migrations, err := filepath.Glob(filepath.Join("..", "..", "..", "..", "migrations", "*.sql"))
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("buscando migraciones: %v", err)
+	}
+	for _, migration := range migrations {
+		script, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("leyendo %s: %v", migration, err)
+		}
+		if err := db.Exec(string(script)).Error; err != nil {
+			t.Fatalf("aplicando %s: %v", filepath.Base(migration), err)
+		}
+	}
+
+	return db
+}
+
+func TestUserRepositoryContract(t *testing.T) {
+	db := openTestDB(t)
+
+	repositorytest.RunUserRepositoryContract(t, func(t *testing.T) repositories.UserRepository {
+		if err := db.Exec("TRUNCATE userservice.users CASCADE").Error; err != nil {
+			t.Fatalf("limpiando usuarios: %v", err)
+		}
+		return relational.NewUserRepository(db, Dialect{})
+	})
+}
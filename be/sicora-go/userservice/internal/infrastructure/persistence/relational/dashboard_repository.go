@@ -0,0 +1,82 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.DashboardSnapshotRepository = (*DashboardSnapshotRepository)(nil)
+
+// DashboardSnapshotRepository implementa repositories.DashboardSnapshotRepository sobre GORM
+type DashboardSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewDashboardSnapshotRepository crea una nueva instancia del repositorio de fotos del dashboard
+func NewDashboardSnapshotRepository(db *gorm.DB) *DashboardSnapshotRepository {
+	return &DashboardSnapshotRepository{db: db}
+}
+
+// Save guarda la foto y sus celdas en una transacción
+func (r *DashboardSnapshotRepository) Save(ctx context.Context, snapshot *entities.DashboardSnapshot) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+
+	if snapshot.ID == uuid.Nil {
+		snapshot.ID = uuid.New()
+	}
+	for i := range snapshot.Cells {
+		snapshot.Cells[i].SnapshotID = snapshot.ID
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(snapshot).Error; err != nil {
+			return err
+		}
+		if len(snapshot.Cells) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(snapshot.Cells, 500).Error
+	})
+} // fin Save
+
+// Latest obtiene la foto más reciente con sus celdas, nil si no hay ninguna
+// Las celdas se ordenan en Go, byte a byte como las ordena el dominio, sin depender de la intercalación del motor
+func (r *DashboardSnapshotRepository) Latest(ctx context.Context) (*entities.DashboardSnapshot, error) {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := findOne[entities.DashboardSnapshot](r.db.WithContext(ctx).
+		Preload("Cells").
+		Order("taken_at_dashboard_snapshot DESC"))
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	snapshot.SortCells()
+	return snapshot, nil
+}
+
+// Prune elimina las fotos anteriores a la fecha dada salvo la más reciente; las celdas se eliminan en cascada
+func (r *DashboardSnapshotRepository) Prune(ctx context.Context, takenBefore time.Time) (int64, error) {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return 0, err
+	}
+
+	result := r.db.WithContext(ctx).
+		Where("taken_at_dashboard_snapshot < ?", takenBefore).
+		Where("id_dashboard_snapshot <> (SELECT id_dashboard_snapshot FROM userservice.dashboard_snapshots " +
+			"ORDER BY taken_at_dashboard_snapshot DESC LIMIT 1)").
+		Delete(&entities.DashboardSnapshot{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,24 @@
+// Package relational implementa los repositorios sobre GORM para los motores relacionales del servicio
+// El SQL es común a PostgreSQL y SQLite; lo que cambia entre ellos lo resuelve el Dialect de cada motor
+package relational
+
+// Dialect reúne lo que el SQL común no puede expresar igual en todos los motores
+type Dialect interface {
+	// UniqueViolation indica si err es una violación de un índice único; field es el campo del dominio
+	// cuando el índice es de usuarios ("id", "email" o "document_number") y vacío en otro caso
+	UniqueViolation(err error) (field string, ok bool)
+
+	// SedeViolation indica si err es una violación de la llave foránea de los usuarios a su sede
+	SedeViolation(err error) bool
+
+	// SearchFold retorna la llamada a search_fold sobre expr, que quita tildes y pasa a minúsculas
+	SearchFold(expr string) string
+
+	// Lower retorna la expresión que pasa expr a minúsculas en todo Unicode, no solo en ASCII
+	// Es la misma expresión del índice único de emails, así las búsquedas por email lo usan
+	Lower(expr string) string
+
+	// TrendBucket retorna la expresión con el inicio, como texto YYYY-MM-DD, del intervalo que contiene a column
+	// Recibe dos argumentos en este orden: el intervalo (repositories.TrendBucket) y la zona horaria IANA
+	TrendBucket(column string) string
+}
@@ -0,0 +1,148 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.FichaRepository = (*FichaRepository)(nil)
+
+// fichaTenantSQL acota las fichas a las de las sedes del alcance; ver applyTenantSedes
+const fichaTenantSQL = "sede_id_ficha IN (%s)"
+
+// FichaRepository implementa repositories.FichaRepository sobre GORM
+type FichaRepository struct {
+	db      *gorm.DB
+	dialect Dialect
+}
+
+// NewFichaRepository crea una nueva instancia del repositorio de fichas
+func NewFichaRepository(db *gorm.DB, dialect Dialect) *FichaRepository {
+	return &FichaRepository{db: db, dialect: dialect}
+}
+
+// Create registra una nueva ficha tras verificar su programa, sede e instructor líder y el alcance de su sede
+func (r *FichaRepository) Create(ctx context.Context, ficha *entities.Ficha) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkFichaReferences(tx, ficha); err != nil {
+			return err
+		}
+		if err := checkTenantSede(ctx, tx, ficha.SedeID); err != nil {
+			return err
+		}
+
+		if err := tx.Create(ficha).Error; err != nil {
+			if isUniqueViolation(r.dialect, err) {
+				return repositories.ErrDuplicateFicha
+			}
+			return err
+		}
+		return nil
+	})
+} // fin Create
+
+// GetByNumber obtiene una ficha por su número, retorna nil si no existe
+func (r *FichaRepository) GetByNumber(ctx context.Context, number string) (*entities.Ficha, error) {
+	return findOne[entities.Ficha](applyTenantSedes(ctx, r.db.WithContext(ctx), fichaTenantSQL).Where("number_ficha = ?", number))
+}
+
+// Update actualiza los datos de la ficha; el número no cambia porque los usuarios lo referencian
+// La ficha debe estar en el alcance antes y después del cambio
+func (r *FichaRepository) Update(ctx context.Context, ficha *entities.Ficha) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkFichaReferences(tx, ficha); err != nil {
+			return err
+		}
+		err := requireRecord(applyTenantSedes(ctx, tx, fichaTenantSQL), &entities.Ficha{},
+			repositories.ErrFichaNotFound, "id_ficha = ?", ficha.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkTenantSede(ctx, tx, ficha.SedeID); err != nil {
+			return err
+		}
+
+		ficha.UpdatedAt = time.Now()
+		result := tx.Model(&entities.Ficha{}).
+			Where("id_ficha = ?", ficha.ID).
+			Select("*").
+			Omit("id_ficha", "number_ficha", "created_at_ficha").
+			Updates(ficha)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrFichaNotFound
+		}
+		return nil
+	})
+} // fin Update
+
+// List obtiene las fichas que cumplen los filtros ordenadas por número
+func (r *FichaRepository) List(ctx context.Context, filters repositories.FichaFilters) ([]*entities.Ficha, error) {
+	query := applyTenantSedes(ctx, r.db.WithContext(ctx), fichaTenantSQL).Order("number_ficha")
+	if filters.ProgramID != nil {
+		query = query.Where("program_id_ficha = ?", *filters.ProgramID)
+	}
+	if filters.SedeID != nil {
+		query = query.Where("sede_id_ficha = ?", *filters.SedeID)
+	}
+	if filters.Status != nil {
+		query = query.Where("status_ficha = ?", *filters.Status)
+	}
+	if filters.LeadInstructorID != nil {
+		query = query.Where("lead_instructor_id_ficha = ?", *filters.LeadInstructorID)
+	}
+
+	fichas := []*entities.Ficha{}
+	if err := query.Find(&fichas).Error; err != nil {
+		return nil, err
+	}
+
+	return fichas, nil
+} // fin List
+
+// checkFichaReferences verifica que el programa y la sede existan y que el instructor líder sea un instructor
+func checkFichaReferences(tx *gorm.DB, ficha *entities.Ficha) error {
+	var count int64
+	if err := tx.Model(&entities.Program{}).Where("id_program = ?", ficha.ProgramID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return repositories.ErrProgramNotFound
+	}
+
+	if ficha.SedeID != nil {
+		if err := requireRecord(tx, &entities.Sede{}, repositories.ErrSedeNotFound, "id_sede = ?", *ficha.SedeID); err != nil {
+			return err
+		}
+	}
+
+	if ficha.LeadInstructorID == nil {
+		return nil
+	}
+	err := tx.Model(&entities.User{}).
+		Where("id_user = ? AND role_user = ? AND deleted_at_user IS NULL", *ficha.LeadInstructorID, entities.RoleInstructor).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repositories.ErrInvalidLeadInstructor
+	}
+	return nil
+} // fin checkFichaReferences
+
+// lockFicha obtiene la ficha bloqueando su fila hasta el fin de la transacción, nil si no existe
+// El bloqueo serializa las asignaciones concurrentes para respetar la capacidad; SQLite omite FOR UPDATE,
+// pero ahí la transacción ya toma el bloqueo de escritura al iniciar (_txlock=immediate)
+func lockFicha(tx *gorm.DB, number string) (*entities.Ficha, error) {
+	return findOne[entities.Ficha](tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("number_ficha = ?", number))
+}
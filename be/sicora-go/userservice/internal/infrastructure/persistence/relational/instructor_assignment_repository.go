@@ -0,0 +1,143 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.InstructorAssignmentRepository = (*InstructorAssignmentRepository)(nil)
+
+// assignmentTenantSQL acota las asignaciones a las de fichas de las sedes del alcance; ver applyTenantSedes
+const assignmentTenantSQL = "ficha_id_assignment IN (SELECT number_ficha FROM userservice.fichas WHERE sede_id_ficha IN (%s))"
+
+// InstructorAssignmentRepository implementa repositories.InstructorAssignmentRepository sobre GORM
+type InstructorAssignmentRepository struct {
+	db *gorm.DB
+}
+
+// NewInstructorAssignmentRepository crea una nueva instancia del repositorio de asignaciones de instructores
+func NewInstructorAssignmentRepository(db *gorm.DB) *InstructorAssignmentRepository {
+	return &InstructorAssignmentRepository{db: db}
+}
+
+// Create registra la asignación tras verificar al instructor, la ficha y que no se cruce con otra
+// La ficha se bloquea para que dos asignaciones concurrentes no se crucen; una ficha fuera del alcance no existe
+func (r *InstructorAssignmentRepository) Create(ctx context.Context, assignment *entities.InstructorAssignment) error {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&entities.User{}).
+			Where("id_user = ? AND role_user = ? AND deleted_at_user IS NULL", assignment.InstructorID, entities.RoleInstructor).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return repositories.ErrInvalidInstructor
+		}
+
+		ficha, err := lockFicha(applyTenantSedes(ctx, tx, fichaTenantSQL), assignment.FichaID)
+		if err != nil {
+			return err
+		}
+		if ficha == nil {
+			return repositories.ErrFichaNotFound
+		}
+
+		overlapping := tx.Model(&entities.InstructorAssignment{}).
+			Where("instructor_id_assignment = ? AND ficha_id_assignment = ? AND competency_assignment = ?",
+				assignment.InstructorID, assignment.FichaID, assignment.Competency).
+			Where("end_date_assignment IS NULL OR end_date_assignment >= ?", assignment.StartDate)
+		if assignment.EndDate != nil {
+			overlapping = overlapping.Where("start_date_assignment <= ?", *assignment.EndDate)
+		}
+		if err := overlapping.Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return repositories.ErrOverlappingAssignment
+		}
+
+		return tx.Create(assignment).Error
+	})
+} // fin Create
+
+// GetByID obtiene una asignación por su ID, retorna nil si no existe
+func (r *InstructorAssignmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.InstructorAssignment, error) {
+	return findOne[entities.InstructorAssignment](applyTenantSedes(ctx, r.db.WithContext(ctx), assignmentTenantSQL).
+		Where("id_assignment = ?", id))
+}
+
+// End cierra la asignación en endDate
+func (r *InstructorAssignmentRepository) End(ctx context.Context, id uuid.UUID, endDate time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		assignment, err := findOne[entities.InstructorAssignment](applyTenantSedes(ctx, tx, assignmentTenantSQL).
+			Where("id_assignment = ?", id))
+		if err != nil {
+			return err
+		}
+		if assignment == nil {
+			return repositories.ErrAssignmentNotFound
+		}
+
+		if err := assignment.EndOn(endDate); err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.InstructorAssignment{}).
+			Where("id_assignment = ?", id).
+			Update("end_date_assignment", assignment.EndDate).Error
+	})
+} // fin End
+
+// ListByInstructor obtiene las asignaciones del instructor ordenadas por ficha e inicio
+func (r *InstructorAssignmentRepository) ListByInstructor(ctx context.Context, instructorID uuid.UUID, activeOn time.Time) ([]*entities.InstructorAssignment, error) {
+	return r.list(activeAssignments(applyTenantSedes(ctx, r.db.WithContext(ctx), assignmentTenantSQL), activeOn).
+		Where("instructor_id_assignment = ?", instructorID).
+		Order("ficha_id_assignment, start_date_assignment, competency_assignment"))
+}
+
+// ListByFicha obtiene las asignaciones de la ficha ordenadas por inicio e instructor
+func (r *InstructorAssignmentRepository) ListByFicha(ctx context.Context, fichaID string, activeOn time.Time) ([]*entities.InstructorAssignment, error) {
+	return r.list(activeAssignments(applyTenantSedes(ctx, r.db.WithContext(ctx), assignmentTenantSQL), activeOn).
+		Where("ficha_id_assignment = ?", fichaID).
+		Order("start_date_assignment, instructor_id_assignment, competency_assignment"))
+}
+
+// IsAssigned indica si el instructor tiene alguna asignación vigente en la ficha en la fecha dada
+func (r *InstructorAssignmentRepository) IsAssigned(ctx context.Context, instructorID uuid.UUID, fichaID string, on time.Time) (bool, error) {
+	var count int64
+	query := applyTenantSedes(ctx, r.db.WithContext(ctx).Model(&entities.InstructorAssignment{}), assignmentTenantSQL)
+	err := activeAssignments(query, on).
+		Where("instructor_id_assignment = ? AND ficha_id_assignment = ?", instructorID, fichaID).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *InstructorAssignmentRepository) list(query *gorm.DB) ([]*entities.InstructorAssignment, error) {
+	assignments := []*entities.InstructorAssignment{}
+	if err := query.Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// activeAssignments acota la consulta a las asignaciones vigentes en la fecha; sin fecha no filtra
+func activeAssignments(query *gorm.DB, on time.Time) *gorm.DB {
+	if on.IsZero() {
+		return query
+	}
+	day := entities.CivilDate(on)
+	return query.Where("start_date_assignment <= ? AND (end_date_assignment IS NULL OR end_date_assignment >= ?)", day, day)
+}
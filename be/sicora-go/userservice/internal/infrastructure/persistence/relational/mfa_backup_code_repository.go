@@ -0,0 +1,96 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.BackupCodeRepository = (*BackupCodeRepository)(nil)
+
+// BackupCodeRepository implementa repositories.BackupCodeRepository sobre GORM
+type BackupCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewBackupCodeRepository crea una nueva instancia del repositorio de códigos de recuperación
+func NewBackupCodeRepository(db *gorm.DB) *BackupCodeRepository {
+	return &BackupCodeRepository{db: db}
+}
+
+// CreateBatch guarda un nuevo juego de códigos de recuperación
+func (r *BackupCodeRepository) CreateBatch(ctx context.Context, codes []*entities.MFABackupCode) error {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	// La fecha de creación se fija aquí: en un lote con fechas mixtas GORM emitiría DEFAULT, que SQLite no acepta
+	now := time.Now()
+	for _, code := range codes {
+		if code.CreatedAt.IsZero() {
+			code.CreatedAt = now
+		}
+	}
+
+	return r.db.WithContext(ctx).Create(codes).Error
+}
+
+// GetUnusedByUserID obtiene los códigos no usados y no expirados de un usuario
+func (r *BackupCodeRepository) GetUnusedByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.MFABackupCode, error) {
+	codes := []*entities.MFABackupCode{}
+	err := r.unused(ctx, userID).
+		Order("created_at_mfa_backup_code ASC").
+		Find(&codes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// CountUnusedByUserID cuenta los códigos no usados y no expirados de un usuario
+func (r *BackupCodeRepository) CountUnusedByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.unused(ctx, userID).Model(&entities.MFABackupCode{}).Count(&count).Error
+	return count, err
+}
+
+// MarkAsUsed marca un código como usado solo si aún no lo estaba
+func (r *BackupCodeRepository) MarkAsUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return requireMFARowsAffected(r.db.WithContext(ctx).
+		Model(&entities.MFABackupCode{}).
+		Where("id_mfa_backup_code = ? AND NOT is_used_mfa_backup_code", id).
+		Updates(map[string]any{
+			"is_used_mfa_backup_code": true,
+			"used_at_mfa_backup_code": usedAt,
+		}))
+}
+
+// DeleteByUserID elimina todos los códigos de un usuario
+func (r *BackupCodeRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("user_id_mfa_backup_code = ?", userID).
+		Delete(&entities.MFABackupCode{}).Error
+}
+
+// DeleteExpired elimina los códigos expirados antes de la fecha dada
+func (r *BackupCodeRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at_mfa_backup_code < ?", before).
+		Delete(&entities.MFABackupCode{})
+	return result.RowsAffected, result.Error
+}
+
+// unused construye la consulta de códigos vigentes de un usuario
+func (r *BackupCodeRepository) unused(ctx context.Context, userID uuid.UUID) *gorm.DB {
+	return r.db.WithContext(ctx).Where(
+		"user_id_mfa_backup_code = ? AND NOT is_used_mfa_backup_code AND expires_at_mfa_backup_code > ?",
+		userID, time.Now(),
+	)
+}
@@ -0,0 +1,132 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.MFAMethodRepository = (*MFAMethodRepository)(nil)
+
+// MFAMethodRepository implementa repositories.MFAMethodRepository sobre GORM
+type MFAMethodRepository struct {
+	db *gorm.DB
+}
+
+// NewMFAMethodRepository crea una nueva instancia del repositorio de métodos MFA
+func NewMFAMethodRepository(db *gorm.DB) *MFAMethodRepository {
+	return &MFAMethodRepository{db: db}
+}
+
+// Create registra un nuevo método MFA
+func (r *MFAMethodRepository) Create(ctx context.Context, method *entities.UserMFAMethod) error {
+	return r.db.WithContext(ctx).Create(method).Error
+}
+
+// GetByID obtiene un método MFA por su ID, retorna nil si no existe
+func (r *MFAMethodRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.UserMFAMethod, error) {
+	return findOne[entities.UserMFAMethod](r.db.WithContext(ctx).Where("id_user_mfa_method = ?", id))
+}
+
+// GetByUserID obtiene todos los métodos MFA configurados por un usuario
+func (r *MFAMethodRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.UserMFAMethod, error) {
+	return r.find(r.db.WithContext(ctx).Where("user_id_user_mfa_method = ?", userID))
+}
+
+// GetEnabledByUserID obtiene los métodos MFA habilitados de un usuario, el primario primero
+func (r *MFAMethodRepository) GetEnabledByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.UserMFAMethod, error) {
+	return r.find(r.db.WithContext(ctx).
+		Where("user_id_user_mfa_method = ? AND is_enabled_user_mfa_method", userID))
+}
+
+// GetPrimaryByUserID obtiene el método MFA primario habilitado, retorna nil si no tiene
+func (r *MFAMethodRepository) GetPrimaryByUserID(ctx context.Context, userID uuid.UUID) (*entities.UserMFAMethod, error) {
+	return findOne[entities.UserMFAMethod](r.db.WithContext(ctx).
+		Where("user_id_user_mfa_method = ? AND is_primary_user_mfa_method AND is_enabled_user_mfa_method", userID))
+}
+
+// GetByUserAndType obtiene el método de un tipo dado, retorna nil si no existe
+func (r *MFAMethodRepository) GetByUserAndType(ctx context.Context, userID uuid.UUID, methodType string) (*entities.UserMFAMethod, error) {
+	return findOne[entities.UserMFAMethod](r.db.WithContext(ctx).
+		Where("user_id_user_mfa_method = ? AND method_type_user_mfa_method = ?", userID, methodType))
+}
+
+// Update actualiza un método MFA existente
+func (r *MFAMethodRepository) Update(ctx context.Context, method *entities.UserMFAMethod) error {
+	method.UpdatedAt = time.Now()
+
+	return requireMFARowsAffected(r.db.WithContext(ctx).
+		Model(&entities.UserMFAMethod{}).
+		Where("id_user_mfa_method = ?", method.ID).
+		Select("*").
+		Omit("id_user_mfa_method", "user_id_user_mfa_method", "created_at_user_mfa_method").
+		Updates(method))
+}
+
+// SetPrimary marca el método como primario y desmarca los demás del usuario
+func (r *MFAMethodRepository) SetPrimary(ctx context.Context, userID, methodID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		err := tx.Model(&entities.UserMFAMethod{}).
+			Where("user_id_user_mfa_method = ? AND id_user_mfa_method <> ?", userID, methodID).
+			Updates(map[string]any{
+				"is_primary_user_mfa_method": false,
+				"updated_at_user_mfa_method": now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return requireMFARowsAffected(tx.Model(&entities.UserMFAMethod{}).
+			Where("user_id_user_mfa_method = ? AND id_user_mfa_method = ?", userID, methodID).
+			Updates(map[string]any{
+				"is_primary_user_mfa_method": true,
+				"updated_at_user_mfa_method": now,
+			}))
+	})
+} // fin SetPrimary
+
+// MarkAsUsed registra el último uso exitoso del método
+func (r *MFAMethodRepository) MarkAsUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return requireMFARowsAffected(r.db.WithContext(ctx).
+		Model(&entities.UserMFAMethod{}).
+		Where("id_user_mfa_method = ?", id).
+		Updates(map[string]any{
+			"last_used_at_user_mfa_method": usedAt,
+			"updated_at_user_mfa_method":   time.Now(),
+		}))
+}
+
+// Delete elimina un método MFA
+func (r *MFAMethodRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return requireMFARowsAffected(r.db.WithContext(ctx).
+		Where("id_user_mfa_method = ?", id).
+		Delete(&entities.UserMFAMethod{}))
+}
+
+// DeleteByUserID elimina todos los métodos MFA de un usuario
+func (r *MFAMethodRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("user_id_user_mfa_method = ?", userID).
+		Delete(&entities.UserMFAMethod{}).Error
+}
+
+// find ejecuta la consulta ordenando el método primario primero
+func (r *MFAMethodRepository) find(query *gorm.DB) ([]*entities.UserMFAMethod, error) {
+	methods := []*entities.UserMFAMethod{}
+	err := query.
+		Order("is_primary_user_mfa_method DESC, created_at_user_mfa_method ASC").
+		Find(&methods).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return methods, nil
+}
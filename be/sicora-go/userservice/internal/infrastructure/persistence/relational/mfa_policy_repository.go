@@ -0,0 +1,65 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.MFAEnforcementPolicyRepository = (*MFAEnforcementPolicyRepository)(nil)
+
+// MFAEnforcementPolicyRepository implementa repositories.MFAEnforcementPolicyRepository sobre GORM
+type MFAEnforcementPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewMFAEnforcementPolicyRepository crea una nueva instancia del repositorio de políticas MFA
+func NewMFAEnforcementPolicyRepository(db *gorm.DB) *MFAEnforcementPolicyRepository {
+	return &MFAEnforcementPolicyRepository{db: db}
+}
+
+// Create registra una nueva política
+func (r *MFAEnforcementPolicyRepository) Create(ctx context.Context, policy *entities.MFAEnforcementPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+// GetByRoleName obtiene la política de un rol, retorna nil si el rol no tiene política
+func (r *MFAEnforcementPolicyRepository) GetByRoleName(ctx context.Context, roleName string) (*entities.MFAEnforcementPolicy, error) {
+	return findOne[entities.MFAEnforcementPolicy](r.db.WithContext(ctx).
+		Where("role_name_mfa_enforcement_policy = ?", roleName))
+}
+
+// List obtiene todas las políticas ordenadas por rol
+func (r *MFAEnforcementPolicyRepository) List(ctx context.Context) ([]*entities.MFAEnforcementPolicy, error) {
+	policies := []*entities.MFAEnforcementPolicy{}
+	if err := r.db.WithContext(ctx).Order("role_name_mfa_enforcement_policy ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// Update actualiza una política existente
+func (r *MFAEnforcementPolicyRepository) Update(ctx context.Context, policy *entities.MFAEnforcementPolicy) error {
+	policy.UpdatedAt = time.Now()
+
+	return requireMFARowsAffected(r.db.WithContext(ctx).
+		Model(&entities.MFAEnforcementPolicy{}).
+		Where("id_mfa_enforcement_policy = ?", policy.ID).
+		Select("*").
+		Omit("id_mfa_enforcement_policy", "created_at_mfa_enforcement_policy").
+		Updates(policy))
+}
+
+// Delete elimina una política
+func (r *MFAEnforcementPolicyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return requireMFARowsAffected(r.db.WithContext(ctx).
+		Where("id_mfa_enforcement_policy = ?", id).
+		Delete(&entities.MFAEnforcementPolicy{}))
+}
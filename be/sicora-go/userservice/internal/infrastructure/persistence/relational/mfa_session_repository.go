@@ -0,0 +1,69 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.MFASessionRepository = (*MFASessionRepository)(nil)
+
+// MFASessionRepository implementa repositories.MFASessionRepository sobre GORM
+type MFASessionRepository struct {
+	db *gorm.DB
+}
+
+// NewMFASessionRepository crea una nueva instancia del repositorio de sesiones MFA
+func NewMFASessionRepository(db *gorm.DB) *MFASessionRepository {
+	return &MFASessionRepository{db: db}
+}
+
+// Create registra una nueva sesión de verificación
+func (r *MFASessionRepository) Create(ctx context.Context, session *entities.MFASession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// GetByID obtiene una sesión por su ID, retorna nil si no existe
+func (r *MFASessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.MFASession, error) {
+	return findOne[entities.MFASession](r.db.WithContext(ctx).Where("id_mfa_session = ?", id))
+}
+
+// GetActiveByUserID obtiene la sesión más reciente no verificada y no expirada
+func (r *MFASessionRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*entities.MFASession, error) {
+	return findOne[entities.MFASession](r.db.WithContext(ctx).
+		Where("user_id_mfa_session = ? AND NOT is_verified_mfa_session AND expires_at_mfa_session > ?", userID, time.Now()).
+		Order("created_at_mfa_session DESC"))
+}
+
+// IncrementAttempts suma un intento fallido a la sesión de forma atómica
+func (r *MFASessionRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	return requireMFARowsAffected(r.db.WithContext(ctx).
+		Model(&entities.MFASession{}).
+		Where("id_mfa_session = ?", id).
+		Update("attempts_mfa_session", gorm.Expr("attempts_mfa_session + 1")))
+}
+
+// MarkAsVerified marca la sesión como verificada
+func (r *MFASessionRepository) MarkAsVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	return requireMFARowsAffected(r.db.WithContext(ctx).
+		Model(&entities.MFASession{}).
+		Where("id_mfa_session = ?", id).
+		Updates(map[string]any{
+			"is_verified_mfa_session": true,
+			"verified_at_mfa_session": verifiedAt,
+		}))
+}
+
+// DeleteExpired elimina las sesiones expiradas antes de la fecha dada
+func (r *MFASessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at_mfa_session < ?", before).
+		Delete(&entities.MFASession{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,252 @@
+package relational
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.OrganizationRepository = (*OrganizationRepository)(nil)
+
+// Condiciones del alcance de sede por nivel: una sede se ve si está en el alcance y un centro o una regional
+// si contiene alguna sede del alcance; ver applyTenantSedes
+const (
+	sedeTenantSQL     = "id_sede IN (%s)"
+	centroTenantSQL   = "id_centro IN (SELECT centro_id_sede FROM userservice.sedes WHERE id_sede IN (%s))"
+	regionalTenantSQL = "id_regional IN (SELECT regional_id_centro FROM userservice.centros " +
+		"JOIN userservice.sedes ON centro_id_sede = id_centro WHERE id_sede IN (%s))"
+)
+
+// OrganizationRepository implementa repositories.OrganizationRepository sobre GORM
+type OrganizationRepository struct {
+	db      *gorm.DB
+	dialect Dialect
+}
+
+// NewOrganizationRepository crea una nueva instancia del repositorio de regionales, centros y sedes
+func NewOrganizationRepository(db *gorm.DB, dialect Dialect) *OrganizationRepository {
+	return &OrganizationRepository{db: db, dialect: dialect}
+}
+
+// CreateRegional registra una nueva regional; requiere un contexto que vea todas las sedes
+func (r *OrganizationRepository) CreateRegional(ctx context.Context, regional *entities.Regional) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+	return r.translateError(r.db.WithContext(ctx).Create(regional).Error, repositories.ErrDuplicateRegional)
+}
+
+// CreateCentro registra un nuevo centro de formación tras verificar su regional; requiere un contexto que vea todas las sedes
+func (r *OrganizationRepository) CreateCentro(ctx context.Context, centro *entities.Centro) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := requireRecord(tx, &entities.Regional{}, repositories.ErrRegionalNotFound, "id_regional = ?", centro.RegionalID); err != nil {
+			return err
+		}
+		return r.translateError(tx.Create(centro).Error, repositories.ErrDuplicateCentro)
+	})
+}
+
+// CreateSede registra una nueva sede tras verificar su centro y que quede en el alcance del contexto
+func (r *OrganizationRepository) CreateSede(ctx context.Context, sede *entities.Sede) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkSedeCentro(ctx, tx, sede); err != nil {
+			return err
+		}
+		return r.translateError(tx.Create(sede).Error, repositories.ErrDuplicateSede)
+	})
+}
+
+// GetRegional obtiene una regional por su ID, retorna nil si no existe
+func (r *OrganizationRepository) GetRegional(ctx context.Context, id uuid.UUID) (*entities.Regional, error) {
+	return findOne[entities.Regional](applyTenantSedes(ctx, r.db.WithContext(ctx), regionalTenantSQL).Where("id_regional = ?", id))
+}
+
+// GetCentro obtiene un centro de formación por su ID, retorna nil si no existe
+func (r *OrganizationRepository) GetCentro(ctx context.Context, id uuid.UUID) (*entities.Centro, error) {
+	return findOne[entities.Centro](applyTenantSedes(ctx, r.db.WithContext(ctx), centroTenantSQL).Where("id_centro = ?", id))
+}
+
+// GetSede obtiene una sede por su ID, retorna nil si no existe
+func (r *OrganizationRepository) GetSede(ctx context.Context, id uuid.UUID) (*entities.Sede, error) {
+	return findOne[entities.Sede](applyTenantSedes(ctx, r.db.WithContext(ctx), sedeTenantSQL).Where("id_sede = ?", id))
+}
+
+// UpdateRegional actualiza los datos de la regional; requiere un contexto que vea todas las sedes
+func (r *OrganizationRepository) UpdateRegional(ctx context.Context, regional *entities.Regional) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+	regional.UpdatedAt = time.Now()
+	query := r.db.WithContext(ctx).Where("id_regional = ?", regional.ID).Omit("id_regional", "created_at_regional")
+	return r.updateNode(query, regional, repositories.ErrRegionalNotFound, repositories.ErrDuplicateRegional)
+}
+
+// UpdateCentro actualiza los datos del centro de formación tras verificar su regional
+// Requiere un contexto que vea todas las sedes
+func (r *OrganizationRepository) UpdateCentro(ctx context.Context, centro *entities.Centro) error {
+	if err := repositories.RequireAllSedes(ctx); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := requireRecord(tx, &entities.Regional{}, repositories.ErrRegionalNotFound, "id_regional = ?", centro.RegionalID); err != nil {
+			return err
+		}
+		centro.UpdatedAt = time.Now()
+		query := tx.Where("id_centro = ?", centro.ID).Omit("id_centro", "created_at_centro")
+		return r.updateNode(query, centro, repositories.ErrCentroNotFound, repositories.ErrDuplicateCentro)
+	})
+}
+
+// UpdateSede actualiza los datos de la sede tras verificar su centro
+// La sede debe estar en el alcance del contexto antes y después del cambio
+func (r *OrganizationRepository) UpdateSede(ctx context.Context, sede *entities.Sede) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := requireRecord(applyTenantSedes(ctx, tx, sedeTenantSQL), &entities.Sede{},
+			repositories.ErrSedeNotFound, "id_sede = ?", sede.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkSedeCentro(ctx, tx, sede); err != nil {
+			return err
+		}
+		sede.UpdatedAt = time.Now()
+		query := tx.Where("id_sede = ?", sede.ID).Omit("id_sede", "created_at_sede")
+		return r.updateNode(query, sede, repositories.ErrSedeNotFound, repositories.ErrDuplicateSede)
+	})
+}
+
+// ListRegionales obtiene las regionales ordenadas por código
+func (r *OrganizationRepository) ListRegionales(ctx context.Context, activeOnly bool) ([]*entities.Regional, error) {
+	query := applyTenantSedes(ctx, r.db.WithContext(ctx), regionalTenantSQL).Order("code_regional")
+	if activeOnly {
+		query = query.Where("is_active_regional")
+	}
+
+	regionales := []*entities.Regional{}
+	if err := query.Find(&regionales).Error; err != nil {
+		return nil, err
+	}
+
+	return regionales, nil
+}
+
+// ListCentros obtiene los centros de formación ordenados por código
+func (r *OrganizationRepository) ListCentros(ctx context.Context, regionalID *uuid.UUID, activeOnly bool) ([]*entities.Centro, error) {
+	query := applyTenantSedes(ctx, r.db.WithContext(ctx), centroTenantSQL).Order("code_centro")
+	if regionalID != nil {
+		query = query.Where("regional_id_centro = ?", *regionalID)
+	}
+	if activeOnly {
+		query = query.Where("is_active_centro")
+	}
+
+	centros := []*entities.Centro{}
+	if err := query.Find(&centros).Error; err != nil {
+		return nil, err
+	}
+
+	return centros, nil
+}
+
+// ListSedes obtiene las sedes ordenadas por nombre
+func (r *OrganizationRepository) ListSedes(ctx context.Context, centroID *uuid.UUID, activeOnly bool) ([]*entities.Sede, error) {
+	query := applyTenantSedes(ctx, r.db.WithContext(ctx), sedeTenantSQL).Order("name_sede, id_sede")
+	if centroID != nil {
+		query = query.Where("centro_id_sede = ?", *centroID)
+	}
+	if activeOnly {
+		query = query.Where("is_active_sede")
+	}
+
+	sedes := []*entities.Sede{}
+	if err := query.Find(&sedes).Error; err != nil {
+		return nil, err
+	}
+
+	return sedes, nil
+}
+
+// GetSedePath obtiene la sede con su centro y su regional, retorna nil si no existe o está fuera del alcance
+func (r *OrganizationRepository) GetSedePath(ctx context.Context, sedeID uuid.UUID) (*repositories.SedePath, error) {
+	path, err := findSedePath(r.db.WithContext(ctx), sedeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := repositories.CheckTenantSede(ctx, path); err != nil {
+		if errors.Is(err, repositories.ErrOutsideTenant) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return path, nil
+}
+
+// checkSedeCentro verifica que el centro de la sede exista y que con él la sede quede en el alcance del contexto
+// Con alcance, un centro inexistente deja la sede fuera de él, como a un usuario con una sede inexistente
+func checkSedeCentro(ctx context.Context, tx *gorm.DB, sede *entities.Sede) error {
+	centro, err := findOne[entities.Centro](tx.Where("id_centro = ?", sede.CentroID))
+	if err != nil {
+		return err
+	}
+
+	var path *repositories.SedePath
+	if centro != nil {
+		path = &repositories.SedePath{SedeID: sede.ID, CentroID: centro.ID, RegionalID: centro.RegionalID}
+	}
+	if err := repositories.CheckTenantSede(ctx, path); err != nil {
+		return err
+	}
+
+	if centro == nil {
+		return repositories.ErrCentroNotFound
+	}
+	return nil
+} // fin checkSedeCentro
+
+// findSedePath obtiene la sede con su centro y su regional, retorna nil si no existe
+// Lo usa también UserRepository para verificar el alcance de sede del contexto
+func findSedePath(db *gorm.DB, sedeID uuid.UUID) (*repositories.SedePath, error) {
+	var paths []repositories.SedePath
+	err := db.
+		Table("userservice.sedes").
+		Joins("JOIN userservice.centros ON id_centro = centro_id_sede").
+		Select("id_sede AS sede_id, id_centro AS centro_id, regional_id_centro AS regional_id").
+		Where("id_sede = ?", sedeID).
+		Scan(&paths).Error
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+
+	return &paths[0], nil
+}
+
+// updateNode guarda todos los campos del nodo en la fila que acota query, salvo los omitidos
+func (r *OrganizationRepository) updateNode(query *gorm.DB, node any, notFound, duplicate error) error {
+	result := query.Model(node).Select("*").Updates(node)
+	if result.Error != nil {
+		return r.translateError(result.Error, duplicate)
+	}
+	if result.RowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// translateError convierte la violación de un índice único en el error de duplicado del nivel
+func (r *OrganizationRepository) translateError(err, duplicate error) error {
+	if isUniqueViolation(r.dialect, err) {
+		return duplicate
+	}
+	return err
+}
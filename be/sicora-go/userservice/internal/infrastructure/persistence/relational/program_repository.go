@@ -0,0 +1,84 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.ProgramRepository = (*ProgramRepository)(nil)
+
+// ProgramRepository implementa repositories.ProgramRepository sobre GORM
+type ProgramRepository struct {
+	db      *gorm.DB
+	dialect Dialect
+}
+
+// NewProgramRepository crea una nueva instancia del repositorio de programas de formación
+func NewProgramRepository(db *gorm.DB, dialect Dialect) *ProgramRepository {
+	return &ProgramRepository{db: db, dialect: dialect}
+}
+
+// Create registra un nuevo programa
+func (r *ProgramRepository) Create(ctx context.Context, program *entities.Program) error {
+	return r.translateError(r.db.WithContext(ctx).Create(program).Error)
+}
+
+// GetByID obtiene un programa por su ID, retorna nil si no existe
+func (r *ProgramRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Program, error) {
+	return findOne[entities.Program](r.db.WithContext(ctx).Where("id_program = ?", id))
+}
+
+// GetByCode obtiene un programa por su código, retorna nil si no existe
+func (r *ProgramRepository) GetByCode(ctx context.Context, code string) (*entities.Program, error) {
+	return findOne[entities.Program](r.db.WithContext(ctx).Where("code_program = ?", code))
+}
+
+// Update actualiza los datos del programa
+func (r *ProgramRepository) Update(ctx context.Context, program *entities.Program) error {
+	program.UpdatedAt = time.Now()
+
+	result := r.db.WithContext(ctx).
+		Model(&entities.Program{}).
+		Where("id_program = ?", program.ID).
+		Select("*").
+		Omit("id_program", "created_at_program").
+		Updates(program)
+	if result.Error != nil {
+		return r.translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrProgramNotFound
+	}
+
+	return nil
+} // fin Update
+
+// List obtiene los programas ordenados por código
+func (r *ProgramRepository) List(ctx context.Context, activeOnly bool) ([]*entities.Program, error) {
+	query := r.db.WithContext(ctx).Order("code_program")
+	if activeOnly {
+		query = query.Where("is_active_program")
+	}
+
+	programs := []*entities.Program{}
+	if err := query.Find(&programs).Error; err != nil {
+		return nil, err
+	}
+
+	return programs, nil
+}
+
+// translateError convierte la violación del código único en ErrDuplicateProgram
+func (r *ProgramRepository) translateError(err error) error {
+	if isUniqueViolation(r.dialect, err) {
+		return repositories.ErrDuplicateProgram
+	}
+	return err
+}
@@ -0,0 +1,213 @@
+package relational
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// userProgramSQL es el código del programa de la ficha del usuario, NULL si no tiene ficha o la ficha no tiene programa
+const userProgramSQL = "(SELECT code_program FROM userservice.fichas " +
+	"JOIN userservice.programs ON id_program = program_id_ficha WHERE number_ficha = ficha_id_user)"
+
+// userFichaOnSQL es la ficha destino del último movimiento del usuario efectivo en la fecha del parámetro,
+// NULL si aún no tenía ficha; los movimientos de la misma fecha se aplican en orden de registro
+const userFichaOnSQL = "(SELECT to_ficha_id_ficha_movement FROM userservice.ficha_movements " +
+	"WHERE user_id_ficha_movement = id_user AND effective_date_ficha_movement <= ? " +
+	"ORDER BY effective_date_ficha_movement DESC, created_at_ficha_movement DESC, id_ficha_movement DESC LIMIT 1)"
+
+// userCentroSQL y userRegionalSQL son el centro y la regional de la sede del usuario, NULL si no tiene sede
+const (
+	userCentroSQL   = "(SELECT centro_id_sede FROM userservice.sedes WHERE id_sede = sede_id_user)"
+	userRegionalSQL = "(SELECT regional_id_centro FROM userservice.sedes " +
+		"JOIN userservice.centros ON id_centro = centro_id_sede WHERE id_sede = sede_id_user)"
+)
+
+// orgKeySQL retorna el ID, como texto, del nodo del nivel al que pertenece la sede del usuario; ” sin sede
+func orgKeySQL(level repositories.OrgLevel) string {
+	switch level {
+	case repositories.OrgLevelRegional:
+		return "COALESCE(CAST(" + userRegionalSQL + " AS TEXT), '')"
+	case repositories.OrgLevelCentro:
+		return "COALESCE(CAST(" + userCentroSQL + " AS TEXT), '')"
+	default:
+		return "COALESCE(CAST(sede_id_user AS TEXT), '')"
+	}
+}
+
+// orgFilterSQL traduce el filtro de jerarquía a una condición con argumentos con nombre; sin filtro retorna TRUE
+func orgFilterSQL(scope repositories.OrgFilter) (string, map[string]any) {
+	var conditions []string
+	args := map[string]any{}
+	if scope.SedeID != nil {
+		conditions = append(conditions, "sede_id_user = @org_sede")
+		args["org_sede"] = *scope.SedeID
+	}
+	if scope.CentroID != nil {
+		conditions = append(conditions, userCentroSQL+" = @org_centro")
+		args["org_centro"] = *scope.CentroID
+	}
+	if scope.RegionalID != nil {
+		conditions = append(conditions, userRegionalSQL+" = @org_regional")
+		args["org_regional"] = *scope.RegionalID
+	}
+
+	if len(conditions) == 0 {
+		return "TRUE", args
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", args
+}
+
+// applyOrgFilter acota la consulta de usuarios a los de la sede, el centro o la regional del filtro
+func applyOrgFilter(query *gorm.DB, scope repositories.OrgFilter) *gorm.DB {
+	if scope.IsZero() {
+		return query
+	}
+	condition, args := orgFilterSQL(scope)
+	return query.Where(condition, args)
+}
+
+// withOrgFilter agrega a args los argumentos con nombre del filtro de jerarquía y retorna su condición
+func withOrgFilter(args map[string]any, scope repositories.OrgFilter) string {
+	condition, orgArgs := orgFilterSQL(scope)
+	maps.Copy(args, orgArgs)
+	return condition
+}
+
+// sedeScopeSQL traduce el alcance a una condición sobre las filas de userservice.sedes con argumentos con nombre
+func sedeScopeSQL(scope repositories.OrgFilter) (string, map[string]any) {
+	var conditions []string
+	args := map[string]any{}
+	if scope.SedeID != nil {
+		conditions = append(conditions, "id_sede = @org_sede")
+		args["org_sede"] = *scope.SedeID
+	}
+	if scope.CentroID != nil {
+		conditions = append(conditions, "centro_id_sede = @org_centro")
+		args["org_centro"] = *scope.CentroID
+	}
+	if scope.RegionalID != nil {
+		conditions = append(conditions,
+			"centro_id_sede IN (SELECT id_centro FROM userservice.centros WHERE regional_id_centro = @org_regional)")
+		args["org_regional"] = *scope.RegionalID
+	}
+
+	if len(conditions) == 0 {
+		return "TRUE", args
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// applyTenantSedes acota la consulta con condition, que recibe en %s la subconsulta de los IDs de las sedes
+// del alcance del contexto; sin alcance, bypass ni marca de proceso interno la consulta falla con ErrNoTenant
+// Lo usan las fichas, las asignaciones y la jerarquía, que se acotan por la sede de la ficha o por las sedes que contienen
+func applyTenantSedes(ctx context.Context, query *gorm.DB, condition string) *gorm.DB {
+	scope, scoped, err := repositories.TenantScope(ctx)
+	if err != nil {
+		query.AddError(err)
+		return query
+	}
+	if !scoped {
+		return query
+	}
+	sedes, args := sedeScopeSQL(scope)
+	return query.Where(fmt.Sprintf(condition, "SELECT id_sede FROM userservice.sedes WHERE "+sedes), args)
+}
+
+// applyTenant acota la consulta de usuarios al alcance de sede del contexto, si tiene
+// Sin alcance, bypass ni marca de proceso interno la consulta falla con ErrNoTenant al ejecutarse
+func applyTenant(ctx context.Context, query *gorm.DB) *gorm.DB {
+	scope, scoped, err := repositories.TenantScope(ctx)
+	if err != nil {
+		query.AddError(err)
+		return query
+	}
+	if !scoped {
+		return query
+	}
+	return applyOrgFilter(query, scope)
+}
+
+// checkTenantSede retorna ErrOutsideTenant si la sede no está en el alcance del contexto
+// Sin sede o con una sede que no existe solo se cumple sin alcance
+func checkTenantSede(ctx context.Context, db *gorm.DB, sedeID *uuid.UUID) error {
+	_, scoped, err := repositories.TenantScope(ctx)
+	if err != nil || !scoped {
+		return err
+	}
+
+	var path *repositories.SedePath
+	if sedeID != nil {
+		if path, err = findSedePath(db.WithContext(ctx), *sedeID); err != nil {
+			return err
+		}
+	}
+
+	return repositories.CheckTenantSede(ctx, path)
+} // fin checkTenantSede
+
+// requireRecord retorna notFound si ninguna fila del modelo cumple la condición
+func requireRecord(tx *gorm.DB, model any, notFound error, query string, args ...any) error {
+	var count int64
+	if err := tx.Model(model).Where(query, args...).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// findOne ejecuta la consulta y retorna el primer registro, nil si no existe
+func findOne[T any](query *gorm.DB) (*T, error) {
+	var record T
+	err := query.First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// requireUserRowsAffected convierte una modificación sin filas afectadas en ErrUserNotFound
+func requireUserRowsAffected(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return repositories.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// requireMFARowsAffected convierte una modificación sin filas afectadas en ErrMFARecordNotFound
+func requireMFARowsAffected(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return repositories.ErrMFARecordNotFound
+	}
+
+	return nil
+}
+
+// isUniqueViolation indica si el error es una violación de un índice único
+func isUniqueViolation(dialect Dialect, err error) bool {
+	_, unique := dialect.UniqueViolation(err)
+	return unique || errors.Is(err, gorm.ErrDuplicatedKey)
+}
@@ -0,0 +1,142 @@
+package relational
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecordLogin guarda el inicio de sesión y actualiza LastLogin si es el más reciente
+// UpdateColumn evita que GORM modifique updated_at_user: iniciar sesión no edita al usuario
+func (r *UserRepository) RecordLogin(ctx context.Context, event *entities.LoginEvent) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := applyTenant(ctx, tx.Model(&entities.User{})).
+			Where("id_user = ? AND deleted_at_user IS NULL", event.UserID).
+			UpdateColumn("last_login_user", gorm.Expr("CASE WHEN last_login_user IS NULL OR last_login_user < ? THEN ? ELSE last_login_user END",
+				event.OccurredAt, event.OccurredAt))
+		if err := requireUserRowsAffected(result); err != nil {
+			return err
+		}
+
+		return tx.Create(event).Error
+	})
+} // fin RecordLogin
+
+// GetLoginHistory obtiene los inicios de sesión del usuario del más reciente al más antiguo
+func (r *UserRepository) GetLoginHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*entities.LoginEvent, error) {
+	found, err := r.exists(ctx, "id_user = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	query := r.db.WithContext(ctx).
+		Where("user_id_login_event = ?", userID).
+		Order("occurred_at_login_event DESC, id_login_event")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var history []*entities.LoginEvent
+	if err := query.Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	return history, nil
+} // fin GetLoginHistory
+
+// activitySQL calcula el último inicio de sesión de cada usuario hasta @at y lo clasifica por segmento
+// Recibe la expresión del segmento y la condición de orgFilterSQL
+// El más reciente se elige con CASE: GREATEST no existe en SQLite y su MAX escalar retorna NULL si un argumento lo es
+const activitySQL = `
+WITH logins AS (
+	SELECT %s AS key,
+		is_active_user AS enabled,
+		(SELECT MAX(occurred_at_login_event) FROM userservice.login_events
+		 WHERE user_id_login_event = id_user AND occurred_at_login_event <= @at) AS event_seen,
+		CASE WHEN last_login_user <= @at THEN last_login_user END AS login_seen
+	FROM userservice.users
+	WHERE deleted_at_user IS NULL AND created_at_user <= @at AND %s
+), seen AS (
+	SELECT key, enabled,
+		CASE WHEN login_seen IS NULL OR event_seen > login_seen THEN event_seen ELSE login_seen END AS last_seen
+	FROM logins
+)
+SELECT key,
+	COUNT(*) AS users,
+	COUNT(*) FILTER (WHERE last_seen > @daily) AS daily,
+	COUNT(*) FILTER (WHERE last_seen > @weekly) AS weekly,
+	COUNT(*) FILTER (WHERE last_seen > @monthly) AS monthly,
+	COUNT(*) FILTER (WHERE enabled AND last_seen IS NULL) AS never_logged_in,
+	COUNT(*) FILTER (WHERE enabled AND last_seen <= @dormant) AS dormant
+FROM seen
+GROUP BY key`
+
+// GetActivityMetrics obtiene las métricas de actividad a la fecha de corte
+func (r *UserRepository) GetActivityMetrics(ctx context.Context, query repositories.ActivityQuery) (*repositories.ActivityReport, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	limits := query.Thresholds()
+	args := map[string]any{
+		"at":      query.At,
+		"daily":   limits.Daily,
+		"weekly":  limits.Weekly,
+		"monthly": limits.Monthly,
+		"dormant": limits.Dormant,
+	}
+	orgFilter, err := repositories.ScopeOrgFilter(ctx, query.OrgFilter)
+	if err != nil {
+		return nil, err
+	}
+	scope := withOrgFilter(args, orgFilter)
+
+	var rows []struct {
+		Key string
+		repositories.ActivityCounts
+	}
+	err = r.db.WithContext(ctx).
+		Raw(fmt.Sprintf(activitySQL, activityKeyColumn(query.Segment), scope), args).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report := repositories.NewActivityReport(query)
+	for _, row := range rows {
+		report.Add(row.Key, row.ActivityCounts)
+	}
+
+	return report, nil
+} // fin GetActivityMetrics
+
+// activityKeyColumn retorna la expresión SQL del valor de la dimensión del segmento
+func activityKeyColumn(segment repositories.ActivitySegment) string {
+	switch segment {
+	case repositories.SegmentRole:
+		return "role_user"
+	case repositories.SegmentSede:
+		return orgKeySQL(repositories.OrgLevelSede)
+	case repositories.SegmentCentro:
+		return orgKeySQL(repositories.OrgLevelCentro)
+	case repositories.SegmentRegional:
+		return orgKeySQL(repositories.OrgLevelRegional)
+	case repositories.SegmentFicha:
+		return "COALESCE(ficha_id_user, '')"
+	default:
+		return "''"
+	}
+}
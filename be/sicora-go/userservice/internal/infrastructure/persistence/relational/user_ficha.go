@@ -0,0 +1,178 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetByFicha obtiene todos los aprendices de una ficha específica, actuales o en la fecha dada
+func (r *UserRepository) GetByFicha(ctx context.Context, fichaID string, on time.Time) ([]*entities.User, error) {
+	var count int64
+	err := applyTenantSedes(ctx, r.db.WithContext(ctx).Model(&entities.Ficha{}), fichaTenantSQL).
+		Where("number_ficha = ?", fichaID).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, repositories.ErrFichaNotFound
+	}
+
+	query := r.notDeleted(ctx).Where("role_user = ?", entities.RoleAprendiz)
+	if on.IsZero() {
+		query = query.Where("ficha_id_user = ?", fichaID)
+	} else {
+		query = query.Where(userFichaOnSQL+" = ?", entities.CivilDate(on), fichaID)
+	}
+
+	var users []*entities.User
+	if err := query.Order("last_name_user ASC, first_name_user ASC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+} // fin GetByFicha
+
+// AssignFicha asigna el aprendiz sin ficha a la ficha respetando su estado y capacidad
+func (r *UserRepository) AssignFicha(ctx context.Context, userID uuid.UUID, fichaID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// El aprendiz queda bloqueado hasta el final: dos asignaciones simultáneas no pueden verlo ambas sin ficha
+		user, err := lockUser(applyTenant(ctx, tx.Model(&entities.User{})), userID)
+		if err != nil {
+			return err
+		}
+		if user != nil && user.FichaID != nil {
+			if *user.FichaID == fichaID {
+				return nil
+			}
+			return repositories.ErrAlreadyInFicha
+		}
+
+		ficha, err := lockFicha(applyTenantSedes(ctx, tx, fichaTenantSQL), fichaID)
+		if err != nil {
+			return err
+		}
+
+		members, err := countFichaMembers(tx, fichaID)
+		if err != nil {
+			return err
+		}
+
+		if err := repositories.CheckFichaAssignment(user, ficha, members); err != nil {
+			return err
+		}
+
+		if err := tx.Create(entities.NewFichaEnrollment(userID, fichaID, time.Now())).Error; err != nil {
+			return err
+		}
+
+		return moveToFicha(tx, user, fichaID)
+	})
+} // fin AssignFicha
+
+// TransferFicha traslada al aprendiz a otra ficha y registra el traslado en su historial
+func (r *UserRepository) TransferFicha(ctx context.Context, transfer *entities.FichaMovement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Como en AssignFicha, dos traslados simultáneos no pueden partir ambos de la misma ficha de origen
+		user, err := lockUser(applyTenant(ctx, tx.Model(&entities.User{})), transfer.UserID)
+		if err != nil {
+			return err
+		}
+
+		// Quien aprueba puede ser de otra sede, p. ej. un coordinador del centro
+		var approver *entities.User
+		if transfer.ApprovedBy != nil {
+			approver, err = findOne[entities.User](tx.Where("id_user = ? AND deleted_at_user IS NULL", *transfer.ApprovedBy))
+			if err != nil {
+				return err
+			}
+		}
+
+		last, err := findOne[entities.FichaMovement](tx.
+			Where("user_id_ficha_movement = ?", transfer.UserID).
+			Order("effective_date_ficha_movement DESC, created_at_ficha_movement DESC, id_ficha_movement DESC"))
+		if err != nil {
+			return err
+		}
+
+		ficha, err := lockFicha(applyTenantSedes(ctx, tx, fichaTenantSQL), transfer.ToFichaID)
+		if err != nil {
+			return err
+		}
+
+		members, err := countFichaMembers(tx, transfer.ToFichaID)
+		if err != nil {
+			return err
+		}
+
+		if err := repositories.CheckFichaTransfer(transfer, user, approver, last, ficha, members); err != nil {
+			return err
+		}
+
+		if err := tx.Create(transfer).Error; err != nil {
+			return err
+		}
+
+		return moveToFicha(tx, user, transfer.ToFichaID)
+	})
+} // fin TransferFicha
+
+// GetFichaHistory obtiene los movimientos de ficha del aprendiz del más antiguo al más reciente
+func (r *UserRepository) GetFichaHistory(ctx context.Context, userID uuid.UUID) ([]*entities.FichaMovement, error) {
+	exists, err := r.exists(ctx, "id_user = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	var history []*entities.FichaMovement
+	err = r.db.WithContext(ctx).
+		Where("user_id_ficha_movement = ?", userID).
+		Order("effective_date_ficha_movement ASC, created_at_ficha_movement ASC, id_ficha_movement ASC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+} // fin GetFichaHistory
+
+// countFichaMembers cuenta los aprendices actuales de la ficha
+func countFichaMembers(tx *gorm.DB, fichaID string) (int, error) {
+	var members int64
+	err := tx.Model(&entities.User{}).
+		Where("ficha_id_user = ? AND role_user = ? AND deleted_at_user IS NULL", fichaID, entities.RoleAprendiz).
+		Count(&members).Error
+	return int(members), err
+}
+
+// lockUser obtiene el usuario no eliminado bloqueándolo hasta el final de la transacción, nil si no existe
+func lockUser(tx *gorm.DB, userID uuid.UUID) (*entities.User, error) {
+	return findOne[entities.User](tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id_user = ? AND deleted_at_user IS NULL", userID))
+}
+
+// moveToFicha cambia la ficha actual del usuario incrementando su versión
+// Solo guarda si la versión no cambió desde que se leyó el usuario; si cambió retorna ErrVersionConflict
+func moveToFicha(tx *gorm.DB, user *entities.User, fichaID string) error {
+	result := tx.Model(&entities.User{}).
+		Where("id_user = ? AND version_user = ?", user.ID, user.Version).
+		Updates(map[string]any{
+			"ficha_id_user":   fichaID,
+			"updated_at_user": time.Now(),
+			"version_user":    gorm.Expr("version_user + 1"),
+		})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return repositories.ErrVersionConflict
+	}
+	return result.Error
+}
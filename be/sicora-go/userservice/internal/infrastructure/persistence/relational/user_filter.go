@@ -0,0 +1,176 @@
+package relational
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"userservice/internal/domain/filter"
+	"userservice/internal/domain/repositories"
+)
+
+// userColumns mapea los campos de repositories.UserFilterSchema a sus columnas
+var userColumns = map[string]string{
+	"id":                "id_user",
+	"first_name":        "first_name_user",
+	"last_name":         "last_name_user",
+	"email":             "email_user",
+	"document_number":   "document_number_user",
+	"document_type":     "document_type_user",
+	"birth_date":        "birth_date_user",
+	"role":              "role_user",
+	"status":            "status_user",
+	"is_active":         "is_active_user",
+	"email_verified":    "email_verified_user",
+	"email_verified_at": "email_verified_at_user",
+	"ficha_id":          "ficha_id_user",
+	"sede_id":           "sede_id_user",
+	"created_at":        "created_at_user",
+	"updated_at":        "updated_at_user",
+	"last_login":        "last_login_user",
+}
+
+// comparisonOperators traduce los operadores de orden a SQL
+var comparisonOperators = map[filter.Op]string{
+	filter.OpEq: "=",
+	filter.OpNe: "<>",
+	filter.OpLt: "<",
+	filter.OpLe: "<=",
+	filter.OpGt: ">",
+	filter.OpGe: ">=",
+}
+
+// filterSQL traduce el AST a una condición parametrizada; los valores nunca se interpolan
+// Las comparaciones sobre columnas nulas se envuelven en COALESCE para que NOT se comporte
+// igual que filter.Evaluate (comparar con NULL es falso, no desconocido)
+func filterSQL(dialect Dialect, expr filter.Expr) (string, []any) {
+	switch e := expr.(type) {
+	case *filter.And:
+		return joinSQL(dialect, e.Terms, " AND ")
+	case *filter.Or:
+		return joinSQL(dialect, e.Terms, " OR ")
+	case *filter.Not:
+		sql, args := filterSQL(dialect, e.Expr)
+		return "NOT " + sql, args
+	case *filter.Comparison:
+		return comparisonSQL(dialect, e)
+	default:
+		return "TRUE", nil
+	}
+}
+
+func joinSQL(dialect Dialect, terms []filter.Expr, connector string) (string, []any) {
+	parts := make([]string, len(terms))
+	var args []any
+	for i, term := range terms {
+		sql, termArgs := filterSQL(dialect, term)
+		parts[i] = sql
+		args = append(args, termArgs...)
+	}
+	return "(" + strings.Join(parts, connector) + ")", args
+}
+
+// comparisonSQL traduce una comparación sobre una columna de la lista blanca
+func comparisonSQL(dialect Dialect, c *filter.Comparison) (string, []any) {
+	column := userColumns[c.Field]
+	operand := c.Values[0]
+
+	if operand.IsNull() {
+		if c.Op == filter.OpEq {
+			return "(" + column + " IS NULL)", nil
+		}
+		return "(" + column + " IS NOT NULL)", nil
+	}
+
+	var sql string
+	var args []any
+	switch c.Op {
+	case filter.OpIn:
+		values := make([]any, 0, len(c.Values))
+		for _, value := range c.Values {
+			if !value.IsNull() {
+				values = append(values, value.Any())
+			}
+		}
+		sql, args = column+" IN ?", []any{values}
+	case filter.OpContains:
+		sql, args = dialect.Lower(column)+" LIKE ?"+likeEscape, []any{"%" + escapeLike(strings.ToLower(operand.Str)) + "%"}
+	case filter.OpStartsWith:
+		sql, args = dialect.Lower(column)+" LIKE ?"+likeEscape, []any{escapeLike(strings.ToLower(operand.Str)) + "%"}
+	default:
+		sql, args = column+" "+comparisonOperators[c.Op]+" ?", []any{operand.Any()}
+	}
+
+	if repositories.UserFilterSchema[c.Field].Nullable {
+		return "COALESCE(" + sql + ", FALSE)", args
+	}
+	return "(" + sql + ")", args
+} // fin comparisonSQL
+
+// orderClause construye el ORDER BY con el ID como desempate en la dirección de la última columna
+// Los nulos se ubican al final en orden ascendente, como en PostgreSQL, también en SQLite que los ubica primero
+func orderClause(fields []filter.SortField, reverse bool) string {
+	parts := make([]string, 0, len(fields)+1)
+	direction := ""
+	for _, field := range fields {
+		desc := field.Desc != reverse
+		direction = sortDirection(desc)
+		part := userColumns[field.Field] + direction
+		if repositories.UserFilterSchema[field.Field].Nullable {
+			part += nullsPosition(desc)
+		}
+		parts = append(parts, part)
+	}
+
+	return strings.Join(append(parts, "id_user"+direction), ", ")
+}
+
+// keysetCondition selecciona las filas posteriores al cursor en el orden del listado
+// (o anteriores si reverse): (a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id < ?)
+func keysetCondition(fields []filter.SortField, cursor *repositories.UserCursor, reverse bool) (string, []any) {
+	keys := cursor.Keys()
+	branches := make([]string, 0, len(fields)+1)
+	var args []any
+
+	var prefix []string
+	var prefixArgs []any
+	desc := false
+	for i, field := range fields {
+		desc = field.Desc
+		column := userColumns[field.Field]
+
+		branch := append(slices.Clone(prefix), fmt.Sprintf("%s %s ?", column, keysetOperator(desc, reverse)))
+		branches = append(branches, "("+strings.Join(branch, " AND ")+")")
+		args = append(append(args, prefixArgs...), keys[i].Any())
+
+		prefix = append(prefix, column+" = ?")
+		prefixArgs = append(prefixArgs, keys[i].Any())
+	}
+
+	branch := append(prefix, fmt.Sprintf("id_user %s ?", keysetOperator(desc, reverse)))
+	branches = append(branches, "("+strings.Join(branch, " AND ")+")")
+	args = append(append(args, prefixArgs...), cursor.ID)
+
+	return "(" + strings.Join(branches, " OR ") + ")", args
+} // fin keysetCondition
+
+func keysetOperator(desc, reverse bool) string {
+	if desc != reverse {
+		return "<"
+	}
+	return ">"
+}
+
+func nullsPosition(desc bool) string {
+	if desc {
+		return " NULLS FIRST"
+	}
+	return " NULLS LAST"
+}
+
+func sortDirection(desc bool) string {
+	if desc {
+		return " DESC"
+	}
+	return " ASC"
+}
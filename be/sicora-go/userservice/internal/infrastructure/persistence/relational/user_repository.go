@@ -0,0 +1,723 @@
+package relational
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Verificar que implementa la interfaz
+var _ repositories.UserRepository = (*UserRepository)(nil)
+
+// likeEscape declara '\' como carácter de escape de LIKE: PostgreSQL ya lo usa por defecto y SQLite no tiene ninguno
+const likeEscape = ` ESCAPE '\'`
+
+// errBulkRollback señala que la transacción atómica debe revertirse por fallas de fila
+var errBulkRollback = errors.New("bulk: revertir transacción")
+
+// UserRepository implementa repositories.UserRepository sobre GORM
+type UserRepository struct {
+	db      *gorm.DB
+	dialect Dialect
+}
+
+// NewUserRepository crea una nueva instancia del repositorio de usuarios
+func NewUserRepository(db *gorm.DB, dialect Dialect) *UserRepository {
+	return &UserRepository{db: db, dialect: dialect}
+}
+
+// Create crea un nuevo usuario en el repositorio
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	if user.Version < 1 {
+		user.Version = 1
+	}
+
+	if err := checkTenantSede(ctx, r.db, user.SedeID); err != nil {
+		return err
+	}
+
+	return r.translateError(r.db.WithContext(ctx).Create(user).Error)
+}
+
+// GetByID obtiene un usuario por su ID, retorna nil si no existe
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	return r.first(ctx, "id_user = ?", id)
+}
+
+// GetByEmail obtiene un usuario por su email, retorna nil si no existe
+func (r *UserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return r.first(ctx, r.dialect.Lower("email_user")+" = ?", email)
+}
+
+// GetByDocument obtiene un usuario por su tipo y número de documento, retorna nil si no existe
+func (r *UserRepository) GetByDocument(ctx context.Context, documentType entities.DocumentType, documentNumber string) (*entities.User, error) {
+	return r.first(ctx, "document_type_user = ? AND document_number_user = ?", documentType, entities.NormalizeDocumentNumber(documentNumber))
+}
+
+// Update actualiza todos los campos de un usuario existente
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	return r.update(ctx, user)
+}
+
+// Delete marca un usuario como eliminado (soft delete) y lo desactiva
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error {
+	return requireUserRowsAffected(r.notDeleted(ctx).
+		Where("id_user = ?", id).
+		Updates(softDeleteValues(deletedBy)))
+}
+
+// Restore revierte el soft delete de un usuario y lo reactiva
+func (r *UserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	result := r.users(ctx).
+		Where("id_user = ? AND deleted_at_user IS NOT NULL", id).
+		Updates(map[string]any{
+			"deleted_at_user": nil,
+			"deleted_by_user": nil,
+			"status_user":     entities.UserStatusActive,
+			"is_active_user":  true,
+			"updated_at_user": time.Now(),
+			"version_user":    gorm.Expr("version_user + 1"),
+		})
+	result.Error = r.translateError(result.Error)
+
+	return requireUserRowsAffected(result)
+} // fin Restore
+
+// Purge elimina definitivamente los usuarios eliminados antes de la fecha dada
+// Los métodos, códigos y sesiones MFA y el historial de inicios de sesión se eliminan en cascada
+func (r *UserRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result := applyTenant(ctx, r.db.WithContext(ctx)).
+		Where("deleted_at_user IS NOT NULL AND deleted_at_user < ?", deletedBefore).
+		Delete(&entities.User{})
+	return result.RowsAffected, result.Error
+}
+
+// List obtiene una lista paginada de usuarios con filtros opcionales
+func (r *UserRepository) List(ctx context.Context, filters repositories.UserFilters) (*repositories.PaginatedUsers, error) {
+	if err := filters.Normalize(); err != nil {
+		return nil, err
+	}
+
+	query, err := r.applyFilters(r.users(ctx), filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if filters.Pagination == repositories.PaginationCursor {
+		return r.listByCursor(query, filters)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var users []*entities.User
+	err = query.
+		Order(r.listOrder(filters)).
+		Offset(filters.Offset()).
+		Limit(filters.PageSize).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return repositories.NewPaginatedUsers(users, total, filters.Page, filters.PageSize), nil
+} // fin List
+
+// listByCursor obtiene una página por keyset: las columnas de orden + id comparadas contra el cursor
+// No calcula el total para evitar el COUNT sobre tablas grandes
+func (r *UserRepository) listByCursor(query *gorm.DB, filters repositories.UserFilters) (*repositories.PaginatedUsers, error) {
+	cursor, err := filters.ValidateCursorMode()
+	if err != nil {
+		return nil, err
+	}
+
+	// Hacia atrás se recorre en sentido inverso desde el cursor y luego se restablece el orden
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		condition, args := keysetCondition(filters.Sort, cursor, backward)
+		query = query.Where(condition, args...)
+	}
+
+	var users []*entities.User
+	err = query.
+		Order(orderClause(filters.Sort, backward)).
+		Limit(filters.PageSize + 1).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if backward {
+		slices.Reverse(users)
+	}
+
+	return repositories.NewCursorPage(users, filters, cursor), nil
+} // fin listByCursor
+
+// ExistsByEmail verifica si existe un usuario con el email dado
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email entities.Email) (bool, error) {
+	return r.exists(ctx, r.dialect.Lower("email_user")+" = ?", email)
+}
+
+// ExistsByDocument verifica si existe un usuario con el tipo y número de documento dados
+func (r *UserRepository) ExistsByDocument(ctx context.Context, documentType entities.DocumentType, documentNumber string) (bool, error) {
+	return r.exists(ctx, "document_type_user = ? AND document_number_user = ?", documentType, entities.NormalizeDocumentNumber(documentNumber))
+}
+
+// BulkCreate crea múltiples usuarios reportando el resultado por fila
+// En modo atómico cada fila usa un savepoint para poder reportar todas las fallas antes de revertir
+func (r *UserRepository) BulkCreate(ctx context.Context, users []*entities.User, mode repositories.BulkMode) (*repositories.BulkOperationResult, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &repositories.BulkOperationResult{Total: len(users)}
+	if len(users) == 0 {
+		return result, nil
+	}
+
+	if mode != repositories.BulkAtomic {
+		for index, user := range users {
+			if user.Version < 1 {
+				user.Version = 1
+			}
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			rowErr := checkTenantSede(ctx, r.db, user.SedeID)
+			if rowErr == nil {
+				rowErr = r.translateError(r.db.WithContext(ctx).Create(user).Error)
+			}
+			result.RecordUser(index, user, rowErr)
+		}
+		return result, nil
+	}
+
+	// Se insertan copias: si el lote se revierte, los usuarios dados quedan sin el ID, la versión
+	// ni el email canónico asignados al insertarlos
+	rows := make([]*entities.User, len(users))
+	for i, user := range users {
+		row := *user
+		if row.Version < 1 {
+			row.Version = 1
+		}
+		rows[i] = &row
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for index, user := range rows {
+			savepoint := fmt.Sprintf("bulk_create_%d", index)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return err
+			}
+
+			rowErr := checkTenantSede(ctx, tx, user.SedeID)
+			if rowErr == nil {
+				if rowErr = tx.Create(user).Error; rowErr != nil {
+					if err := tx.RollbackTo(savepoint).Error; err != nil {
+						return err
+					}
+				}
+			}
+			result.RecordUser(index, user, r.translateError(rowErr))
+		}
+
+		if result.Failed > 0 {
+			return errBulkRollback
+		}
+		return nil
+	})
+
+	if errors.Is(err, errBulkRollback) {
+		result.RollBack()
+		return result, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, user := range rows {
+		*users[i] = *user
+	}
+	return result, nil
+} // fin BulkCreate
+
+// BulkUpdate actualiza múltiples usuarios identificados por email
+// Cada actualización es independiente: los fallos se reportan por fila
+func (r *UserRepository) BulkUpdate(ctx context.Context, updates map[entities.Email]*entities.User) (*repositories.BulkOperationResult, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &repositories.BulkOperationResult{Total: len(updates)}
+
+	for index, email := range sortedKeys(updates) {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		user := updates[email]
+		existing, err := r.GetByEmail(ctx, email)
+		if err == nil && existing == nil {
+			err = repositories.ErrUserNotFound
+		}
+
+		if err == nil {
+			user.ID = existing.ID
+			user.CreatedAt = existing.CreatedAt
+			err = r.update(ctx, user)
+		}
+
+		result.Record(index, email.String(), err)
+	}
+
+	return result, nil
+} // fin BulkUpdate
+
+// BulkDelete elimina (soft delete) múltiples usuarios por emails
+func (r *UserRepository) BulkDelete(ctx context.Context, emails []entities.Email, deletedBy *uuid.UUID) (*repositories.BulkOperationResult, error) {
+	return r.bulkUpdateByEmail(ctx, emails, func() map[string]any {
+		return softDeleteValues(deletedBy)
+	})
+}
+
+// BulkStatusChange cambia el estado de múltiples usuarios
+func (r *UserRepository) BulkStatusChange(ctx context.Context, emails []entities.Email, isActive bool) (*repositories.BulkOperationResult, error) {
+	return r.bulkUpdateByEmail(ctx, emails, func() map[string]any {
+		return map[string]any{
+			"is_active_user":  isActive,
+			"updated_at_user": time.Now(),
+			"version_user":    gorm.Expr("version_user + 1"),
+		}
+	})
+}
+
+// GetMultipleByEmails obtiene múltiples usuarios por sus emails
+func (r *UserRepository) GetMultipleByEmails(ctx context.Context, emails []entities.Email) ([]*entities.User, error) {
+	users := []*entities.User{}
+	if len(emails) == 0 {
+		return users, nil
+	}
+
+	if err := r.notDeleted(ctx).Where(r.dialect.Lower("email_user")+" IN ?", emails).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetTotalUsersByRole obtiene el conteo de usuarios por rol
+func (r *UserRepository) GetTotalUsersByRole(ctx context.Context) (map[string]int, error) {
+	var rows []groupCount
+	err := r.notDeleted(ctx).
+		Select("role_user AS label, COUNT(*) AS total").
+		Group("role_user").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return groupCountsToMap(rows), nil
+}
+
+// GetTotalUsersByProgram obtiene el conteo de usuarios por código del programa de su ficha
+func (r *UserRepository) GetTotalUsersByProgram(ctx context.Context) (map[string]int, error) {
+	var rows []groupCount
+	err := r.notDeleted(ctx).
+		Joins("JOIN userservice.fichas ON number_ficha = ficha_id_user").
+		Joins("JOIN userservice.programs ON id_program = program_id_ficha").
+		Select("code_program AS label, COUNT(*) AS total").
+		Group("code_program").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return groupCountsToMap(rows), nil
+}
+
+// GetTotalUsersByOrgLevel obtiene el conteo de usuarios por nodo del nivel al que pertenece su sede
+func (r *UserRepository) GetTotalUsersByOrgLevel(ctx context.Context, level repositories.OrgLevel, scope repositories.OrgFilter) (map[string]int, error) {
+	if !level.Valid() {
+		return nil, fmt.Errorf("%w: %q", repositories.ErrInvalidOrgLevel, level)
+	}
+
+	var rows []groupCount
+	err := applyOrgFilter(r.notDeleted(ctx), scope).
+		Select(orgKeySQL(level) + " AS label, COUNT(*) AS total").
+		Where("sede_id_user IS NOT NULL").
+		Group("label").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := groupCountsToMap(rows)
+	delete(counts, repositories.TrendKeyNone) // Sedes sin registrar, anteriores a la jerarquía
+	return counts, nil
+} // fin GetTotalUsersByOrgLevel
+
+// GetUserRegistrationTrend obtiene la serie de registros por intervalo en la zona de la consulta
+// Los intervalos se cortan con TrendBucket del dialecto en la zona pedida, sin depender de la zona de la sesión
+func (r *UserRepository) GetUserRegistrationTrend(ctx context.Context, query repositories.RegistrationTrendQuery) (*repositories.RegistrationTrend, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	key, err := trendKeyColumn(query.Breakdown)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Bucket string
+		Key    string
+		Total  int
+	}
+	err = applyOrgFilter(r.notDeleted(ctx), query.OrgFilter).
+		Select(r.dialect.TrendBucket("created_at_user")+" AS bucket, "+key+" AS key, COUNT(*) AS total",
+			string(query.Bucket), query.Location.String()).
+		Where("created_at_user >= ? AND created_at_user < ?", query.From, query.To).
+		Group("bucket, key").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	trend := repositories.NewRegistrationTrend(query)
+	for _, row := range rows {
+		start, err := time.ParseInLocation(time.DateOnly, row.Bucket, query.Location)
+		if err != nil {
+			return nil, err
+		}
+		trend.Add(start, row.Key, row.Total)
+	}
+
+	return trend, nil
+} // fin GetUserRegistrationTrend
+
+// trendKeyColumn retorna la expresión SQL del valor de la dimensión del desglose
+func trendKeyColumn(breakdown repositories.TrendBreakdown) (string, error) {
+	switch breakdown {
+	case repositories.BreakdownRole:
+		return "role_user", nil
+	case repositories.BreakdownSede:
+		return orgKeySQL(repositories.OrgLevelSede), nil
+	case repositories.BreakdownCentro:
+		return orgKeySQL(repositories.OrgLevelCentro), nil
+	case repositories.BreakdownRegional:
+		return orgKeySQL(repositories.OrgLevelRegional), nil
+	case repositories.BreakdownProgram:
+		return "COALESCE(" + userProgramSQL + ", '')", nil
+	case repositories.BreakdownNone:
+		return "''", nil
+	default:
+		return "", repositories.ErrUnsupportedFilter
+	}
+}
+
+// AggregateDashboard cruza los usuarios no eliminados por rol, programa, sede y estado en una sola consulta
+// El programa es el de la ficha del usuario; sin ficha o sin programa la celda queda con DashboardKeyNone
+func (r *UserRepository) AggregateDashboard(ctx context.Context) (*entities.DashboardSnapshot, error) {
+	snapshot := entities.NewDashboardSnapshot(time.Now())
+
+	var rows []struct {
+		Role    string
+		Program string
+		Sede    string
+		Status  string
+		Total   int
+	}
+	err := r.notDeleted(ctx).
+		Select("role_user AS role, COALESCE("+userProgramSQL+", '') AS program, COALESCE(CAST(sede_id_user AS TEXT), '') AS sede, "+
+			"CASE WHEN is_active_user THEN ? ELSE ? END AS status, COUNT(*) AS total",
+			entities.DashboardStatusActive, entities.DashboardStatusInactive).
+		Group("role, program, sede, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		snapshot.Add(row.Role, row.Program, row.Sede, row.Status, row.Total)
+	}
+
+	return snapshot, nil
+} // fin AggregateDashboard
+
+// GetActiveInactiveCount obtiene el conteo de usuarios activos e inactivos
+func (r *UserRepository) GetActiveInactiveCount(ctx context.Context) (active int, inactive int, err error) {
+	var row struct {
+		Active   int
+		Inactive int
+	}
+
+	err = r.notDeleted(ctx).
+		Select("COUNT(*) FILTER (WHERE is_active_user) AS active, COUNT(*) FILTER (WHERE NOT is_active_user) AS inactive").
+		Scan(&row).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return row.Active, row.Inactive, nil
+} // fin GetActiveInactiveCount
+
+// first obtiene el primer usuario que cumple la condición, nil si no existe
+func (r *UserRepository) first(ctx context.Context, query string, args ...any) (*entities.User, error) {
+	return findOne[entities.User](r.notDeleted(ctx).Where(query, args...))
+}
+
+// exists verifica si existe al menos un usuario que cumple la condición
+func (r *UserRepository) exists(ctx context.Context, query string, args ...any) (bool, error) {
+	var count int64
+	err := r.notDeleted(ctx).Where(query, args...).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// notDeleted construye una consulta sobre los usuarios no eliminados del alcance de sede del contexto
+func (r *UserRepository) notDeleted(ctx context.Context) *gorm.DB {
+	return r.users(ctx).Where("deleted_at_user IS NULL")
+}
+
+// users construye una consulta sobre los usuarios del alcance de sede del contexto, eliminados incluidos
+func (r *UserRepository) users(ctx context.Context) *gorm.DB {
+	return applyTenant(ctx, r.db.WithContext(ctx).Model(&entities.User{}))
+}
+
+// update guarda todos los campos editables del usuario
+// Solo guarda si la versión almacenada coincide con user.Version y luego la incrementa
+// El usuario y su nueva sede deben estar en el alcance de sede del contexto
+func (r *UserRepository) update(ctx context.Context, user *entities.User) error {
+	if err := checkTenantSede(ctx, r.db, user.SedeID); err != nil {
+		// Un usuario fuera del alcance se comporta como inexistente
+		if found, existsErr := r.exists(ctx, "id_user = ?", user.ID); existsErr != nil || !found {
+			return cmp.Or(existsErr, repositories.ErrUserNotFound)
+		}
+		return err
+	}
+
+	expected := user.Version
+	updatedAt := user.UpdatedAt
+	user.Version = expected + 1
+	user.UpdatedAt = time.Now()
+
+	// last_login_user solo lo escribe RecordLogin, que no cambia la versión
+	omit := []string{"id_user", "created_at_user", "deleted_at_user", "deleted_by_user", "last_login_user"}
+	if user.Password == "" {
+		// Un usuario leído sin credenciales conserva su contraseña
+		omit = append(omit, "password_user")
+	}
+
+	result := r.users(ctx).
+		Where("id_user = ? AND version_user = ? AND deleted_at_user IS NULL", user.ID, expected).
+		Select("*").
+		Omit(omit...).
+		Updates(user)
+	if result.Error == nil && result.RowsAffected > 0 {
+		return nil
+	}
+
+	user.Version = expected
+	user.UpdatedAt = updatedAt
+	if result.Error != nil {
+		return r.translateError(result.Error)
+	}
+
+	var current int
+	err := r.users(ctx).
+		Select("version_user").
+		Where("id_user = ? AND deleted_at_user IS NULL", user.ID).
+		Scan(&current).Error
+	if err != nil {
+		return err
+	}
+
+	if current == 0 {
+		return repositories.ErrUserNotFound
+	}
+
+	return &repositories.VersionConflictError{
+		UserID:          user.ID,
+		ExpectedVersion: expected,
+		CurrentVersion:  current,
+	}
+} // fin update
+
+// bulkUpdateByEmail aplica los mismos valores a cada email y reporta el resultado por fila
+func (r *UserRepository) bulkUpdateByEmail(ctx context.Context, emails []entities.Email, values func() map[string]any) (*repositories.BulkOperationResult, error) {
+	if err := repositories.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &repositories.BulkOperationResult{Total: len(emails)}
+
+	for index, email := range emails {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		res := r.notDeleted(ctx).
+			Where(r.dialect.Lower("email_user")+" = ?", email).
+			Updates(values())
+
+		err := res.Error
+		if err == nil && res.RowsAffected == 0 {
+			err = repositories.ErrUserNotFound
+		}
+
+		result.Record(index, email.String(), err)
+	}
+
+	return result, nil
+} // fin bulkUpdateByEmail
+
+// applyFilters agrega las condiciones de UserFilters a la consulta
+func (r *UserRepository) applyFilters(query *gorm.DB, filters repositories.UserFilters) (*gorm.DB, error) {
+	switch filters.Deleted {
+	case repositories.DeletedInclude:
+	case repositories.DeletedOnly:
+		query = query.Where("deleted_at_user IS NOT NULL")
+	default:
+		query = query.Where("deleted_at_user IS NULL")
+	}
+
+	if filters.Rol != nil {
+		query = query.Where("role_user = ?", *filters.Rol)
+	}
+
+	if filters.FichaID != nil {
+		query = query.Where("ficha_id_user = ?", *filters.FichaID)
+	}
+
+	if filters.Programa != nil {
+		query = query.Where(userProgramSQL+" = ?", *filters.Programa)
+	}
+
+	query = applyOrgFilter(query, filters.OrgFilter)
+
+	if filters.IsActive != nil {
+		query = query.Where("is_active_user = ?", *filters.IsActive)
+	}
+
+	if filters.Filter != nil {
+		condition, args := filterSQL(r.dialect, filters.Filter)
+		query = query.Where(condition, args...)
+	}
+
+	// Cada término debe coincidir; los términos ya vienen sin tildes y en minúsculas
+	search := r.dialect.SearchFold("first_name_user") + " LIKE ?" + likeEscape +
+		" OR " + r.dialect.SearchFold("last_name_user") + " LIKE ?" + likeEscape +
+		" OR " + r.dialect.Lower("email_user") + " LIKE ?" + likeEscape + " OR document_number_user LIKE ?" + likeEscape
+	for _, token := range filters.SearchTokens() {
+		contains := "%" + escapeLike(token) + "%"
+		query = query.Where(search, contains, contains, contains, escapeLike(token)+"%")
+	}
+
+	return query, nil
+} // fin applyFilters
+
+// listOrder retorna el ORDER BY del listado: relevancia de la búsqueda o las columnas de filters.Sort
+func (r *UserRepository) listOrder(filters repositories.UserFilters) any {
+	if filters.SortsByRelevance() {
+		return r.relevanceOrder(filters.SearchTokens())
+	}
+	return orderClause(filters.Sort, false)
+}
+
+// relevanceOrder ordena por el mismo puntaje que repositories.SearchScore y desempata por apellido y nombre normalizados
+func (r *UserRepository) relevanceOrder(tokens []string) clause.OrderBy {
+	firstName, lastName := r.dialect.SearchFold("first_name_user"), r.dialect.SearchFold("last_name_user")
+	words := "' ' || " + firstName + " || ' ' || " + lastName + " || ' '"
+	score := fmt.Sprintf("CASE WHEN document_number_user LIKE ?%[5]s THEN %[1]d WHEN %[6]s LIKE ?%[5]s THEN %[2]d WHEN %[6]s LIKE ?%[5]s THEN %[3]d ELSE %[4]d END",
+		repositories.SearchScoreDocumentPrefix,
+		repositories.SearchScoreNameWord,
+		repositories.SearchScoreNamePrefix,
+		repositories.SearchScoreContains,
+		likeEscape, words)
+
+	cases := make([]string, 0, len(tokens))
+	vars := make([]any, 0, len(tokens)*3)
+	for _, token := range tokens {
+		cases = append(cases, score)
+		vars = append(vars, escapeLike(token)+"%", "% "+escapeLike(token)+" %", "% "+escapeLike(token)+"%")
+	}
+
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                "(" + strings.Join(cases, " + ") + ") DESC, " + lastName + " ASC, " + firstName + " ASC, id_user ASC",
+		Vars:               vars,
+		WithoutParentheses: true,
+	}}
+} // fin relevanceOrder
+
+// translateError convierte las violaciones de unicidad en *DuplicateUserError y las de la sede en ErrSedeNotFound
+func (r *UserRepository) translateError(err error) error {
+	if field, ok := r.dialect.UniqueViolation(err); ok {
+		return &repositories.DuplicateUserError{Field: field}
+	}
+
+	if r.dialect.SedeViolation(err) {
+		return repositories.ErrSedeNotFound
+	}
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return &repositories.DuplicateUserError{}
+	}
+
+	return err
+} // fin translateError
+
+// softDeleteValues retorna los valores que marcan a un usuario como eliminado
+func softDeleteValues(deletedBy *uuid.UUID) map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"deleted_at_user": now,
+		"deleted_by_user": deletedBy,
+		"status_user":     entities.UserStatusDeleted,
+		"is_active_user":  false,
+		"updated_at_user": now,
+		"version_user":    gorm.Expr("version_user + 1"),
+	}
+}
+
+// groupCount representa una fila de un conteo agrupado
+type groupCount struct {
+	Label string
+	Total int
+}
+
+func groupCountsToMap(rows []groupCount) map[string]int {
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Label] = row.Total
+	}
+	return counts
+}
+
+// sortedKeys retorna las llaves del mapa en orden para que los índices sean deterministas
+func sortedKeys(updates map[entities.Email]*entities.User) []entities.Email {
+	keys := make([]entities.Email, 0, len(updates))
+	for key := range updates {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// escapeLike escapa los comodines de LIKE en el término de búsqueda
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
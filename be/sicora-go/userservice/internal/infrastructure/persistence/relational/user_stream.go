@@ -0,0 +1,64 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+)
+
+// Stream recorre los usuarios que cumplen los filtros fila por fila sobre una sola consulta
+// Una sola consulta ve una foto consistente aunque otras conexiones escriban durante el recorrido:
+// PostgreSQL la toma al iniciar la sentencia y SQLite al iniciar la lectura, gracias a WAL
+// Ni pgx ni SQLite traen el resultado completo, así que la memoria usada es de una fila
+func (r *UserRepository) Stream(ctx context.Context, filters repositories.UserFilters) iter.Seq2[*entities.User, error] {
+	return func(yield func(*entities.User, error) bool) {
+		filters.Pagination, filters.Cursor = repositories.PaginationOffset, ""
+		if err := filters.Normalize(); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		tx := r.db.WithContext(ctx).Begin(&sql.TxOptions{ReadOnly: true})
+		if tx.Error != nil {
+			yield(nil, tx.Error)
+			return
+		}
+		defer tx.Rollback()
+
+		query, err := r.applyFilters(applyTenant(ctx, tx.Model(&entities.User{})), filters)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		rows, err := query.Order(r.listOrder(filters)).Rows()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			var user entities.User
+			if err := tx.ScanRows(rows, &user); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(&user, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+} // fin Stream
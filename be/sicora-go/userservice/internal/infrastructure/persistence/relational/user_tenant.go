@@ -0,0 +1,56 @@
+package relational
+
+import (
+	"context"
+	"time"
+
+	"userservice/internal/domain/entities"
+	"userservice/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordTenantBypass guarda el bypass de sede en la bitácora tras verificar el rol, la sede y el estado del actor
+// El actor se busca sin el alcance del contexto, porque un directivo nacional no tiene sede, y se bloquea
+// para que no cambie entre la verificación y el registro
+func (r *UserRepository) RecordTenantBypass(ctx context.Context, bypass *entities.TenantBypass) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		actor, err := findOne[entities.User](tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("id_user = ? AND deleted_at_user IS NULL", bypass.ActorID))
+		if err != nil {
+			return err
+		}
+		if err := repositories.CheckTenantBypassActor(actor); err != nil {
+			return err
+		}
+
+		return tx.Create(bypass).Error
+	})
+}
+
+// ListTenantBypasses obtiene los bypass de sede concedidos desde since, del más reciente al más antiguo
+// Con alcance de sede solo ve los de actores del alcance
+func (r *UserRepository) ListTenantBypasses(ctx context.Context, since time.Time) ([]*entities.TenantBypass, error) {
+	scope, scoped, err := repositories.TenantScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx)
+	if scoped {
+		condition, args := orgFilterSQL(scope)
+		query = query.Where("actor_id_tenant_bypass IN (SELECT id_user FROM userservice.users WHERE "+condition+")", args)
+	}
+
+	bypasses := []*entities.TenantBypass{}
+	err = query.
+		Where("granted_at_tenant_bypass >= ?", since).
+		Order("granted_at_tenant_bypass DESC, id_tenant_bypass").
+		Find(&bypasses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return bypasses, nil
+}
@@ -0,0 +1,97 @@
+// Package persistence arma los repositorios del servicio sobre el motor elegido en la configuración
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"userservice/internal/domain/repositories"
+	"userservice/internal/infrastructure/config"
+	"userservice/internal/infrastructure/persistence/cached"
+	"userservice/internal/infrastructure/persistence/postgres"
+	"userservice/internal/infrastructure/persistence/relational"
+	"userservice/internal/infrastructure/persistence/sqlite"
+
+	"gorm.io/gorm"
+)
+
+// Motores de base de datos soportados en DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Repositories agrupa los repositorios del servicio
+type Repositories struct {
+	DB *gorm.DB
+
+	Users        repositories.UserRepository
+	MFAMethods   repositories.MFAMethodRepository
+	BackupCodes  repositories.BackupCodeRepository
+	MFASessions  repositories.MFASessionRepository
+	MFAPolicies  repositories.MFAEnforcementPolicyRepository
+	Dashboards   repositories.DashboardSnapshotRepository
+	Programs     repositories.ProgramRepository
+	Fichas       repositories.FichaRepository
+	Assignments  repositories.InstructorAssignmentRepository
+	Organization repositories.OrganizationRepository
+}
+
+// Open conecta con el motor configurado y crea sus repositorios
+// El repositorio de usuarios se envuelve con la caché si hay un respaldo configurado
+func Open(ctx context.Context, cfg *config.Config) (*Repositories, error) {
+	var repos *Repositories
+	switch cfg.Database.Driver {
+	case DriverPostgres, "":
+		db, err := postgres.NewConnection(cfg.Database)
+		if err != nil {
+			return nil, err
+		}
+		repos = newRepositories(db, postgres.Dialect{})
+	case DriverSQLite:
+		db, err := sqlite.NewConnection(cfg.Database)
+		if err != nil {
+			return nil, err
+		}
+		repos = newRepositories(db, sqlite.Dialect{})
+	default:
+		return nil, fmt.Errorf("motor de base de datos desconocido: %q", cfg.Database.Driver)
+	}
+
+	userCache, err := cached.NewCache(ctx, cfg.Cache)
+	if err != nil {
+		repos.Close()
+		return nil, err
+	}
+	if userCache != nil {
+		repos.Users = cached.NewUserRepository(repos.Users, userCache, cached.OptionsFromConfig(cfg.Cache))
+	}
+
+	return repos, nil
+} // fin Open
+
+// newRepositories crea los repositorios de relational sobre la conexión con el dialecto de su motor
+func newRepositories(db *gorm.DB, dialect relational.Dialect) *Repositories {
+	return &Repositories{
+		DB:           db,
+		Users:        relational.NewUserRepository(db, dialect),
+		MFAMethods:   relational.NewMFAMethodRepository(db),
+		BackupCodes:  relational.NewBackupCodeRepository(db),
+		MFASessions:  relational.NewMFASessionRepository(db),
+		MFAPolicies:  relational.NewMFAEnforcementPolicyRepository(db),
+		Dashboards:   relational.NewDashboardSnapshotRepository(db),
+		Programs:     relational.NewProgramRepository(db, dialect),
+		Fichas:       relational.NewFichaRepository(db, dialect),
+		Assignments:  relational.NewInstructorAssignmentRepository(db),
+		Organization: relational.NewOrganizationRepository(db, dialect),
+	}
+}
+
+// Close cierra el pool de conexiones
+func (r *Repositories) Close() error {
+	sqlDB, err := r.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
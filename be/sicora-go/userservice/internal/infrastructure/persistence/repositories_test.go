@@ -0,0 +1,46 @@
+package persistence
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"userservice/internal/infrastructure/config"
+	"userservice/internal/infrastructure/persistence/cached"
+	"userservice/internal/infrastructure/persistence/relational"
+)
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{Driver: DriverSQLite, Path: filepath.Join(t.TempDir(), "sicora.db")},
+		Cache:    config.CacheConfig{Backend: "lru", LRUSize: 10, TTL: 60},
+	}
+
+	repos, err := Open(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Open(sqlite): %v", err)
+	}
+	if _, ok := repos.Users.(*cached.UserRepository); !ok {
+		t.Errorf("Users = %T, se esperaba el decorador con caché", repos.Users)
+	}
+	if err := repos.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	// Sin caché se usa el repositorio del motor directamente, sobre el mismo archivo
+	cfg.Cache.Backend = "none"
+	repos, err = Open(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Open(sqlite sin caché): %v", err)
+	}
+	defer repos.Close()
+	if _, ok := repos.Users.(*relational.UserRepository); !ok {
+		t.Errorf("Users = %T, se esperaba *relational.UserRepository", repos.Users)
+	}
+
+	cfg.Database.Driver = "oracle"
+	if _, err := Open(ctx, cfg); err == nil {
+		t.Error("Open con un motor desconocido debe fallar")
+	}
+}
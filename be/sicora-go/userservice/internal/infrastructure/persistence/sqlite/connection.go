@@ -0,0 +1,234 @@
+// Package sqlite conecta los repositorios de relational con SQLite para desarrollo local y demostraciones sin servidor
+// Se comporta igual que el paquete postgres: ambos se verifican con la misma suite de contrato
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"userservice/internal/domain/repositories"
+	"userservice/internal/infrastructure/config"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// schema crea las tablas e índices si no existen
+//
+//go:embed schema.sql
+var schema string
+
+// timeFormat es el formato con el que el driver guarda las fechas; en UTC ordena como texto
+const timeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// Funciones que el SQL de los repositorios espera encontrar, registradas en cada conexión
+func init() {
+	// gen_random_uuid y now_utc alimentan los valores por defecto del esquema
+	gosqlite.MustRegisterScalarFunction("gen_random_uuid", 0, func(_ *gosqlite.FunctionContext, _ []driver.Value) (driver.Value, error) {
+		return uuid.NewString(), nil
+	})
+	gosqlite.MustRegisterScalarFunction("now_utc", 0, func(_ *gosqlite.FunctionContext, _ []driver.Value) (driver.Value, error) {
+		return time.Now().UTC().Format(timeFormat), nil
+	})
+
+	// search_fold es el equivalente de userservice.search_fold en PostgreSQL
+	gosqlite.MustRegisterDeterministicScalarFunction("search_fold", 1, textFunction(repositories.FoldSearchText))
+
+	// unicode_lower es el LOWER de PostgreSQL; el de SQLite solo convierte ASCII y se deja intacto
+	// porque la función se registra en todas las conexiones del driver, no solo en las del servicio
+	gosqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, textFunction(strings.ToLower))
+
+	// trend_bucket reemplaza al date_trunc por zona horaria de PostgreSQL en la tendencia de registros
+	gosqlite.MustRegisterDeterministicScalarFunction("trend_bucket", 3, trendBucket)
+}
+
+// NewConnection abre la base de datos SQLite en cfg.Path y crea el esquema si no existe
+// El archivo se adjunta como "userservice" en cada conexión del pool
+func NewConnection(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	base, err := sql.Open(sqlite.DriverName, "")
+	if err != nil {
+		return nil, err
+	}
+	sqliteDriver := base.Driver()
+	base.Close()
+
+	sqlDB := sql.OpenDB(&connector{driver: sqliteDriver, path: cfg.Path})
+
+	db, err := gorm.Open(sqlite.Dialector{Conn: sqlDB}, &gorm.Config{})
+	if err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("error abriendo sqlite: %w", err)
+	}
+
+	if err := dropStaleEmailIndex(db); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("error actualizando esquema en sqlite: %w", err)
+	}
+
+	if err := db.Exec(schema).Error; err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("error creando esquema en sqlite: %w", err)
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
+
+	return db, nil
+} // fin NewConnection
+
+// emailIndex es el índice único de emails, que el esquema crea sobre unicode_lower
+const emailIndex = "uq_users_email_lower_active"
+
+// dropStaleEmailIndex elimina el índice de emails creado sobre LOWER por versiones anteriores del esquema
+// para que el esquema lo cree de nuevo sobre unicode_lower; sus entradas no coinciden con el LOWER de SQLite
+func dropStaleEmailIndex(db *gorm.DB) error {
+	var definitions []string
+	err := db.Raw("SELECT sql FROM userservice.sqlite_master WHERE type = 'index' AND name = ?", emailIndex).
+		Scan(&definitions).Error
+	if err != nil {
+		return err
+	}
+
+	if len(definitions) == 0 || strings.Contains(definitions[0], "unicode_lower(") {
+		return nil
+	}
+	return db.Exec("DROP INDEX userservice." + emailIndex).Error
+}
+
+// connector abre conexiones con la base adjunta y la configuración que el esquema necesita
+type connector struct {
+	driver driver.Driver
+	path   string
+}
+
+// connectionDSN configura la conexión principal, que queda en memoria y vacía
+// case_sensitive_like iguala LIKE al de PostgreSQL; las escrituras toman el bloqueo al iniciar la transacción
+const connectionDSN = ":memory:?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=case_sensitive_like(1)&_txlock=immediate"
+
+// Connect abre una conexión y adjunta la base de datos como "userservice"
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(connectionDSN)
+	if err != nil {
+		return nil, err
+	}
+
+	sqliteConn, ok := conn.(sqliteConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("sqlite: conexión %T sin soporte de contexto", conn)
+	}
+
+	setup := []string{
+		"ATTACH DATABASE " + quoteLiteral(c.path) + " AS userservice",
+		// WAL permite leer mientras otra conexión escribe, como necesita Stream
+		"PRAGMA userservice.journal_mode = WAL",
+	}
+	for _, statement := range setup {
+		if _, err := sqliteConn.ExecContext(ctx, statement, nil); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("sqlite: %s: %w", statement, err)
+		}
+	}
+
+	return utcConn{sqliteConn}, nil
+} // fin Connect
+
+// Driver retorna el driver de SQLite
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// sqliteConn agrupa las interfaces opcionales que implementa la conexión del driver
+type sqliteConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+}
+
+// utcConn convierte las fechas de los parámetros a UTC antes de guardarlas o compararlas
+// SQLite compara las fechas como texto, así que todas deben tener la misma zona
+type utcConn struct {
+	sqliteConn
+}
+
+// CheckNamedValue implementa driver.NamedValueChecker
+func (utcConn) CheckNamedValue(value *driver.NamedValue) error {
+	converted, err := driver.DefaultParameterConverter.ConvertValue(value.Value)
+	if err != nil {
+		return err
+	}
+
+	if t, ok := converted.(time.Time); ok {
+		converted = t.UTC()
+	}
+
+	value.Value = converted
+	return nil
+}
+
+// textFunction adapta una función de texto a una función escalar de SQLite; NULL se conserva
+func textFunction(fn func(string) string) func(*gosqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+	return func(_ *gosqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch value := args[0].(type) {
+		case nil:
+			return nil, nil
+		case string:
+			return fn(value), nil
+		case []byte:
+			return fn(string(value)), nil
+		default:
+			return fn(fmt.Sprint(value)), nil
+		}
+	}
+}
+
+// locations guarda las zonas horarias ya cargadas por trend_bucket
+var locations sync.Map
+
+// trendBucket implementa trend_bucket(intervalo, instante, zona): el inicio, como YYYY-MM-DD, del intervalo
+// que contiene al instante en la zona dada, calculado con RegistrationTrendQuery.BucketStart; NULL se conserva
+func trendBucket(_ *gosqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[1] == nil {
+		return nil, nil
+	}
+
+	at, err := time.Parse(timeFormat, fmt.Sprint(args[1]))
+	if err != nil {
+		return nil, err
+	}
+
+	zone := fmt.Sprint(args[2])
+	location, ok := locations.Load(zone)
+	if !ok {
+		loaded, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, err
+		}
+		location, _ = locations.LoadOrStore(zone, loaded)
+	}
+
+	query := repositories.RegistrationTrendQuery{
+		Bucket:   repositories.TrendBucket(fmt.Sprint(args[0])),
+		Location: location.(*time.Location),
+	}
+	return query.BucketStart(at).Format(time.DateOnly), nil
+} // fin trendBucket
+
+// quoteLiteral escribe un literal de texto de SQL
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"userservice/internal/infrastructure/config"
+
+	"github.com/glebarez/sqlite"
+)
+
+// TestBuiltinLowerUnchanged verifica que registrar las funciones del servicio no cambie el LOWER
+// de SQLite en otras conexiones del mismo driver
+func TestBuiltinLowerUnchanged(t *testing.T) {
+	db, err := sql.Open(sqlite.DriverName, ":memory:")
+	if err != nil {
+		t.Fatalf("abriendo sqlite: %v", err)
+	}
+	defer db.Close()
+
+	var builtin, unicode string
+	if err := db.QueryRow("SELECT lower('ÑANDÚ'), unicode_lower('ÑANDÚ')").Scan(&builtin, &unicode); err != nil {
+		t.Fatalf("consultando: %v", err)
+	}
+	if builtin != "ÑandÚ" {
+		t.Errorf("lower('ÑANDÚ') = %q, se esperaba el LOWER de SQLite, que solo convierte ASCII", builtin)
+	}
+	if unicode != "ñandú" {
+		t.Errorf("unicode_lower('ÑANDÚ') = %q, se esperaba %q", unicode, "ñandú")
+	}
+}
+
+// TestNewConnectionReplacesLowerEmailIndex verifica que una base con el índice de emails sobre LOWER
+// quede con el índice sobre unicode_lower al abrirla
+func TestNewConnectionReplacesLowerEmailIndex(t *testing.T) {
+	cfg := config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "sicora.db")}
+
+	db, err := NewConnection(cfg)
+	if err != nil {
+		t.Fatalf("abriendo sqlite: %v", err)
+	}
+	err = db.Exec("DROP INDEX userservice." + emailIndex).Error
+	if err == nil {
+		err = db.Exec("CREATE UNIQUE INDEX userservice." + emailIndex + " ON users(LOWER(email_user)) WHERE deleted_at_user IS NULL").Error
+	}
+	if err != nil {
+		t.Fatalf("creando el índice anterior: %v", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+
+	db, err = NewConnection(cfg)
+	if err != nil {
+		t.Fatalf("reabriendo sqlite: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	var definition string
+	if err := db.Raw("SELECT sql FROM userservice.sqlite_master WHERE name = ?", emailIndex).Scan(&definition).Error; err != nil {
+		t.Fatalf("leyendo el índice: %v", err)
+	}
+	if !strings.Contains(definition, "unicode_lower(email_user)") {
+		t.Errorf("índice de emails = %q, se esperaba sobre unicode_lower", definition)
+	}
+}
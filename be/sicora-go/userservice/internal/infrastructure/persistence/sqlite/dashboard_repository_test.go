@@ -0,0 +1,15 @@
+package sqlite
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories"
+	"userservice/internal/domain/repositories/repositorytest"
+	"userservice/internal/infrastructure/persistence/relational"
+)
+
+func TestDashboardSnapshotRepositoryContract(t *testing.T) {
+	repositorytest.RunDashboardSnapshotRepositoryContract(t, func(t *testing.T) repositories.DashboardSnapshotRepository {
+		return relational.NewDashboardSnapshotRepository(openTestDB(t))
+	})
+}
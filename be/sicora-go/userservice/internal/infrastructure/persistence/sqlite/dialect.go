@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"errors"
+	"strings"
+
+	"userservice/internal/infrastructure/persistence/relational"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+)
+
+// Verificar que implementa la interfaz
+var _ relational.Dialect = Dialect{}
+
+// Códigos extendidos de SQLite para violaciones de unicidad y de llave foránea
+const (
+	constraintForeignKeyCode = 787  // SQLITE_CONSTRAINT_FOREIGNKEY
+	constraintPrimaryKeyCode = 1555 // SQLITE_CONSTRAINT_PRIMARYKEY
+	constraintUniqueCode     = 2067 // SQLITE_CONSTRAINT_UNIQUE
+)
+
+// uniqueColumnFields mapea las columnas e índices únicos de usuarios al campo del dominio
+// SQLite reporta las columnas del índice violado ("UNIQUE constraint failed: users.id_user")
+// salvo en los índices por expresión, de los que reporta el nombre ("UNIQUE constraint failed: index 'uq_...'")
+var uniqueColumnFields = map[string]string{
+	"users.id_user": "id",
+	"users.document_type_user, users.document_number_user": "document_number",
+	"uq_users_email_lower_active":                          "email",
+}
+
+// Dialect implementa relational.Dialect para SQLite
+type Dialect struct{}
+
+// UniqueViolation identifica el índice violado por el mensaje del error, que es lo único que SQLite reporta
+func (Dialect) UniqueViolation(err error) (string, bool) {
+	var sqliteErr *gosqlite.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code() == constraintUniqueCode || sqliteErr.Code() == constraintPrimaryKeyCode) {
+		return uniqueViolationField(sqliteErr.Error()), true
+	}
+	return "", false
+}
+
+// SedeViolation acepta cualquier violación de llave foránea: SQLite no reporta cuál falló,
+// y la única llave foránea que se escribe desde los usuarios es su sede
+func (Dialect) SedeViolation(err error) bool {
+	var sqliteErr *gosqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == constraintForeignKeyCode
+}
+
+// SearchFold usa la función registrada en cada conexión; SQLite no admite funciones con esquema
+func (Dialect) SearchFold(expr string) string {
+	return "search_fold(" + expr + ")"
+}
+
+// Lower usa la función unicode_lower registrada en cada conexión: el LOWER de SQLite solo convierte ASCII
+func (Dialect) Lower(expr string) string {
+	return "unicode_lower(" + expr + ")"
+}
+
+// TrendBucket usa la función trend_bucket registrada en cada conexión: SQLite no conoce las zonas horarias
+func (Dialect) TrendBucket(column string) string {
+	return "trend_bucket(?, " + column + ", ?)"
+}
+
+// uniqueViolationField extrae el campo del mensaje "UNIQUE constraint failed: users.document_type_user,
+// users.document_number_user (2067)" o "UNIQUE constraint failed: index 'uq_users_email_lower_active' (2067)"
+func uniqueViolationField(message string) string {
+	_, columns, _ := strings.Cut(message, "UNIQUE constraint failed: ")
+	if index, found := strings.CutPrefix(columns, "index '"); found {
+		name, _, _ := strings.Cut(index, "'")
+		return uniqueColumnFields[name]
+	}
+	columns, _, _ = strings.Cut(columns, " (")
+	return uniqueColumnFields[columns]
+}
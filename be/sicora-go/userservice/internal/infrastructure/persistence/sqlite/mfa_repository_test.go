@@ -0,0 +1,11 @@
+package sqlite
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories/repositorytest"
+)
+
+func TestMFARepositoryContract(t *testing.T) {
+	repositorytest.RunMFARepositoryContract(t, newTestRepositories)
+}
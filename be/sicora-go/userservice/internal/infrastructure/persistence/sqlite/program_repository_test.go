@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"testing"
+
+	"userservice/internal/domain/repositories/repositorytest"
+	"userservice/internal/infrastructure/persistence/relational"
+)
+
+// newTestRepositories crea los repositorios sobre una base de datos nueva
+func newTestRepositories(t *testing.T) repositorytest.Repositories {
+	db := openTestDB(t)
+	return repositorytest.Repositories{
+		Users:        relational.NewUserRepository(db, Dialect{}),
+		Programs:     relational.NewProgramRepository(db, Dialect{}),
+		Fichas:       relational.NewFichaRepository(db, Dialect{}),
+		Assignments:  relational.NewInstructorAssignmentRepository(db),
+		Organization: relational.NewOrganizationRepository(db, Dialect{}),
+		MFAMethods:   relational.NewMFAMethodRepository(db),
+		BackupCodes:  relational.NewBackupCodeRepository(db),
+		MFASessions:  relational.NewMFASessionRepository(db),
+		MFAPolicies:  relational.NewMFAEnforcementPolicyRepository(db),
+	}
+}
+
+func TestProgramRepositoryContract(t *testing.T) {
+	repositorytest.RunProgramRepositoryContract(t, newTestRepositories)
+}
@@ -0,0 +1,274 @@
+-- Esquema de userservice para SQLite, equivalente a migrations/001-015
+-- La base se adjunta como "userservice" para que los nombres de tabla de las entidades funcionen sin cambios
+-- Las fechas se guardan como texto UTC ("2006-01-02 15:04:05.999999999+00:00"), que ordena cronológicamente
+
+CREATE TABLE IF NOT EXISTS userservice.users (
+    id_user TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    first_name_user VARCHAR(100) NOT NULL,
+    last_name_user VARCHAR(100) NOT NULL,
+    email_user VARCHAR(100) NOT NULL,
+    document_number_user VARCHAR(20) NOT NULL,
+    document_type_user VARCHAR(20) NOT NULL CHECK (document_type_user IN ('CC', 'TI', 'CE', 'PPT', 'PEP', 'PA', 'NIT')),
+    birth_date_user DATE,
+    phone_user VARCHAR(20),
+    role_user VARCHAR(20) NOT NULL,
+    status_user VARCHAR(20) NOT NULL DEFAULT 'active',
+    password_user VARCHAR(255),
+    is_active_user BOOLEAN NOT NULL DEFAULT TRUE,
+    ficha_id_user VARCHAR(20),
+    sede_id_user TEXT REFERENCES sedes(id_sede),
+    email_verified_user BOOLEAN NOT NULL DEFAULT FALSE,
+    email_verified_at_user DATETIME,
+    accepted_privacy_policy_at_user DATETIME,
+    accepted_terms_at_user DATETIME,
+    accepted_data_treatment_at_user DATETIME,
+    privacy_policy_version_user VARCHAR(20),
+    terms_version_user VARCHAR(20),
+    data_treatment_version_user VARCHAR(20),
+    acceptance_ip_address_user VARCHAR(45),
+    created_at_user DATETIME NOT NULL DEFAULT (now_utc()),
+    updated_at_user DATETIME NOT NULL DEFAULT (now_utc()),
+    last_login_user DATETIME,
+    version_user INTEGER NOT NULL DEFAULT 1,
+    deleted_at_user DATETIME,
+    deleted_by_user TEXT
+);
+
+CREATE INDEX IF NOT EXISTS userservice.idx_users_role ON users(role_user);
+CREATE INDEX IF NOT EXISTS userservice.idx_users_ficha ON users(ficha_id_user);
+CREATE INDEX IF NOT EXISTS userservice.idx_users_sede ON users(sede_id_user);
+CREATE INDEX IF NOT EXISTS userservice.idx_users_created_at ON users(created_at_user);
+CREATE INDEX IF NOT EXISTS userservice.idx_users_deleted_at ON users(deleted_at_user);
+
+-- La unicidad solo aplica a usuarios no eliminados para permitir re-registros
+-- El email se compara sin distinguir mayúsculas; las bases anteriores a 014 reemplazan el índice por columna
+-- unicode_lower convierte todo Unicode, como el LOWER de PostgreSQL; NewConnection reemplaza el índice anterior sobre LOWER
+DROP INDEX IF EXISTS userservice.uq_users_email_active;
+CREATE UNIQUE INDEX IF NOT EXISTS userservice.uq_users_email_lower_active
+    ON users(unicode_lower(email_user))
+    WHERE deleted_at_user IS NULL;
+
+-- El documento es único por tipo: una CC y un pasaporte pueden tener el mismo número
+DROP INDEX IF EXISTS userservice.uq_users_document_number_active;
+CREATE UNIQUE INDEX IF NOT EXISTS userservice.uq_users_document_active
+    ON users(document_type_user, document_number_user)
+    WHERE deleted_at_user IS NULL;
+
+CREATE TABLE IF NOT EXISTS userservice.user_mfa_methods (
+    id_user_mfa_method TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    user_id_user_mfa_method TEXT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    method_type_user_mfa_method VARCHAR(20) NOT NULL,
+    is_primary_user_mfa_method BOOLEAN NOT NULL DEFAULT FALSE,
+    is_enabled_user_mfa_method BOOLEAN NOT NULL DEFAULT TRUE,
+    secret_encrypted_user_mfa_method TEXT,
+    phone_number_user_mfa_method VARCHAR(20),
+    email_address_user_mfa_method VARCHAR(255),
+    webauthn_data_user_mfa_method TEXT,
+    last_used_at_user_mfa_method DATETIME,
+    created_at_user_mfa_method DATETIME NOT NULL DEFAULT (now_utc()),
+    updated_at_user_mfa_method DATETIME NOT NULL DEFAULT (now_utc()),
+    CONSTRAINT uq_user_mfa_methods_user_type UNIQUE (user_id_user_mfa_method, method_type_user_mfa_method)
+);
+
+CREATE INDEX IF NOT EXISTS userservice.idx_user_mfa_methods_user ON user_mfa_methods(user_id_user_mfa_method);
+
+-- Un solo método primario por usuario
+CREATE UNIQUE INDEX IF NOT EXISTS userservice.uq_user_mfa_methods_primary
+    ON user_mfa_methods(user_id_user_mfa_method)
+    WHERE is_primary_user_mfa_method;
+
+CREATE TABLE IF NOT EXISTS userservice.mfa_backup_codes (
+    id_mfa_backup_code TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    user_id_mfa_backup_code TEXT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    code_hash_mfa_backup_code VARCHAR(255) NOT NULL,
+    is_used_mfa_backup_code BOOLEAN NOT NULL DEFAULT FALSE,
+    used_at_mfa_backup_code DATETIME,
+    created_at_mfa_backup_code DATETIME NOT NULL DEFAULT (now_utc()),
+    expires_at_mfa_backup_code DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS userservice.idx_mfa_backup_codes_user_unused
+    ON mfa_backup_codes(user_id_mfa_backup_code)
+    WHERE NOT is_used_mfa_backup_code;
+
+CREATE TABLE IF NOT EXISTS userservice.mfa_sessions (
+    id_mfa_session TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    user_id_mfa_session TEXT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    method_type_mfa_session VARCHAR(20) NOT NULL,
+    code_hash_mfa_session VARCHAR(255),
+    is_verified_mfa_session BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_at_mfa_session DATETIME,
+    attempts_mfa_session INTEGER NOT NULL DEFAULT 0,
+    max_attempts_mfa_session INTEGER NOT NULL DEFAULT 3,
+    ip_address_mfa_session VARCHAR(45),
+    user_agent_mfa_session TEXT,
+    created_at_mfa_session DATETIME NOT NULL DEFAULT (now_utc()),
+    expires_at_mfa_session DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS userservice.idx_mfa_sessions_user ON mfa_sessions(user_id_mfa_session, created_at_mfa_session DESC);
+CREATE INDEX IF NOT EXISTS userservice.idx_mfa_sessions_expires ON mfa_sessions(expires_at_mfa_session);
+
+-- Las listas de métodos se guardan con el mismo literal de arreglo que text[] en PostgreSQL
+CREATE TABLE IF NOT EXISTS userservice.mfa_enforcement_policies (
+    id_mfa_enforcement_policy TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    role_name_mfa_enforcement_policy VARCHAR(50) NOT NULL UNIQUE,
+    primary_methods_mfa_enforcement_policy TEXT NOT NULL,
+    alternative_methods_mfa_enforcement_policy TEXT,
+    enforcement_level_mfa_enforcement_policy VARCHAR(20) NOT NULL,
+    grace_period_days_mfa_enforcement_policy INTEGER NOT NULL DEFAULT 0,
+    require_backup_codes_mfa_enforcement_policy BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_mfa_enforcement_policy DATETIME NOT NULL DEFAULT (now_utc()),
+    updated_at_mfa_enforcement_policy DATETIME NOT NULL DEFAULT (now_utc())
+);
+
+-- Historial de inicios de sesión
+CREATE TABLE IF NOT EXISTS userservice.login_events (
+    id_login_event TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    user_id_login_event TEXT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    occurred_at_login_event DATETIME NOT NULL DEFAULT (now_utc()),
+    ip_address_login_event VARCHAR(45),
+    user_agent_login_event TEXT
+);
+
+CREATE INDEX IF NOT EXISTS userservice.idx_login_events_user_occurred ON login_events(user_id_login_event, occurred_at_login_event DESC);
+
+-- Fotos del cruce de usuarios del dashboard directivo
+CREATE TABLE IF NOT EXISTS userservice.dashboard_snapshots (
+    id_dashboard_snapshot TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    taken_at_dashboard_snapshot DATETIME NOT NULL DEFAULT (now_utc())
+);
+
+CREATE INDEX IF NOT EXISTS userservice.idx_dashboard_snapshots_taken_at ON dashboard_snapshots(taken_at_dashboard_snapshot DESC);
+
+CREATE TABLE IF NOT EXISTS userservice.dashboard_snapshot_cells (
+    snapshot_id_dashboard_cell TEXT NOT NULL REFERENCES dashboard_snapshots(id_dashboard_snapshot) ON DELETE CASCADE,
+    role_dashboard_cell VARCHAR(20) NOT NULL,
+    program_dashboard_cell VARCHAR(50) NOT NULL DEFAULT '',
+    sede_dashboard_cell VARCHAR(36) NOT NULL DEFAULT '',
+    status_dashboard_cell VARCHAR(10) NOT NULL,
+    count_dashboard_cell INTEGER NOT NULL,
+    PRIMARY KEY (snapshot_id_dashboard_cell, role_dashboard_cell, program_dashboard_cell, sede_dashboard_cell, status_dashboard_cell)
+);
+
+-- Programas de formación
+CREATE TABLE IF NOT EXISTS userservice.programs (
+    id_program TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    code_program VARCHAR(20) NOT NULL,
+    name_program VARCHAR(200) NOT NULL,
+    level_program VARCHAR(30) NOT NULL CHECK (level_program IN ('auxiliar', 'operario', 'tecnico', 'tecnologo', 'especializacion_tecnologica')),
+    duration_months_program INTEGER NOT NULL CHECK (duration_months_program > 0),
+    is_active_program BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_program DATETIME NOT NULL DEFAULT (now_utc()),
+    updated_at_program DATETIME NOT NULL DEFAULT (now_utc())
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS userservice.uq_programs_code ON programs(code_program);
+
+-- Fichas de formación, fuente única de las fichas de los usuarios
+CREATE TABLE IF NOT EXISTS userservice.fichas (
+    id_ficha TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    number_ficha VARCHAR(20) NOT NULL,
+    program_id_ficha TEXT NOT NULL REFERENCES programs(id_program),
+    sede_id_ficha TEXT REFERENCES sedes(id_sede),
+    jornada_ficha VARCHAR(20) NOT NULL CHECK (jornada_ficha IN ('diurna', 'nocturna', 'mixta', 'madrugada', 'fin_de_semana')),
+    start_date_ficha DATE NOT NULL,
+    end_date_ficha DATE NOT NULL,
+    lead_instructor_id_ficha TEXT REFERENCES users(id_user) ON DELETE SET NULL,
+    status_ficha VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (status_ficha IN ('planned', 'in_progress', 'finished', 'cancelled')),
+    capacity_ficha INTEGER NOT NULL CHECK (capacity_ficha BETWEEN 1 AND 100),
+    created_at_ficha DATETIME NOT NULL DEFAULT (now_utc()),
+    updated_at_ficha DATETIME NOT NULL DEFAULT (now_utc()),
+    CHECK (end_date_ficha > start_date_ficha)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS userservice.uq_fichas_number ON fichas(number_ficha);
+CREATE INDEX IF NOT EXISTS userservice.idx_fichas_program ON fichas(program_id_ficha);
+CREATE INDEX IF NOT EXISTS userservice.idx_fichas_sede ON fichas(sede_id_ficha);
+CREATE INDEX IF NOT EXISTS userservice.idx_fichas_lead_instructor ON fichas(lead_instructor_id_ficha);
+
+-- Asignaciones de instructores a fichas
+CREATE TABLE IF NOT EXISTS userservice.instructor_assignments (
+    id_assignment TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    instructor_id_assignment TEXT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    ficha_id_assignment VARCHAR(20) NOT NULL REFERENCES fichas(number_ficha) ON DELETE CASCADE,
+    role_assignment VARCHAR(20) NOT NULL CHECK (role_assignment IN ('tecnico', 'transversal', 'seguimiento')),
+    competency_assignment VARCHAR(100) NOT NULL DEFAULT '',
+    start_date_assignment DATE NOT NULL,
+    end_date_assignment DATE,
+    created_at_assignment DATETIME NOT NULL DEFAULT (now_utc()),
+    CHECK (end_date_assignment IS NULL OR end_date_assignment >= start_date_assignment)
+);
+
+CREATE INDEX IF NOT EXISTS userservice.idx_instructor_assignments_instructor ON instructor_assignments(instructor_id_assignment, start_date_assignment);
+CREATE INDEX IF NOT EXISTS userservice.idx_instructor_assignments_ficha ON instructor_assignments(ficha_id_assignment, start_date_assignment);
+
+-- Historial inmutable de fichas de los aprendices
+CREATE TABLE IF NOT EXISTS userservice.ficha_movements (
+    id_ficha_movement TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    user_id_ficha_movement TEXT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    from_ficha_id_ficha_movement VARCHAR(20),
+    to_ficha_id_ficha_movement VARCHAR(20) NOT NULL REFERENCES fichas(number_ficha),
+    reason_ficha_movement VARCHAR(500) NOT NULL,
+    approved_by_ficha_movement TEXT REFERENCES users(id_user) ON DELETE SET NULL,
+    effective_date_ficha_movement DATE NOT NULL,
+    created_at_ficha_movement DATETIME NOT NULL DEFAULT (now_utc()),
+    CHECK (from_ficha_id_ficha_movement IS NOT to_ficha_id_ficha_movement)
+);
+
+CREATE INDEX IF NOT EXISTS userservice.idx_ficha_movements_user ON ficha_movements(user_id_ficha_movement, effective_date_ficha_movement);
+CREATE INDEX IF NOT EXISTS userservice.idx_ficha_movements_to_ficha ON ficha_movements(to_ficha_id_ficha_movement);
+
+CREATE TRIGGER IF NOT EXISTS userservice.trg_ficha_movements_immutable
+BEFORE UPDATE ON ficha_movements
+BEGIN
+    SELECT RAISE(ABORT, 'los movimientos de ficha no se pueden modificar');
+END;
+
+-- Jerarquía del SENA: regionales, centros de formación y sedes
+CREATE TABLE IF NOT EXISTS userservice.regionales (
+    id_regional TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    code_regional VARCHAR(20) NOT NULL,
+    name_regional VARCHAR(150) NOT NULL,
+    is_active_regional BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_regional DATETIME NOT NULL DEFAULT (now_utc()),
+    updated_at_regional DATETIME NOT NULL DEFAULT (now_utc())
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS userservice.uq_regionales_code ON regionales(code_regional);
+
+CREATE TABLE IF NOT EXISTS userservice.centros (
+    id_centro TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    regional_id_centro TEXT NOT NULL REFERENCES regionales(id_regional),
+    code_centro VARCHAR(20) NOT NULL,
+    name_centro VARCHAR(150) NOT NULL,
+    is_active_centro BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_centro DATETIME NOT NULL DEFAULT (now_utc()),
+    updated_at_centro DATETIME NOT NULL DEFAULT (now_utc())
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS userservice.uq_centros_code ON centros(code_centro);
+CREATE INDEX IF NOT EXISTS userservice.idx_centros_regional ON centros(regional_id_centro);
+
+CREATE TABLE IF NOT EXISTS userservice.sedes (
+    id_sede TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    centro_id_sede TEXT NOT NULL REFERENCES centros(id_centro),
+    name_sede VARCHAR(150) NOT NULL,
+    address_sede VARCHAR(255) NOT NULL DEFAULT '',
+    is_active_sede BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_sede DATETIME NOT NULL DEFAULT (now_utc()),
+    updated_at_sede DATETIME NOT NULL DEFAULT (now_utc())
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS userservice.uq_sedes_centro_name ON sedes(centro_id_sede, name_sede);
+
+-- Bitácora de los bypass de sede; sin FK al actor para conservarla tras Purge
+CREATE TABLE IF NOT EXISTS userservice.tenant_bypasses (
+    id_tenant_bypass TEXT PRIMARY KEY NOT NULL DEFAULT (gen_random_uuid()),
+    actor_id_tenant_bypass TEXT NOT NULL,
+    reason_tenant_bypass VARCHAR(500) NOT NULL,
+    granted_at_tenant_bypass DATETIME NOT NULL DEFAULT (now_utc())
+);
+
+CREATE INDEX IF NOT EXISTS userservice.idx_tenant_bypasses_granted_at ON tenant_bypasses(granted_at_tenant_bypass DESC);
+CREATE INDEX IF NOT EXISTS userservice.idx_tenant_bypasses_actor ON tenant_bypasses(actor_id_tenant_bypass);
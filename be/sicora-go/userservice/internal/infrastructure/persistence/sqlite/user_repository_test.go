@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"userservice/internal/domain/repositories"
+	"userservice/internal/domain/repositories/repositorytest"
+	"userservice/internal/infrastructure/config"
+	"userservice/internal/infrastructure/persistence/relational"
+
+	"gorm.io/gorm"
+)
+
+// openTestDB crea una base de datos SQLite nueva en un directorio temporal
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := NewConnection(config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "sicora.db")})
+	if err != nil {
+		t.Fatalf("abriendo sqlite: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return db
+}
+
+func TestUserRepositoryContract(t *testing.T) {
+	repositorytest.RunUserRepositoryContract(t, func(t *testing.T) repositories.UserRepository {
+		return relational.NewUserRepository(openTestDB(t), Dialect{})
+	})
+}
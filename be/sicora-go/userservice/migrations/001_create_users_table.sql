@@ -0,0 +1,37 @@
+-- migrations/001_create_users_table.sql
+CREATE SCHEMA IF NOT EXISTS userservice;
+
+CREATE TABLE IF NOT EXISTS userservice.users (
+    id_user UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    first_name_user VARCHAR(100) NOT NULL,
+    last_name_user VARCHAR(100) NOT NULL,
+    email_user VARCHAR(100) NOT NULL,
+    document_number_user VARCHAR(20) NOT NULL,
+    document_type_user VARCHAR(20) NOT NULL,
+    phone_user VARCHAR(20),
+    role_user VARCHAR(20) NOT NULL,
+    status_user VARCHAR(20) NOT NULL DEFAULT 'active',
+    password_user VARCHAR(255),
+    is_active_user BOOLEAN NOT NULL DEFAULT TRUE,
+    ficha_id_user VARCHAR(20),
+    sede_id_user UUID,
+    email_verified_user BOOLEAN NOT NULL DEFAULT FALSE,
+    email_verified_at_user TIMESTAMPTZ,
+    accepted_privacy_policy_at_user TIMESTAMPTZ,
+    accepted_terms_at_user TIMESTAMPTZ,
+    accepted_data_treatment_at_user TIMESTAMPTZ,
+    privacy_policy_version_user VARCHAR(20),
+    terms_version_user VARCHAR(20),
+    data_treatment_version_user VARCHAR(20),
+    acceptance_ip_address_user VARCHAR(45),
+    created_at_user TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at_user TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_user TIMESTAMPTZ,
+    CONSTRAINT uq_users_email UNIQUE (email_user),
+    CONSTRAINT uq_users_document_number UNIQUE (document_number_user)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_role ON userservice.users(role_user);
+CREATE INDEX IF NOT EXISTS idx_users_ficha ON userservice.users(ficha_id_user);
+CREATE INDEX IF NOT EXISTS idx_users_sede ON userservice.users(sede_id_user);
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON userservice.users(created_at_user);
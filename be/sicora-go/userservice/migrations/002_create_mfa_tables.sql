@@ -0,0 +1,67 @@
+-- migrations/002_create_mfa_tables.sql
+CREATE TABLE IF NOT EXISTS userservice.user_mfa_methods (
+    id_user_mfa_method UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id_user_mfa_method UUID NOT NULL REFERENCES userservice.users(id_user) ON DELETE CASCADE,
+    method_type_user_mfa_method VARCHAR(20) NOT NULL,
+    is_primary_user_mfa_method BOOLEAN NOT NULL DEFAULT FALSE,
+    is_enabled_user_mfa_method BOOLEAN NOT NULL DEFAULT TRUE,
+    secret_encrypted_user_mfa_method TEXT,
+    phone_number_user_mfa_method VARCHAR(20),
+    email_address_user_mfa_method VARCHAR(255),
+    webauthn_data_user_mfa_method JSONB,
+    last_used_at_user_mfa_method TIMESTAMPTZ,
+    created_at_user_mfa_method TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at_user_mfa_method TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_user_mfa_methods_user_type UNIQUE (user_id_user_mfa_method, method_type_user_mfa_method)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_mfa_methods_user ON userservice.user_mfa_methods(user_id_user_mfa_method);
+
+-- Un solo método primario por usuario
+CREATE UNIQUE INDEX IF NOT EXISTS uq_user_mfa_methods_primary
+    ON userservice.user_mfa_methods(user_id_user_mfa_method)
+    WHERE is_primary_user_mfa_method;
+
+CREATE TABLE IF NOT EXISTS userservice.mfa_backup_codes (
+    id_mfa_backup_code UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id_mfa_backup_code UUID NOT NULL REFERENCES userservice.users(id_user) ON DELETE CASCADE,
+    code_hash_mfa_backup_code VARCHAR(255) NOT NULL,
+    is_used_mfa_backup_code BOOLEAN NOT NULL DEFAULT FALSE,
+    used_at_mfa_backup_code TIMESTAMPTZ,
+    created_at_mfa_backup_code TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at_mfa_backup_code TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_backup_codes_user_unused
+    ON userservice.mfa_backup_codes(user_id_mfa_backup_code)
+    WHERE NOT is_used_mfa_backup_code;
+
+CREATE TABLE IF NOT EXISTS userservice.mfa_sessions (
+    id_mfa_session UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id_mfa_session UUID NOT NULL REFERENCES userservice.users(id_user) ON DELETE CASCADE,
+    method_type_mfa_session VARCHAR(20) NOT NULL,
+    code_hash_mfa_session VARCHAR(255),
+    is_verified_mfa_session BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_at_mfa_session TIMESTAMPTZ,
+    attempts_mfa_session INTEGER NOT NULL DEFAULT 0,
+    max_attempts_mfa_session INTEGER NOT NULL DEFAULT 3,
+    ip_address_mfa_session VARCHAR(45),
+    user_agent_mfa_session TEXT,
+    created_at_mfa_session TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at_mfa_session TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_sessions_user ON userservice.mfa_sessions(user_id_mfa_session, created_at_mfa_session DESC);
+CREATE INDEX IF NOT EXISTS idx_mfa_sessions_expires ON userservice.mfa_sessions(expires_at_mfa_session);
+
+CREATE TABLE IF NOT EXISTS userservice.mfa_enforcement_policies (
+    id_mfa_enforcement_policy UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    role_name_mfa_enforcement_policy VARCHAR(50) NOT NULL UNIQUE,
+    primary_methods_mfa_enforcement_policy TEXT[] NOT NULL,
+    alternative_methods_mfa_enforcement_policy TEXT[],
+    enforcement_level_mfa_enforcement_policy VARCHAR(20) NOT NULL,
+    grace_period_days_mfa_enforcement_policy INTEGER NOT NULL DEFAULT 0,
+    require_backup_codes_mfa_enforcement_policy BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_mfa_enforcement_policy TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at_mfa_enforcement_policy TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
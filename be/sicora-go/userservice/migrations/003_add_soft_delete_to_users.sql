@@ -0,0 +1,23 @@
+-- migrations/003_add_soft_delete_to_users.sql
+ALTER TABLE userservice.users
+    ADD COLUMN IF NOT EXISTS deleted_at_user TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deleted_by_user UUID;
+
+-- Marcar como eliminados los usuarios que el soft delete anterior dejó con estado 'deleted'
+UPDATE userservice.users
+SET deleted_at_user = updated_at_user
+WHERE status_user = 'deleted' AND deleted_at_user IS NULL;
+
+-- La unicidad solo aplica a usuarios no eliminados para permitir re-registros
+ALTER TABLE userservice.users DROP CONSTRAINT IF EXISTS uq_users_email;
+ALTER TABLE userservice.users DROP CONSTRAINT IF EXISTS uq_users_document_number;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_email_active
+    ON userservice.users(email_user)
+    WHERE deleted_at_user IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_document_number_active
+    ON userservice.users(document_number_user)
+    WHERE deleted_at_user IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON userservice.users(deleted_at_user);
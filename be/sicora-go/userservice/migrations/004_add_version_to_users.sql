@@ -0,0 +1,4 @@
+-- migrations/004_add_version_to_users.sql
+-- Control de concurrencia optimista: cada modificación incrementa la versión
+ALTER TABLE userservice.users
+    ADD COLUMN IF NOT EXISTS version_user INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,27 @@
+-- migrations/005_add_user_search.sql
+-- Búsqueda sin distinguir tildes ni mayúsculas: "Jose Nunez" encuentra a "José Núñez"
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() no es IMMUTABLE; el envoltorio fija el diccionario para poder indexar la expresión
+-- Debe coincidir con repositories.FoldSearchText
+CREATE OR REPLACE FUNCTION userservice.search_fold(value TEXT)
+RETURNS TEXT
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+AS $$ SELECT lower(public.unaccent('public.unaccent'::regdictionary, value)) $$;
+
+CREATE INDEX IF NOT EXISTS idx_users_first_name_search
+    ON userservice.users USING gin (userservice.search_fold(first_name_user) gin_trgm_ops)
+    WHERE deleted_at_user IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_last_name_search
+    ON userservice.users USING gin (userservice.search_fold(last_name_user) gin_trgm_ops)
+    WHERE deleted_at_user IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_email_search
+    ON userservice.users USING gin (LOWER(email_user) gin_trgm_ops)
+    WHERE deleted_at_user IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_document_number_prefix
+    ON userservice.users(document_number_user varchar_pattern_ops)
+    WHERE deleted_at_user IS NULL;
@@ -0,0 +1,22 @@
+-- migrations/006_create_login_events.sql
+-- Historial de inicios de sesión para las métricas de actividad del dashboard directivo
+CREATE TABLE IF NOT EXISTS userservice.login_events (
+    id_login_event UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id_login_event UUID NOT NULL REFERENCES userservice.users(id_user) ON DELETE CASCADE,
+    occurred_at_login_event TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ip_address_login_event VARCHAR(45),
+    user_agent_login_event TEXT
+);
+
+-- Historial de un usuario y último inicio de sesión hasta una fecha
+CREATE INDEX IF NOT EXISTS idx_login_events_user_occurred
+    ON userservice.login_events(user_id_login_event, occurred_at_login_event DESC);
+
+-- Los inicios de sesión anteriores al historial solo se conocen por last_login_user
+INSERT INTO userservice.login_events (user_id_login_event, occurred_at_login_event)
+SELECT id_user, last_login_user
+FROM userservice.users
+WHERE last_login_user IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM userservice.login_events WHERE user_id_login_event = id_user
+  );
@@ -0,0 +1,20 @@
+-- migrations/007_create_dashboard_snapshots.sql
+-- Fotos periódicas del cruce de usuarios por rol, programa, sede y estado para el dashboard directivo
+CREATE TABLE IF NOT EXISTS userservice.dashboard_snapshots (
+    id_dashboard_snapshot UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    taken_at_dashboard_snapshot TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dashboard_snapshots_taken_at
+    ON userservice.dashboard_snapshots(taken_at_dashboard_snapshot DESC);
+
+-- Las dimensiones sin valor (sin sede, sin programa) se guardan como texto vacío para formar la llave
+CREATE TABLE IF NOT EXISTS userservice.dashboard_snapshot_cells (
+    snapshot_id_dashboard_cell UUID NOT NULL REFERENCES userservice.dashboard_snapshots(id_dashboard_snapshot) ON DELETE CASCADE,
+    role_dashboard_cell VARCHAR(20) NOT NULL,
+    program_dashboard_cell VARCHAR(50) NOT NULL DEFAULT '',
+    sede_dashboard_cell VARCHAR(36) NOT NULL DEFAULT '',
+    status_dashboard_cell VARCHAR(10) NOT NULL,
+    count_dashboard_cell INTEGER NOT NULL,
+    PRIMARY KEY (snapshot_id_dashboard_cell, role_dashboard_cell, program_dashboard_cell, sede_dashboard_cell, status_dashboard_cell)
+);
@@ -0,0 +1,13 @@
+-- migrations/008_create_programs.sql
+-- Programas de formación: el aprendiz pertenece al programa de su ficha (009_create_fichas.sql)
+CREATE TABLE IF NOT EXISTS userservice.programs (
+    id_program UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code_program VARCHAR(20) NOT NULL,
+    name_program VARCHAR(200) NOT NULL,
+    level_program VARCHAR(30) NOT NULL CHECK (level_program IN ('auxiliar', 'operario', 'tecnico', 'tecnologo', 'especializacion_tecnologica')),
+    duration_months_program INTEGER NOT NULL CHECK (duration_months_program > 0),
+    is_active_program BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_program TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at_program TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_programs_code UNIQUE (code_program)
+);
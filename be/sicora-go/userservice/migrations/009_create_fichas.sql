@@ -0,0 +1,22 @@
+-- migrations/009_create_fichas.sql
+-- Fichas de formación: fuente única de las fichas que referencian users.ficha_id_user y los demás servicios
+CREATE TABLE IF NOT EXISTS userservice.fichas (
+    id_ficha UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    number_ficha VARCHAR(20) NOT NULL,
+    program_id_ficha UUID NOT NULL REFERENCES userservice.programs(id_program),
+    sede_id_ficha UUID,
+    jornada_ficha VARCHAR(20) NOT NULL CHECK (jornada_ficha IN ('diurna', 'nocturna', 'mixta', 'madrugada', 'fin_de_semana')),
+    start_date_ficha DATE NOT NULL,
+    end_date_ficha DATE NOT NULL,
+    lead_instructor_id_ficha UUID REFERENCES userservice.users(id_user) ON DELETE SET NULL,
+    status_ficha VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (status_ficha IN ('planned', 'in_progress', 'finished', 'cancelled')),
+    capacity_ficha INTEGER NOT NULL CHECK (capacity_ficha BETWEEN 1 AND 100),
+    created_at_ficha TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at_ficha TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_fichas_number UNIQUE (number_ficha),
+    CONSTRAINT ck_fichas_dates CHECK (end_date_ficha > start_date_ficha)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fichas_program ON userservice.fichas(program_id_ficha);
+CREATE INDEX IF NOT EXISTS idx_fichas_sede ON userservice.fichas(sede_id_ficha);
+CREATE INDEX IF NOT EXISTS idx_fichas_lead_instructor ON userservice.fichas(lead_instructor_id_ficha);
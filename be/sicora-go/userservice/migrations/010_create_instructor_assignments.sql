@@ -0,0 +1,19 @@
+-- migrations/010_create_instructor_assignments.sql
+-- Asignaciones de instructores a fichas por competencia y rango de fechas
+CREATE TABLE IF NOT EXISTS userservice.instructor_assignments (
+    id_assignment UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    instructor_id_assignment UUID NOT NULL REFERENCES userservice.users(id_user) ON DELETE CASCADE,
+    ficha_id_assignment VARCHAR(20) NOT NULL REFERENCES userservice.fichas(number_ficha) ON DELETE CASCADE,
+    role_assignment VARCHAR(20) NOT NULL CHECK (role_assignment IN ('tecnico', 'transversal', 'seguimiento')),
+    competency_assignment VARCHAR(100) NOT NULL DEFAULT '',
+    start_date_assignment DATE NOT NULL,
+    end_date_assignment DATE, -- Inclusiva; NULL mientras siga vigente
+    created_at_assignment TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ck_instructor_assignments_dates CHECK (end_date_assignment IS NULL OR end_date_assignment >= start_date_assignment)
+);
+
+-- Fichas de un instructor e instructores de una ficha
+CREATE INDEX IF NOT EXISTS idx_instructor_assignments_instructor
+    ON userservice.instructor_assignments(instructor_id_assignment, start_date_assignment);
+CREATE INDEX IF NOT EXISTS idx_instructor_assignments_ficha
+    ON userservice.instructor_assignments(ficha_id_assignment, start_date_assignment);
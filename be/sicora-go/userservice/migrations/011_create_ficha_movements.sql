@@ -0,0 +1,46 @@
+-- migrations/011_create_ficha_movements.sql
+-- Historial inmutable de fichas de los aprendices: ingresos y traslados entre fichas
+CREATE TABLE IF NOT EXISTS userservice.ficha_movements (
+    id_ficha_movement UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id_ficha_movement UUID NOT NULL REFERENCES userservice.users(id_user) ON DELETE CASCADE,
+    from_ficha_id_ficha_movement VARCHAR(20), -- NULL en el ingreso; sin FK porque puede ser una ficha anterior a userservice.fichas
+    to_ficha_id_ficha_movement VARCHAR(20) NOT NULL REFERENCES userservice.fichas(number_ficha),
+    reason_ficha_movement VARCHAR(500) NOT NULL,
+    approved_by_ficha_movement UUID REFERENCES userservice.users(id_user) ON DELETE SET NULL,
+    effective_date_ficha_movement DATE NOT NULL,
+    created_at_ficha_movement TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ck_ficha_movements_fichas CHECK (from_ficha_id_ficha_movement IS DISTINCT FROM to_ficha_id_ficha_movement)
+);
+
+-- Historial de un aprendiz y aprendices de una ficha en una fecha
+CREATE INDEX IF NOT EXISTS idx_ficha_movements_user
+    ON userservice.ficha_movements(user_id_ficha_movement, effective_date_ficha_movement);
+CREATE INDEX IF NOT EXISTS idx_ficha_movements_to_ficha
+    ON userservice.ficha_movements(to_ficha_id_ficha_movement);
+
+-- Los movimientos no se modifican; un error se corrige con un nuevo traslado
+-- Eliminar al usuario sí elimina su historial
+CREATE OR REPLACE FUNCTION userservice.reject_ficha_movement_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'los movimientos de ficha no se pueden modificar';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_ficha_movements_immutable ON userservice.ficha_movements;
+CREATE TRIGGER trg_ficha_movements_immutable
+    BEFORE UPDATE ON userservice.ficha_movements
+    FOR EACH ROW EXECUTE FUNCTION userservice.reject_ficha_movement_update();
+
+-- Los aprendices que ya tenían ficha reciben un ingreso con la fecha de su registro
+-- Las fichas que no existen en userservice.fichas no tienen historial hasta que el aprendiz se traslade
+INSERT INTO userservice.ficha_movements (user_id_ficha_movement, to_ficha_id_ficha_movement, reason_ficha_movement,
+                                         effective_date_ficha_movement, created_at_ficha_movement)
+SELECT id_user,
+       ficha_id_user,
+       'Ficha registrada antes del historial de movimientos',
+       created_at_user::date,
+       created_at_user
+FROM userservice.users
+JOIN userservice.fichas ON number_ficha = ficha_id_user
+WHERE role_user = 'aprendiz'
+  AND NOT EXISTS (SELECT 1 FROM userservice.ficha_movements WHERE user_id_ficha_movement = id_user);
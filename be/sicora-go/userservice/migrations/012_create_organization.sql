@@ -0,0 +1,46 @@
+-- migrations/012_create_organization.sql
+-- Jerarquía del SENA: regionales, centros de formación y sedes, a las que apuntan usuarios y fichas
+CREATE TABLE IF NOT EXISTS userservice.regionales (
+    id_regional UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code_regional VARCHAR(20) NOT NULL,
+    name_regional VARCHAR(150) NOT NULL,
+    is_active_regional BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_regional TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at_regional TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_regionales_code UNIQUE (code_regional)
+);
+
+CREATE TABLE IF NOT EXISTS userservice.centros (
+    id_centro UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    regional_id_centro UUID NOT NULL REFERENCES userservice.regionales(id_regional),
+    code_centro VARCHAR(20) NOT NULL,
+    name_centro VARCHAR(150) NOT NULL,
+    is_active_centro BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_centro TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at_centro TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_centros_code UNIQUE (code_centro)
+);
+
+CREATE INDEX IF NOT EXISTS idx_centros_regional ON userservice.centros(regional_id_centro);
+
+CREATE TABLE IF NOT EXISTS userservice.sedes (
+    id_sede UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    centro_id_sede UUID NOT NULL REFERENCES userservice.centros(id_centro),
+    name_sede VARCHAR(150) NOT NULL,
+    address_sede VARCHAR(255) NOT NULL DEFAULT '',
+    is_active_sede BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at_sede TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at_sede TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_sedes_centro_name UNIQUE (centro_id_sede, name_sede)
+);
+
+-- Usuarios y fichas solo pueden apuntar a sedes registradas
+-- NOT VALID: las sedes ya guardadas se verifican con VALIDATE CONSTRAINT una vez cargada la jerarquía;
+-- mientras tanto solo se verifican las filas nuevas y las que cambian de sede
+ALTER TABLE userservice.users DROP CONSTRAINT IF EXISTS fk_users_sede;
+ALTER TABLE userservice.users
+    ADD CONSTRAINT fk_users_sede FOREIGN KEY (sede_id_user) REFERENCES userservice.sedes(id_sede) NOT VALID;
+
+ALTER TABLE userservice.fichas DROP CONSTRAINT IF EXISTS fk_fichas_sede;
+ALTER TABLE userservice.fichas
+    ADD CONSTRAINT fk_fichas_sede FOREIGN KEY (sede_id_ficha) REFERENCES userservice.sedes(id_sede) NOT VALID;
@@ -0,0 +1,12 @@
+-- migrations/013_create_tenant_bypasses.sql
+-- Bitácora de auditoría de los directivos nacionales que operan sobre usuarios sin el alcance de sede
+-- Sin FK al actor: la bitácora se conserva aunque Purge elimine al usuario
+CREATE TABLE IF NOT EXISTS userservice.tenant_bypasses (
+    id_tenant_bypass UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id_tenant_bypass UUID NOT NULL,
+    reason_tenant_bypass VARCHAR(500) NOT NULL,
+    granted_at_tenant_bypass TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_bypasses_granted_at ON userservice.tenant_bypasses(granted_at_tenant_bypass DESC);
+CREATE INDEX IF NOT EXISTS idx_tenant_bypasses_actor ON userservice.tenant_bypasses(actor_id_tenant_bypass);
@@ -0,0 +1,39 @@
+-- migrations/014_case_insensitive_email.sql
+-- Los emails se guardan en forma canónica (sin espacios alrededor y en minúsculas) y son únicos sin
+-- distinguir mayúsculas: Juan@SENA.edu.co y juan@sena.edu.co son el mismo usuario
+
+-- Antes de normalizar se detectan los usuarios no eliminados cuyos emails solo difieren en mayúsculas o espacios
+-- La migración se detiene listando cada colisión; se resuelven a mano (eliminando o cambiando el email) y se repite
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(format('%s: %s', email, ids), E'\n' ORDER BY email)
+    INTO collisions
+    FROM (
+        SELECT LOWER(TRIM(email_user)) AS email,
+               string_agg(id_user::TEXT || ' (' || email_user || ')', ', ' ORDER BY created_at_user) AS ids
+        FROM userservice.users
+        WHERE deleted_at_user IS NULL
+        GROUP BY LOWER(TRIM(email_user))
+        HAVING COUNT(*) > 1
+    ) duplicated;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'usuarios con el mismo email sin distinguir mayúsculas:%', E'\n' || collisions
+            USING HINT = 'Elimine o cambie el email de los usuarios repetidos y vuelva a ejecutar la migración';
+    END IF;
+END;
+$$;
+
+-- Los eliminados también se normalizan: pueden volver a registrarse con el mismo email
+UPDATE userservice.users
+SET email_user = LOWER(TRIM(email_user))
+WHERE email_user <> LOWER(TRIM(email_user));
+
+-- LOWER() en el índice protege también las filas que no pasen por entities.Email
+DROP INDEX IF EXISTS userservice.uq_users_email_active;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_email_lower_active
+    ON userservice.users(LOWER(email_user))
+    WHERE deleted_at_user IS NULL;
@@ -0,0 +1,59 @@
+-- migrations/015_document_types.sql
+-- Catálogo de tipos de documento (entities.DocumentType), fecha de nacimiento para verificar la edad
+-- según el tipo y unicidad del documento por tipo y número
+
+ALTER TABLE userservice.users
+    ADD COLUMN IF NOT EXISTS birth_date_user DATE;
+
+-- Los tipos se guardaban como texto libre: "c.c.", "Pasaporte", "ti "...
+UPDATE userservice.users
+SET document_type_user = CASE UPPER(REPLACE(TRIM(document_type_user), '.', ''))
+        WHEN 'PASAPORTE' THEN 'PA'
+        WHEN 'PAS' THEN 'PA'
+        WHEN 'PP' THEN 'PA'
+        ELSE UPPER(REPLACE(TRIM(document_type_user), '.', ''))
+    END
+WHERE document_type_user NOT IN ('CC', 'TI', 'CE', 'PPT', 'PEP', 'PA', 'NIT');
+
+-- NOT VALID: los tipos que no se pudieron convertir se corrigen a mano y luego se ejecuta
+-- ALTER TABLE userservice.users VALIDATE CONSTRAINT ck_users_document_type; las filas nuevas ya se verifican
+ALTER TABLE userservice.users DROP CONSTRAINT IF EXISTS ck_users_document_type;
+ALTER TABLE userservice.users
+    ADD CONSTRAINT ck_users_document_type
+    CHECK (document_type_user IN ('CC', 'TI', 'CE', 'PPT', 'PEP', 'PA', 'NIT')) NOT VALID;
+
+-- Los números se guardan como los normaliza entities.NormalizeDocumentNumber: sin espacios ni puntos y en mayúsculas
+-- Antes se detectan los usuarios no eliminados cuyos documentos solo difieren en ese formato
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(format('%s %s: %s', document_type, document_number, ids), E'\n' ORDER BY document_type, document_number)
+    INTO collisions
+    FROM (
+        SELECT document_type_user AS document_type,
+               UPPER(REPLACE(REPLACE(document_number_user, ' ', ''), '.', '')) AS document_number,
+               string_agg(id_user::TEXT || ' (' || document_number_user || ')', ', ' ORDER BY created_at_user) AS ids
+        FROM userservice.users
+        WHERE deleted_at_user IS NULL
+        GROUP BY 1, 2
+        HAVING COUNT(*) > 1
+    ) duplicated;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'usuarios con el mismo documento:%', E'\n' || collisions
+            USING HINT = 'Elimine o corrija el documento de los usuarios repetidos y vuelva a ejecutar la migración';
+    END IF;
+END;
+$$;
+
+UPDATE userservice.users
+SET document_number_user = UPPER(REPLACE(REPLACE(document_number_user, ' ', ''), '.', ''))
+WHERE document_number_user <> UPPER(REPLACE(REPLACE(document_number_user, ' ', ''), '.', ''));
+
+-- El documento es único por tipo: una CC y un pasaporte pueden tener el mismo número
+DROP INDEX IF EXISTS userservice.uq_users_document_number_active;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_document_active
+    ON userservice.users(document_type_user, document_number_user)
+    WHERE deleted_at_user IS NULL;